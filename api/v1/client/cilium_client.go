@@ -13,6 +13,7 @@ import (
 
 	"github.com/cilium/cilium/api/v1/client/daemon"
 	"github.com/cilium/cilium/api/v1/client/endpoint"
+	"github.com/cilium/cilium/api/v1/client/flows"
 	"github.com/cilium/cilium/api/v1/client/ipam"
 	"github.com/cilium/cilium/api/v1/client/policy"
 	"github.com/cilium/cilium/api/v1/client/prefilter"
@@ -64,6 +65,8 @@ func New(transport runtime.ClientTransport, formats strfmt.Registry) *Cilium {
 
 	cli.Endpoint = endpoint.New(transport, formats)
 
+	cli.Flows = flows.New(transport, formats)
+
 	cli.IPAM = ipam.New(transport, formats)
 
 	cli.Policy = policy.New(transport, formats)
@@ -120,6 +123,8 @@ type Cilium struct {
 
 	Endpoint *endpoint.Client
 
+	Flows *flows.Client
+
 	IPAM *ipam.Client
 
 	Policy *policy.Client
@@ -139,6 +144,8 @@ func (c *Cilium) SetTransport(transport runtime.ClientTransport) {
 
 	c.Endpoint.SetTransport(transport)
 
+	c.Flows.SetTransport(transport)
+
 	c.IPAM.SetTransport(transport)
 
 	c.Policy.SetTransport(transport)
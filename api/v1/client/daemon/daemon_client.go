@@ -55,6 +55,38 @@ func (a *Client) GetConfig(params *GetConfigParams) (*GetConfigOK, error) {
 
 }
 
+/*
+GetConfigLabelPrefixes gets label prefix configuration
+
+Returns the label prefixes used to determine which labels of an
+endpoint are used to derive its security identity.
+
+*/
+func (a *Client) GetConfigLabelPrefixes(params *GetConfigLabelPrefixesParams) (*GetConfigLabelPrefixesOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetConfigLabelPrefixesParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "GetConfigLabelPrefixes",
+		Method:             "GET",
+		PathPattern:        "/config/label-prefixes",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &GetConfigLabelPrefixesReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*GetConfigLabelPrefixesOK), nil
+
+}
+
 /*
 GetDebuginfo retrieves information about the agent and evironment for debugging
 */
@@ -149,6 +181,42 @@ func (a *Client) PatchConfig(params *PatchConfigParams) (*PatchConfigOK, error)
 
 }
 
+/*
+PutConfigLabelPrefixes replaces label prefix configuration
+
+Replaces the list of label prefixes and re-reads the label prefix
+file. The labels of all local endpoints are filtered again. New
+identities are allocated and the policy of all local endpoints is
+updated before the endpoints switch to the new identities and
+release their old identities. Returns the endpoints which changed
+identity.
+
+*/
+func (a *Client) PutConfigLabelPrefixes(params *PutConfigLabelPrefixesParams) (*PutConfigLabelPrefixesOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewPutConfigLabelPrefixesParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "PutConfigLabelPrefixes",
+		Method:             "PUT",
+		PathPattern:        "/config/label-prefixes",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &PutConfigLabelPrefixesReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*PutConfigLabelPrefixesOK), nil
+
+}
+
 // SetTransport changes the transport on the client
 func (a *Client) SetTransport(transport runtime.ClientTransport) {
 	a.transport = transport
@@ -190,6 +190,39 @@ func (a *Client) GetEndpointIDHealthz(params *GetEndpointIDHealthzParams) (*GetE
 
 }
 
+/*
+GetEndpointIDIdentityExplain explains how the identity of an endpoint was derived
+
+Returns all labels of the endpoint and whether each label is used
+to determine the security identity of the endpoint, along with the
+label prefix which decided about the label.
+
+*/
+func (a *Client) GetEndpointIDIdentityExplain(params *GetEndpointIDIdentityExplainParams) (*GetEndpointIDIdentityExplainOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetEndpointIDIdentityExplainParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "GetEndpointIDIdentityExplain",
+		Method:             "GET",
+		PathPattern:        "/endpoint/{id}/identity/explain",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &GetEndpointIDIdentityExplainReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*GetEndpointIDIdentityExplainOK), nil
+
+}
+
 /*
 GetEndpointIDLabels retrieves the list of labels associated with an endpoint
 */
@@ -52,8 +52,107 @@ func (a *Client) DeleteIPAMIP(params *DeleteIPAMIPParams) (*DeleteIPAMIPOK, erro
 
 }
 
+/*
+GetIPAM retrieves the list of allocated IP addresses
+
+Returns all allocated IP addresses and their owners. Addresses
+allocated by a previous run of the agent which have not been
+reconciled yet are included.
+
+*/
+func (a *Client) GetIPAM(params *GetIPAMParams) (*GetIPAMOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetIPAMParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "GetIPAM",
+		Method:             "GET",
+		PathPattern:        "/ipam",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &GetIPAMReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*GetIPAMOK), nil
+
+}
+
+/*
+GetIPAMIP retrieves an allocated IP address
+
+Returns the owner, the allocation time and the endpoint using an
+allocated IP address.
+
+*/
+func (a *Client) GetIPAMIP(params *GetIPAMIPParams) (*GetIPAMIPOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetIPAMIPParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "GetIPAMIP",
+		Method:             "GET",
+		PathPattern:        "/ipam/{ip}",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &GetIPAMIPReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*GetIPAMIPOK), nil
+
+}
+
+/*
+GetIPAMPools retrieves the list of IP address pools and their usage
+*/
+func (a *Client) GetIPAMPools(params *GetIPAMPoolsParams) (*GetIPAMPoolsOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetIPAMPoolsParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "GetIPAMPools",
+		Method:             "GET",
+		PathPattern:        "/ipam/pools",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &GetIPAMPoolsReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*GetIPAMPoolsOK), nil
+
+}
+
 /*
 PostIPAM allocates an IP address
+
+Allocates an IPv4 and/or IPv6 address. The addresses are allocated
+from the pool specified by name. If no pool is specified and a
+Kubernetes pod is given, the pool is selected via the pool annotation
+of the pod or of its namespace. The default pool is used otherwise.
+
 */
 func (a *Client) PostIPAM(params *PostIPAMParams) (*PostIPAMCreated, error) {
 	// TODO: Validate the params before sending
@@ -67,6 +67,13 @@ type PostIPAMIPParams struct {
 
 	*/
 	IP string
+	/*Owner
+	  Owner of the allocation in the form container:<container ID>,
+	endpoint:<endpoint ID> or health
+
+
+	*/
+	Owner *string
 
 	timeout    time.Duration
 	Context    context.Context
@@ -117,6 +124,17 @@ func (o *PostIPAMIPParams) SetIP(ip string) {
 	o.IP = ip
 }
 
+// WithOwner adds the owner to the post IP a m IP params
+func (o *PostIPAMIPParams) WithOwner(owner *string) *PostIPAMIPParams {
+	o.SetOwner(owner)
+	return o
+}
+
+// SetOwner adds the owner to the post IP a m IP params
+func (o *PostIPAMIPParams) SetOwner(owner *string) {
+	o.Owner = owner
+}
+
 // WriteToRequest writes these params to a swagger request
 func (o *PostIPAMIPParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.Registry) error {
 
@@ -130,6 +148,22 @@ func (o *PostIPAMIPParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.Re
 		return err
 	}
 
+	if o.Owner != nil {
+
+		// query param owner
+		var qrOwner string
+		if o.Owner != nil {
+			qrOwner = *o.Owner
+		}
+		qOwner := qrOwner
+		if qOwner != "" {
+			if err := r.SetQueryParam("owner", qOwner); err != nil {
+				return err
+			}
+		}
+
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
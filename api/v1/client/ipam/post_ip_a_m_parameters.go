@@ -64,6 +64,25 @@ type PostIPAMParams struct {
 
 	/*Family*/
 	Family *string
+	/*K8sPod
+	  Kubernetes pod in the form namespace/name used to select the pool if
+	no pool is specified
+
+
+	*/
+	K8sPod *string
+	/*Owner
+	  Owner of the allocation in the form container:<container ID>,
+	endpoint:<endpoint ID> or health
+
+
+	*/
+	Owner *string
+	/*Pool
+	  Name of the pool to allocate from
+
+	*/
+	Pool *string
 
 	timeout    time.Duration
 	Context    context.Context
@@ -114,6 +133,39 @@ func (o *PostIPAMParams) SetFamily(family *string) {
 	o.Family = family
 }
 
+// WithK8sPod adds the k8sPod to the post IP a m params
+func (o *PostIPAMParams) WithK8sPod(k8sPod *string) *PostIPAMParams {
+	o.SetK8sPod(k8sPod)
+	return o
+}
+
+// SetK8sPod adds the k8sPod to the post IP a m params
+func (o *PostIPAMParams) SetK8sPod(k8sPod *string) {
+	o.K8sPod = k8sPod
+}
+
+// WithOwner adds the owner to the post IP a m params
+func (o *PostIPAMParams) WithOwner(owner *string) *PostIPAMParams {
+	o.SetOwner(owner)
+	return o
+}
+
+// SetOwner adds the owner to the post IP a m params
+func (o *PostIPAMParams) SetOwner(owner *string) {
+	o.Owner = owner
+}
+
+// WithPool adds the pool to the post IP a m params
+func (o *PostIPAMParams) WithPool(pool *string) *PostIPAMParams {
+	o.SetPool(pool)
+	return o
+}
+
+// SetPool adds the pool to the post IP a m params
+func (o *PostIPAMParams) SetPool(pool *string) {
+	o.Pool = pool
+}
+
 // WriteToRequest writes these params to a swagger request
 func (o *PostIPAMParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.Registry) error {
 
@@ -138,6 +190,54 @@ func (o *PostIPAMParams) WriteToRequest(r runtime.ClientRequest, reg strfmt.Regi
 
 	}
 
+	if o.K8sPod != nil {
+
+		// query param k8s-pod
+		var qrK8sPod string
+		if o.K8sPod != nil {
+			qrK8sPod = *o.K8sPod
+		}
+		qK8sPod := qrK8sPod
+		if qK8sPod != "" {
+			if err := r.SetQueryParam("k8s-pod", qK8sPod); err != nil {
+				return err
+			}
+		}
+
+	}
+
+	if o.Owner != nil {
+
+		// query param owner
+		var qrOwner string
+		if o.Owner != nil {
+			qrOwner = *o.Owner
+		}
+		qOwner := qrOwner
+		if qOwner != "" {
+			if err := r.SetQueryParam("owner", qOwner); err != nil {
+				return err
+			}
+		}
+
+	}
+
+	if o.Pool != nil {
+
+		// query param pool
+		var qrPool string
+		if o.Pool != nil {
+			qrPool = *o.Pool
+		}
+		qPool := qrPool
+		if qPool != "" {
+			if err := r.SetQueryParam("pool", qPool); err != nil {
+				return err
+			}
+		}
+
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
@@ -32,6 +32,13 @@ func (o *PostIPAMReader) ReadResponse(response runtime.ClientResponse, consumer
 		}
 		return result, nil
 
+	case 404:
+		result := NewPostIPAMPoolNotFound()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return nil, result
+
 	case 502:
 		result := NewPostIPAMFailure()
 		if err := result.readResponse(response, consumer, o.formats); err != nil {
@@ -73,6 +80,33 @@ func (o *PostIPAMCreated) readResponse(response runtime.ClientResponse, consumer
 	return nil
 }
 
+// NewPostIPAMPoolNotFound creates a PostIPAMPoolNotFound with default headers values
+func NewPostIPAMPoolNotFound() *PostIPAMPoolNotFound {
+	return &PostIPAMPoolNotFound{}
+}
+
+/*PostIPAMPoolNotFound handles this case with default header values.
+
+Pool not found
+*/
+type PostIPAMPoolNotFound struct {
+	Payload models.Error
+}
+
+func (o *PostIPAMPoolNotFound) Error() string {
+	return fmt.Sprintf("[POST /ipam][%d] postIpAMPoolNotFound  %+v", 404, o.Payload)
+}
+
+func (o *PostIPAMPoolNotFound) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	// response payload
+	if err := consumer.Consume(response.Body(), &o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
 // NewPostIPAMFailure creates a PostIPAMFailure with default headers values
 func NewPostIPAMFailure() *PostIPAMFailure {
 	return &PostIPAMFailure{}
@@ -83,6 +83,39 @@ func (a *Client) GetIdentity(params *GetIdentityParams) (*GetIdentityOK, error)
 
 }
 
+/*
+GetIdentityGc retrieves the identity garbage collection report
+
+Returns the identities which are no longer referenced by any node
+and will be released by the next garbage collector run, as well as
+the identities which are only referenced by stale nodes.
+
+*/
+func (a *Client) GetIdentityGc(params *GetIdentityGcParams) (*GetIdentityGcOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetIdentityGcParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "GetIdentityGc",
+		Method:             "GET",
+		PathPattern:        "/identity/gc",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &GetIdentityGcReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*GetIdentityGcOK), nil
+
+}
+
 /*
 GetIdentityID retrieves identity
 */
@@ -170,6 +203,38 @@ func (a *Client) GetPolicyResolve(params *GetPolicyResolveParams) (*GetPolicyRes
 
 }
 
+/*
+PostIdentityGc runs the identity garbage collector
+
+Releases all identities which are no longer referenced by any node
+and returns the released identities.
+
+*/
+func (a *Client) PostIdentityGc(params *PostIdentityGcParams) (*PostIdentityGcOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewPostIdentityGcParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "PostIdentityGc",
+		Method:             "POST",
+		PathPattern:        "/identity/gc",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &PostIdentityGcReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*PostIdentityGcOK), nil
+
+}
+
 /*
 PutPolicy creates or update a policy sub tree
 */
@@ -6,6 +6,8 @@ package models
 // Editing this file might prove futile when you re-run the swagger generate command
 
 import (
+	"encoding/json"
+
 	strfmt "github.com/go-openapi/strfmt"
 
 	"github.com/go-openapi/errors"
@@ -18,6 +20,19 @@ import (
 
 type BackendAddress struct {
 
+	// Time at which the draining backend is removed from the service.
+	// Draining backends have been removed from the service and only
+	// serve their established connections. Unset unless the backend is
+	// draining. Ignored when the service is updated.
+	//
+	DrainDeadline strfmt.DateTime `json:"drain-deadline,omitempty"`
+
+	// Health of the backend as determined by the health check of the
+	// service. Unhealthy backends are not selected for new connections.
+	// Ignored when the service is updated.
+	//
+	Health string `json:"health,omitempty"`
+
 	// Layer 3 address
 	// Required: true
 	IP *string `json:"ip"`
@@ -25,31 +40,95 @@ type BackendAddress struct {
 	// Layer 4 port number
 	Port uint16 `json:"port,omitempty"`
 
+	// Traffic sent to the backend by this node. Only set when a single
+	// service is retrieved. Ignored when the service is updated.
+	//
+	Statistics *ServiceStatistics `json:"statistics,omitempty"`
+
 	// Weight for Round Robin
 	Weight uint16 `json:"weight,omitempty"`
 }
 
+/* polymorph BackendAddress drain-deadline false */
+
+/* polymorph BackendAddress health false */
+
 /* polymorph BackendAddress ip false */
 
 /* polymorph BackendAddress port false */
 
+/* polymorph BackendAddress statistics false */
+
 /* polymorph BackendAddress weight false */
 
 // Validate validates this backend address
 func (m *BackendAddress) Validate(formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.validateHealth(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if err := m.validateIP(formats); err != nil {
 		// prop
 		res = append(res, err)
 	}
 
+	if err := m.validateStatistics(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
 	return nil
 }
 
+var backendAddressTypeHealthPropEnum []interface{}
+
+func init() {
+	var res []string
+	if err := json.Unmarshal([]byte(`["Unknown","Healthy","Unhealthy"]`), &res); err != nil {
+		panic(err)
+	}
+	for _, v := range res {
+		backendAddressTypeHealthPropEnum = append(backendAddressTypeHealthPropEnum, v)
+	}
+}
+
+const (
+	// BackendAddressHealthUnknown captures enum value "Unknown"
+	BackendAddressHealthUnknown string = "Unknown"
+	// BackendAddressHealthHealthy captures enum value "Healthy"
+	BackendAddressHealthHealthy string = "Healthy"
+	// BackendAddressHealthUnhealthy captures enum value "Unhealthy"
+	BackendAddressHealthUnhealthy string = "Unhealthy"
+)
+
+// prop value enum
+func (m *BackendAddress) validateHealthEnum(path, location string, value string) error {
+	if err := validate.Enum(path, location, value, backendAddressTypeHealthPropEnum); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *BackendAddress) validateHealth(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Health) { // not required
+		return nil
+	}
+
+	// value enum
+	if err := m.validateHealthEnum("health", "body", m.Health); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (m *BackendAddress) validateIP(formats strfmt.Registry) error {
 
 	if err := validate.Required("ip", "body", m.IP); err != nil {
@@ -59,6 +138,25 @@ func (m *BackendAddress) validateIP(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *BackendAddress) validateStatistics(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Statistics) { // not required
+		return nil
+	}
+
+	if m.Statistics != nil {
+
+		if err := m.Statistics.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("statistics")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 // MarshalBinary interface implementation
 func (m *BackendAddress) MarshalBinary() ([]byte, error) {
 	if m == nil {
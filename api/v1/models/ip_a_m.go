@@ -25,12 +25,20 @@ type IPAM struct {
 	// host addressing
 	// Required: true
 	HostAddressing *NodeAddressing `json:"host-addressing"`
+
+	// Name of the pool the addresses have been allocated from. Addresses
+	// of a family not provided by the pool are allocated from the default
+	// pool.
+	//
+	Pool string `json:"pool,omitempty"`
 }
 
 /* polymorph IPAM endpoint false */
 
 /* polymorph IPAM host-addressing false */
 
+/* polymorph IPAM pool false */
+
 // Validate validates this IP a m
 func (m *IPAM) Validate(formats strfmt.Registry) error {
 	var res []error
@@ -6,10 +6,14 @@ package models
 // Editing this file might prove futile when you re-run the swagger generate command
 
 import (
+	"encoding/json"
+	"strconv"
+
 	strfmt "github.com/go-openapi/strfmt"
 
 	"github.com/go-openapi/errors"
 	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
 )
 
 // MonitorStatus Status of the node monitor
@@ -20,6 +24,12 @@ type MonitorStatus struct {
 	// Number of CPUs to listen on for events.
 	Cpus int64 `json:"cpus,omitempty"`
 
+	// Number of listeners disconnected for being too slow.
+	Disconnected int64 `json:"disconnected,omitempty"`
+
+	// Status of all connected monitor listeners
+	Listeners []*MonitorListenerStatus `json:"listeners"`
+
 	// Number of samples lost by perf.
 	Lost int64 `json:"lost,omitempty"`
 
@@ -29,30 +39,119 @@ type MonitorStatus struct {
 	// Pages size used for the perf ring buffer.
 	Pagesize int64 `json:"pagesize,omitempty"`
 
+	// Policy applied to listeners which cannot keep up with the event rate.
+	SlowConsumerPolicy string `json:"slow-consumer-policy,omitempty"`
+
 	// Number of unknown samples.
 	Unknown int64 `json:"unknown,omitempty"`
 }
 
 /* polymorph MonitorStatus cpus false */
 
+/* polymorph MonitorStatus disconnected false */
+
+/* polymorph MonitorStatus listeners false */
+
 /* polymorph MonitorStatus lost false */
 
 /* polymorph MonitorStatus npages false */
 
 /* polymorph MonitorStatus pagesize false */
 
+/* polymorph MonitorStatus slow-consumer-policy false */
+
 /* polymorph MonitorStatus unknown false */
 
 // Validate validates this monitor status
 func (m *MonitorStatus) Validate(formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.validateListeners(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateSlowConsumerPolicy(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
 	return nil
 }
 
+func (m *MonitorStatus) validateListeners(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Listeners) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.Listeners); i++ {
+
+		if swag.IsZero(m.Listeners[i]) { // not required
+			continue
+		}
+
+		if m.Listeners[i] != nil {
+
+			if err := m.Listeners[i].Validate(formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("listeners" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
+var monitorStatusTypeSlowConsumerPolicyPropEnum []interface{}
+
+func init() {
+	var res []string
+	if err := json.Unmarshal([]byte(`["drop-newest","drop-oldest","disconnect"]`), &res); err != nil {
+		panic(err)
+	}
+	for _, v := range res {
+		monitorStatusTypeSlowConsumerPolicyPropEnum = append(monitorStatusTypeSlowConsumerPolicyPropEnum, v)
+	}
+}
+
+const (
+	// MonitorStatusSlowConsumerPolicyDropNewest captures enum value "drop-newest"
+	MonitorStatusSlowConsumerPolicyDropNewest string = "drop-newest"
+	// MonitorStatusSlowConsumerPolicyDropOldest captures enum value "drop-oldest"
+	MonitorStatusSlowConsumerPolicyDropOldest string = "drop-oldest"
+	// MonitorStatusSlowConsumerPolicyDisconnect captures enum value "disconnect"
+	MonitorStatusSlowConsumerPolicyDisconnect string = "disconnect"
+)
+
+// prop value enum
+func (m *MonitorStatus) validateSlowConsumerPolicyEnum(path, location string, value string) error {
+	if err := validate.Enum(path, location, value, monitorStatusTypeSlowConsumerPolicyPropEnum); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *MonitorStatus) validateSlowConsumerPolicy(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.SlowConsumerPolicy) { // not required
+		return nil
+	}
+
+	// value enum
+	if err := m.validateSlowConsumerPolicyEnum("slow-consumer-policy", "body", m.SlowConsumerPolicy); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // MarshalBinary interface implementation
 func (m *MonitorStatus) MarshalBinary() ([]byte, error) {
 	if m == nil {
@@ -6,6 +6,7 @@ package models
 // Editing this file might prove futile when you re-run the swagger generate command
 
 import (
+	"encoding/json"
 	"strconv"
 
 	strfmt "github.com/go-openapi/strfmt"
@@ -30,8 +31,42 @@ type Service struct {
 	// Required: true
 	FrontendAddress *FrontendAddress `json:"frontend-address"`
 
+	// Type of the frontend. Kubernetes services have a ClusterIP
+	// frontend and additional NodePort frontends on each address of the
+	// node and ExternalIP frontends. Services added through the API are
+	// ClusterIP services unless specified otherwise.
+	//
+	FrontendType string `json:"frontend-type,omitempty"`
+
+	// Health check of the backends. Backends failing the health check
+	// are removed from the service until they pass it again.
+	//
+	HealthCheck *ServiceHealthCheck `json:"health-check,omitempty"`
+
 	// Unique identification
 	ID int64 `json:"id,omitempty"`
+
+	// Algorithm used to select the backend of a connection. The maglev
+	// algorithm uses a consistent hashing lookup table which only remaps
+	// a minimal share of the connections when backends are added or
+	// removed.
+	//
+	LbAlgorithm string `json:"lb-algorithm,omitempty"`
+
+	// Session affinity of the service. With ClientIP, all connections
+	// of a client are sent to the same backend until the client has
+	// been idle for the session affinity timeout.
+	//
+	SessionAffinity string `json:"session-affinity,omitempty"`
+
+	// Session affinity timeout in seconds
+	SessionAffinityTimeout int64 `json:"session-affinity-timeout,omitempty"`
+
+	// Traffic sent to all backends of the frontend by this node. Only set
+	// when a single service is retrieved. Ignored when the service is
+	// updated.
+	//
+	Statistics *ServiceStatistics `json:"statistics,omitempty"`
 }
 
 /* polymorph Service backend-addresses false */
@@ -40,8 +75,20 @@ type Service struct {
 
 /* polymorph Service frontend-address false */
 
+/* polymorph Service frontend-type false */
+
+/* polymorph Service health-check false */
+
 /* polymorph Service id false */
 
+/* polymorph Service lb-algorithm false */
+
+/* polymorph Service session-affinity false */
+
+/* polymorph Service session-affinity-timeout false */
+
+/* polymorph Service statistics false */
+
 // Validate validates this service
 func (m *Service) Validate(formats strfmt.Registry) error {
 	var res []error
@@ -61,6 +108,31 @@ func (m *Service) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateFrontendType(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateHealthCheck(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateLbAlgorithm(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateSessionAffinity(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateStatistics(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
@@ -132,6 +204,169 @@ func (m *Service) validateFrontendAddress(formats strfmt.Registry) error {
 	return nil
 }
 
+var serviceTypeFrontendTypePropEnum []interface{}
+
+func init() {
+	var res []string
+	if err := json.Unmarshal([]byte(`["ClusterIP","NodePort","ExternalIP"]`), &res); err != nil {
+		panic(err)
+	}
+	for _, v := range res {
+		serviceTypeFrontendTypePropEnum = append(serviceTypeFrontendTypePropEnum, v)
+	}
+}
+
+const (
+	// ServiceFrontendTypeClusterIP captures enum value "ClusterIP"
+	ServiceFrontendTypeClusterIP string = "ClusterIP"
+	// ServiceFrontendTypeNodePort captures enum value "NodePort"
+	ServiceFrontendTypeNodePort string = "NodePort"
+	// ServiceFrontendTypeExternalIP captures enum value "ExternalIP"
+	ServiceFrontendTypeExternalIP string = "ExternalIP"
+)
+
+// prop value enum
+func (m *Service) validateFrontendTypeEnum(path, location string, value string) error {
+	if err := validate.Enum(path, location, value, serviceTypeFrontendTypePropEnum); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *Service) validateFrontendType(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.FrontendType) { // not required
+		return nil
+	}
+
+	// value enum
+	if err := m.validateFrontendTypeEnum("frontend-type", "body", m.FrontendType); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *Service) validateHealthCheck(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.HealthCheck) { // not required
+		return nil
+	}
+
+	if m.HealthCheck != nil {
+
+		if err := m.HealthCheck.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("health-check")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+var serviceTypeLbAlgorithmPropEnum []interface{}
+
+func init() {
+	var res []string
+	if err := json.Unmarshal([]byte(`["random","maglev"]`), &res); err != nil {
+		panic(err)
+	}
+	for _, v := range res {
+		serviceTypeLbAlgorithmPropEnum = append(serviceTypeLbAlgorithmPropEnum, v)
+	}
+}
+
+const (
+	// ServiceLbAlgorithmRandom captures enum value "random"
+	ServiceLbAlgorithmRandom string = "random"
+	// ServiceLbAlgorithmMaglev captures enum value "maglev"
+	ServiceLbAlgorithmMaglev string = "maglev"
+)
+
+// prop value enum
+func (m *Service) validateLbAlgorithmEnum(path, location string, value string) error {
+	if err := validate.Enum(path, location, value, serviceTypeLbAlgorithmPropEnum); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *Service) validateLbAlgorithm(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.LbAlgorithm) { // not required
+		return nil
+	}
+
+	// value enum
+	if err := m.validateLbAlgorithmEnum("lb-algorithm", "body", m.LbAlgorithm); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var serviceTypeSessionAffinityPropEnum []interface{}
+
+func init() {
+	var res []string
+	if err := json.Unmarshal([]byte(`["None","ClientIP"]`), &res); err != nil {
+		panic(err)
+	}
+	for _, v := range res {
+		serviceTypeSessionAffinityPropEnum = append(serviceTypeSessionAffinityPropEnum, v)
+	}
+}
+
+const (
+	// ServiceSessionAffinityNone captures enum value "None"
+	ServiceSessionAffinityNone string = "None"
+	// ServiceSessionAffinityClientIP captures enum value "ClientIP"
+	ServiceSessionAffinityClientIP string = "ClientIP"
+)
+
+// prop value enum
+func (m *Service) validateSessionAffinityEnum(path, location string, value string) error {
+	if err := validate.Enum(path, location, value, serviceTypeSessionAffinityPropEnum); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *Service) validateSessionAffinity(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.SessionAffinity) { // not required
+		return nil
+	}
+
+	// value enum
+	if err := m.validateSessionAffinityEnum("session-affinity", "body", m.SessionAffinity); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *Service) validateStatistics(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Statistics) { // not required
+		return nil
+	}
+
+	if m.Statistics != nil {
+
+		if err := m.Statistics.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("statistics")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 // MarshalBinary interface implementation
 func (m *Service) MarshalBinary() ([]byte, error) {
 	if m == nil {
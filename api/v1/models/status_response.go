@@ -38,6 +38,9 @@ type StatusResponse struct {
 	// Status of key/value datastore
 	Kvstore *Status `json:"kvstore,omitempty"`
 
+	// Status of re-creating keys after the kvstore lease expired
+	KvstoreResync *KvstoreResyncStatus `json:"kvstore-resync,omitempty"`
+
 	// Status of the node monitor
 	NodeMonitor *MonitorStatus `json:"nodeMonitor,omitempty"`
 
@@ -59,6 +62,8 @@ type StatusResponse struct {
 
 /* polymorph StatusResponse kvstore false */
 
+/* polymorph StatusResponse kvstore-resync false */
+
 /* polymorph StatusResponse nodeMonitor false */
 
 /* polymorph StatusResponse proxy false */
@@ -97,6 +102,11 @@ func (m *StatusResponse) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateKvstoreResync(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if err := m.validateNodeMonitor(formats); err != nil {
 		// prop
 		res = append(res, err)
@@ -227,6 +237,25 @@ func (m *StatusResponse) validateKvstore(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *StatusResponse) validateKvstoreResync(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.KvstoreResync) { // not required
+		return nil
+	}
+
+	if m.KvstoreResync != nil {
+
+		if err := m.KvstoreResync.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("kvstore-resync")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *StatusResponse) validateNodeMonitor(formats strfmt.Registry) error {
 
 	if swag.IsZero(m.NodeMonitor) { // not required
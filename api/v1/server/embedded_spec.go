@@ -80,6 +80,58 @@ func init() {
         }
       }
     },
+    "/config/label-prefixes": {
+      "get": {
+        "description": "Returns the label prefixes used to determine which labels of an\nendpoint are used to derive its security identity.\n",
+        "tags": [
+          "daemon"
+        ],
+        "summary": "Get label prefix configuration",
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "$ref": "#/definitions/LabelPrefixConfiguration"
+            }
+          }
+        }
+      },
+      "put": {
+        "description": "Replaces the list of label prefixes and re-reads the label prefix\nfile. The labels of all local endpoints are filtered again. New\nidentities are allocated and the policy of all local endpoints is\nupdated before the endpoints switch to the new identities and\nrelease their old identities. Returns the endpoints which changed\nidentity.\n",
+        "tags": [
+          "daemon"
+        ],
+        "summary": "Replace label prefix configuration",
+        "parameters": [
+          {
+            "name": "configuration",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/LabelPrefixConfiguration"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "type": "array",
+              "items": {
+                "$ref": "#/definitions/IdentityMigration"
+              }
+            }
+          },
+          "400": {
+            "description": "Invalid label prefix",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            },
+            "x-go-name": "Invalid"
+          }
+        }
+      }
+    },
     "/debuginfo": {
       "get": {
         "tags": [
@@ -364,6 +416,38 @@ func init() {
         }
       }
     },
+    "/endpoint/{id}/identity/explain": {
+      "get": {
+        "description": "Returns all labels of the endpoint and whether each label is used\nto determine the security identity of the endpoint, along with the\nlabel prefix which decided about the label.\n",
+        "tags": [
+          "endpoint"
+        ],
+        "summary": "Explain how the identity of an endpoint was derived",
+        "parameters": [
+          {
+            "$ref": "#/parameters/endpoint-id"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "$ref": "#/definitions/IdentityExplanation"
+            }
+          },
+          "400": {
+            "description": "Invalid endpoint ID format for specified type",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            },
+            "x-go-name": "Invalid"
+          },
+          "404": {
+            "description": "Endpoint not found"
+          }
+        }
+      }
+    },
     "/endpoint/{id}/labels": {
       "get": {
         "tags": [
@@ -458,6 +542,98 @@ func init() {
         }
       }
     },
+    "/flows": {
+      "get": {
+        "description": "Returns the flows recently observed by endpoints of this node, ordered\nby time. Flows are recorded from trace and drop notifications of the\ndatapath and from L7 access log records. Only a limited number of\nflows is kept for each endpoint.\n",
+        "tags": [
+          "flows"
+        ],
+        "summary": "Retrieve recently observed flows",
+        "parameters": [
+          {
+            "type": "integer",
+            "description": "Only return flows observed by the endpoint with the given ID",
+            "name": "endpoint",
+            "in": "query"
+          },
+          {
+            "enum": [
+              "Forwarded",
+              "Denied",
+              "Error"
+            ],
+            "type": "string",
+            "description": "Only return flows with the given verdict",
+            "name": "verdict",
+            "in": "query"
+          },
+          {
+            "type": "string",
+            "description": "Only return flows observed within the given duration, e.g. 1m\n",
+            "name": "since",
+            "in": "query"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "type": "array",
+              "items": {
+                "$ref": "#/definitions/Flow"
+              }
+            }
+          },
+          "400": {
+            "description": "Invalid endpoint ID or duration",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            },
+            "x-go-name": "Invalid"
+          },
+          "501": {
+            "description": "Flow store is disabled",
+            "x-go-name": "Disabled"
+          }
+        }
+      }
+    },
+    "/flows/graph": {
+      "get": {
+        "description": "Returns the graph of identities and services which have been observed\nto communicate with each other. The graph is built from trace and\ndrop notifications, connection tracking entries and L7 access log\nrecords.\n",
+        "tags": [
+          "flows"
+        ],
+        "summary": "Retrieve the dependency graph of observed flows",
+        "parameters": [
+          {
+            "type": "string",
+            "description": "Only include edges observed within the given duration, e.g. 10m\n",
+            "name": "since",
+            "in": "query"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "$ref": "#/definitions/FlowGraph"
+            }
+          },
+          "400": {
+            "description": "Invalid duration",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            },
+            "x-go-name": "Invalid"
+          },
+          "501": {
+            "description": "Flow graph is disabled",
+            "x-go-name": "Disabled"
+          }
+        }
+      }
+    },
     "/healthz": {
       "get": {
         "description": "Returns health and status information of the Cilium daemon and related\ncomponents such as the local container runtime, connected datastore,\nKubernetes integration.\n",
@@ -517,6 +693,55 @@ func init() {
         }
       }
     },
+    "/identity/gc": {
+      "get": {
+        "description": "Returns the identities which are no longer referenced by any node\nand will be released by the next garbage collector run, as well as\nthe identities which are only referenced by stale nodes.\n",
+        "tags": [
+          "policy"
+        ],
+        "summary": "Retrieve the identity garbage collection report",
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "$ref": "#/definitions/IdentityGCReport"
+            }
+          },
+          "520": {
+            "description": "Identity storage unreachable. Likely a network problem.",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            },
+            "x-go-name": "Unreachable"
+          }
+        }
+      },
+      "post": {
+        "description": "Releases all identities which are no longer referenced by any node\nand returns the released identities.\n",
+        "tags": [
+          "policy"
+        ],
+        "summary": "Run the identity garbage collector",
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "type": "array",
+              "items": {
+                "$ref": "#/definitions/IdentityGCEntry"
+              }
+            }
+          },
+          "520": {
+            "description": "Identity storage unreachable. Likely a network problem.",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            },
+            "x-go-name": "Unreachable"
+          }
+        }
+      }
+    },
     "/identity/{id}": {
       "get": {
         "tags": [
@@ -559,7 +784,34 @@ func init() {
       }
     },
     "/ipam": {
+      "get": {
+        "description": "Returns all allocated IP addresses and their owners. Addresses\nallocated by a previous run of the agent which have not been\nreconciled yet are included.\n",
+        "tags": [
+          "ipam"
+        ],
+        "summary": "Retrieve the list of allocated IP addresses",
+        "parameters": [
+          {
+            "type": "boolean",
+            "description": "Only return allocations which are not used by any endpoint\n",
+            "name": "orphans",
+            "in": "query"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "type": "array",
+              "items": {
+                "$ref": "#/definitions/IPAMAllocation"
+              }
+            }
+          }
+        }
+      },
       "post": {
+        "description": "Allocates an IPv4 and/or IPv6 address. The addresses are allocated\nfrom the pool specified by name. If no pool is specified and a\nKubernetes pod is given, the pool is selected via the pool annotation\nof the pod or of its namespace. The default pool is used otherwise.\n",
         "tags": [
           "ipam"
         ],
@@ -567,6 +819,15 @@ func init() {
         "parameters": [
           {
             "$ref": "#/parameters/ipam-family"
+          },
+          {
+            "$ref": "#/parameters/ipam-pool"
+          },
+          {
+            "$ref": "#/parameters/ipam-k8s-pod"
+          },
+          {
+            "$ref": "#/parameters/ipam-owner"
           }
         ],
         "responses": {
@@ -576,6 +837,13 @@ func init() {
               "$ref": "#/definitions/IPAM"
             }
           },
+          "404": {
+            "description": "Pool not found",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            },
+            "x-go-name": "PoolNotFound"
+          },
           "502": {
             "description": "Allocation failure",
             "schema": {
@@ -586,7 +854,53 @@ func init() {
         }
       }
     },
+    "/ipam/pools": {
+      "get": {
+        "tags": [
+          "ipam"
+        ],
+        "summary": "Retrieve the list of IP address pools and their usage",
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "type": "array",
+              "items": {
+                "$ref": "#/definitions/IPAMPool"
+              }
+            }
+          }
+        }
+      }
+    },
     "/ipam/{ip}": {
+      "get": {
+        "description": "Returns the owner, the allocation time and the endpoint using an\nallocated IP address.\n",
+        "tags": [
+          "ipam"
+        ],
+        "summary": "Retrieve an allocated IP address",
+        "parameters": [
+          {
+            "$ref": "#/parameters/ipam-ip"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "$ref": "#/definitions/IPAMAllocation"
+            }
+          },
+          "400": {
+            "description": "Invalid IP address",
+            "x-go-name": "Invalid"
+          },
+          "404": {
+            "description": "IP address not allocated"
+          }
+        }
+      },
       "post": {
         "tags": [
           "ipam"
@@ -595,6 +909,9 @@ func init() {
         "parameters": [
           {
             "$ref": "#/parameters/ipam-ip"
+          },
+          {
+            "$ref": "#/parameters/ipam-owner"
           }
         ],
         "responses": {
@@ -995,6 +1312,20 @@ func init() {
         "ip"
       ],
       "properties": {
+        "drain-deadline": {
+          "description": "Time at which the draining backend is removed from the service.\nDraining backends have been removed from the service and only\nserve their established connections. Unset unless the backend is\ndraining. Ignored when the service is updated.\n",
+          "type": "string",
+          "format": "date-time"
+        },
+        "health": {
+          "description": "Health of the backend as determined by the health check of the\nservice. Unhealthy backends are not selected for new connections.\nIgnored when the service is updated.\n",
+          "type": "string",
+          "enum": [
+            "Unknown",
+            "Healthy",
+            "Unhealthy"
+          ]
+        },
         "ip": {
           "description": "Layer 3 address",
           "type": "string"
@@ -1004,6 +1335,10 @@ func init() {
           "type": "integer",
           "format": "uint16"
         },
+        "statistics": {
+          "description": "Traffic sent to the backend by this node. Only set when a single\nservice is retrieved. Ignored when the service is updated.\n",
+          "$ref": "#/definitions/ServiceStatistics"
+        },
         "weight": {
           "description": "Weight for Round Robin",
           "type": "integer",
@@ -1526,16 +1861,347 @@ func init() {
     "Error": {
       "type": "string"
     },
-    "FrontendAddress": {
-      "description": "Layer 4 address",
+    "Flow": {
+      "description": "Flow observed by an endpoint. Flows originating from the datapath carry\nthe trace observation point and connection state, flows originating\nfrom the L7 proxy carry the protocol specific request or response.\n",
       "type": "object",
       "properties": {
-        "ip": {
-          "description": "Layer 3 address",
+        "connection-state": {
+          "description": "Connection tracking state of a forwarded packet, e.g. new or\nestablished\n",
           "type": "string"
         },
-        "port": {
-          "description": "Layer 4 port number",
+        "destination": {
+          "description": "Destination of the flow",
+          "$ref": "#/definitions/FlowEndpoint"
+        },
+        "drop-reason": {
+          "description": "Reason the flow was dropped by the datapath",
+          "type": "string"
+        },
+        "endpoint-id": {
+          "description": "ID of the endpoint which observed the flow",
+          "type": "integer"
+        },
+        "flow-event": {
+          "description": "Event type of L4 flows",
+          "type": "string"
+        },
+        "http": {
+          "description": "HTTP request or response",
+          "$ref": "#/definitions/FlowHTTP"
+        },
+        "info": {
+          "description": "Rule that matched or error that occurred",
+          "type": "string"
+        },
+        "ip-version": {
+          "description": "IP version of the flow",
+          "type": "string",
+          "enum": [
+            "IPv4",
+            "IPv6"
+          ]
+        },
+        "kafka": {
+          "description": "Kafka request or response",
+          "$ref": "#/definitions/FlowKafka"
+        },
+        "metadata": {
+          "description": "Additional arbitrary metadata",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "node-address": {
+          "description": "Addresses of the node the flow was observed on",
+          "$ref": "#/definitions/FlowNodeAddress"
+        },
+        "observation-point": {
+          "description": "Direction in which the flow was observed",
+          "type": "string",
+          "enum": [
+            "Ingress",
+            "Egress"
+          ]
+        },
+        "service": {
+          "description": "Service the flow went through",
+          "$ref": "#/definitions/FlowService"
+        },
+        "source": {
+          "description": "Source of the flow",
+          "$ref": "#/definitions/FlowEndpoint"
+        },
+        "timestamp": {
+          "description": "Time the flow was observed",
+          "type": "string",
+          "format": "date-time"
+        },
+        "trace-observation-point": {
+          "description": "Datapath location at which a forwarded packet was traced, e.g.\nto-endpoint or from-stack\n",
+          "type": "string"
+        },
+        "transport-protocol": {
+          "description": "Layer 4 protocol number of the flow",
+          "type": "integer"
+        },
+        "type": {
+          "description": "Type of the flow",
+          "type": "string",
+          "enum": [
+            "Request",
+            "Response",
+            "Sample"
+          ]
+        },
+        "verdict": {
+          "description": "Verdict taken on the flow",
+          "type": "string",
+          "enum": [
+            "Forwarded",
+            "Denied",
+            "Error"
+          ]
+        }
+      }
+    },
+    "FlowEndpoint": {
+      "description": "Source or destination of a flow",
+      "type": "object",
+      "properties": {
+        "id": {
+          "description": "ID of the local endpoint, if any",
+          "type": "integer"
+        },
+        "identity": {
+          "description": "Security identity",
+          "type": "integer"
+        },
+        "ipv4": {
+          "description": "IPv4 address",
+          "type": "string"
+        },
+        "ipv6": {
+          "description": "IPv6 address",
+          "type": "string"
+        },
+        "labels": {
+          "description": "Security relevant labels",
+          "$ref": "#/definitions/Labels"
+        },
+        "port": {
+          "description": "Source port of the source, destination port of the destination",
+          "type": "integer"
+        }
+      }
+    },
+    "FlowGraph": {
+      "description": "Dependency graph of identities and services built from observed flows",
+      "type": "object",
+      "properties": {
+        "edges": {
+          "description": "Observed communication between identities",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/FlowGraphEdge"
+          }
+        },
+        "nodes": {
+          "description": "Identities observed as source or destination of a flow",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/FlowGraphNode"
+          }
+        },
+        "service-edges": {
+          "description": "Observed communication between services",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/FlowGraphEdge"
+          }
+        }
+      }
+    },
+    "FlowGraphEdge": {
+      "description": "Observed communication from a source to a destination. Identity edges\ncarry the source and destination identity, service edges the source\nand destination service.\n",
+      "type": "object",
+      "properties": {
+        "connections": {
+          "description": "Number of connection tracking entries observed",
+          "type": "integer"
+        },
+        "destination-identity": {
+          "description": "Security identity of the destination",
+          "type": "integer"
+        },
+        "destination-service": {
+          "description": "Service of the destination",
+          "type": "string"
+        },
+        "dropped": {
+          "description": "Number of dropped packets and denied L7 requests",
+          "type": "integer"
+        },
+        "first-seen": {
+          "description": "Time the edge was first observed",
+          "type": "string",
+          "format": "date-time"
+        },
+        "forwarded": {
+          "description": "Number of forwarded packets traced",
+          "type": "integer"
+        },
+        "l7-methods": {
+          "description": "L7 request methods, e.g. HTTP methods or Kafka API keys",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "l7-requests": {
+          "description": "Number of L7 requests",
+          "type": "integer"
+        },
+        "last-seen": {
+          "description": "Time the edge was last observed",
+          "type": "string",
+          "format": "date-time"
+        },
+        "ports": {
+          "description": "Destination ports in the form port/protocol",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        },
+        "source-identity": {
+          "description": "Security identity of the source",
+          "type": "integer"
+        },
+        "source-service": {
+          "description": "Service backed by the source",
+          "type": "string"
+        }
+      }
+    },
+    "FlowGraphNode": {
+      "description": "Security identity observed in the flow graph",
+      "type": "object",
+      "properties": {
+        "identity": {
+          "description": "Numeric security identity",
+          "type": "integer"
+        },
+        "labels": {
+          "description": "Labels of the security identity",
+          "$ref": "#/definitions/Labels"
+        },
+        "services": {
+          "description": "Services backed by endpoints of the identity",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        }
+      }
+    },
+    "FlowHTTP": {
+      "description": "HTTP specific portion of a flow",
+      "type": "object",
+      "properties": {
+        "code": {
+          "description": "Status code of the response",
+          "type": "integer"
+        },
+        "headers": {
+          "description": "HTTP headers of the request",
+          "type": "object",
+          "additionalProperties": {
+            "type": "string"
+          }
+        },
+        "method": {
+          "description": "Method of the request",
+          "type": "string"
+        },
+        "protocol": {
+          "description": "HTTP protocol version",
+          "type": "string"
+        },
+        "url": {
+          "description": "URL of the request",
+          "type": "string"
+        }
+      }
+    },
+    "FlowKafka": {
+      "description": "Kafka specific portion of a flow",
+      "type": "object",
+      "properties": {
+        "api-key": {
+          "description": "Kafka API key of the message",
+          "type": "string"
+        },
+        "api-version": {
+          "description": "Version of the Kafka API",
+          "type": "integer"
+        },
+        "correlation-id": {
+          "description": "Correlation ID of the request and response",
+          "type": "integer"
+        },
+        "error-code": {
+          "description": "Kafka error code of the response",
+          "type": "integer"
+        },
+        "topic": {
+          "description": "Topic of the request",
+          "type": "string"
+        }
+      }
+    },
+    "FlowNodeAddress": {
+      "description": "Addresses of a node",
+      "type": "object",
+      "properties": {
+        "ipv4": {
+          "description": "IPv4 address of the node",
+          "type": "string"
+        },
+        "ipv6": {
+          "description": "IPv6 address of the node",
+          "type": "string"
+        }
+      }
+    },
+    "FlowService": {
+      "description": "Service a flow went through",
+      "type": "object",
+      "properties": {
+        "ip": {
+          "description": "IP address of the service",
+          "type": "string"
+        },
+        "name": {
+          "description": "Name of the service",
+          "type": "string"
+        },
+        "port": {
+          "description": "Port of the service",
+          "type": "integer"
+        }
+      }
+    },
+    "FrontendAddress": {
+      "description": "Layer 4 address",
+      "type": "object",
+      "properties": {
+        "ip": {
+          "description": "Layer 3 address",
+          "type": "string"
+        },
+        "port": {
+          "description": "Layer 4 port number",
           "type": "integer",
           "format": "uint16"
         },
@@ -1563,6 +2229,87 @@ func init() {
         },
         "host-addressing": {
           "$ref": "#/definitions/NodeAddressing"
+        },
+        "pool": {
+          "description": "Name of the pool the addresses have been allocated from. Addresses\nof a family not provided by the pool are allocated from the default\npool.\n",
+          "type": "string"
+        }
+      }
+    },
+    "IPAMAllocation": {
+      "description": "Allocated IP address",
+      "type": "object",
+      "properties": {
+        "allocation-time": {
+          "description": "Time the address has been allocated",
+          "type": "string",
+          "format": "date-time"
+        },
+        "endpoint-id": {
+          "description": "ID of the local endpoint using the address",
+          "type": "integer"
+        },
+        "endpoint-state": {
+          "description": "State of the local endpoint using the address",
+          "$ref": "#/definitions/EndpointState"
+        },
+        "ip": {
+          "description": "Allocated IP address",
+          "type": "string"
+        },
+        "orphan": {
+          "description": "Reason why the address is considered orphaned, empty if the\naddress is in use\n",
+          "type": "string"
+        },
+        "owner": {
+          "description": "Owner of the address in the form container:\u003ccontainer ID\u003e,\nendpoint:\u003cendpoint ID\u003e, health or loopback. Empty if the owner is\nunknown.\n",
+          "type": "string"
+        },
+        "pool": {
+          "description": "Name of the pool the address has been allocated from",
+          "type": "string"
+        }
+      }
+    },
+    "IPAMPool": {
+      "description": "Pool of IP addresses",
+      "type": "object",
+      "properties": {
+        "ipv4": {
+          "$ref": "#/definitions/IPAMPoolRange"
+        },
+        "ipv6": {
+          "$ref": "#/definitions/IPAMPoolRange"
+        },
+        "name": {
+          "description": "Name of the pool",
+          "type": "string"
+        }
+      }
+    },
+    "IPAMPoolRange": {
+      "description": "Address range of a pool and its usage",
+      "type": "object",
+      "properties": {
+        "capacity": {
+          "description": "Number of addresses which can be allocated",
+          "type": "integer"
+        },
+        "cidr": {
+          "description": "Range addresses are allocated from",
+          "type": "string"
+        },
+        "fragmentation": {
+          "description": "Fraction of the free addresses which are not part of the largest\nrange of consecutive free addresses. 0 if all free addresses are\nconsecutive.\n",
+          "type": "number"
+        },
+        "free": {
+          "description": "Number of addresses which can still be allocated",
+          "type": "integer"
+        },
+        "used": {
+          "description": "Number of allocated addresses",
+          "type": "integer"
         }
       }
     },
@@ -1624,6 +2371,110 @@ func init() {
         }
       }
     },
+    "IdentityExplanation": {
+      "description": "Explanation of how the identity of an endpoint was derived",
+      "type": "object",
+      "properties": {
+        "endpoint-id": {
+          "description": "ID of the endpoint",
+          "type": "integer"
+        },
+        "identity": {
+          "description": "Security identity of the endpoint",
+          "$ref": "#/definitions/Identity"
+        },
+        "labels": {
+          "description": "All labels of the endpoint",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/LabelExplanation"
+          }
+        },
+        "shared-with": {
+          "description": "IDs of other local endpoints with the same identity",
+          "type": "array",
+          "items": {
+            "type": "integer"
+          }
+        }
+      }
+    },
+    "IdentityGCEntry": {
+      "description": "Identity as seen by the garbage collector",
+      "type": "object",
+      "properties": {
+        "id": {
+          "description": "Unique identifier",
+          "type": "integer"
+        },
+        "labels": {
+          "description": "Labels describing the identity",
+          "$ref": "#/definitions/Labels"
+        },
+        "last-use": {
+          "description": "Last time a reference to the identity has been observed by this\nagent\n",
+          "type": "string",
+          "format": "date-time"
+        },
+        "nodes": {
+          "description": "Nodes referencing the identity",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        }
+      }
+    },
+    "IdentityGCReport": {
+      "description": "Identities which are candidates for garbage collection",
+      "type": "object",
+      "properties": {
+        "stale-node-detection": {
+          "description": "Whether stale nodes can be detected. If false, stale-referenced is\nalways empty.\n",
+          "type": "boolean"
+        },
+        "stale-referenced": {
+          "description": "Identities which are only referenced by stale nodes",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/IdentityGCEntry"
+          }
+        },
+        "unreferenced": {
+          "description": "Identities without any reference which will be released by the\nnext garbage collector run\n",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/IdentityGCEntry"
+          }
+        }
+      }
+    },
+    "IdentityMigration": {
+      "description": "Change of the identity of an endpoint",
+      "type": "object",
+      "properties": {
+        "endpoint-id": {
+          "description": "ID of the endpoint",
+          "type": "integer"
+        },
+        "error": {
+          "description": "Error if the endpoint could not be migrated",
+          "type": "string"
+        },
+        "labels": {
+          "description": "New identity labels of the endpoint",
+          "$ref": "#/definitions/Labels"
+        },
+        "new-identity": {
+          "description": "Identity of the endpoint after the change",
+          "type": "integer"
+        },
+        "old-identity": {
+          "description": "Identity of the endpoint before the change",
+          "type": "integer"
+        }
+      }
+    },
     "K8sStatus": {
       "description": "Status of Kubernetes integration",
       "type": "object",
@@ -1666,6 +2517,55 @@ func init() {
         }
       }
     },
+    "KvstoreResyncStatus": {
+      "description": "Status of re-creating keys attached to the kvstore lease after the lease\nhas expired and has been replaced\n",
+      "type": "object",
+      "properties": {
+        "last-renewal": {
+          "description": "Time the lease has last been replaced",
+          "type": "string",
+          "format": "date-time"
+        },
+        "renewals": {
+          "description": "Number of times the lease has been replaced",
+          "type": "integer"
+        },
+        "subsystems": {
+          "description": "Status of all subsystems owning keys attached to the lease",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/KvstoreResyncSubsystem"
+          }
+        }
+      }
+    },
+    "KvstoreResyncSubsystem": {
+      "description": "Status of a subsystem re-creating its keys",
+      "type": "object",
+      "properties": {
+        "keys": {
+          "description": "Number of keys re-created in the last attempt",
+          "type": "integer"
+        },
+        "msg": {
+          "description": "Error of the last failed attempt",
+          "type": "string"
+        },
+        "name": {
+          "description": "Name of the subsystem",
+          "type": "string"
+        },
+        "state": {
+          "description": "State of re-creating the keys",
+          "type": "string",
+          "enum": [
+            "Pending",
+            "Ok",
+            "Failure"
+          ]
+        }
+      }
+    },
     "L4Policy": {
       "description": "L4 endpoint policy",
       "type": "object",
@@ -1722,6 +2622,53 @@ func init() {
         }
       }
     },
+    "LabelExplanation": {
+      "description": "Decision whether a label is used to determine the identity",
+      "type": "object",
+      "properties": {
+        "kept": {
+          "description": "True if the label is used to determine the identity",
+          "type": "boolean"
+        },
+        "label": {
+          "description": "Label in the form source:key[=value]",
+          "type": "string"
+        },
+        "prefix": {
+          "description": "Label prefix which decided about the label",
+          "type": "string"
+        },
+        "reason": {
+          "description": "Human readable reason of the decision",
+          "type": "string"
+        },
+        "source": {
+          "description": "Source of the label, e.g. k8s, container, mesos, reserved",
+          "type": "string"
+        }
+      }
+    },
+    "LabelPrefixConfiguration": {
+      "description": "Label prefixes used to determine the identity relevant labels",
+      "type": "object",
+      "properties": {
+        "effective": {
+          "description": "All label prefixes in effect including the label prefixes of the\nlabel prefix file or the default label prefixes. Ignored when\nreplacing the configuration.\n",
+          "type": "array",
+          "items": {
+            "type": "string"
+          },
+          "readOnly": true
+        },
+        "prefixes": {
+          "description": "Label prefixes in the form [source:][!]prefix appended to the\nlabel prefixes of the label prefix file or the default label\nprefixes. A prefix starting with ! excludes matching labels.\n",
+          "type": "array",
+          "items": {
+            "type": "string"
+          }
+        }
+      }
+    },
     "Labels": {
       "description": "Set of labels",
       "type": "array",
@@ -1751,6 +2698,36 @@ func init() {
         }
       }
     },
+    "MonitorListenerStatus": {
+      "description": "Status of a listener connected to the node monitor",
+      "properties": {
+        "connected-since": {
+          "description": "Time at which the listener connected",
+          "type": "string",
+          "format": "date-time"
+        },
+        "dropped": {
+          "description": "Number of notifications dropped for this listener",
+          "type": "integer"
+        },
+        "id": {
+          "description": "Unique identifier of the listener",
+          "type": "integer"
+        },
+        "pid": {
+          "description": "Process ID of the listener, if known",
+          "type": "integer"
+        },
+        "queue-depth": {
+          "description": "Number of notifications waiting to be sent to the listener",
+          "type": "integer"
+        },
+        "queue-size": {
+          "description": "Maximum number of notifications queued for the listener",
+          "type": "integer"
+        }
+      }
+    },
     "MonitorStatus": {
       "description": "Status of the node monitor",
       "properties": {
@@ -1758,6 +2735,17 @@ func init() {
           "description": "Number of CPUs to listen on for events.",
           "type": "integer"
         },
+        "disconnected": {
+          "description": "Number of listeners disconnected for being too slow.",
+          "type": "integer"
+        },
+        "listeners": {
+          "description": "Status of all connected monitor listeners",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/MonitorListenerStatus"
+          }
+        },
         "lost": {
           "description": "Number of samples lost by perf.",
           "type": "integer"
@@ -1770,6 +2758,15 @@ func init() {
           "description": "Pages size used for the perf ring buffer.",
           "type": "integer"
         },
+        "slow-consumer-policy": {
+          "description": "Policy applied to listeners which cannot keep up with the event rate.",
+          "type": "string",
+          "enum": [
+            "drop-newest",
+            "drop-oldest",
+            "disconnect"
+          ]
+        },
         "unknown": {
           "description": "Number of unknown samples.",
           "type": "integer"
@@ -1986,9 +2983,106 @@ func init() {
           "description": "Frontend address",
           "$ref": "#/definitions/FrontendAddress"
         },
+        "frontend-type": {
+          "description": "Type of the frontend. Kubernetes services have a ClusterIP\nfrontend and additional NodePort frontends on each address of the\nnode and ExternalIP frontends. Services added through the API are\nClusterIP services unless specified otherwise.\n",
+          "type": "string",
+          "enum": [
+            "ClusterIP",
+            "NodePort",
+            "ExternalIP"
+          ]
+        },
+        "health-check": {
+          "description": "Health check of the backends. Backends failing the health check\nare removed from the service until they pass it again.\n",
+          "$ref": "#/definitions/ServiceHealthCheck"
+        },
         "id": {
           "description": "Unique identification",
           "type": "integer"
+        },
+        "lb-algorithm": {
+          "description": "Algorithm used to select the backend of a connection. The maglev\nalgorithm uses a consistent hashing lookup table which only remaps\na minimal share of the connections when backends are added or\nremoved.\n",
+          "type": "string",
+          "enum": [
+            "random",
+            "maglev"
+          ]
+        },
+        "session-affinity": {
+          "description": "Session affinity of the service. With ClientIP, all connections\nof a client are sent to the same backend until the client has\nbeen idle for the session affinity timeout.\n",
+          "type": "string",
+          "enum": [
+            "None",
+            "ClientIP"
+          ]
+        },
+        "session-affinity-timeout": {
+          "description": "Session affinity timeout in seconds",
+          "type": "integer"
+        },
+        "statistics": {
+          "description": "Traffic sent to all backends of the frontend by this node. Only set\nwhen a single service is retrieved. Ignored when the service is\nupdated.\n",
+          "$ref": "#/definitions/ServiceStatistics"
+        }
+      }
+    },
+    "ServiceHealthCheck": {
+      "description": "Health check of the backends of a service",
+      "type": "object",
+      "required": [
+        "type"
+      ],
+      "properties": {
+        "healthy-threshold": {
+          "description": "Number of consecutive successful checks after which an unhealthy\nbackend is considered healthy again\n",
+          "type": "integer"
+        },
+        "interval": {
+          "description": "Interval between checks in seconds",
+          "type": "integer"
+        },
+        "path": {
+          "description": "Path of the http check",
+          "type": "string"
+        },
+        "port": {
+          "description": "Port to check, the port of the backend if 0",
+          "type": "integer",
+          "format": "uint16"
+        },
+        "timeout": {
+          "description": "Timeout of a check in seconds",
+          "type": "integer"
+        },
+        "type": {
+          "description": "Type of the health check. A tcp check succeeds if a connection to\nthe backend can be established, an http check if the backend\nresponds to a GET request with a 2xx or 3xx status code.\n",
+          "type": "string",
+          "enum": [
+            "tcp",
+            "http"
+          ]
+        },
+        "unhealthy-threshold": {
+          "description": "Number of consecutive failed checks after which a backend is\nconsidered unhealthy\n",
+          "type": "integer"
+        }
+      }
+    },
+    "ServiceStatistics": {
+      "description": "Traffic statistics of a service frontend or backend since the backend\nhas been added to the service\n",
+      "type": "object",
+      "properties": {
+        "bytes": {
+          "description": "Number of bytes",
+          "type": "integer"
+        },
+        "connections": {
+          "description": "Number of new TCP connections. Connections of other protocols are\nnot counted.\n",
+          "type": "integer"
+        },
+        "packets": {
+          "description": "Number of packets",
+          "type": "integer"
         }
       }
     },
@@ -2044,6 +3138,10 @@ func init() {
           "description": "Status of key/value datastore",
           "$ref": "#/definitions/Status"
         },
+        "kvstore-resync": {
+          "description": "Status of re-creating keys after the kvstore lease expired",
+          "$ref": "#/definitions/KvstoreResyncStatus"
+        },
         "nodeMonitor": {
           "description": "Status of the node monitor",
           "$ref": "#/definitions/MonitorStatus"
@@ -2111,6 +3209,24 @@ func init() {
       "in": "path",
       "required": true
     },
+    "ipam-k8s-pod": {
+      "type": "string",
+      "description": "Kubernetes pod in the form namespace/name used to select the pool if\nno pool is specified\n",
+      "name": "k8s-pod",
+      "in": "query"
+    },
+    "ipam-owner": {
+      "type": "string",
+      "description": "Owner of the allocation in the form container:\u003ccontainer ID\u003e,\nendpoint:\u003cendpoint ID\u003e or health\n",
+      "name": "owner",
+      "in": "query"
+    },
+    "ipam-pool": {
+      "type": "string",
+      "description": "Name of the pool to allocate from",
+      "name": "pool",
+      "in": "query"
+    },
     "labels": {
       "description": "List of labels\n",
       "name": "labels",
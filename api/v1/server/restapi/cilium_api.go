@@ -21,6 +21,7 @@ import (
 
 	"github.com/cilium/cilium/api/v1/server/restapi/daemon"
 	"github.com/cilium/cilium/api/v1/server/restapi/endpoint"
+	"github.com/cilium/cilium/api/v1/server/restapi/flows"
 	"github.com/cilium/cilium/api/v1/server/restapi/ipam"
 	"github.com/cilium/cilium/api/v1/server/restapi/policy"
 	"github.com/cilium/cilium/api/v1/server/restapi/prefilter"
@@ -60,6 +61,9 @@ func NewCiliumAPI(spec *loads.Document) *CiliumAPI {
 		DaemonGetConfigHandler: daemon.GetConfigHandlerFunc(func(params daemon.GetConfigParams) middleware.Responder {
 			return middleware.NotImplemented("operation DaemonGetConfig has not yet been implemented")
 		}),
+		DaemonGetConfigLabelPrefixesHandler: daemon.GetConfigLabelPrefixesHandlerFunc(func(params daemon.GetConfigLabelPrefixesParams) middleware.Responder {
+			return middleware.NotImplemented("operation DaemonGetConfigLabelPrefixes has not yet been implemented")
+		}),
 		DaemonGetDebuginfoHandler: daemon.GetDebuginfoHandlerFunc(func(params daemon.GetDebuginfoParams) middleware.Responder {
 			return middleware.NotImplemented("operation DaemonGetDebuginfo has not yet been implemented")
 		}),
@@ -75,18 +79,39 @@ func NewCiliumAPI(spec *loads.Document) *CiliumAPI {
 		EndpointGetEndpointIDHealthzHandler: endpoint.GetEndpointIDHealthzHandlerFunc(func(params endpoint.GetEndpointIDHealthzParams) middleware.Responder {
 			return middleware.NotImplemented("operation EndpointGetEndpointIDHealthz has not yet been implemented")
 		}),
+		EndpointGetEndpointIDIdentityExplainHandler: endpoint.GetEndpointIDIdentityExplainHandlerFunc(func(params endpoint.GetEndpointIDIdentityExplainParams) middleware.Responder {
+			return middleware.NotImplemented("operation EndpointGetEndpointIDIdentityExplain has not yet been implemented")
+		}),
 		EndpointGetEndpointIDLabelsHandler: endpoint.GetEndpointIDLabelsHandlerFunc(func(params endpoint.GetEndpointIDLabelsParams) middleware.Responder {
 			return middleware.NotImplemented("operation EndpointGetEndpointIDLabels has not yet been implemented")
 		}),
 		EndpointGetEndpointIDLogHandler: endpoint.GetEndpointIDLogHandlerFunc(func(params endpoint.GetEndpointIDLogParams) middleware.Responder {
 			return middleware.NotImplemented("operation EndpointGetEndpointIDLog has not yet been implemented")
 		}),
+		FlowsGetFlowsHandler: flows.GetFlowsHandlerFunc(func(params flows.GetFlowsParams) middleware.Responder {
+			return middleware.NotImplemented("operation FlowsGetFlows has not yet been implemented")
+		}),
+		FlowsGetFlowsGraphHandler: flows.GetFlowsGraphHandlerFunc(func(params flows.GetFlowsGraphParams) middleware.Responder {
+			return middleware.NotImplemented("operation FlowsGetFlowsGraph has not yet been implemented")
+		}),
 		DaemonGetHealthzHandler: daemon.GetHealthzHandlerFunc(func(params daemon.GetHealthzParams) middleware.Responder {
 			return middleware.NotImplemented("operation DaemonGetHealthz has not yet been implemented")
 		}),
+		IPAMGetIPAMHandler: ipam.GetIPAMHandlerFunc(func(params ipam.GetIPAMParams) middleware.Responder {
+			return middleware.NotImplemented("operation IPAMGetIPAM has not yet been implemented")
+		}),
+		IPAMGetIPAMIPHandler: ipam.GetIPAMIPHandlerFunc(func(params ipam.GetIPAMIPParams) middleware.Responder {
+			return middleware.NotImplemented("operation IPAMGetIPAMIP has not yet been implemented")
+		}),
+		IPAMGetIPAMPoolsHandler: ipam.GetIPAMPoolsHandlerFunc(func(params ipam.GetIPAMPoolsParams) middleware.Responder {
+			return middleware.NotImplemented("operation IPAMGetIPAMPools has not yet been implemented")
+		}),
 		PolicyGetIdentityHandler: policy.GetIdentityHandlerFunc(func(params policy.GetIdentityParams) middleware.Responder {
 			return middleware.NotImplemented("operation PolicyGetIdentity has not yet been implemented")
 		}),
+		PolicyGetIdentityGcHandler: policy.GetIdentityGcHandlerFunc(func(params policy.GetIdentityGcParams) middleware.Responder {
+			return middleware.NotImplemented("operation PolicyGetIdentityGc has not yet been implemented")
+		}),
 		PolicyGetIdentityIDHandler: policy.GetIdentityIDHandlerFunc(func(params policy.GetIdentityIDParams) middleware.Responder {
 			return middleware.NotImplemented("operation PolicyGetIdentityID has not yet been implemented")
 		}),
@@ -120,6 +145,12 @@ func NewCiliumAPI(spec *loads.Document) *CiliumAPI {
 		IPAMPostIPAMIPHandler: ipam.PostIPAMIPHandlerFunc(func(params ipam.PostIPAMIPParams) middleware.Responder {
 			return middleware.NotImplemented("operation IPAMPostIPAMIP has not yet been implemented")
 		}),
+		PolicyPostIdentityGcHandler: policy.PostIdentityGcHandlerFunc(func(params policy.PostIdentityGcParams) middleware.Responder {
+			return middleware.NotImplemented("operation PolicyPostIdentityGc has not yet been implemented")
+		}),
+		DaemonPutConfigLabelPrefixesHandler: daemon.PutConfigLabelPrefixesHandlerFunc(func(params daemon.PutConfigLabelPrefixesParams) middleware.Responder {
+			return middleware.NotImplemented("operation DaemonPutConfigLabelPrefixes has not yet been implemented")
+		}),
 		EndpointPutEndpointIDHandler: endpoint.PutEndpointIDHandlerFunc(func(params endpoint.PutEndpointIDParams) middleware.Responder {
 			return middleware.NotImplemented("operation EndpointPutEndpointID has not yet been implemented")
 		}),
@@ -176,6 +207,8 @@ type CiliumAPI struct {
 	ServiceDeleteServiceIDHandler service.DeleteServiceIDHandler
 	// DaemonGetConfigHandler sets the operation handler for the get config operation
 	DaemonGetConfigHandler daemon.GetConfigHandler
+	// DaemonGetConfigLabelPrefixesHandler sets the operation handler for the get config label prefixes operation
+	DaemonGetConfigLabelPrefixesHandler daemon.GetConfigLabelPrefixesHandler
 	// DaemonGetDebuginfoHandler sets the operation handler for the get debuginfo operation
 	DaemonGetDebuginfoHandler daemon.GetDebuginfoHandler
 	// EndpointGetEndpointHandler sets the operation handler for the get endpoint operation
@@ -186,14 +219,28 @@ type CiliumAPI struct {
 	EndpointGetEndpointIDConfigHandler endpoint.GetEndpointIDConfigHandler
 	// EndpointGetEndpointIDHealthzHandler sets the operation handler for the get endpoint ID healthz operation
 	EndpointGetEndpointIDHealthzHandler endpoint.GetEndpointIDHealthzHandler
+	// EndpointGetEndpointIDIdentityExplainHandler sets the operation handler for the get endpoint ID identity explain operation
+	EndpointGetEndpointIDIdentityExplainHandler endpoint.GetEndpointIDIdentityExplainHandler
 	// EndpointGetEndpointIDLabelsHandler sets the operation handler for the get endpoint ID labels operation
 	EndpointGetEndpointIDLabelsHandler endpoint.GetEndpointIDLabelsHandler
 	// EndpointGetEndpointIDLogHandler sets the operation handler for the get endpoint ID log operation
 	EndpointGetEndpointIDLogHandler endpoint.GetEndpointIDLogHandler
+	// FlowsGetFlowsHandler sets the operation handler for the get flows operation
+	FlowsGetFlowsHandler flows.GetFlowsHandler
+	// FlowsGetFlowsGraphHandler sets the operation handler for the get flows graph operation
+	FlowsGetFlowsGraphHandler flows.GetFlowsGraphHandler
 	// DaemonGetHealthzHandler sets the operation handler for the get healthz operation
 	DaemonGetHealthzHandler daemon.GetHealthzHandler
+	// IPAMGetIPAMHandler sets the operation handler for the get IP a m operation
+	IPAMGetIPAMHandler ipam.GetIPAMHandler
+	// IPAMGetIPAMIPHandler sets the operation handler for the get IP a m IP operation
+	IPAMGetIPAMIPHandler ipam.GetIPAMIPHandler
+	// IPAMGetIPAMPoolsHandler sets the operation handler for the get IP a m pools operation
+	IPAMGetIPAMPoolsHandler ipam.GetIPAMPoolsHandler
 	// PolicyGetIdentityHandler sets the operation handler for the get identity operation
 	PolicyGetIdentityHandler policy.GetIdentityHandler
+	// PolicyGetIdentityGcHandler sets the operation handler for the get identity gc operation
+	PolicyGetIdentityGcHandler policy.GetIdentityGcHandler
 	// PolicyGetIdentityIDHandler sets the operation handler for the get identity ID operation
 	PolicyGetIdentityIDHandler policy.GetIdentityIDHandler
 	// PolicyGetPolicyHandler sets the operation handler for the get policy operation
@@ -216,6 +263,10 @@ type CiliumAPI struct {
 	IPAMPostIPAMHandler ipam.PostIPAMHandler
 	// IPAMPostIPAMIPHandler sets the operation handler for the post IP a m IP operation
 	IPAMPostIPAMIPHandler ipam.PostIPAMIPHandler
+	// PolicyPostIdentityGcHandler sets the operation handler for the post identity gc operation
+	PolicyPostIdentityGcHandler policy.PostIdentityGcHandler
+	// DaemonPutConfigLabelPrefixesHandler sets the operation handler for the put config label prefixes operation
+	DaemonPutConfigLabelPrefixesHandler daemon.PutConfigLabelPrefixesHandler
 	// EndpointPutEndpointIDHandler sets the operation handler for the put endpoint ID operation
 	EndpointPutEndpointIDHandler endpoint.PutEndpointIDHandler
 	// EndpointPutEndpointIDLabelsHandler sets the operation handler for the put endpoint ID labels operation
@@ -313,6 +364,10 @@ func (o *CiliumAPI) Validate() error {
 		unregistered = append(unregistered, "daemon.GetConfigHandler")
 	}
 
+	if o.DaemonGetConfigLabelPrefixesHandler == nil {
+		unregistered = append(unregistered, "daemon.GetConfigLabelPrefixesHandler")
+	}
+
 	if o.DaemonGetDebuginfoHandler == nil {
 		unregistered = append(unregistered, "daemon.GetDebuginfoHandler")
 	}
@@ -333,6 +388,10 @@ func (o *CiliumAPI) Validate() error {
 		unregistered = append(unregistered, "endpoint.GetEndpointIDHealthzHandler")
 	}
 
+	if o.EndpointGetEndpointIDIdentityExplainHandler == nil {
+		unregistered = append(unregistered, "endpoint.GetEndpointIDIdentityExplainHandler")
+	}
+
 	if o.EndpointGetEndpointIDLabelsHandler == nil {
 		unregistered = append(unregistered, "endpoint.GetEndpointIDLabelsHandler")
 	}
@@ -341,14 +400,38 @@ func (o *CiliumAPI) Validate() error {
 		unregistered = append(unregistered, "endpoint.GetEndpointIDLogHandler")
 	}
 
+	if o.FlowsGetFlowsHandler == nil {
+		unregistered = append(unregistered, "flows.GetFlowsHandler")
+	}
+
+	if o.FlowsGetFlowsGraphHandler == nil {
+		unregistered = append(unregistered, "flows.GetFlowsGraphHandler")
+	}
+
 	if o.DaemonGetHealthzHandler == nil {
 		unregistered = append(unregistered, "daemon.GetHealthzHandler")
 	}
 
+	if o.IPAMGetIPAMHandler == nil {
+		unregistered = append(unregistered, "ipam.GetIPAMHandler")
+	}
+
+	if o.IPAMGetIPAMIPHandler == nil {
+		unregistered = append(unregistered, "ipam.GetIPAMIPHandler")
+	}
+
+	if o.IPAMGetIPAMPoolsHandler == nil {
+		unregistered = append(unregistered, "ipam.GetIPAMPoolsHandler")
+	}
+
 	if o.PolicyGetIdentityHandler == nil {
 		unregistered = append(unregistered, "policy.GetIdentityHandler")
 	}
 
+	if o.PolicyGetIdentityGcHandler == nil {
+		unregistered = append(unregistered, "policy.GetIdentityGcHandler")
+	}
+
 	if o.PolicyGetIdentityIDHandler == nil {
 		unregistered = append(unregistered, "policy.GetIdentityIDHandler")
 	}
@@ -393,6 +476,14 @@ func (o *CiliumAPI) Validate() error {
 		unregistered = append(unregistered, "ipam.PostIPAMIPHandler")
 	}
 
+	if o.PolicyPostIdentityGcHandler == nil {
+		unregistered = append(unregistered, "policy.PostIdentityGcHandler")
+	}
+
+	if o.DaemonPutConfigLabelPrefixesHandler == nil {
+		unregistered = append(unregistered, "daemon.PutConfigLabelPrefixesHandler")
+	}
+
 	if o.EndpointPutEndpointIDHandler == nil {
 		unregistered = append(unregistered, "endpoint.PutEndpointIDHandler")
 	}
@@ -533,6 +624,11 @@ func (o *CiliumAPI) initHandlerCache() {
 	}
 	o.handlers["GET"]["/config"] = daemon.NewGetConfig(o.context, o.DaemonGetConfigHandler)
 
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/config/label-prefixes"] = daemon.NewGetConfigLabelPrefixes(o.context, o.DaemonGetConfigLabelPrefixesHandler)
+
 	if o.handlers["GET"] == nil {
 		o.handlers["GET"] = make(map[string]http.Handler)
 	}
@@ -558,6 +654,11 @@ func (o *CiliumAPI) initHandlerCache() {
 	}
 	o.handlers["GET"]["/endpoint/{id}/healthz"] = endpoint.NewGetEndpointIDHealthz(o.context, o.EndpointGetEndpointIDHealthzHandler)
 
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/endpoint/{id}/identity/explain"] = endpoint.NewGetEndpointIDIdentityExplain(o.context, o.EndpointGetEndpointIDIdentityExplainHandler)
+
 	if o.handlers["GET"] == nil {
 		o.handlers["GET"] = make(map[string]http.Handler)
 	}
@@ -568,16 +669,46 @@ func (o *CiliumAPI) initHandlerCache() {
 	}
 	o.handlers["GET"]["/endpoint/{id}/log"] = endpoint.NewGetEndpointIDLog(o.context, o.EndpointGetEndpointIDLogHandler)
 
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/flows"] = flows.NewGetFlows(o.context, o.FlowsGetFlowsHandler)
+
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/flows/graph"] = flows.NewGetFlowsGraph(o.context, o.FlowsGetFlowsGraphHandler)
+
 	if o.handlers["GET"] == nil {
 		o.handlers["GET"] = make(map[string]http.Handler)
 	}
 	o.handlers["GET"]["/healthz"] = daemon.NewGetHealthz(o.context, o.DaemonGetHealthzHandler)
 
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/ipam"] = ipam.NewGetIPAM(o.context, o.IPAMGetIPAMHandler)
+
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/ipam/{ip}"] = ipam.NewGetIPAMIP(o.context, o.IPAMGetIPAMIPHandler)
+
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/ipam/pools"] = ipam.NewGetIPAMPools(o.context, o.IPAMGetIPAMPoolsHandler)
+
 	if o.handlers["GET"] == nil {
 		o.handlers["GET"] = make(map[string]http.Handler)
 	}
 	o.handlers["GET"]["/identity"] = policy.NewGetIdentity(o.context, o.PolicyGetIdentityHandler)
 
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/identity/gc"] = policy.NewGetIdentityGc(o.context, o.PolicyGetIdentityGcHandler)
+
 	if o.handlers["GET"] == nil {
 		o.handlers["GET"] = make(map[string]http.Handler)
 	}
@@ -633,6 +764,16 @@ func (o *CiliumAPI) initHandlerCache() {
 	}
 	o.handlers["POST"]["/ipam/{ip}"] = ipam.NewPostIPAMIP(o.context, o.IPAMPostIPAMIPHandler)
 
+	if o.handlers["POST"] == nil {
+		o.handlers["POST"] = make(map[string]http.Handler)
+	}
+	o.handlers["POST"]["/identity/gc"] = policy.NewPostIdentityGc(o.context, o.PolicyPostIdentityGcHandler)
+
+	if o.handlers["PUT"] == nil {
+		o.handlers["PUT"] = make(map[string]http.Handler)
+	}
+	o.handlers["PUT"]["/config/label-prefixes"] = daemon.NewPutConfigLabelPrefixes(o.context, o.DaemonPutConfigLabelPrefixesHandler)
+
 	if o.handlers["PUT"] == nil {
 		o.handlers["PUT"] = make(map[string]http.Handler)
 	}
@@ -33,6 +33,12 @@ func NewPostIPAM(ctx *middleware.Context, handler PostIPAMHandler) *PostIPAM {
 
 Allocate an IP address
 
+Allocates an IPv4 and/or IPv6 address. The addresses are allocated
+from the pool specified by name. If no pool is specified and a
+Kubernetes pod is given, the pool is selected via the pool annotation
+of the pod or of its namespace. The default pool is used otherwise.
+
+
 */
 type PostIPAM struct {
 	Context *middleware.Context
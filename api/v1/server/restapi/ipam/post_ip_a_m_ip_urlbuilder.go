@@ -14,7 +14,8 @@ import (
 
 // PostIPAMIPURL generates an URL for the post IP a m IP operation
 type PostIPAMIPURL struct {
-	IP string
+	IP    string
+	Owner *string
 
 	_basePath string
 	// avoid unkeyed usage
@@ -54,6 +55,18 @@ func (o *PostIPAMIPURL) Build() (*url.URL, error) {
 	}
 	result.Path = golangswaggerpaths.Join(_basePath, _path)
 
+	qs := make(url.Values)
+
+	var owner string
+	if o.Owner != nil {
+		owner = *o.Owner
+	}
+	if owner != "" {
+		qs.Set("owner", owner)
+	}
+
+	result.RawQuery = qs.Encode()
+
 	return &result, nil
 }
 
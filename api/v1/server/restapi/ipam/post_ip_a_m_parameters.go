@@ -36,6 +36,22 @@ type PostIPAMParams struct {
 	  In: query
 	*/
 	Family *string
+	/*Kubernetes pod in the form namespace/name used to select the pool if
+	no pool is specified
+
+	  In: query
+	*/
+	K8sPod *string
+	/*Owner of the allocation in the form container:<container ID>,
+	endpoint:<endpoint ID> or health
+
+	  In: query
+	*/
+	Owner *string
+	/*Name of the pool to allocate from
+	  In: query
+	*/
+	Pool *string
 }
 
 // BindRequest both binds and validates a request, it assumes that complex things implement a Validatable(strfmt.Registry) error interface
@@ -51,6 +67,21 @@ func (o *PostIPAMParams) BindRequest(r *http.Request, route *middleware.MatchedR
 		res = append(res, err)
 	}
 
+	qK8sPod, qhkK8sPod, _ := qs.GetOK("k8s-pod")
+	if err := o.bindK8sPod(qK8sPod, qhkK8sPod, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qOwner, qhkOwner, _ := qs.GetOK("owner")
+	if err := o.bindOwner(qOwner, qhkOwner, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
+	qPool, qhkPool, _ := qs.GetOK("pool")
+	if err := o.bindPool(qPool, qhkPool, route.Formats); err != nil {
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
@@ -83,3 +114,45 @@ func (o *PostIPAMParams) validateFamily(formats strfmt.Registry) error {
 
 	return nil
 }
+
+func (o *PostIPAMParams) bindK8sPod(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	o.K8sPod = &raw
+
+	return nil
+}
+
+func (o *PostIPAMParams) bindOwner(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	o.Owner = &raw
+
+	return nil
+}
+
+func (o *PostIPAMParams) bindPool(rawData []string, hasKey bool, formats strfmt.Registry) error {
+	var raw string
+	if len(rawData) > 0 {
+		raw = rawData[len(rawData)-1]
+	}
+	if raw == "" { // empty values pass all other validations
+		return nil
+	}
+
+	o.Pool = &raw
+
+	return nil
+}
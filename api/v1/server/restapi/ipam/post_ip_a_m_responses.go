@@ -56,6 +56,48 @@ func (o *PostIPAMCreated) WriteResponse(rw http.ResponseWriter, producer runtime
 	}
 }
 
+// PostIPAMPoolNotFoundCode is the HTTP code returned for type PostIPAMPoolNotFound
+const PostIPAMPoolNotFoundCode int = 404
+
+/*PostIPAMPoolNotFound Pool not found
+
+swagger:response postIpAMPoolNotFound
+*/
+type PostIPAMPoolNotFound struct {
+
+	/*
+	  In: Body
+	*/
+	Payload models.Error `json:"body,omitempty"`
+}
+
+// NewPostIPAMPoolNotFound creates PostIPAMPoolNotFound with default headers values
+func NewPostIPAMPoolNotFound() *PostIPAMPoolNotFound {
+	return &PostIPAMPoolNotFound{}
+}
+
+// WithPayload adds the payload to the post Ip a m pool not found response
+func (o *PostIPAMPoolNotFound) WithPayload(payload models.Error) *PostIPAMPoolNotFound {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the post Ip a m pool not found response
+func (o *PostIPAMPoolNotFound) SetPayload(payload models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *PostIPAMPoolNotFound) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(404)
+	payload := o.Payload
+	if err := producer.Produce(rw, payload); err != nil {
+		panic(err) // let the recovery middleware deal with this
+	}
+
+}
+
 // PostIPAMFailureCode is the HTTP code returned for type PostIPAMFailure
 const PostIPAMFailureCode int = 502
 
@@ -14,6 +14,9 @@ import (
 // PostIPAMURL generates an URL for the post IP a m operation
 type PostIPAMURL struct {
 	Family *string
+	K8sPod *string
+	Owner  *string
+	Pool   *string
 
 	_basePath string
 	// avoid unkeyed usage
@@ -57,6 +60,30 @@ func (o *PostIPAMURL) Build() (*url.URL, error) {
 		qs.Set("family", family)
 	}
 
+	var k8sPod string
+	if o.K8sPod != nil {
+		k8sPod = *o.K8sPod
+	}
+	if k8sPod != "" {
+		qs.Set("k8s-pod", k8sPod)
+	}
+
+	var owner string
+	if o.Owner != nil {
+		owner = *o.Owner
+	}
+	if owner != "" {
+		qs.Set("owner", owner)
+	}
+
+	var pool string
+	if o.Pool != nil {
+		pool = *o.Pool
+	}
+	if pool != "" {
+		qs.Set("pool", pool)
+	}
+
 	result.RawQuery = qs.Encode()
 
 	return &result, nil
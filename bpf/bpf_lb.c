@@ -95,10 +95,13 @@ static inline int handle_ipv6(struct __sk_buff *skb)
 		return TC_ACT_OK;
 	}
 
-	slave = lb6_select_slave(skb, &key, svc->count, svc->weight);
+	slave = lb6_affinity_select_slave(skb, &key, svc, (union v6addr *) &ip6->saddr,
+					  nexthdr, l4_off);
 	if (!(svc = lb6_lookup_slave(skb, &key, slave)))
 		return DROP_NO_SERVICE;
 
+	lb6_update_stats(skb, svc, nexthdr, l4_off);
+
 	ipv6_addr_copy(&new_dst, &svc->target);
 	if (svc->rev_nat_index)
 		new_dst.p4 |= svc->rev_nat_index;
@@ -153,10 +156,13 @@ static inline int handle_ipv4(struct __sk_buff *skb)
 		return TC_ACT_OK;
 	}
 
-	slave = lb4_select_slave(skb, &key, svc->count, svc->weight);
+	slave = lb4_affinity_select_slave(skb, &key, svc, ip->saddr,
+					  nexthdr, l4_off);
 	if (!(svc = lb4_lookup_slave(skb, &key, slave)))
 		return DROP_NO_SERVICE;
 
+	lb4_update_stats(skb, svc, nexthdr, l4_off);
+
 	new_dst = svc->target;
 	ret = lb4_xlate(skb, &new_dst, NULL, NULL, nexthdr, l3_off, l4_off, &csum_off, &key, svc);
 	if (IS_ERR(ret))
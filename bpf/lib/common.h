@@ -398,6 +398,56 @@ struct lb_sequence {
 	__u16 idx[LB_RR_MAX_SEQ];
 };
 
+// LB_MAGLEV_TABLE_SIZE generated by daemon in node_config.h
+struct lb_maglev {
+	__u16 idx[LB_MAGLEV_TABLE_SIZE];
+};
+
+struct lb_affinity_match {
+	__u32 timeout;		/* in seconds */
+};
+
+struct lb4_affinity_key {
+	__be32 client_ip;
+	__u16 rev_nat_id;
+	__u16 pad;
+} __attribute__((packed));
+
+struct lb6_affinity_key {
+	union v6addr client_ip;
+	__u16 rev_nat_id;
+	__u16 pad;
+} __attribute__((packed));
+
+struct lb_affinity_val {
+	__u32 last_used;	/* in seconds */
+	__u16 slave;
+	__u16 pad;
+};
+
+struct lb_drain {
+	__u16 slave;		/* Slave receiving the new connections */
+	__u16 pad;
+};
+
+struct lb4_stats_key {
+	__be32 target;
+	__be16 port;
+	__u16 rev_nat_index;
+} __attribute__((packed));
+
+struct lb6_stats_key {
+	union v6addr target;
+	__be16 port;
+	__u16 rev_nat_index;
+} __attribute__((packed));
+
+struct lb_stats {
+	__u64 packets;
+	__u64 bytes;
+	__u64 conns;		/* New TCP connections */
+};
+
 struct ct_state {
 	__u16 rev_nat_index;
 	__u16 loopback:1,
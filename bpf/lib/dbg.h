@@ -107,6 +107,9 @@ enum {
 	DBG_IP_ID_MAP_SUCCEED6,	/* arg1: daddr (last 4 bytes)
 				 * arg2: identity
 				 * arg3: unused */
+	DBG_MAGLEV_SLAVE_SEL,	/* arg1: hash
+				 * arg2: slave
+				 * arg3: unused */
 };
 
 /* Capture types */
@@ -35,6 +35,16 @@
 /* FIXME: Make configurable */
 #define CILIUM_LB_MAP_MAX_ENTRIES	65536
 #define CILIUM_LB_MAP_MAX_FE		256
+#define CILIUM_LB_AFFINITY_MAX_ENTRIES	65536
+#define CILIUM_LB_STATS_MAX_ENTRIES	65536
+
+struct bpf_elf_map __section_maps cilium_lb_affinity_match = {
+	.type		= BPF_MAP_TYPE_HASH,
+	.size_key	= sizeof(__u16),
+	.size_value	= sizeof(struct lb_affinity_match),
+	.pinning	= PIN_GLOBAL_NS,
+	.max_elem	= CILIUM_LB_MAP_MAX_ENTRIES,
+};
 
 struct bpf_elf_map __section_maps cilium_lb6_reverse_nat = {
 	.type		= BPF_MAP_TYPE_HASH,
@@ -60,6 +70,38 @@ struct bpf_elf_map __section_maps cilium_lb6_rr_seq = {
 	.max_elem       = CILIUM_LB_MAP_MAX_FE,
 };
 
+struct bpf_elf_map __section_maps cilium_lb6_maglev = {
+	.type           = BPF_MAP_TYPE_HASH,
+	.size_key       = sizeof(struct lb6_key),
+	.size_value     = sizeof(struct lb_maglev),
+	.pinning        = PIN_GLOBAL_NS,
+	.max_elem       = CILIUM_LB_MAP_MAX_FE,
+};
+
+struct bpf_elf_map __section_maps cilium_lb6_affinity = {
+	.type		= BPF_MAP_TYPE_LRU_HASH,
+	.size_key	= sizeof(struct lb6_affinity_key),
+	.size_value	= sizeof(struct lb_affinity_val),
+	.pinning	= PIN_GLOBAL_NS,
+	.max_elem	= CILIUM_LB_AFFINITY_MAX_ENTRIES,
+};
+
+struct bpf_elf_map __section_maps cilium_lb6_drain = {
+	.type		= BPF_MAP_TYPE_HASH,
+	.size_key	= sizeof(struct lb6_key),
+	.size_value	= sizeof(struct lb_drain),
+	.pinning	= PIN_GLOBAL_NS,
+	.max_elem	= CILIUM_LB_MAP_MAX_ENTRIES,
+};
+
+struct bpf_elf_map __section_maps cilium_lb6_stats = {
+	.type		= BPF_MAP_TYPE_PERCPU_HASH,
+	.size_key	= sizeof(struct lb6_stats_key),
+	.size_value	= sizeof(struct lb_stats),
+	.pinning	= PIN_GLOBAL_NS,
+	.max_elem	= CILIUM_LB_STATS_MAX_ENTRIES,
+};
+
 struct bpf_elf_map __section_maps cilium_lb4_reverse_nat = {
 	.type		= BPF_MAP_TYPE_HASH,
 	.size_key	= sizeof(__u16),
@@ -83,6 +125,38 @@ struct bpf_elf_map __section_maps cilium_lb4_rr_seq = {
 	.pinning        = PIN_GLOBAL_NS,
 	.max_elem       = CILIUM_LB_MAP_MAX_FE,
 };
+
+struct bpf_elf_map __section_maps cilium_lb4_maglev = {
+	.type           = BPF_MAP_TYPE_HASH,
+	.size_key       = sizeof(struct lb4_key),
+	.size_value     = sizeof(struct lb_maglev),
+	.pinning        = PIN_GLOBAL_NS,
+	.max_elem       = CILIUM_LB_MAP_MAX_FE,
+};
+
+struct bpf_elf_map __section_maps cilium_lb4_affinity = {
+	.type		= BPF_MAP_TYPE_LRU_HASH,
+	.size_key	= sizeof(struct lb4_affinity_key),
+	.size_value	= sizeof(struct lb_affinity_val),
+	.pinning	= PIN_GLOBAL_NS,
+	.max_elem	= CILIUM_LB_AFFINITY_MAX_ENTRIES,
+};
+
+struct bpf_elf_map __section_maps cilium_lb4_drain = {
+	.type		= BPF_MAP_TYPE_HASH,
+	.size_key	= sizeof(struct lb4_key),
+	.size_value	= sizeof(struct lb_drain),
+	.pinning	= PIN_GLOBAL_NS,
+	.max_elem	= CILIUM_LB_MAP_MAX_ENTRIES,
+};
+
+struct bpf_elf_map __section_maps cilium_lb4_stats = {
+	.type		= BPF_MAP_TYPE_PERCPU_HASH,
+	.size_key	= sizeof(struct lb4_stats_key),
+	.size_value	= sizeof(struct lb_stats),
+	.pinning	= PIN_GLOBAL_NS,
+	.max_elem	= CILIUM_LB_STATS_MAX_ENTRIES,
+};
 #define REV_NAT_F_TUPLE_SADDR 1
 #ifdef LB_DEBUG
 #define cilium_dbg_lb cilium_dbg
@@ -108,6 +182,28 @@ static inline int lb_next_rr(struct __sk_buff *skb,
 }
 #endif
 
+/* The Maglev lookup accesses the map value at a variable offset as well and
+ * is subject to the same verifier complexity concerns as the wrr sequence
+ * below. It is thus only compiled in if enabled with --enable-maglev.
+ */
+#if defined ENABLE_MAGLEV && defined HAVE_MAP_VAL_ADJ
+static inline int lb_next_maglev(struct __sk_buff *skb,
+				 struct lb_maglev *lut,
+				 __u32 hash)
+{
+	/* Unlike a modulo, the multiply and shift maps the hash onto
+	 * [0, LB_MAGLEV_TABLE_SIZE) in a way the verifier can bound.
+	 */
+	__u32 offset = ((__u64) hash * LB_MAGLEV_TABLE_SIZE) >> 32;
+	/* Slave 0 is reserved for the master slot */
+	int slave = lut->idx[offset] + 1;
+
+	cilium_dbg_lb(skb, DBG_MAGLEV_SLAVE_SEL, hash, slave);
+
+	return slave;
+}
+#endif
+
 static inline __u32 lb_enforce_rehash(struct __sk_buff *skb)
 {
 #ifdef HAVE_SET_HASH_INVALID
@@ -129,6 +225,24 @@ static inline int lb6_select_slave(struct __sk_buff *skb,
 	__u32 hash = lb_enforce_rehash(skb);
 	int slave = 0;
 
+#if defined ENABLE_MAGLEV && defined HAVE_MAP_VAL_ADJ
+	/* Services are only added to the Maglev map if they have been
+	 * configured to use the Maglev algorithm explicitly.
+	 */
+	{
+		struct lb_maglev *lut;
+
+		lut = map_lookup_elem(&cilium_lb6_maglev, key);
+		if (lut)
+			slave = lb_next_maglev(skb, lut, hash);
+		/* The lookup table may refer to a slave which has been
+		 * removed already while the service is being updated.
+		 */
+		if (slave > count)
+			slave = 0;
+	}
+#endif
+
 /* Disabled for now since on older kernels dynamic map access
  * will cause a significant complexity increase for the entire
  * program due to pruning having less opportunities matching
@@ -138,7 +252,7 @@ static inline int lb6_select_slave(struct __sk_buff *skb,
  * selection based on hash instead of hash w/ weights.
  */
 #if 0 /* HAVE_MAP_VAL_ADJ */
-	if (weight) {
+	if (slave == 0 && weight) {
 		struct lb_sequence *seq;
 
 		seq = map_lookup_elem(&cilium_lb6_rr_seq, key);
@@ -163,6 +277,24 @@ static inline int lb4_select_slave(struct __sk_buff *skb,
 	__u32 hash = lb_enforce_rehash(skb);
 	int slave = 0;
 
+#if defined ENABLE_MAGLEV && defined HAVE_MAP_VAL_ADJ
+	/* Services are only added to the Maglev map if they have been
+	 * configured to use the Maglev algorithm explicitly.
+	 */
+	{
+		struct lb_maglev *lut;
+
+		lut = map_lookup_elem(&cilium_lb4_maglev, key);
+		if (lut)
+			slave = lb_next_maglev(skb, lut, hash);
+		/* The lookup table may refer to a slave which has been
+		 * removed already while the service is being updated.
+		 */
+		if (slave > count)
+			slave = 0;
+	}
+#endif
+
 /* Disabled for now since on older kernels dynamic map access
  * will cause a significant complexity increase for the entire
  * program due to pruning having less opportunities matching
@@ -172,7 +304,7 @@ static inline int lb4_select_slave(struct __sk_buff *skb,
  * selection based on hash instead of hash w/ weights.
  */
 #if 0 /* HAVE_MAP_VAL_ADJ */
-	if (weight) {
+	if (slave == 0 && weight) {
 		struct lb_sequence *seq;
 
 		seq = map_lookup_elem(&cilium_lb4_rr_seq, key);
@@ -190,6 +322,263 @@ static inline int lb4_select_slave(struct __sk_buff *skb,
 	return slave;
 }
 
+/* Returns the session affinity timeout in seconds of the service with the
+ * given reverse NAT index or 0 if the service does not use session affinity.
+ */
+static inline __u32 lb_affinity_timeout(__u16 rev_nat_index)
+{
+	struct lb_affinity_match *match;
+
+	match = map_lookup_elem(&cilium_lb_affinity_match, &rev_nat_index);
+	if (match)
+		return match->timeout;
+
+	return 0;
+}
+
+/* Returns the slave previously selected for the client if it has not been
+ * idle for longer than timeout or 0 otherwise.
+ */
+static inline int lb_affinity_slave(struct lb_affinity_val *val,
+				    __u32 timeout, __u16 count)
+{
+	__u32 now = bpf_ktime_get_sec();
+
+	if (!val || val->slave == 0 || val->slave > count ||
+	    val->last_used + timeout < now)
+		return 0;
+
+	val->last_used = now;
+	return val->slave;
+}
+
+#define LB_TCP_FLAGS_OFF	13
+#define LB_TCP_FLAG_SYN		0x02
+#define LB_TCP_FLAG_ACK		0x10
+
+/* Returns true if the packet may open a new connection. Only TCP allows to
+ * tell new connections apart, packets of all other protocols are treated as
+ * opening a new connection.
+ */
+static inline bool lb_new_conn(struct __sk_buff *skb, __u8 nexthdr, int l4_off)
+{
+	__u8 flags;
+
+	if (nexthdr != IPPROTO_TCP)
+		return true;
+
+	if (skb_load_bytes(skb, l4_off + LB_TCP_FLAGS_OFF, &flags, 1) < 0)
+		return true;
+
+	return (flags & (LB_TCP_FLAG_SYN | LB_TCP_FLAG_ACK)) == LB_TCP_FLAG_SYN;
+}
+
+/* Returns the slave receiving the new connections of the given slave if the
+ * slave is draining or 0 otherwise.
+ */
+static inline int lb6_drain_slave(struct lb6_key *key, __u16 slave)
+{
+	struct lb6_key dkey = *key;
+	struct lb_drain *drain;
+
+	dkey.slave = slave;
+	drain = map_lookup_elem(&cilium_lb6_drain, &dkey);
+	if (drain)
+		return drain->slave;
+
+	return 0;
+}
+
+static inline int lb4_drain_slave(struct lb4_key *key, __u16 slave)
+{
+	struct lb4_key dkey = *key;
+	struct lb_drain *drain;
+
+	dkey.slave = slave;
+	drain = map_lookup_elem(&cilium_lb4_drain, &dkey);
+	if (drain)
+		return drain->slave;
+
+	return 0;
+}
+
+static inline void lb_update_stats(struct __sk_buff *skb, struct lb_stats *stats,
+				   __u8 nexthdr, int l4_off)
+{
+	stats->packets++;
+	stats->bytes += skb->len;
+	if (nexthdr == IPPROTO_TCP && lb_new_conn(skb, nexthdr, l4_off))
+		stats->conns++;
+}
+
+/* Accounts the packet to the traffic statistics of the slave it has been
+ * sent to if ENABLE_LB_STATS is defined. Only TCP allows to count new
+ * connections.
+ */
+static inline void lb6_update_stats(struct __sk_buff *skb,
+				    struct lb6_service *slave,
+				    __u8 nexthdr, int l4_off)
+{
+#ifdef ENABLE_LB_STATS
+	struct lb6_stats_key key = {
+		.port = slave->port,
+		.rev_nat_index = slave->rev_nat_index,
+	};
+	struct lb_stats *stats, zero = {};
+
+	ipv6_addr_copy(&key.target, &slave->target);
+	stats = map_lookup_elem(&cilium_lb6_stats, &key);
+	if (!stats) {
+		map_update_elem(&cilium_lb6_stats, &key, &zero, BPF_NOEXIST);
+		stats = map_lookup_elem(&cilium_lb6_stats, &key);
+		if (!stats)
+			return;
+	}
+
+	lb_update_stats(skb, stats, nexthdr, l4_off);
+#endif
+}
+
+static inline void lb4_update_stats(struct __sk_buff *skb,
+				    struct lb4_service *slave,
+				    __u8 nexthdr, int l4_off)
+{
+#ifdef ENABLE_LB_STATS
+	struct lb4_stats_key key = {
+		.target = slave->target,
+		.port = slave->port,
+		.rev_nat_index = slave->rev_nat_index,
+	};
+	struct lb_stats *stats, zero = {};
+
+	stats = map_lookup_elem(&cilium_lb4_stats, &key);
+	if (!stats) {
+		map_update_elem(&cilium_lb4_stats, &key, &zero, BPF_NOEXIST);
+		stats = map_lookup_elem(&cilium_lb4_stats, &key);
+		if (!stats)
+			return;
+	}
+
+	lb_update_stats(skb, stats, nexthdr, l4_off);
+#endif
+}
+
+/* Selects the slave of the service for the packet. With ENABLE_LB_DRAIN, new
+ * connections are never sent to a draining slave while established connections
+ * remain on it. With ENABLE_SESSION_AFFINITY, clients of services using session
+ * affinity stick to the previously selected slave.
+ */
+static inline int lb6_affinity_select_slave(struct __sk_buff *skb,
+					    struct lb6_key *key,
+					    struct lb6_service *svc,
+					    union v6addr *saddr,
+					    __u8 nexthdr, int l4_off)
+{
+#ifdef ENABLE_SESSION_AFFINITY
+	struct lb6_affinity_key akey = {
+		.rev_nat_id = svc->rev_nat_index,
+	};
+	struct lb_affinity_val val = {};
+	bool update = false;
+	__u32 timeout;
+#endif
+	int slave = 0;
+
+#ifdef ENABLE_SESSION_AFFINITY
+	timeout = lb_affinity_timeout(svc->rev_nat_index);
+	if (timeout) {
+		ipv6_addr_copy(&akey.client_ip, saddr);
+		slave = lb_affinity_slave(map_lookup_elem(&cilium_lb6_affinity, &akey),
+					  timeout, svc->count);
+	}
+#endif
+
+	if (slave == 0) {
+		slave = lb6_select_slave(skb, key, svc->count, svc->weight);
+#ifdef ENABLE_SESSION_AFFINITY
+		update = true;
+#endif
+	}
+
+#ifdef ENABLE_LB_DRAIN
+	if (lb_new_conn(skb, nexthdr, l4_off)) {
+		int drain = lb6_drain_slave(key, slave);
+
+		if (drain > 0 && drain <= svc->count) {
+			slave = drain;
+#ifdef ENABLE_SESSION_AFFINITY
+			update = true;
+#endif
+		}
+	}
+#endif
+
+#ifdef ENABLE_SESSION_AFFINITY
+	if (timeout && update) {
+		val.last_used = bpf_ktime_get_sec();
+		val.slave = slave;
+		map_update_elem(&cilium_lb6_affinity, &akey, &val, 0);
+	}
+#endif
+
+	return slave;
+}
+
+static inline int lb4_affinity_select_slave(struct __sk_buff *skb,
+					    struct lb4_key *key,
+					    struct lb4_service *svc,
+					    __be32 saddr,
+					    __u8 nexthdr, int l4_off)
+{
+#ifdef ENABLE_SESSION_AFFINITY
+	struct lb4_affinity_key akey = {
+		.client_ip = saddr,
+		.rev_nat_id = svc->rev_nat_index,
+	};
+	struct lb_affinity_val val = {};
+	bool update = false;
+	__u32 timeout;
+#endif
+	int slave = 0;
+
+#ifdef ENABLE_SESSION_AFFINITY
+	timeout = lb_affinity_timeout(svc->rev_nat_index);
+	if (timeout)
+		slave = lb_affinity_slave(map_lookup_elem(&cilium_lb4_affinity, &akey),
+					  timeout, svc->count);
+#endif
+
+	if (slave == 0) {
+		slave = lb4_select_slave(skb, key, svc->count, svc->weight);
+#ifdef ENABLE_SESSION_AFFINITY
+		update = true;
+#endif
+	}
+
+#ifdef ENABLE_LB_DRAIN
+	if (lb_new_conn(skb, nexthdr, l4_off)) {
+		int drain = lb4_drain_slave(key, slave);
+
+		if (drain > 0 && drain <= svc->count) {
+			slave = drain;
+#ifdef ENABLE_SESSION_AFFINITY
+			update = true;
+#endif
+		}
+	}
+#endif
+
+#ifdef ENABLE_SESSION_AFFINITY
+	if (timeout && update) {
+		val.last_used = bpf_ktime_get_sec();
+		val.slave = slave;
+		map_update_elem(&cilium_lb4_affinity, &akey, &val, 0);
+	}
+#endif
+
+	return slave;
+}
+
 static inline int __inline__ extract_l4_port(struct __sk_buff *skb, __u8 nexthdr,
 					     int l4_off, __be16 *port)
 {
@@ -432,10 +821,13 @@ static inline int __inline__ lb6_local(struct __sk_buff *skb, int l3_off, int l4
 	__u16 slave;
 	union v6addr *addr;
 
-	slave = lb6_select_slave(skb, key, svc->count, svc->weight);
+	slave = lb6_affinity_select_slave(skb, key, svc, &tuple->saddr,
+					  tuple->nexthdr, l4_off);
 	if (!(svc = lb6_lookup_slave(skb, key, slave)))
 		return DROP_NO_SERVICE;
 
+	lb6_update_stats(skb, svc, tuple->nexthdr, l4_off);
+
 	ipv6_addr_copy(&tuple->daddr, &svc->target);
 	addr = &tuple->daddr;
 
@@ -669,10 +1061,13 @@ static inline int __inline__ lb4_local(struct __sk_buff *skb, int l3_off, int l4
 	__be32 new_saddr = 0, new_daddr;
 	__u16 slave;
 
-	slave = lb4_select_slave(skb, key, svc->count, svc->weight);
+	slave = lb4_affinity_select_slave(skb, key, svc, saddr,
+					  tuple->nexthdr, l4_off);
 	if (!(svc = lb4_lookup_slave(skb, key, slave)))
 		return DROP_NO_SERVICE;
 
+	lb4_update_stats(skb, svc, tuple->nexthdr, l4_off);
+
 	state->rev_nat_index = svc->rev_nat_index;
 	state->addr = new_daddr = svc->target;
 
@@ -49,6 +49,11 @@
 #define NODE_MAC { .addr = { 0xde, 0xad, 0xbe, 0xef, 0xc0, 0xde } }
 #define ENABLE_IPV4
 #define LB_RR_MAX_SEQ 31
+#define LB_MAGLEV_TABLE_SIZE 1021
+#define ENABLE_MAGLEV
+#define ENABLE_SESSION_AFFINITY
+#define ENABLE_LB_STATS
+#define ENABLE_LB_DRAIN
 #define TUNNEL_ENDPOINT_MAP_SIZE 65536
 #define ENDPOINTS_MAP_SIZE 65536
 #define CILIUM_NET_MAC  { .addr = { 0xce, 0x72, 0xa7, 0x03, 0x88, 0x57 } }
@@ -0,0 +1,107 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/cilium/cilium/pkg/command"
+
+	"github.com/spf13/cobra"
+)
+
+var resetLabelPrefixes bool
+
+// configLabelPrefixesCmd represents the config_label_prefixes command
+var configLabelPrefixesCmd = &cobra.Command{
+	Use:   "label-prefixes [<prefix> ...]",
+	Short: "View or replace the label prefixes determining identity relevant labels",
+	Long: `Without arguments, the label prefixes are shown. Otherwise, the list of label
+prefixes passed to the agent with --labels is replaced by the given prefixes
+in the form [source:][!]prefix. The labels of all local endpoints are filtered
+again and the endpoints which changed identity are listed. The change is not
+persisted across agent restarts.`,
+	Example: "cilium config label-prefixes k8s:app k8s:io.kubernetes.pod.namespace",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 && !resetLabelPrefixes {
+			getLabelPrefixes()
+			return
+		}
+
+		if len(args) > 0 && resetLabelPrefixes {
+			Usagef(cmd, "--reset cannot be combined with label prefixes")
+		}
+
+		setLabelPrefixes(args)
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configLabelPrefixesCmd)
+	configLabelPrefixesCmd.Flags().BoolVar(&resetLabelPrefixes, "reset", false, "Remove all label prefixes passed to the agent")
+	command.AddJSONOutput(configLabelPrefixesCmd)
+}
+
+func getLabelPrefixes() {
+	cfg, err := client.ConfigLabelPrefixesGet()
+	if err != nil {
+		Fatalf("Cannot get label prefixes: %s\n", err)
+	}
+
+	if command.OutputJSON() {
+		if err := command.PrintOutput(cfg); err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Println("Configured label prefixes:")
+	for _, p := range cfg.Prefixes {
+		fmt.Printf("  %s\n", p)
+	}
+
+	fmt.Println("Effective label prefixes:")
+	for _, p := range cfg.Effective {
+		fmt.Printf("  %s\n", p)
+	}
+}
+
+func setLabelPrefixes(prefixes []string) {
+	migrations, err := client.ConfigLabelPrefixesPut(prefixes)
+	if err != nil {
+		Fatalf("Cannot replace label prefixes: %s\n", err)
+	}
+
+	if command.OutputJSON() {
+		if err := command.PrintOutput(migrations); err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(migrations) == 0 {
+		fmt.Println("No endpoint changed identity")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 2, 0, 3, ' ', 0)
+	fmt.Fprintf(w, "ENDPOINT\tOLD IDENTITY\tNEW IDENTITY\tLABELS\tERROR\n")
+	for _, m := range migrations {
+		fmt.Fprintf(w, "%d\t%d\t%d\t%s\t%s\n", m.EndpointID, m.OldIdentity, m.NewIdentity, m.Labels, m.Error)
+	}
+	w.Flush()
+}
@@ -0,0 +1,116 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/flowgraph"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	graphOutput string
+	graphSince  time.Duration
+)
+
+// flowsGraphCmd represents the flows_graph command
+var flowsGraphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Display the dependency graph of observed flows",
+	Long: `Display which identities and services have been observed to communicate
+with each other, including the destination ports, L7 methods and counters.
+The graph can be rendered with Graphviz using the dot output, e.g.:
+
+  cilium flows graph -o dot | dot -Tsvg > flows.svg
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		getFlowGraph()
+	},
+}
+
+func init() {
+	flowsCmd.AddCommand(flowsGraphCmd)
+	flowsGraphCmd.Flags().StringVarP(&graphOutput, "output", "o", "", "Output format { dot | json }")
+	flowsGraphCmd.Flags().DurationVar(&graphSince, "since", 0, "Only show edges observed within the given duration (e.g. 10m)")
+}
+
+func getFlowGraph() {
+	var since string
+	if graphSince > 0 {
+		since = graphSince.String()
+	}
+
+	graph, err := client.FlowGraphGet(since)
+	if err != nil {
+		Fatalf("Cannot get flow graph: %s", err)
+	}
+
+	switch graphOutput {
+	case "dot":
+		if err := flowgraph.WriteDOT(os.Stdout, graph); err != nil {
+			Fatalf("Cannot write flow graph: %s", err)
+		}
+	case "json":
+		result, err := json.MarshalIndent(graph, "", "  ")
+		if err != nil {
+			Fatalf("Cannot marshal flow graph: %s", err)
+		}
+		fmt.Println(string(result))
+	case "":
+		printFlowGraph(graph)
+	default:
+		Fatalf("Unknown output format %q, must be { dot | json }", graphOutput)
+	}
+}
+
+func edgeColumns(e *models.FlowGraphEdge) string {
+	return fmt.Sprintf("%s\t%s\t%d\t%d\t%d\t%d\t%s",
+		strings.Join(e.Ports, ","), strings.Join(e.L7Methods, ","),
+		e.Connections, e.Forwarded, e.Dropped, e.L7Requests,
+		time.Time(e.LastSeen).Format(time.RFC3339))
+}
+
+func printFlowGraph(graph *models.FlowGraph) {
+	w := tabwriter.NewWriter(os.Stdout, 5, 0, 3, ' ', 0)
+
+	fmt.Fprintln(w, "SOURCE\tDESTINATION\tPORTS\tL7 METHODS\tCONNECTIONS\tFORWARDED\tDROPPED\tL7 REQUESTS\tLAST SEEN")
+	for _, e := range graph.Edges {
+		fmt.Fprintf(w, "%d\t%d\t%s\n", e.SourceIdentity, e.DestinationIdentity, edgeColumns(e))
+	}
+
+	if len(graph.ServiceEdges) > 0 {
+		fmt.Fprintln(w, "\nSOURCE SERVICE\tDESTINATION SERVICE\tPORTS\tL7 METHODS\tCONNECTIONS\tFORWARDED\tDROPPED\tL7 REQUESTS\tLAST SEEN")
+		for _, e := range graph.ServiceEdges {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", e.SourceService, e.DestinationService, edgeColumns(e))
+		}
+	}
+
+	if len(graph.Nodes) > 0 {
+		fmt.Fprintln(w, "\nIDENTITY\tLABELS\tSERVICES")
+		for _, n := range graph.Nodes {
+			fmt.Fprintf(w, "%d\t%s\t%s\n", n.Identity, strings.Join(n.Labels, " "), strings.Join(n.Services, ","))
+		}
+	}
+
+	w.Flush()
+}
@@ -0,0 +1,123 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/command"
+	"github.com/cilium/cilium/pkg/u8proto"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	flowsEndpoint int64
+	flowsVerdict  string
+	flowsSince    time.Duration
+)
+
+// flowsListCmd represents the flows_list command
+var flowsListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List recently observed flows",
+	Long: `List the flows recently observed by endpoints of this node. Flows are
+recorded from trace and drop notifications of the datapath and from L7 access
+log records. Only a limited number of flows is kept for each endpoint.
+`,
+	Example: "  cilium flows list --endpoint 3978 --verdict Denied --since 1m",
+	Run: func(cmd *cobra.Command, args []string) {
+		listFlows()
+	},
+}
+
+func init() {
+	flowsCmd.AddCommand(flowsListCmd)
+	flowsListCmd.Flags().Int64Var(&flowsEndpoint, "endpoint", 0, "Only show flows observed by the endpoint with the given ID")
+	flowsListCmd.Flags().StringVar(&flowsVerdict, "verdict", "", "Only show flows with the given verdict { Forwarded | Denied | Error }")
+	flowsListCmd.Flags().DurationVar(&flowsSince, "since", 0, "Only show flows observed within the given duration (e.g. 1m)")
+	command.AddJSONOutput(flowsListCmd)
+}
+
+func listFlows() {
+	var since string
+	if flowsSince > 0 {
+		since = flowsSince.String()
+	}
+
+	flows, err := client.FlowsList(flowsEndpoint, flowsVerdict, since)
+	if err != nil {
+		Fatalf("Cannot get flows: %s", err)
+	}
+
+	if command.OutputJSON() {
+		if err := command.PrintOutput(flows); err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 5, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "TIME\tENDPOINT\tTYPE\tVERDICT\tSOURCE\tDESTINATION\tPROTOCOL\tSUMMARY")
+	for _, f := range flows {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			time.Time(f.Timestamp).Format(time.RFC3339), f.EndpointID,
+			f.Type, f.Verdict, flowEndpoint(f.Source), flowEndpoint(f.Destination),
+			u8proto.U8proto(f.TransportProtocol).String(), flowSummary(f))
+	}
+	w.Flush()
+}
+
+// flowEndpoint returns the address and identity of a flow endpoint
+func flowEndpoint(e *models.FlowEndpoint) string {
+	if e == nil {
+		return ""
+	}
+
+	ip := e.IPV4
+	if ip == "" {
+		ip = e.IPV6
+	}
+	addr := ip
+	if e.Port != 0 {
+		addr = net.JoinHostPort(ip, strconv.FormatInt(e.Port, 10))
+	}
+	return fmt.Sprintf("%s (%d)", addr, e.Identity)
+}
+
+// flowSummary returns the protocol specific details of a flow
+func flowSummary(f *models.Flow) string {
+	switch {
+	case f.HTTP != nil:
+		if f.Type == models.FlowTypeResponse {
+			return fmt.Sprintf("HTTP %d %s %s", f.HTTP.Code, f.HTTP.Method, f.HTTP.URL)
+		}
+		return fmt.Sprintf("HTTP %s %s", f.HTTP.Method, f.HTTP.URL)
+	case f.Kafka != nil:
+		return fmt.Sprintf("Kafka %s topic %s", f.Kafka.APIKey, f.Kafka.Topic)
+	case f.DropReason != "":
+		return f.DropReason
+	case f.TraceObservationPoint != "":
+		return fmt.Sprintf("%s state %s", f.TraceObservationPoint, f.ConnectionState)
+	}
+	return f.Info
+}
@@ -0,0 +1,92 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/cilium/cilium/pkg/command"
+
+	"github.com/spf13/cobra"
+)
+
+// identityExplainCmd represents the identity_explain command
+var identityExplainCmd = &cobra.Command{
+	Use:   "explain <endpoint id>",
+	Short: "Explain which labels of an endpoint determine its identity",
+	Long: `Lists all labels of an endpoint and whether each label is used to determine
+the security identity of the endpoint. For each label, the label prefix which
+decided about the label and the reason of the decision is shown.`,
+	Example: "cilium identity explain 5421",
+	Run: func(cmd *cobra.Command, args []string) {
+		requireEndpointID(cmd, args)
+		explainIdentity(args[0])
+	},
+}
+
+func init() {
+	identityCmd.AddCommand(identityExplainCmd)
+	command.AddJSONOutput(identityExplainCmd)
+}
+
+func explainIdentity(eID string) {
+	explanation, err := client.EndpointIdentityExplain(eID)
+	if err != nil {
+		Fatalf("Cannot explain identity of endpoint %s: %s\n", eID, err)
+	}
+
+	if command.OutputJSON() {
+		if err := command.PrintOutput(explanation); err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if explanation.Identity != nil {
+		fmt.Printf("Identity:    %d\n", explanation.Identity.ID)
+	} else {
+		fmt.Printf("Identity:    <none>\n")
+	}
+
+	sharedWith := "-"
+	if len(explanation.SharedWith) > 0 {
+		ids := make([]string, 0, len(explanation.SharedWith))
+		for _, id := range explanation.SharedWith {
+			ids = append(ids, fmt.Sprintf("%d", id))
+		}
+		sharedWith = strings.Join(ids, ", ")
+	}
+	fmt.Printf("Shared with: %s\n\n", sharedWith)
+
+	w := tabwriter.NewWriter(os.Stdout, 2, 0, 3, ' ', 0)
+	fmt.Fprintf(w, "LABEL\tSOURCE\tDECISION\tPREFIX\tREASON\n")
+	for _, l := range explanation.Labels {
+		decision := "dropped"
+		if l.Kept {
+			decision = "kept"
+		}
+
+		prefix := l.Prefix
+		if prefix == "" {
+			prefix = "-"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", l.Label, l.Source, decision, prefix, l.Reason)
+	}
+	w.Flush()
+}
@@ -0,0 +1,118 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	pkg "github.com/cilium/cilium/pkg/client"
+	"github.com/cilium/cilium/pkg/command"
+
+	"github.com/spf13/cobra"
+)
+
+var identityGCDryRun bool
+
+// identityGCCmd represents the identity_gc command
+var identityGCCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Release identities which are no longer in use",
+	Long: `Releases all identities which are no longer referenced by any node. With
+--dry-run, the identities which would be released are listed together with
+the identities which are only referenced by nodes no longer part of the
+cluster.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if identityGCDryRun {
+			resp, err := client.Policy.GetIdentityGc(nil)
+			if err != nil {
+				Fatalf("Cannot get identity garbage collection report: %s", pkg.Hint(err))
+			}
+			printIdentityGCReport(resp.Payload)
+			return
+		}
+
+		resp, err := client.Policy.PostIdentityGc(nil)
+		if err != nil {
+			Fatalf("Cannot run identity garbage collector: %s", pkg.Hint(err))
+		}
+
+		if command.OutputJSON() {
+			if err := command.PrintOutput(resp.Payload); err != nil {
+				Fatalf("Unable to provide JSON output: %s", err)
+			}
+			return
+		}
+
+		fmt.Printf("Released %d identities\n", len(resp.Payload))
+		printIdentityGCEntries(resp.Payload)
+	},
+}
+
+func init() {
+	identityCmd.AddCommand(identityGCCmd)
+	identityGCCmd.Flags().BoolVar(&identityGCDryRun, "dry-run", false, "Only list the identities which would be released")
+	command.AddJSONOutput(identityGCCmd)
+}
+
+func printIdentityGCReport(report *models.IdentityGCReport) {
+	if command.OutputJSON() {
+		if err := command.PrintOutput(report); err != nil {
+			Fatalf("Unable to provide JSON output: %s", err)
+		}
+		return
+	}
+
+	fmt.Printf("Identities without references (released by next garbage collection): %d\n",
+		len(report.Unreferenced))
+	printIdentityGCEntries(report.Unreferenced)
+
+	if !report.StaleNodeDetection {
+		fmt.Printf("\nIdentities referenced only by stale nodes: unknown (list of nodes not available)\n")
+		return
+	}
+
+	fmt.Printf("\nIdentities referenced only by stale nodes: %d\n", len(report.StaleReferenced))
+	printIdentityGCEntries(report.StaleReferenced)
+}
+
+func printIdentityGCEntries(entries []*models.IdentityGCEntry) {
+	if len(entries) == 0 {
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 2, 0, 3, ' ', 0)
+
+	fmt.Fprintf(w, "ID\tLABELS\tLAST USE\tNODES\n")
+	for _, entry := range entries {
+		lastUse := "unknown"
+		if t := time.Time(entry.LastUse); !t.IsZero() {
+			lastUse = t.Format(time.RFC3339)
+		}
+
+		nodes := "-"
+		if len(entry.Nodes) > 0 {
+			nodes = strings.Join(entry.Nodes, ",")
+		}
+
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", entry.ID, entry.Labels, lastUse, nodes)
+	}
+
+	w.Flush()
+}
@@ -0,0 +1,87 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/command"
+
+	"github.com/spf13/cobra"
+)
+
+// ipamGetCmd represents the ipam_get command
+var ipamGetCmd = &cobra.Command{
+	Use:   "get <IP>",
+	Short: "Display an allocated IP address",
+	Long: `Displays the owner, the allocation time and the endpoint using an
+allocated IP address.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			Usagef(cmd, "Missing IP address argument")
+		}
+
+		if net.ParseIP(args[0]) == nil {
+			Usagef(cmd, "Invalid IP address %q", args[0])
+		}
+
+		a, err := client.IPAMGetAllocation(args[0])
+		if err != nil {
+			Fatalf("Cannot get IPAM allocation %s: %s", args[0], err)
+		}
+
+		if command.OutputJSON() {
+			if err := command.PrintOutput(a); err != nil {
+				Fatalf("Unable to provide JSON output: %s", err)
+			}
+			return
+		}
+
+		printIPAMAllocation(a)
+	},
+}
+
+func init() {
+	ipamCmd.AddCommand(ipamGetCmd)
+	command.AddJSONOutput(ipamGetCmd)
+}
+
+func printIPAMAllocation(a *models.IPAMAllocation) {
+	w := tabwriter.NewWriter(os.Stdout, 2, 0, 3, ' ', 0)
+
+	endpoint, state := ipamEndpointColumns(a)
+	if endpoint == "" {
+		endpoint = "<none>"
+	}
+
+	fmt.Fprintf(w, "IP:\t%s\n", a.IP)
+	fmt.Fprintf(w, "Owner:\t%s\n", ipamOwnerColumn(a))
+	fmt.Fprintf(w, "Pool:\t%s\n", a.Pool)
+	fmt.Fprintf(w, "Allocated:\t%s\n", time.Time(a.AllocationTime).Format(time.RFC3339))
+	fmt.Fprintf(w, "Endpoint:\t%s\n", endpoint)
+	if state != "" {
+		fmt.Fprintf(w, "Endpoint state:\t%s\n", state)
+	}
+	if a.Orphan != "" {
+		fmt.Fprintf(w, "Orphan:\t%s\n", a.Orphan)
+	}
+
+	w.Flush()
+}
@@ -0,0 +1,103 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/command"
+
+	"github.com/spf13/cobra"
+)
+
+var ipamListOrphans bool
+
+// ipamListCmd represents the ipam_list command
+var ipamListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List allocated IP addresses and their owners",
+	Long: `Lists all IP addresses allocated by the agent together with their owner,
+the time of the allocation and the endpoint using the address, followed by the
+usage of all pools. Allocations are persisted in the state directory and reconciled with the
+restored endpoints and running containers when the agent restarts. Addresses
+which are not used by any endpoint are reported as orphans and can be
+released with 'cilium ipam release'.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		allocations, err := client.IPAMGetAllocations(ipamListOrphans)
+		if err != nil {
+			Fatalf("Cannot get IPAM allocations: %s", err)
+		}
+
+		if command.OutputJSON() {
+			if err := command.PrintOutput(allocations); err != nil {
+				Fatalf("Unable to provide JSON output: %s", err)
+			}
+			return
+		}
+
+		printIPAMAllocations(allocations)
+
+		if ipamListOrphans {
+			return
+		}
+
+		pools, err := client.IPAMGetPools()
+		if err != nil {
+			Fatalf("Cannot get IPAM pools: %s", err)
+		}
+
+		fmt.Println()
+		printIPAMPools(pools)
+	},
+}
+
+func init() {
+	ipamCmd.AddCommand(ipamListCmd)
+	ipamListCmd.Flags().BoolVar(&ipamListOrphans, "orphans", false, "Only list addresses not used by any endpoint")
+	command.AddJSONOutput(ipamListCmd)
+}
+
+func printIPAMAllocations(allocations []*models.IPAMAllocation) {
+	w := tabwriter.NewWriter(os.Stdout, 2, 0, 3, ' ', 0)
+
+	fmt.Fprintf(w, "IP\tOWNER\tPOOL\tALLOCATED\tENDPOINT\tSTATE\tORPHAN\n")
+	for _, a := range allocations {
+		endpoint, state := ipamEndpointColumns(a)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", a.IP, ipamOwnerColumn(a),
+			a.Pool, time.Time(a.AllocationTime).Format(time.RFC3339), endpoint, state, a.Orphan)
+	}
+
+	w.Flush()
+}
+
+func ipamOwnerColumn(a *models.IPAMAllocation) string {
+	if a.Owner == "" {
+		return "<unknown>"
+	}
+	return a.Owner
+}
+
+func ipamEndpointColumns(a *models.IPAMAllocation) (endpoint, state string) {
+	if a.EndpointID == 0 {
+		return "", ""
+	}
+	return strconv.FormatInt(a.EndpointID, 10), string(a.EndpointState)
+}
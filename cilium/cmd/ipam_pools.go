@@ -0,0 +1,75 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/command"
+
+	"github.com/spf13/cobra"
+)
+
+// ipamPoolsCmd represents the ipam_pools command
+var ipamPoolsCmd = &cobra.Command{
+	Use:   "pools",
+	Short: "List IP address pools and their usage",
+	Long: `Lists all IP address pools of the agent. Additional pools are configured
+with the agent option --ipam-pool and selected with the
+io.cilium.network.ipam-pool annotation of pods or namespaces.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		pools, err := client.IPAMGetPools()
+		if err != nil {
+			Fatalf("Cannot get IPAM pools: %s", err)
+		}
+
+		if command.OutputJSON() {
+			if err := command.PrintOutput(pools); err != nil {
+				Fatalf("Unable to provide JSON output: %s", err)
+			}
+			return
+		}
+
+		printIPAMPools(pools)
+	},
+}
+
+func init() {
+	ipamCmd.AddCommand(ipamPoolsCmd)
+	command.AddJSONOutput(ipamPoolsCmd)
+}
+
+func printIPAMPools(pools []*models.IPAMPool) {
+	w := tabwriter.NewWriter(os.Stdout, 2, 0, 3, ' ', 0)
+
+	fmt.Fprintf(w, "NAME\tFAMILY\tCIDR\tUSED\tCAPACITY\tFREE\tFRAGMENTATION\n")
+	for _, pool := range pools {
+		for _, r := range []struct {
+			family string
+			r      *models.IPAMPoolRange
+		}{{"IPv4", pool.IPV4}, {"IPv6", pool.IPV6}} {
+			if r.r == nil {
+				continue
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%d\t%.0f%%\n", pool.Name, r.family,
+				r.r.Cidr, r.r.Used, r.r.Capacity, r.r.Free, r.r.Fragmentation*100)
+		}
+	}
+
+	w.Flush()
+}
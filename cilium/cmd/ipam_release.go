@@ -0,0 +1,53 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/spf13/cobra"
+)
+
+// ipamReleaseCmd represents the ipam_release command
+var ipamReleaseCmd = &cobra.Command{
+	Use:   "release <IP>...",
+	Short: "Release allocated IP addresses",
+	Long: `Releases IP addresses back to their pool. This is intended for orphaned
+addresses as reported by 'cilium ipam list --orphans'. Releasing an address
+still in use by an endpoint results in duplicate addresses.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			Usagef(cmd, "Missing IP address argument")
+		}
+
+		for _, arg := range args {
+			if net.ParseIP(arg) == nil {
+				Usagef(cmd, "Invalid IP address %q", arg)
+			}
+		}
+
+		for _, arg := range args {
+			if err := client.IPAMReleaseIP(arg); err != nil {
+				Fatalf("Cannot release %s: %s", arg, err)
+			}
+			fmt.Printf("Released %s\n", arg)
+		}
+	},
+}
+
+func init() {
+	ipamCmd.AddCommand(ipamReleaseCmd)
+}
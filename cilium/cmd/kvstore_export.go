@@ -0,0 +1,73 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/cilium/cilium/pkg/kvstore"
+
+	"github.com/spf13/cobra"
+)
+
+var exportPrefix string
+
+var kvstoreExportCmd = &cobra.Command{
+	Use:   "export [options] [<file>]",
+	Short: "Export all Cilium keys to a snapshot file",
+	Long: `Export all keys below a prefix to a portable snapshot file. The snapshot can
+be imported into an empty kvstore of any backend with 'cilium kvstore import',
+e.g. to migrate between backends or to restore a lost kvstore without
+reallocating security identities. The snapshot is written to stdout if no
+file is given.
+
+The embedded backend can only be accessed by a single process. Stop the agent
+before exporting from or importing into it.
+`,
+	Example: "cilium kvstore export cilium-kvstore.json",
+	Run: func(cmd *cobra.Command, args []string) {
+		setupKvstore()
+
+		snapshot, err := kvstore.ExportSnapshot(exportPrefix)
+		if err != nil {
+			Fatalf("Unable to export keys: %s", err)
+		}
+
+		var w io.Writer = os.Stdout
+		if len(args) > 0 && args[0] != "-" {
+			f, err := os.OpenFile(args[0], os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+			if err != nil {
+				Fatalf("Unable to create snapshot file: %s", err)
+			}
+			defer f.Close()
+			w = f
+		}
+
+		if err := kvstore.WriteSnapshot(w, snapshot); err != nil {
+			Fatalf("Unable to write snapshot: %s", err)
+		}
+
+		if w != os.Stdout {
+			fmt.Printf("Exported %d keys below %s to %s\n", len(snapshot.Pairs), exportPrefix, args[0])
+		}
+	},
+}
+
+func init() {
+	kvstoreCmd.AddCommand(kvstoreExportCmd)
+	kvstoreExportCmd.Flags().StringVar(&exportPrefix, "prefix", kvstore.BaseKeyPrefix, "Prefix of the keys to export")
+}
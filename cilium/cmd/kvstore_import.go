@@ -0,0 +1,96 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cilium/cilium/pkg/kvstore"
+
+	"github.com/spf13/cobra"
+)
+
+var importDryRun bool
+
+var kvstoreImportCmd = &cobra.Command{
+	Use:   "import [options] <file>",
+	Short: "Import keys from a snapshot file",
+	Long: `Import all keys of a snapshot created with 'cilium kvstore export'. The
+kvstore must not contain any key below the prefix of the snapshot. Keys are
+imported without a lease. Agents attach their own keys to a new lease when
+they resync with the kvstore, keys of nodes which no longer exist must be
+removed manually.
+
+With --dry-run, the differences between the snapshot and the kvstore are
+shown instead:
+
+  + key   key only exists in the snapshot
+  ~ key   key exists with a different value
+  - key   key only exists in the kvstore
+`,
+	Example: "cilium kvstore import --dry-run cilium-kvstore.json",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 1 {
+			Usagef(cmd, "Missing snapshot file")
+		}
+
+		f, err := os.Open(args[0])
+		if err != nil {
+			Fatalf("Unable to open snapshot file: %s", err)
+		}
+		snapshot, err := kvstore.ReadSnapshot(f)
+		f.Close()
+		if err != nil {
+			Fatalf("Unable to read snapshot: %s", err)
+		}
+
+		setupKvstore()
+
+		if importDryRun {
+			diff, err := kvstore.DiffSnapshot(snapshot)
+			if err != nil {
+				Fatalf("Unable to compare snapshot: %s", err)
+			}
+			printSnapshotDiff(diff)
+			return
+		}
+
+		if err := kvstore.ImportSnapshot(snapshot); err != nil {
+			Fatalf("Unable to import snapshot: %s", err)
+		}
+		fmt.Printf("Imported %d keys below %s exported from %s at %s\n",
+			len(snapshot.Pairs), snapshot.Prefix, snapshot.Backend, snapshot.Created)
+	},
+}
+
+func init() {
+	kvstoreCmd.AddCommand(kvstoreImportCmd)
+	kvstoreImportCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "Only show the differences between the snapshot and the kvstore")
+}
+
+func printSnapshotDiff(diff *kvstore.SnapshotDiff) {
+	for _, key := range diff.Added {
+		fmt.Printf("+ %s\n", key)
+	}
+	for _, key := range diff.Modified {
+		fmt.Printf("~ %s\n", key)
+	}
+	for _, key := range diff.Removed {
+		fmt.Printf("- %s\n", key)
+	}
+	fmt.Printf("%d to add, %d to modify, %d only in kvstore, %d unchanged\n",
+		len(diff.Added), len(diff.Modified), len(diff.Removed), diff.Unchanged)
+}
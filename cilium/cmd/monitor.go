@@ -113,6 +113,7 @@ func init() {
 	monitorCmd.Flags().Var(&toDst, "to", "Filter by destination endpoint id")
 	monitorCmd.Flags().Var(&related, "related-to", "Filter by either source or destination endpoint id")
 	monitorCmd.Flags().BoolVarP(&verboseMonitor, "verbose", "v", false, "Enable verbose output")
+	monitorCmd.Flags().DurationVar(&since, "since", 0, "Replay buffered events of the given period (e.g. 5m) before live events")
 }
 
 var (
@@ -123,6 +124,7 @@ var (
 	related        = uint16Flags{}
 	verboseMonitor = false
 	verbosity      = INFO
+	since          time.Duration
 )
 
 func setVerbosity() {
@@ -286,6 +288,29 @@ func setupSigHandler() {
 	}()
 }
 
+// dialMonitor connects to the node monitor. If a replay period was requested,
+// the replay socket is used for the first connection so buffered events are
+// received before live events. Reconnections do not replay events again.
+func dialMonitor() (net.Conn, error) {
+	if since <= 0 {
+		return net.Dial("unix", defaults.MonitorSockPath)
+	}
+
+	conn, err := net.Dial("unix", defaults.MonitorReplaySockPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req := payload.ReplayRequest{Since: int64(since)}
+	if err := req.WriteBinary(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("unable to send replay request: %s", err)
+	}
+	since = 0
+
+	return conn, nil
+}
+
 func runMonitor() {
 	setVerbosity()
 	setupSigHandler()
@@ -297,7 +322,7 @@ func runMonitor() {
 	}
 	fmt.Printf("Press Ctrl-C to quit\n")
 start:
-	conn, err := net.Dial("unix", defaults.MonitorSockPath)
+	conn, err := dialMonitor()
 	if err != nil {
 		fmt.Printf("Error: unable to connect to monitor %s\n", err)
 		os.Exit(1)
@@ -18,7 +18,9 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 
+	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/common/types"
 	"github.com/cilium/cilium/pkg/command"
 
@@ -46,11 +48,18 @@ var serviceGetCmd = &cobra.Command{
 		for _, be := range svc.BackendAddresses {
 			if bea, err := types.NewL3n4AddrFromBackendModel(be); err != nil {
 				slice = append(slice, fmt.Sprintf("invalid backend: %+v", be))
+			} else if !time.Time(be.DrainDeadline).IsZero() {
+				slice = append(slice, fmt.Sprintf("%s [Draining until %s]", bea.String(), be.DrainDeadline))
 			} else {
 				slice = append(slice, bea.String())
 			}
 		}
 
+		stats := make([]string, len(svc.BackendAddresses))
+		for i, be := range svc.BackendAddresses {
+			stats[i] = formatServiceStatistics(be.Statistics)
+		}
+
 		if command.OutputJSON() {
 			if err := command.PrintOutput(svc); err != nil {
 				os.Exit(1)
@@ -61,15 +70,24 @@ var serviceGetCmd = &cobra.Command{
 		if fea, err := types.NewL3n4AddrFromModel(svc.FrontendAddress); err != nil {
 			fmt.Fprintf(os.Stderr, "invalid frontend model: %s", err)
 		} else {
-			fmt.Printf("%s =>\n", fea.String())
+			fmt.Printf("%s =>%s\n", fea.String(), formatServiceStatistics(svc.Statistics))
 		}
 
 		for i, be := range slice {
-			fmt.Printf("\t\t%d => %s (%d)\n", i+1, be, svc.ID)
+			fmt.Printf("\t\t%d => %s (%d)%s\n", i+1, be, svc.ID, stats[i])
 		}
 	},
 }
 
+// formatServiceStatistics returns the traffic statistics s to be appended
+// to a frontend or backend or an empty string if s is unset
+func formatServiceStatistics(s *models.ServiceStatistics) string {
+	if s == nil {
+		return ""
+	}
+	return fmt.Sprintf(" [%d packets, %d bytes, %d connections]", s.Packets, s.Bytes, s.Connections)
+}
+
 func init() {
 	serviceCmd.AddCommand(serviceGetCmd)
 	command.AddJSONOutput(serviceGetCmd)
@@ -19,6 +19,7 @@ import (
 	"os"
 	"sort"
 	"text/tabwriter"
+	"time"
 
 	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/common/types"
@@ -51,11 +52,14 @@ func listServices(cmd *cobra.Command, args []string) {
 }
 
 func printServiceList(w *tabwriter.Writer, list []*models.Service) {
-	fmt.Fprintln(w, "ID\tFrontend\tBackend\t")
+	fmt.Fprintln(w, "ID\tFrontend\tType\tAlgorithm\tAffinity\tBackend\t")
 
 	type ServiceOutput struct {
 		ID               int64
 		FrontendAddress  string
+		Type             string
+		Algorithm        string
+		Affinity         string
 		BackendAddresses []string
 	}
 	svcs := []ServiceOutput{}
@@ -80,12 +84,36 @@ func printServiceList(w *tabwriter.Writer, list []*models.Service) {
 			} else {
 				str = fmt.Sprintf("%d => %s", i+1, beA.String())
 			}
+			if be.Health != "" {
+				str += fmt.Sprintf(" [%s]", be.Health)
+			}
+			if !time.Time(be.DrainDeadline).IsZero() {
+				str += fmt.Sprintf(" [Draining until %s]", be.DrainDeadline)
+			}
 			backendAddresses = append(backendAddresses, str)
 		}
 
+		feType := svc.FrontendType
+		if feType == "" {
+			feType = string(types.LBFrontendClusterIP)
+		}
+
+		algorithm := svc.LbAlgorithm
+		if algorithm == "" {
+			algorithm = string(types.LBAlgorithmRandom)
+		}
+
+		affinity := models.ServiceSessionAffinityNone
+		if svc.SessionAffinity == models.ServiceSessionAffinityClientIP {
+			affinity = fmt.Sprintf("%s (%ds)", svc.SessionAffinity, svc.SessionAffinityTimeout)
+		}
+
 		SvcOutput := ServiceOutput{
 			ID:               svc.ID,
 			FrontendAddress:  feA.String(),
+			Type:             feType,
+			Algorithm:        algorithm,
+			Affinity:         affinity,
 			BackendAddresses: backendAddresses,
 		}
 		svcs = append(svcs, SvcOutput)
@@ -106,19 +134,20 @@ func printServiceList(w *tabwriter.Writer, list []*models.Service) {
 		var str string
 
 		if len(service.BackendAddresses) == 0 {
-			str = fmt.Sprintf("%d\t%s\t\t",
-				service.ID, service.FrontendAddress)
+			str = fmt.Sprintf("%d\t%s\t%s\t%s\t%s\t\t",
+				service.ID, service.FrontendAddress, service.Type,
+				service.Algorithm, service.Affinity)
 			fmt.Fprintln(w, str)
 			continue
 		}
 
-		str = fmt.Sprintf("%d\t%s\t%s\t",
-			service.ID, service.FrontendAddress,
-			service.BackendAddresses[0])
+		str = fmt.Sprintf("%d\t%s\t%s\t%s\t%s\t%s\t",
+			service.ID, service.FrontendAddress, service.Type,
+			service.Algorithm, service.Affinity, service.BackendAddresses[0])
 		fmt.Fprintln(w, str)
 
 		for _, bkaddr := range service.BackendAddresses[1:] {
-			str := fmt.Sprintf("\t\t%s\t", bkaddr)
+			str := fmt.Sprintf("\t\t\t\t\t%s\t", bkaddr)
 			fmt.Fprintln(w, str)
 		}
 	}
@@ -21,6 +21,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/common/types"
@@ -29,10 +30,20 @@ import (
 )
 
 var (
-	addRev   bool
-	idU      uint64
-	frontend string
-	backends []string
+	addRev      bool
+	idU         uint64
+	frontend    string
+	backends    []string
+	lbAlgorithm string
+
+	sessionAffinity        bool
+	sessionAffinityTimeout time.Duration
+
+	healthCheck         string
+	healthCheckPort     uint16
+	healthCheckPath     string
+	healthCheckInterval time.Duration
+	healthCheckTimeout  time.Duration
 )
 
 // serviceUpdateCmd represents the service_update command
@@ -50,6 +61,14 @@ func init() {
 	serviceUpdateCmd.Flags().Uint64VarP(&idU, "id", "", 0, "Identifier")
 	serviceUpdateCmd.Flags().StringVarP(&frontend, "frontend", "", "", "Frontend address")
 	serviceUpdateCmd.Flags().StringSliceVarP(&backends, "backends", "", []string{}, "Backend address or addresses followed by optional weight (<IP:Port>[/weight])")
+	serviceUpdateCmd.Flags().StringVarP(&lbAlgorithm, "lb-algorithm", "", string(types.LBAlgorithmRandom), "Backend selection algorithm (random, maglev)")
+	serviceUpdateCmd.Flags().BoolVarP(&sessionAffinity, "session-affinity", "", false, "Send all connections of a client to the same backend")
+	serviceUpdateCmd.Flags().DurationVarP(&sessionAffinityTimeout, "session-affinity-timeout", "", types.DefaultSessionAffinityTimeout, "Time after which an idle client is no longer bound to its backend")
+	serviceUpdateCmd.Flags().StringVarP(&healthCheck, "health-check", "", "", "Health check of the backends (tcp, http)")
+	serviceUpdateCmd.Flags().Uint16VarP(&healthCheckPort, "health-check-port", "", 0, "Port to health check, the port of the backend if 0")
+	serviceUpdateCmd.Flags().StringVarP(&healthCheckPath, "health-check-path", "", "/", "Request path of http health checks")
+	serviceUpdateCmd.Flags().DurationVarP(&healthCheckInterval, "health-check-interval", "", types.DefaultHealthCheckInterval, "Interval between health checks")
+	serviceUpdateCmd.Flags().DurationVarP(&healthCheckTimeout, "health-check-timeout", "", types.DefaultHealthCheckTimeout, "Timeout of a health check")
 }
 
 func parseFrontendAddress(address string) (*models.FrontendAddress, net.IP) {
@@ -74,10 +93,29 @@ func updateService(cmd *cobra.Command, args []string) {
 		ID:               id,
 		FrontendAddress:  fa,
 		BackendAddresses: []*models.BackendAddress{},
+		LbAlgorithm:      lbAlgorithm,
+		SessionAffinity:  models.ServiceSessionAffinityNone,
 		Flags: &models.ServiceFlags{
 			DirectServerReturn: addRev,
 		},
 	}
+	if sessionAffinity {
+		svc.SessionAffinity = models.ServiceSessionAffinityClientIP
+		svc.SessionAffinityTimeout = int64(sessionAffinityTimeout / time.Second)
+	}
+	if healthCheck != "" {
+		svc.HealthCheck = &models.ServiceHealthCheck{
+			Type:     &healthCheck,
+			Port:     healthCheckPort,
+			Path:     healthCheckPath,
+			Interval: int64(healthCheckInterval / time.Second),
+			Timeout:  int64(healthCheckTimeout / time.Second),
+		}
+	}
+
+	if _, err := types.NewLBSVCOptionsFromModel(svc); err != nil {
+		Usagef(cmd, "%s", err)
+	}
 
 	if len(backends) == 0 {
 		fmt.Printf("Reading backend list from stdin...\n")
@@ -19,12 +19,14 @@ import (
 	"fmt"
 	"net"
 	"strings"
+	"time"
 
 	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/pkg/lock"
 	"github.com/cilium/cilium/pkg/logging"
 	"github.com/cilium/cilium/pkg/logging/logfields"
 
+	"github.com/go-openapi/strfmt"
 	"github.com/sirupsen/logrus"
 )
 
@@ -51,17 +53,289 @@ type ServiceID uint16
 type LBBackEnd struct {
 	L3n4Addr
 	Weight uint16
+	// Health is the health of the backend, empty if the service has no
+	// health check
+	Health LBBackEndHealth
+	// DrainDeadline is the time at which the backend is removed from the
+	// service, zero unless the backend is draining. Draining backends
+	// have been removed from the service but remain in the lb maps to
+	// serve their established connections.
+	DrainDeadline time.Time
 }
 
+// IsDraining returns true if the backend only serves its established
+// connections until it is removed from the service.
+func (lbbe *LBBackEnd) IsDraining() bool {
+	return !lbbe.DrainDeadline.IsZero()
+}
+
+// LBBackEndHealth is the health of a backend as determined by the health
+// check of its service.
+type LBBackEndHealth string
+
+const (
+	// LBBackEndHealthUnknown is the health of a backend which has not
+	// been checked often enough to determine its health
+	LBBackEndHealthUnknown = LBBackEndHealth(models.BackendAddressHealthUnknown)
+	// LBBackEndHealthHealthy is the health of a backend passing the
+	// health check
+	LBBackEndHealthHealthy = LBBackEndHealth(models.BackendAddressHealthHealthy)
+	// LBBackEndHealthUnhealthy is the health of a backend failing the
+	// health check. New connections are not sent to the backend.
+	LBBackEndHealthUnhealthy = LBBackEndHealth(models.BackendAddressHealthUnhealthy)
+)
+
 func (lbbe *LBBackEnd) String() string {
 	return fmt.Sprintf("%s, weight: %d", lbbe.L3n4Addr.String(), lbbe.Weight)
 }
 
+// LBAlgorithm is the algorithm used to select the backend of a connection.
+type LBAlgorithm string
+
+const (
+	// LBAlgorithmRandom selects the backend based on the hash of the
+	// packet, optionally following a weighted round robin sequence.
+	LBAlgorithmRandom = LBAlgorithm("random")
+	// LBAlgorithmMaglev selects the backend with a Maglev consistent
+	// hashing lookup table.
+	LBAlgorithmMaglev = LBAlgorithm("maglev")
+)
+
+// NewLBAlgorithm returns the LBAlgorithm with the given name. An empty name
+// is the default algorithm LBAlgorithmRandom.
+func NewLBAlgorithm(name string) (LBAlgorithm, error) {
+	switch strings.ToLower(name) {
+	case "", string(LBAlgorithmRandom):
+		return LBAlgorithmRandom, nil
+	case string(LBAlgorithmMaglev):
+		return LBAlgorithmMaglev, nil
+	default:
+		return "", fmt.Errorf("unknown load-balancing algorithm %q", name)
+	}
+}
+
+// DefaultSessionAffinityTimeout is the session affinity timeout used if no
+// timeout has been configured. It matches the default of Kubernetes.
+const DefaultSessionAffinityTimeout = 3 * time.Hour
+
+// DefaultLBDrainTimeout is the time for which backends removed from a
+// service keep serving their established connections by default. Draining
+// is disabled by default as it adds a map lookup to the load-balancing of
+// new connections.
+const DefaultLBDrainTimeout = time.Duration(0)
+
+// LBFrontendType is the type of the frontend of a service.
+type LBFrontendType string
+
+const (
+	// LBFrontendClusterIP is the virtual IP of a service
+	LBFrontendClusterIP = LBFrontendType(models.ServiceFrontendTypeClusterIP)
+	// LBFrontendNodePort is the node port of a service on an address of
+	// the node
+	LBFrontendNodePort = LBFrontendType(models.ServiceFrontendTypeNodePort)
+	// LBFrontendExternalIP is an external IP of a service
+	LBFrontendExternalIP = LBFrontendType(models.ServiceFrontendTypeExternalIP)
+)
+
+// NewLBFrontendType returns the frontend type with the given name. An empty
+// name selects LBFrontendClusterIP.
+func NewLBFrontendType(name string) (LBFrontendType, error) {
+	switch t := LBFrontendType(name); t {
+	case "":
+		return LBFrontendClusterIP, nil
+	case LBFrontendClusterIP, LBFrontendNodePort, LBFrontendExternalIP:
+		return t, nil
+	}
+	return "", fmt.Errorf("unknown frontend type %q", name)
+}
+
+// LBSVCOptions are the options of a service which apply to all of its
+// backends.
+type LBSVCOptions struct {
+	// FrontendType is the type of the frontend of the service
+	FrontendType LBFrontendType
+
+	// Algorithm is the algorithm used to select the backend of a
+	// connection.
+	Algorithm LBAlgorithm
+
+	// SessionAffinity is true if all connections of a client are sent
+	// to the same backend (ClientIP session affinity)
+	SessionAffinity bool
+
+	// SessionAffinityTimeout is the duration after which an idle client
+	// is no longer bound to its backend
+	SessionAffinityTimeout time.Duration
+
+	// HealthCheck is the health check of the backends
+	HealthCheck LBHealthCheck
+}
+
+// LBHealthCheckType is the type of the health check of a service.
+type LBHealthCheckType string
+
+const (
+	// LBHealthCheckTCP checks that a TCP connection to the backend can
+	// be established
+	LBHealthCheckTCP = LBHealthCheckType(models.ServiceHealthCheckTypeTCP)
+	// LBHealthCheckHTTP checks that the backend responds to a HTTP GET
+	// request with a 2xx or 3xx status code
+	LBHealthCheckHTTP = LBHealthCheckType(models.ServiceHealthCheckTypeHTTP)
+)
+
+const (
+	// DefaultHealthCheckInterval is the default interval between the
+	// health checks of a backend
+	DefaultHealthCheckInterval = 10 * time.Second
+	// DefaultHealthCheckTimeout is the default timeout of a health check
+	DefaultHealthCheckTimeout = 2 * time.Second
+	// DefaultHealthCheckUnhealthyThreshold is the default number of
+	// consecutive failed checks after which a backend is unhealthy
+	DefaultHealthCheckUnhealthyThreshold = 3
+	// DefaultHealthCheckHealthyThreshold is the default number of
+	// consecutive successful checks after which an unhealthy backend is
+	// healthy again
+	DefaultHealthCheckHealthyThreshold = 2
+)
+
+// LBHealthCheck is the health check of the backends of a service. The health
+// check is disabled if Type is empty.
+type LBHealthCheck struct {
+	Type LBHealthCheckType
+	// Port is the port to check, the port of the backend if 0
+	Port uint16
+	// Path is the request path of HTTP checks
+	Path               string
+	Interval           time.Duration
+	Timeout            time.Duration
+	UnhealthyThreshold int
+	HealthyThreshold   int
+}
+
+// IsEnabled returns true if the backends of the service are health checked.
+func (hc *LBHealthCheck) IsEnabled() bool {
+	return hc.Type != ""
+}
+
+// NewLBHealthCheckFromModel returns the health check of the model with the
+// defaults applied. A nil model disables the health check.
+func NewLBHealthCheckFromModel(base *models.ServiceHealthCheck) (LBHealthCheck, error) {
+	hc := LBHealthCheck{}
+	if base == nil {
+		return hc, nil
+	}
+
+	if base.Type == nil {
+		return hc, fmt.Errorf("missing health check type")
+	}
+	switch t := LBHealthCheckType(*base.Type); t {
+	case LBHealthCheckTCP, LBHealthCheckHTTP:
+		hc.Type = t
+	default:
+		return hc, fmt.Errorf("unknown health check type %q", *base.Type)
+	}
+
+	if base.Interval < 0 || base.Timeout < 0 || base.UnhealthyThreshold < 0 || base.HealthyThreshold < 0 {
+		return hc, fmt.Errorf("health check interval, timeout and thresholds must not be negative")
+	}
+
+	hc.Port = base.Port
+	hc.Path = base.Path
+	if hc.Type == LBHealthCheckHTTP && hc.Path == "" {
+		hc.Path = "/"
+	}
+
+	hc.Interval = time.Duration(base.Interval) * time.Second
+	if hc.Interval == 0 {
+		hc.Interval = DefaultHealthCheckInterval
+	}
+	hc.Timeout = time.Duration(base.Timeout) * time.Second
+	if hc.Timeout == 0 {
+		hc.Timeout = DefaultHealthCheckTimeout
+	}
+	if hc.Timeout > hc.Interval {
+		return hc, fmt.Errorf("health check timeout %s exceeds interval %s", hc.Timeout, hc.Interval)
+	}
+
+	hc.UnhealthyThreshold = int(base.UnhealthyThreshold)
+	if hc.UnhealthyThreshold == 0 {
+		hc.UnhealthyThreshold = DefaultHealthCheckUnhealthyThreshold
+	}
+	hc.HealthyThreshold = int(base.HealthyThreshold)
+	if hc.HealthyThreshold == 0 {
+		hc.HealthyThreshold = DefaultHealthCheckHealthyThreshold
+	}
+
+	return hc, nil
+}
+
+// GetModel returns the model of the health check, nil if it is disabled.
+func (hc *LBHealthCheck) GetModel() *models.ServiceHealthCheck {
+	if !hc.IsEnabled() {
+		return nil
+	}
+
+	t := string(hc.Type)
+	return &models.ServiceHealthCheck{
+		Type:               &t,
+		Port:               hc.Port,
+		Path:               hc.Path,
+		Interval:           int64(hc.Interval / time.Second),
+		Timeout:            int64(hc.Timeout / time.Second),
+		UnhealthyThreshold: int64(hc.UnhealthyThreshold),
+		HealthyThreshold:   int64(hc.HealthyThreshold),
+	}
+}
+
+// NewLBSVCOptionsFromModel returns the options of the service model.
+func NewLBSVCOptionsFromModel(base *models.Service) (LBSVCOptions, error) {
+	opts := LBSVCOptions{}
+	if base == nil {
+		return opts, nil
+	}
+
+	frontendType, err := NewLBFrontendType(base.FrontendType)
+	if err != nil {
+		return opts, err
+	}
+	opts.FrontendType = frontendType
+
+	algorithm, err := NewLBAlgorithm(base.LbAlgorithm)
+	if err != nil {
+		return opts, err
+	}
+	opts.Algorithm = algorithm
+
+	switch base.SessionAffinity {
+	case "", models.ServiceSessionAffinityNone:
+	case models.ServiceSessionAffinityClientIP:
+		opts.SessionAffinity = true
+	default:
+		return opts, fmt.Errorf("unknown session affinity %q", base.SessionAffinity)
+	}
+
+	if base.SessionAffinityTimeout < 0 {
+		return opts, fmt.Errorf("invalid session affinity timeout %d", base.SessionAffinityTimeout)
+	}
+	opts.SessionAffinityTimeout = time.Duration(base.SessionAffinityTimeout) * time.Second
+	if opts.SessionAffinity && opts.SessionAffinityTimeout == 0 {
+		opts.SessionAffinityTimeout = DefaultSessionAffinityTimeout
+	}
+
+	opts.HealthCheck, err = NewLBHealthCheckFromModel(base.HealthCheck)
+	if err != nil {
+		return opts, err
+	}
+
+	return opts, nil
+}
+
 // LBSVC is essentially used for the REST API.
 type LBSVC struct {
-	Sha256 string
-	FE     L3n4AddrID
-	BES    []LBBackEnd
+	Sha256  string
+	FE      L3n4AddrID
+	BES     []LBBackEnd
+	Options LBSVCOptions
 }
 
 func (s *LBSVC) GetModel() *models.Service {
@@ -73,8 +347,17 @@ func (s *LBSVC) GetModel() *models.Service {
 	svc := &models.Service{
 		ID:               id,
 		FrontendAddress:  s.FE.GetModel(),
+		FrontendType:     string(s.Options.FrontendType),
 		BackendAddresses: make([]*models.BackendAddress, len(s.BES)),
+		LbAlgorithm:      string(s.Options.Algorithm),
+		SessionAffinity:  models.ServiceSessionAffinityNone,
+	}
+
+	if s.Options.SessionAffinity {
+		svc.SessionAffinity = models.ServiceSessionAffinityClientIP
+		svc.SessionAffinityTimeout = int64(s.Options.SessionAffinityTimeout / time.Second)
 	}
+	svc.HealthCheck = s.Options.HealthCheck.GetModel()
 
 	for i, be := range s.BES {
 		svc.BackendAddresses[i] = be.GetBackendModel()
@@ -83,6 +366,68 @@ func (s *LBSVC) GetModel() *models.Service {
 	return svc
 }
 
+// ActiveBackends returns the backends of the service in the lb maps.
+// Unhealthy backends are left out unless all backends are unhealthy, in
+// which case all backends are returned. Draining backends are included but
+// do not receive new connections.
+func (s *LBSVC) ActiveBackends() []LBBackEnd {
+	active := make([]LBBackEnd, 0, len(s.BES))
+	for _, be := range s.BES {
+		if be.Health != LBBackEndHealthUnhealthy {
+			active = append(active, be)
+		}
+	}
+
+	if len(active) == 0 {
+		return s.BES
+	}
+	return active
+}
+
+// DrainBackends returns the backends of a service changing its backends from
+// prev to bes. Backends of prev which are not part of bes are kept as
+// draining backends until deadline, unless they are draining already.
+// Backends of bes which are draining in prev stop draining. The backends of
+// prev keep their position so that their established connections remain on
+// them, new backends are appended. If bes is empty, no backend is left to
+// take over the new connections of the removed backends, so they are removed
+// right away.
+func DrainBackends(prev, bes []LBBackEnd, deadline time.Time) []LBBackEnd {
+	if len(bes) == 0 {
+		return bes
+	}
+
+	idx := make(map[string]int, len(bes))
+	for i, be := range bes {
+		idx[be.L3n4Addr.String()] = i
+	}
+
+	drained := make([]LBBackEnd, 0, len(prev)+len(bes))
+	added := make([]bool, len(bes))
+	for _, be := range prev {
+		if i, ok := idx[be.L3n4Addr.String()]; ok {
+			if !added[i] {
+				drained = append(drained, bes[i])
+				added[i] = true
+			}
+			continue
+		}
+
+		if !be.IsDraining() {
+			be.DrainDeadline = deadline
+		}
+		drained = append(drained, be)
+	}
+
+	for i, be := range bes {
+		if !added[i] {
+			drained = append(drained, be)
+		}
+	}
+
+	return drained
+}
+
 // SVCMap is a map of the daemon's services. The key is the sha256sum of the LBSVC's FE
 // and the value the LBSVC.
 type SVCMap map[string]LBSVC
@@ -105,6 +450,10 @@ type LoadBalancer struct {
 	K8sServices  map[K8sServiceNamespace]*K8sServiceInfo
 	K8sEndpoints map[K8sServiceNamespace]*K8sServiceEndpoint
 	K8sIngress   map[K8sServiceNamespace]*K8sServiceInfo
+
+	// K8sNodeAddrs are the addresses of all nodes of the cluster on which
+	// the node ports of the K8sServices have been installed
+	K8sNodeAddrs []net.IP
 }
 
 // AddService adds a service to list of loadbalancers and returns true if created.
@@ -173,6 +522,11 @@ type K8sServiceInfo struct {
 	Ports      map[FEPortName]*FEPort
 	Labels     map[string]string
 	Selector   map[string]string
+	Options    LBSVCOptions
+
+	// ExternalIPs are additional frontend IPs on which all ports of the
+	// service are exposed
+	ExternalIPs []net.IP
 }
 
 // IsExternal returns true if the service is expected to serve out-of-cluster endpoints:
@@ -180,6 +534,16 @@ func (si K8sServiceInfo) IsExternal() bool {
 	return len(si.Selector) == 0
 }
 
+// HasNodePort returns true if any port of the service is exposed on a node port
+func (si K8sServiceInfo) HasNodePort() bool {
+	for _, port := range si.Ports {
+		if port.NodePort != 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // NewK8sServiceInfo creates a new K8sServiceInfo with the Ports map initialized.
 func NewK8sServiceInfo(ip net.IP, headless bool, labels map[string]string, selector map[string]string) *K8sServiceInfo {
 	return &K8sServiceInfo{
@@ -188,6 +552,7 @@ func NewK8sServiceInfo(ip net.IP, headless bool, labels map[string]string, selec
 		Ports:      map[FEPortName]*FEPort{},
 		Labels:     labels,
 		Selector:   selector,
+		Options:    LBSVCOptions{Algorithm: LBAlgorithmRandom},
 	}
 }
 
@@ -238,6 +603,12 @@ func (l *L4Addr) DeepCopy() *L4Addr {
 type FEPort struct {
 	*L4Addr
 	ID ServiceID
+	// NodePort is the port on which the port is exposed on the addresses
+	// of each node, 0 if none
+	NodePort uint16
+	// FrontendIDs are the IDs of the node port and external IP frontends
+	// of the port indexed by the frontend address
+	FrontendIDs map[string]ServiceID
 }
 
 // NewFEPort creates a new FEPort with the ID set to 0.
@@ -365,11 +736,16 @@ func (b *LBBackEnd) GetBackendModel() *models.BackendAddress {
 	}
 
 	ip := b.IP.String()
-	return &models.BackendAddress{
+	be := &models.BackendAddress{
 		IP:     &ip,
 		Port:   b.Port,
 		Weight: b.Weight,
+		Health: string(b.Health),
+	}
+	if b.IsDraining() {
+		be.DrainDeadline = strfmt.DateTime(b.DrainDeadline)
 	}
+	return be
 }
 
 // String returns the L3n4Addr in the "IPv4:Port" format for IPv4 and "[IPv6]:Port" format
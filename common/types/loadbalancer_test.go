@@ -15,7 +15,11 @@
 package types
 
 import (
+	"net"
 	"testing"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
 
 	"gopkg.in/check.v1"
 )
@@ -37,3 +41,140 @@ func (s *TypesSuite) TestIsK8ServiceExternal(c *check.C) {
 	si.Selector = map[string]string{"l": "v"}
 	c.Assert(si.IsExternal(), check.Equals, false)
 }
+
+func (s *TypesSuite) TestNewLBSVCOptionsFromModel(c *check.C) {
+	opts, err := NewLBSVCOptionsFromModel(&models.Service{})
+	c.Assert(err, check.IsNil)
+	c.Assert(opts, check.Equals, LBSVCOptions{
+		FrontendType: LBFrontendClusterIP,
+		Algorithm:    LBAlgorithmRandom,
+	})
+
+	opts, err = NewLBSVCOptionsFromModel(&models.Service{
+		LbAlgorithm:     models.ServiceLbAlgorithmMaglev,
+		SessionAffinity: models.ServiceSessionAffinityClientIP,
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(opts, check.Equals, LBSVCOptions{
+		FrontendType:           LBFrontendClusterIP,
+		Algorithm:              LBAlgorithmMaglev,
+		SessionAffinity:        true,
+		SessionAffinityTimeout: DefaultSessionAffinityTimeout,
+	})
+
+	svc := LBSVC{Options: opts}
+	model := svc.GetModel()
+	c.Assert(model.SessionAffinity, check.Equals, models.ServiceSessionAffinityClientIP)
+	c.Assert(model.SessionAffinityTimeout, check.Equals, int64(DefaultSessionAffinityTimeout/time.Second))
+
+	opts, err = NewLBSVCOptionsFromModel(&models.Service{
+		SessionAffinity:        models.ServiceSessionAffinityClientIP,
+		SessionAffinityTimeout: 60,
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(opts.SessionAffinityTimeout, check.Equals, time.Minute)
+
+	opts, err = NewLBSVCOptionsFromModel(&models.Service{FrontendType: models.ServiceFrontendTypeNodePort})
+	c.Assert(err, check.IsNil)
+	c.Assert(opts.FrontendType, check.Equals, LBFrontendNodePort)
+
+	_, err = NewLBSVCOptionsFromModel(&models.Service{FrontendType: "foo"})
+	c.Assert(err, check.Not(check.IsNil))
+	_, err = NewLBSVCOptionsFromModel(&models.Service{SessionAffinity: "foo"})
+	c.Assert(err, check.Not(check.IsNil))
+	_, err = NewLBSVCOptionsFromModel(&models.Service{
+		SessionAffinity:        models.ServiceSessionAffinityClientIP,
+		SessionAffinityTimeout: -1,
+	})
+	c.Assert(err, check.Not(check.IsNil))
+}
+
+func (s *TypesSuite) TestNewLBHealthCheckFromModel(c *check.C) {
+	hc, err := NewLBHealthCheckFromModel(nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(hc.IsEnabled(), check.Equals, false)
+	c.Assert(hc.GetModel(), check.IsNil)
+
+	http := models.ServiceHealthCheckTypeHTTP
+	hc, err = NewLBHealthCheckFromModel(&models.ServiceHealthCheck{Type: &http})
+	c.Assert(err, check.IsNil)
+	c.Assert(hc, check.Equals, LBHealthCheck{
+		Type:               LBHealthCheckHTTP,
+		Path:               "/",
+		Interval:           DefaultHealthCheckInterval,
+		Timeout:            DefaultHealthCheckTimeout,
+		UnhealthyThreshold: DefaultHealthCheckUnhealthyThreshold,
+		HealthyThreshold:   DefaultHealthCheckHealthyThreshold,
+	})
+
+	model := hc.GetModel()
+	c.Assert(*model.Type, check.Equals, http)
+	c.Assert(model.Interval, check.Equals, int64(DefaultHealthCheckInterval/time.Second))
+
+	unknown := "udp"
+	_, err = NewLBHealthCheckFromModel(&models.ServiceHealthCheck{Type: &unknown})
+	c.Assert(err, check.Not(check.IsNil))
+	_, err = NewLBHealthCheckFromModel(&models.ServiceHealthCheck{Type: &http, Interval: 1, Timeout: 5})
+	c.Assert(err, check.Not(check.IsNil))
+}
+
+func (s *TypesSuite) TestActiveBackends(c *check.C) {
+	svc := LBSVC{
+		BES: []LBBackEnd{
+			{Weight: 1, Health: LBBackEndHealthHealthy},
+			{Weight: 2, Health: LBBackEndHealthUnhealthy},
+			{Weight: 3, Health: LBBackEndHealthUnknown},
+		},
+	}
+
+	active := svc.ActiveBackends()
+	c.Assert(active, check.HasLen, 2)
+	c.Assert(active[0].Weight, check.Equals, uint16(1))
+	c.Assert(active[1].Weight, check.Equals, uint16(3))
+
+	// All backends are used if all of them are unhealthy
+	svc.BES[0].Health = LBBackEndHealthUnhealthy
+	svc.BES[2].Health = LBBackEndHealthUnhealthy
+	c.Assert(svc.ActiveBackends(), check.HasLen, 3)
+}
+
+func (s *TypesSuite) TestDrainBackends(c *check.C) {
+	backend := func(ip string) LBBackEnd {
+		be, err := NewLBBackEnd(TCP, net.ParseIP(ip), 80, 0)
+		c.Assert(err, check.IsNil)
+		return *be
+	}
+	addrs := func(bes []LBBackEnd) []string {
+		s := make([]string, len(bes))
+		for i, be := range bes {
+			s[i] = be.L3n4Addr.String()
+		}
+		return s
+	}
+
+	deadline := time.Unix(1000, 0)
+	prev := []LBBackEnd{backend("10.0.0.1"), backend("10.0.0.2"), backend("10.0.0.3")}
+
+	// Removed backends keep their position and drain, new backends are
+	// appended
+	bes := DrainBackends(prev, []LBBackEnd{backend("10.0.0.4"), backend("10.0.0.3"), backend("10.0.0.1")}, deadline)
+	c.Assert(addrs(bes), check.DeepEquals, []string{"10.0.0.1:80", "10.0.0.2:80", "10.0.0.3:80", "10.0.0.4:80"})
+	c.Assert(bes[0].IsDraining(), check.Equals, false)
+	c.Assert(bes[1].IsDraining(), check.Equals, true)
+	c.Assert(bes[1].DrainDeadline, check.Equals, deadline)
+	c.Assert(bes[3].IsDraining(), check.Equals, false)
+
+	// Draining backends keep their deadline
+	bes = DrainBackends(bes, []LBBackEnd{backend("10.0.0.4")}, time.Unix(2000, 0))
+	c.Assert(addrs(bes), check.DeepEquals, []string{"10.0.0.1:80", "10.0.0.2:80", "10.0.0.3:80", "10.0.0.4:80"})
+	c.Assert(bes[0].DrainDeadline, check.Equals, time.Unix(2000, 0))
+	c.Assert(bes[1].DrainDeadline, check.Equals, deadline)
+
+	// Backends which are added back stop draining
+	bes = DrainBackends(bes, []LBBackEnd{backend("10.0.0.2"), backend("10.0.0.4")}, deadline)
+	c.Assert(bes[1].IsDraining(), check.Equals, false)
+	c.Assert(bes[3].IsDraining(), check.Equals, false)
+
+	// Backends are not drained if no backend is left
+	c.Assert(DrainBackends(bes, nil, deadline), check.HasLen, 0)
+}
@@ -18,9 +18,11 @@ import (
 	"net"
 	"os"
 	"runtime"
+	"time"
 
 	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/daemon/options"
+	"github.com/cilium/cilium/pkg/flowexport"
 	"github.com/cilium/cilium/pkg/lock"
 	"github.com/cilium/cilium/pkg/option"
 )
@@ -60,6 +62,29 @@ type Config struct {
 	IPv4Disabled    bool       // Disable IPv4 allocation
 	LBInterface     string     // Set with name of the interface to loadbalance packets from
 
+	// LBDrainTimeout is the time for which backends removed from a
+	// service keep serving their established connections. Backends are
+	// removed immediately if set to 0.
+	LBDrainTimeout time.Duration
+
+	// EnableMaglev compiles the Maglev backend selection into the
+	// datapath. Services requesting the Maglev algorithm fall back to the
+	// default algorithm if disabled.
+	EnableMaglev bool
+
+	// EnableSessionAffinity compiles session affinity into the datapath.
+	// The session affinity of services is ignored if disabled.
+	EnableSessionAffinity bool
+
+	// EnableLBStats compiles the traffic statistics of service backends
+	// into the datapath. No statistics are reported if disabled.
+	EnableLBStats bool
+
+	// KVStoreLocklessAllocation allocates identities and address blocks
+	// without kvstore locks if supported by the kvstore backend. Must only
+	// be enabled once all agents support lockless allocation.
+	KVStoreLocklessAllocation bool
+
 	Tunnel string // Tunnel mode
 
 	DryMode       bool // Do not create BPF maps, devices, ..
@@ -87,6 +112,22 @@ type Config struct {
 
 	// Monitor contains the configuration for the node monitor.
 	Monitor *models.MonitorStatus
+
+	// MonitorSlowConsumerPolicy is the policy applied by the node monitor
+	// to listeners which cannot keep up with the event rate
+	MonitorSlowConsumerPolicy string
+
+	// FlowExport is the configuration of the export of conntrack entries
+	// as flow records. The export is disabled if no collector is set.
+	FlowExport flowexport.Config
+
+	// FlowGraph enables the aggregation of observed flows into a
+	// dependency graph of identities and services
+	FlowGraph bool
+
+	// FlowStoreSize is the number of recent flows stored per endpoint. The
+	// flow store is disabled if set to 0.
+	FlowStoreSize int
 }
 
 func NewConfig() *Config {
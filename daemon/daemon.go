@@ -42,10 +42,13 @@ import (
 	"github.com/cilium/cilium/pkg/byteorder"
 	"github.com/cilium/cilium/pkg/endpoint"
 	"github.com/cilium/cilium/pkg/endpointmanager"
+	"github.com/cilium/cilium/pkg/flowgraph"
+	"github.com/cilium/cilium/pkg/flowstore"
 	"github.com/cilium/cilium/pkg/identity"
 	"github.com/cilium/cilium/pkg/ipam"
 	"github.com/cilium/cilium/pkg/ipcache"
 	"github.com/cilium/cilium/pkg/k8s"
+	"github.com/cilium/cilium/pkg/kvstore"
 	"github.com/cilium/cilium/pkg/labels"
 	"github.com/cilium/cilium/pkg/lock"
 	"github.com/cilium/cilium/pkg/logging"
@@ -106,6 +109,12 @@ type Daemon struct {
 	nodeMonitor  *monitorLaunch.NodeMonitor
 	ciliumHealth *health.CiliumHealth
 
+	// flowGraph aggregates observed flows, nil if disabled
+	flowGraph *flowgraph.Graph
+
+	// flowStore keeps the recent flows of each endpoint, nil if disabled
+	flowStore *flowstore.Store
+
 	// k8sAPIs is a set of k8s API in use. They are setup in EnableK8sWatcher,
 	// and may be disabled while the agent runs.
 	// This is on this object, instead of a global, because EnableK8sWatcher is
@@ -602,12 +611,18 @@ func (d *Daemon) installIptablesRules() error {
 		}
 
 		// Masquerade all traffic from node prefix not going to node prefix
-		// which is not going over the tunnel device
+		// which is not going over the tunnel device. With cluster-wide
+		// IPAM, the addresses of the node are spread over the cluster
+		// range.
+		masqRange := node.GetIPv4AllocRange()
+		if ipam.ClusterPoolEnabled() {
+			masqRange = node.GetIPv4ClusterRange()
+		}
 		if err := runProg("iptables", []string{
 			"-t", "nat",
 			"-A", "CILIUM_POST",
-			"-s", node.GetIPv4AllocRange().String(),
-			"!", "-d", node.GetIPv4AllocRange().String(),
+			"-s", masqRange.String(),
+			"!", "-d", masqRange.String(),
 			"!", "-o", "cilium_+",
 			"-m", "comment", "--comment", "cilium masquerade non-cluster",
 			"-j", "MASQUERADE"}, false); err != nil {
@@ -810,6 +825,19 @@ func (d *Daemon) init() error {
 	fmt.Fprintf(fw, "#define WORLD_ID %d\n", identity.GetReservedID(labels.IDNameWorld))
 	fmt.Fprintf(fw, "#define CLUSTER_ID %d\n", identity.GetReservedID(labels.IDNameCluster))
 	fmt.Fprintf(fw, "#define LB_RR_MAX_SEQ %d\n", lbmap.MaxSeq)
+	fmt.Fprintf(fw, "#define LB_MAGLEV_TABLE_SIZE %d\n", lbmap.MaglevTableSize)
+	if d.conf.EnableMaglev {
+		fw.WriteString("#define ENABLE_MAGLEV\n")
+	}
+	if d.conf.EnableSessionAffinity {
+		fw.WriteString("#define ENABLE_SESSION_AFFINITY\n")
+	}
+	if d.conf.EnableLBStats {
+		fw.WriteString("#define ENABLE_LB_STATS\n")
+	}
+	if d.conf.LBDrainTimeout > 0 {
+		fw.WriteString("#define ENABLE_LB_DRAIN\n")
+	}
 
 	fmt.Fprintf(fw, "#define TUNNEL_ENDPOINT_MAP_SIZE %d\n", tunnel.MaxEntries)
 	fmt.Fprintf(fw, "#define ENDPOINTS_MAP_SIZE %d\n", lxcmap.MaxKeys)
@@ -857,6 +885,21 @@ func (d *Daemon) init() error {
 		if _, err := lbmap.RRSeq6Map.OpenOrCreate(); err != nil {
 			return err
 		}
+		if _, err := lbmap.Maglev6Map.OpenOrCreate(); err != nil {
+			return err
+		}
+		if _, err := lbmap.AffinityMatchMap.OpenOrCreate(); err != nil {
+			return err
+		}
+		if _, err := lbmap.Affinity6Map.OpenOrCreate(); err != nil {
+			return err
+		}
+		if _, err := lbmap.Drain6Map.OpenOrCreate(); err != nil {
+			return err
+		}
+		if _, err := lbmap.Stats6Map.OpenOrCreate(); err != nil {
+			return err
+		}
 		if !d.conf.IPv4Disabled {
 			if _, err := lbmap.Service4Map.OpenOrCreate(); err != nil {
 				return err
@@ -867,6 +910,18 @@ func (d *Daemon) init() error {
 			if _, err := lbmap.RRSeq4Map.OpenOrCreate(); err != nil {
 				return err
 			}
+			if _, err := lbmap.Maglev4Map.OpenOrCreate(); err != nil {
+				return err
+			}
+			if _, err := lbmap.Affinity4Map.OpenOrCreate(); err != nil {
+				return err
+			}
+			if _, err := lbmap.Drain4Map.OpenOrCreate(); err != nil {
+				return err
+			}
+			if _, err := lbmap.Stats4Map.OpenOrCreate(); err != nil {
+				return err
+			}
 		}
 		// Clean all lb entries
 		if !d.conf.RestoreState {
@@ -884,6 +939,21 @@ func (d *Daemon) init() error {
 			if err := lbmap.RRSeq6Map.DeleteAll(); err != nil {
 				return err
 			}
+			if err := lbmap.Maglev6Map.DeleteAll(); err != nil {
+				return err
+			}
+			if err := lbmap.AffinityMatchMap.DeleteAll(); err != nil {
+				return err
+			}
+			if err := lbmap.Affinity6Map.DeleteAll(); err != nil {
+				return err
+			}
+			if err := lbmap.Drain6Map.DeleteAll(); err != nil {
+				return err
+			}
+			if err := lbmap.Stats6Map.DeleteAll(); err != nil {
+				return err
+			}
 
 			if !d.conf.IPv4Disabled {
 				if err := lbmap.Service4Map.DeleteAll(); err != nil {
@@ -892,6 +962,18 @@ func (d *Daemon) init() error {
 				if err := lbmap.RRSeq4Map.DeleteAll(); err != nil {
 					return err
 				}
+				if err := lbmap.Maglev4Map.DeleteAll(); err != nil {
+					return err
+				}
+				if err := lbmap.Affinity4Map.DeleteAll(); err != nil {
+					return err
+				}
+				if err := lbmap.Drain4Map.DeleteAll(); err != nil {
+					return err
+				}
+				if err := lbmap.Stats4Map.DeleteAll(); err != nil {
+					return err
+				}
 			}
 		}
 	}
@@ -981,6 +1063,29 @@ func NewDaemon(c *Config) (*Daemon, error) {
 		node.SetIPv4AllocRange(net)
 	}
 
+	if ipamClusterRange != "" {
+		if v4Prefix != AutoCIDR {
+			log.Fatal("--ipv4-range cannot be combined with --ipam-cluster-range")
+		}
+
+		_, clusterRange, err := net.ParseCIDR(ipamClusterRange)
+		if err != nil {
+			log.WithError(err).WithField(logfields.V4Prefix, ipamClusterRange).Fatal("Invalid IPAM cluster range")
+		}
+
+		primaryBlock, err := ipam.InitClusterPool(clusterRange, ipamBlockSize)
+		if err != nil {
+			log.WithError(err).WithField(logfields.V4Prefix, clusterRange).Fatal("Unable to initialize cluster-wide IPAM")
+		}
+
+		// The primary block determines the tunnel map key of all
+		// blocks, the whole cluster range is routed to cilium_host
+		ones, _ := clusterRange.Mask.Size()
+		node.SetIPv4AllocRange(primaryBlock)
+		node.SetIPv4ClusterCidrMaskSize(ones)
+		node.AddAuxPrefix(clusterRange)
+	}
+
 	if v4ServicePrefix != AutoCIDR {
 		_, ipnet, err := net.ParseCIDR(v4ServicePrefix)
 		if err != nil {
@@ -1028,6 +1133,24 @@ func NewDaemon(c *Config) (*Daemon, error) {
 		log.WithError(err).Fatal("IPAM init failed")
 	}
 
+	for name, value := range ipamPools {
+		ipv4Range, ipv6Range, err := ipam.ParsePoolRanges(value)
+		if err != nil {
+			log.WithError(err).WithField("pool", name).Fatal("Invalid IPAM pool")
+		}
+
+		if err := ipam.AddPool(name, ipv4Range, ipv6Range); err != nil {
+			log.WithError(err).WithField("pool", name).Fatal("Unable to add IPAM pool")
+		}
+	}
+
+	// Addresses allocated by the previous run remain reserved until they
+	// have been reconciled with the restored endpoints
+	ipamStatePath := filepath.Join(d.conf.StateDir, ipam.StateFileName)
+	if err := ipam.InitState(ipamStatePath, c.RestoreState); err != nil {
+		log.WithError(err).WithField(logfields.Path, ipamStatePath).Warn("Unable to restore IPAM state")
+	}
+
 	if err := node.ValidatePostInit(); err != nil {
 		log.WithError(err).Fatal("postinit failed")
 	}
@@ -1055,9 +1178,13 @@ func NewDaemon(c *Config) (*Daemon, error) {
 	// Start watcher for endpoint IP --> identity mappings in key-value store.
 	ipcache.InitIPIdentityWatcher(&d)
 
+	// Re-create endpoint IP --> identity mappings when the kvstore lease
+	// they are attached to has expired.
+	kvstore.RegisterResync("endpoint-ip-identities", syncEndpointIPIdentities)
+
 	if !d.conf.IPv4Disabled {
 		// Allocate IPv4 service loopback IP
-		loopbackIPv4, _, err := ipam.AllocateNext("ipv4")
+		loopbackIPv4, _, err := ipam.AllocateNext("ipv4", ipam.OwnerLoopback)
 		if err != nil {
 			return nil, fmt.Errorf("Unable to reserve IPv4 loopback address: %s", err)
 		}
@@ -1070,6 +1197,11 @@ func NewDaemon(c *Config) (*Daemon, error) {
 		return nil, err
 	}
 
+	// The flow graph and store are fed by the access log of the proxy and
+	// must be created before the proxy is started
+	d.startFlowGraph()
+	d.startFlowStore()
+
 	// FIXME: Make configurable
 	d.l7Proxy = proxy.StartProxySupport(10000, 20000, d.conf.RunDir)
 
@@ -1087,10 +1219,17 @@ func NewDaemon(c *Config) (*Daemon, error) {
 		containerd.IgnoreRunningContainers()
 	}
 
+	d.reconcileIPAMState()
+	d.startIPAMMetrics()
+	d.startLBAffinityGC()
+	d.startLBHealthChecks()
+	d.startLBDrain()
+	d.startLBStats()
+
 	d.collectStaleMapGarbage()
 
 	// Allocate health endpoint IPs after restoring state
-	health4, health6, err := ipam.AllocateNext("")
+	health4, health6, err := ipam.AllocateNext("", ipam.OwnerHealth)
 	if err != nil {
 		log.WithError(err).Fatal("Error while allocating cilium-health IP")
 	}
@@ -1234,8 +1373,9 @@ func (h *patchConfig) Handle(params PatchConfigParams) middleware.Responder {
 
 	if numPagesEntry, ok := params.Configuration.Mutable["MonitorNumPages"]; ok {
 		nmArgs := d.nodeMonitor.GetArgs()
-		if len(nmArgs) == 0 || nmArgs[0] != numPagesEntry {
-			args := []string{"--num-pages %s", numPagesEntry}
+		if len(nmArgs) < 2 || nmArgs[1] != numPagesEntry {
+			args := []string{"--num-pages", numPagesEntry,
+				"--slow-consumer-policy", d.conf.MonitorSlowConsumerPolicy}
 			d.nodeMonitor.Restart(args)
 		}
 		if len(params.Configuration.Mutable) == 0 {
@@ -1405,6 +1545,12 @@ func (d *Daemon) SendNotification(typ monitor.AgentNotification, text string) er
 
 // NewProxyLogRecord is invoked by the proxy accesslog on each new access log entry
 func (d *Daemon) NewProxyLogRecord(l *logger.LogRecord) error {
+	if d.flowGraph != nil {
+		d.flowGraph.AddLogRecord(&l.LogRecord, time.Now())
+	}
+	if d.flowStore != nil {
+		d.flowStore.AddLogRecord(&l.LogRecord, time.Now())
+	}
 	return d.nodeMonitor.SendEvent(monitor.MessageTypeAccessLog, l.LogRecord)
 }
 
@@ -29,6 +29,10 @@ const (
 	//StateDir is the default path for the state directory relative to RuntimePath
 	StateDir = "state"
 
+	// EmbeddedKvstoreDB is the name of the database file of the embedded
+	// kvstore backend relative to the state directory
+	EmbeddedKvstoreDB = "kvstore.db"
+
 	// BpfDir is the default path for template files relative to LibDir
 	BpfDir = "bpf"
 
@@ -45,6 +49,10 @@ const (
 	// between multiple monitors.
 	MonitorSockPath = RuntimePath + "/monitor.sock"
 
+	// MonitorReplaySockPath is the path to the UNIX domain socket used by
+	// monitors which request a replay of buffered events before live ones.
+	MonitorReplaySockPath = RuntimePath + "/monitor-replay.sock"
+
 	// PidFilePath is the path to the pid file for the agent.
 	PidFilePath = RuntimePath + "/cilium.pid"
 
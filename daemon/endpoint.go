@@ -19,6 +19,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
 	"sync"
 	"time"
 
@@ -160,6 +161,15 @@ func (d *Daemon) createEndpoint(epTemplate *models.EndpointChangeRequest, id str
 		return PutEndpointIDFailedCode, err
 	}
 
+	// The addresses have been allocated via the API before, the endpoint
+	// is their owner from now on
+	if ep.IPv4 != nil {
+		ipam.SetOwner(ep.IPv4.IP(), ipam.EndpointOwner(ep.ID))
+	}
+	if ep.IPv6 != nil {
+		ipam.SetOwner(ep.IPv6.IP(), ipam.EndpointOwner(ep.ID))
+	}
+
 	add := labels.NewLabelsFromModel(lbls)
 
 	if len(add) > 0 {
@@ -370,6 +380,10 @@ func (d *Daemon) deleteEndpointQuiet(ep *endpoint.Endpoint) []error {
 	// listed or queued for rebuilds.
 	endpointmanager.Remove(ep)
 
+	if d.flowStore != nil {
+		d.flowStore.RemoveEndpoint(ep.ID)
+	}
+
 	// If dry mode is enabled, no changes to BPF maps are performed
 	if !d.DryModeEnabled() {
 		errors := lxcmap.DeleteElement(ep)
@@ -571,6 +585,47 @@ func (h *getEndpointIDLabels) Handle(params GetEndpointIDLabelsParams) middlewar
 	return NewGetEndpointIDLabelsOK().WithPayload(&cfg)
 }
 
+type getEndpointIDIdentityExplain struct {
+	d *Daemon
+}
+
+func NewGetEndpointIDIdentityExplainHandler(d *Daemon) GetEndpointIDIdentityExplainHandler {
+	return &getEndpointIDIdentityExplain{d: d}
+}
+
+func (h *getEndpointIDIdentityExplain) Handle(params GetEndpointIDIdentityExplainParams) middleware.Responder {
+	log.WithField(logfields.Params, logfields.Repr(params)).Debug("GET /endpoint/{id}/identity/explain request")
+
+	ep, err := endpointmanager.Lookup(params.ID)
+	if err != nil {
+		return apierror.Error(GetEndpointIDIdentityExplainInvalidCode, err)
+	}
+	if ep == nil {
+		return NewGetEndpointIDIdentityExplainNotFound()
+	}
+
+	explanation := ep.GetIdentityExplanationModel()
+
+	if explanation.Identity != nil {
+		for _, other := range endpointmanager.GetEndpoints() {
+			if other == ep {
+				continue
+			}
+
+			other.Mutex.RLock()
+			if other.GetIdentity() == identity.NumericIdentity(explanation.Identity.ID) {
+				explanation.SharedWith = append(explanation.SharedWith, int64(other.ID))
+			}
+			other.Mutex.RUnlock()
+		}
+		sort.Slice(explanation.SharedWith, func(i, j int) bool {
+			return explanation.SharedWith[i] < explanation.SharedWith[j]
+		})
+	}
+
+	return NewGetEndpointIDIdentityExplainOK().WithPayload(explanation)
+}
+
 type getEndpointIDLog struct {
 	d *Daemon
 }
@@ -794,3 +849,25 @@ func (d *Daemon) OnIPIdentityCacheGC() {
 			RunInterval: time.Duration(5) * time.Minute,
 		})
 }
+
+// syncEndpointIPIdentities writes the IP to security identity mappings of all
+// endpoints to the kvstore. It is called after the kvstore lease the mappings
+// are attached to has expired.
+func syncEndpointIPIdentities() (int, error) {
+	synced, failed := 0, 0
+	for _, ep := range endpointmanager.GetEndpoints() {
+		n, err := ep.SyncIPIdentityMappings()
+		synced += n
+		if err != nil {
+			log.WithError(err).WithField(logfields.EndpointID, ep.ID).
+				Warning("Unable to re-create IP to identity mapping")
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return synced, fmt.Errorf("unable to re-create IP to identity mappings of %d endpoints", failed)
+	}
+
+	return synced, nil
+}
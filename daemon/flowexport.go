@@ -0,0 +1,72 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net"
+
+	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/controller"
+	"github.com/cilium/cilium/pkg/endpointmanager"
+	"github.com/cilium/cilium/pkg/flowexport"
+	"github.com/cilium/cilium/pkg/identity"
+	"github.com/cilium/cilium/pkg/ipcache"
+	"github.com/cilium/cilium/pkg/maps/ctmap"
+
+	"github.com/sirupsen/logrus"
+)
+
+// dumpConntrackFlows returns the entries of all CT maps together with the
+// current time of the BPF monotonic clock in seconds
+func (d *Daemon) dumpConntrackFlows() ([]ctmap.FlowEntry, uint32, error) {
+	t, err := bpf.GetMtime()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	flows, err := endpointmanager.DumpConntrackFlows(!d.conf.IPv4Disabled, true)
+	return flows, uint32(t / 1000000000), err
+}
+
+func lookupIdentityByIP(ip net.IP) (identity.NumericIdentity, bool) {
+	return ipcache.IPIdentityCache.LookupByIP(ip.String())
+}
+
+// startFlowExport starts the periodic export of the CT entries as flow
+// records if a collector has been configured
+func (d *Daemon) startFlowExport() error {
+	if d.conf.FlowExport.Collector == "" {
+		return nil
+	}
+
+	exporter, err := flowexport.NewExporter(d.conf.FlowExport, d.dumpConntrackFlows, lookupIdentityByIP)
+	if err != nil {
+		return err
+	}
+
+	controller.NewManager().UpdateController("flow-export",
+		controller.ControllerParams{
+			DoFunc:      exporter.Export,
+			StopFunc:    exporter.Close,
+			RunInterval: exporter.Interval(),
+		})
+
+	log.WithFields(logrus.Fields{
+		"collector": d.conf.FlowExport.Collector,
+		"protocol":  d.conf.FlowExport.Protocol,
+	}).Info("Exporting conntrack entries as flow records")
+
+	return nil
+}
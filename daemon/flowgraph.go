@@ -0,0 +1,147 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	. "github.com/cilium/cilium/api/v1/server/restapi/flows"
+	"github.com/cilium/cilium/pkg/apierror"
+	"github.com/cilium/cilium/pkg/controller"
+	"github.com/cilium/cilium/pkg/flowgraph"
+	"github.com/cilium/cilium/pkg/identity"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+
+	"github.com/go-openapi/runtime/middleware"
+)
+
+const (
+	// flowGraphInterval is the interval in which the CT entries are
+	// added to the flow graph and expired edges are removed
+	flowGraphInterval = 10 * time.Second
+)
+
+// serviceIndex maps frontend and backend IPs of services to the service name
+type serviceIndex struct {
+	mutex lock.RWMutex
+	ips   map[string]string
+}
+
+// update rebuilds the index from the Kubernetes services of the loadbalancer.
+// If an IP belongs to multiple services, the lexically smallest service name
+// is used.
+func (idx *serviceIndex) update(d *Daemon) {
+	ips := map[string]string{}
+	add := func(ip, name string) {
+		if old, ok := ips[ip]; !ok || name < old {
+			ips[ip] = name
+		}
+	}
+
+	d.loadBalancer.K8sMU.Lock()
+	for svc, info := range d.loadBalancer.K8sServices {
+		if info.FEIP != nil {
+			add(info.FEIP.String(), svc.Namespace+"/"+svc.ServiceName)
+		}
+	}
+	for svc, ep := range d.loadBalancer.K8sEndpoints {
+		for ip := range ep.BEIPs {
+			add(ip, svc.Namespace+"/"+svc.ServiceName)
+		}
+	}
+	d.loadBalancer.K8sMU.Unlock()
+
+	idx.mutex.Lock()
+	idx.ips = ips
+	idx.mutex.Unlock()
+}
+
+func (idx *serviceIndex) lookup(ip net.IP) string {
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+	return idx.ips[ip.String()]
+}
+
+func lookupLabelsByIdentity(id identity.NumericIdentity) []string {
+	if ident := identity.LookupIdentityByID(id); ident != nil {
+		return ident.Labels.GetModel()
+	}
+	return nil
+}
+
+// startFlowGraph starts aggregating trace and drop notifications, CT entries
+// and L7 access log records into the flow graph if enabled
+func (d *Daemon) startFlowGraph() {
+	if !d.conf.FlowGraph {
+		return
+	}
+
+	services := &serviceIndex{}
+	services.update(d)
+	d.flowGraph = flowgraph.NewGraph(lookupIdentityByIP, services.lookup)
+
+	controller.NewManager().UpdateController("flow-graph",
+		controller.ControllerParams{
+			DoFunc: func() error {
+				services.update(d)
+
+				flows, _, err := d.dumpConntrackFlows()
+				if err != nil {
+					return err
+				}
+
+				now := time.Now()
+				d.flowGraph.UpdateConnections(flows, now)
+				d.flowGraph.Expire(now.Add(-flowgraph.DefaultRetention))
+				return nil
+			},
+			RunInterval: flowGraphInterval,
+		})
+
+	log.Info("Aggregating observed flows into flow graph")
+}
+
+type getFlowsGraph struct {
+	daemon *Daemon
+}
+
+// NewGetFlowsGraphHandler returns the flow graph endpoint handler for the
+// agent
+func NewGetFlowsGraphHandler(d *Daemon) GetFlowsGraphHandler {
+	return &getFlowsGraph{daemon: d}
+}
+
+func (h *getFlowsGraph) Handle(params GetFlowsGraphParams) middleware.Responder {
+	log.WithField(logfields.Params, logfields.Repr(params)).Debug("GET /flows/graph request")
+
+	if h.daemon.flowGraph == nil {
+		return NewGetFlowsGraphDisabled()
+	}
+
+	var since time.Time
+	if params.Since != nil {
+		duration, err := time.ParseDuration(*params.Since)
+		if err != nil || duration < 0 {
+			return apierror.Error(GetFlowsGraphInvalidCode,
+				fmt.Errorf("invalid duration %q", *params.Since))
+		}
+		since = time.Now().Add(-duration)
+	}
+
+	return NewGetFlowsGraphOK().WithPayload(h.daemon.flowGraph.GetModel(since, lookupLabelsByIdentity))
+}
@@ -0,0 +1,88 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	. "github.com/cilium/cilium/api/v1/server/restapi/flows"
+	"github.com/cilium/cilium/pkg/apierror"
+	"github.com/cilium/cilium/pkg/flowstore"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/node"
+
+	"github.com/go-openapi/runtime/middleware"
+)
+
+// startFlowStore starts recording the recent flows of each endpoint if
+// enabled
+func (d *Daemon) startFlowStore() {
+	if d.conf.FlowStoreSize <= 0 {
+		return
+	}
+
+	nodeAddress := &models.FlowNodeAddress{}
+	if ipv4 := node.GetExternalIPv4(); ipv4 != nil {
+		nodeAddress.IPV4 = ipv4.String()
+	}
+	if ipv6 := node.GetIPv6(); ipv6 != nil {
+		nodeAddress.IPV6 = ipv6.String()
+	}
+	d.flowStore = flowstore.NewStore(d.conf.FlowStoreSize, nodeAddress)
+
+	log.WithField("size", d.conf.FlowStoreSize).Info("Recording recent flows of endpoints")
+}
+
+type getFlows struct {
+	daemon *Daemon
+}
+
+// NewGetFlowsHandler returns the recent flows endpoint handler for the agent
+func NewGetFlowsHandler(d *Daemon) GetFlowsHandler {
+	return &getFlows{daemon: d}
+}
+
+func (h *getFlows) Handle(params GetFlowsParams) middleware.Responder {
+	log.WithField(logfields.Params, logfields.Repr(params)).Debug("GET /flows request")
+
+	if h.daemon.flowStore == nil {
+		return NewGetFlowsDisabled()
+	}
+
+	filter := flowstore.Filter{}
+	if params.Endpoint != nil {
+		if *params.Endpoint < 0 || *params.Endpoint > 0xffff {
+			return apierror.Error(GetFlowsInvalidCode,
+				fmt.Errorf("invalid endpoint ID %d", *params.Endpoint))
+		}
+		id := uint16(*params.Endpoint)
+		filter.Endpoint = &id
+	}
+	if params.Verdict != nil {
+		filter.Verdict = *params.Verdict
+	}
+	if params.Since != nil {
+		duration, err := time.ParseDuration(*params.Since)
+		if err != nil || duration < 0 {
+			return apierror.Error(GetFlowsInvalidCode,
+				fmt.Errorf("invalid duration %q", *params.Since))
+		}
+		filter.Since = time.Now().Add(-duration)
+	}
+
+	return NewGetFlowsOK().WithPayload(h.daemon.flowStore.List(filter, lookupLabelsByIdentity))
+}
@@ -17,9 +17,13 @@ package main
 import (
 	"github.com/cilium/cilium/api/v1/models"
 	. "github.com/cilium/cilium/api/v1/server/restapi/policy"
+	"github.com/cilium/cilium/pkg/apierror"
 	"github.com/cilium/cilium/pkg/identity"
+	"github.com/cilium/cilium/pkg/k8s"
+	"github.com/cilium/cilium/pkg/kvstore/allocator"
 	"github.com/cilium/cilium/pkg/labels"
 	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/node"
 
 	"github.com/go-openapi/runtime/middleware"
 )
@@ -65,3 +69,61 @@ func (h *getIdentityID) Handle(params GetIdentityIDParams) middleware.Responder
 
 	return NewGetIdentityIDOK().WithPayload(identity.GetModel())
 }
+
+// staleNodeFunc returns a function reporting whether the node owning an
+// identity reference is no longer part of the cluster. The list of nodes is
+// only known if Kubernetes is enabled, otherwise nil is returned.
+func (d *Daemon) staleNodeFunc() allocator.StaleNodeFunc {
+	if !k8s.IsEnabled() {
+		return nil
+	}
+
+	nodes := node.GetNodes()
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	alive := map[string]struct{}{d.GetNodeSuffix(): {}}
+	for _, n := range nodes {
+		for _, addr := range n.IPAddresses {
+			alive[addr.IP.String()] = struct{}{}
+		}
+	}
+
+	return func(suffix string) bool {
+		_, ok := alive[suffix]
+		return !ok
+	}
+}
+
+type getIdentityGC struct {
+	daemon *Daemon
+}
+
+func newGetIdentityGCHandler(d *Daemon) GetIdentityGcHandler { return &getIdentityGC{daemon: d} }
+
+func (h *getIdentityGC) Handle(params GetIdentityGcParams) middleware.Responder {
+	log.WithField(logfields.Params, logfields.Repr(params)).Debug("GET /identity/gc request")
+
+	report, err := identity.GetGCReport(h.daemon.staleNodeFunc())
+	if err != nil {
+		return apierror.Error(GetIdentityGcUnreachableCode, err)
+	}
+
+	return NewGetIdentityGcOK().WithPayload(report)
+}
+
+type postIdentityGC struct{}
+
+func newPostIdentityGCHandler(d *Daemon) PostIdentityGcHandler { return &postIdentityGC{} }
+
+func (h *postIdentityGC) Handle(params PostIdentityGcParams) middleware.Responder {
+	log.WithField(logfields.Params, logfields.Repr(params)).Debug("POST /identity/gc request")
+
+	released, err := identity.RunGC()
+	if err != nil {
+		return apierror.Error(PostIdentityGcUnreachableCode, err)
+	}
+
+	return NewPostIdentityGcOK().WithPayload(released)
+}
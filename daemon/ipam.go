@@ -15,17 +15,70 @@
 package main
 
 import (
+	"fmt"
+	"net"
 	"strings"
+	"time"
 
 	"github.com/cilium/cilium/api/v1/models"
 	ipamapi "github.com/cilium/cilium/api/v1/server/restapi/ipam"
+	"github.com/cilium/cilium/pkg/annotation"
 	"github.com/cilium/cilium/pkg/apierror"
+	"github.com/cilium/cilium/pkg/controller"
+	"github.com/cilium/cilium/pkg/endpointmanager"
 	"github.com/cilium/cilium/pkg/ipam"
+	"github.com/cilium/cilium/pkg/k8s"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/metrics"
+	"github.com/cilium/cilium/pkg/node"
+	"github.com/cilium/cilium/pkg/workloads/containerd"
 
 	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
 	"github.com/go-openapi/swag"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// ipamOrphanGracePeriod is the time after which an allocated address which
+// is not used by any endpoint is considered orphaned. Endpoints are created
+// shortly after their addresses have been allocated via the API.
+const ipamOrphanGracePeriod = 2 * time.Minute
+
+// ipamMetricsInterval is the interval in which the IPAM metrics are updated
+const ipamMetricsInterval = 10 * time.Second
+
+// getPodIPAMPool returns the IPAM pool selected by the annotations of the
+// pod given in the form namespace/name or of its namespace. An empty string
+// is returned if no pool is selected.
+func getPodIPAMPool(pod string) (string, error) {
+	if !k8s.IsEnabled() {
+		return "", nil
+	}
+
+	parts := strings.SplitN(pod, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", fmt.Errorf("invalid pod %q, must be in the form namespace/name", pod)
+	}
+	namespace, name := parts[0], parts[1]
+
+	p, err := k8s.Client().CoreV1().Pods(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("unable to get pod %s: %s", pod, err)
+	}
+
+	if pool, ok := p.GetAnnotations()[annotation.IPAMPool]; ok {
+		return pool, nil
+	}
+
+	ns, err := k8s.Client().CoreV1().Namespaces().Get(namespace, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("unable to get namespace %s: %s", namespace, err)
+	}
+
+	return ns.GetAnnotations()[annotation.IPAMPool], nil
+}
+
 type postIPAM struct {
 	daemon *Daemon
 }
@@ -42,11 +95,31 @@ func (h *postIPAM) Handle(params ipamapi.PostIPAMParams) middleware.Responder {
 		Endpoint:       &models.EndpointAddressing{},
 	}
 
-	ipv4, ipv6, err := ipam.AllocateNext(strings.ToLower(swag.StringValue(params.Family)))
+	pool := swag.StringValue(params.Pool)
+	if pool == "" && swag.StringValue(params.K8sPod) != "" {
+		var err error
+		pool, err = getPodIPAMPool(swag.StringValue(params.K8sPod))
+		if err != nil {
+			return apierror.Error(ipamapi.PostIPAMFailureCode, err)
+		}
+	}
+
+	if pool == "" {
+		pool = ipam.DefaultPool
+	}
+
+	if !ipam.PoolExists(pool) {
+		return apierror.New(ipamapi.PostIPAMPoolNotFoundCode, "IPAM pool %s not found", pool)
+	}
+
+	ipv4, ipv6, err := ipam.AllocateNextFromPool(pool, strings.ToLower(swag.StringValue(params.Family)),
+		swag.StringValue(params.Owner))
 	if err != nil {
 		return apierror.Error(ipamapi.PostIPAMFailureCode, err)
 	}
 
+	resp.Pool = pool
+
 	if ipv4 != nil {
 		resp.Endpoint.IPV4 = ipv4.String()
 	}
@@ -58,6 +131,53 @@ func (h *postIPAM) Handle(params ipamapi.PostIPAMParams) middleware.Responder {
 	return ipamapi.NewPostIPAMCreated().WithPayload(resp)
 }
 
+type getIPAMPools struct{}
+
+// NewGetIPAMPoolsHandler returns a handler listing all IPAM pools.
+func NewGetIPAMPoolsHandler(d *Daemon) ipamapi.GetIPAMPoolsHandler {
+	return &getIPAMPools{}
+}
+
+func (h *getIPAMPools) Handle(params ipamapi.GetIPAMPoolsParams) middleware.Responder {
+	return ipamapi.NewGetIPAMPoolsOK().WithPayload(ipam.GetPoolsModel())
+}
+
+type getIPAM struct {
+	daemon *Daemon
+}
+
+// NewGetIPAMHandler returns a handler listing all allocated addresses.
+func NewGetIPAMHandler(d *Daemon) ipamapi.GetIPAMHandler {
+	return &getIPAM{daemon: d}
+}
+
+func (h *getIPAM) Handle(params ipamapi.GetIPAMParams) middleware.Responder {
+	return ipamapi.NewGetIPAMOK().WithPayload(h.daemon.getIPAMAllocations(swag.BoolValue(params.Orphans)))
+}
+
+type getIPAMIP struct {
+	daemon *Daemon
+}
+
+// NewGetIPAMIPHandler returns a handler returning a single allocated address.
+func NewGetIPAMIPHandler(d *Daemon) ipamapi.GetIPAMIPHandler {
+	return &getIPAMIP{daemon: d}
+}
+
+func (h *getIPAMIP) Handle(params ipamapi.GetIPAMIPParams) middleware.Responder {
+	ip := net.ParseIP(params.IP)
+	if ip == nil {
+		return ipamapi.NewGetIPAMIPInvalid()
+	}
+
+	a := ipam.GetAllocation(ip)
+	if a == nil {
+		return ipamapi.NewGetIPAMIPNotFound()
+	}
+
+	return ipamapi.NewGetIPAMIPOK().WithPayload(h.daemon.newIPAMAllocationModel(a, getIPAMEndpoints(), time.Now()))
+}
+
 type postIPAMIP struct{}
 
 // NewPostIPAMIPHandler creates a new postIPAM from the daemon.
@@ -67,7 +187,7 @@ func NewPostIPAMIPHandler(d *Daemon) ipamapi.PostIPAMIPHandler {
 
 // Handle incoming requests address allocation requests for the daemon.
 func (h *postIPAMIP) Handle(params ipamapi.PostIPAMIPParams) middleware.Responder {
-	if err := ipam.AllocateIPString(params.IP); err != nil {
+	if err := ipam.AllocateIPString(params.IP, swag.StringValue(params.Owner)); err != nil {
 		return apierror.Error(ipamapi.PostIPAMIPFailureCode, err)
 	}
 
@@ -98,3 +218,167 @@ func (d *Daemon) DumpIPAM() *models.IPAMStatus {
 		IPV6: allocv6,
 	}
 }
+
+// ipamEndpoint is the local endpoint using an allocated address
+type ipamEndpoint struct {
+	id    uint16
+	state string
+}
+
+// getIPAMEndpoints returns the local endpoints indexed by their addresses
+func getIPAMEndpoints() map[string]ipamEndpoint {
+	endpoints := map[string]ipamEndpoint{}
+	for _, ep := range endpointmanager.GetEndpoints() {
+		ep.Mutex.RLock()
+		e := ipamEndpoint{id: ep.ID, state: ep.GetStateLocked()}
+		if ep.IPv4 != nil {
+			endpoints[ep.IPv4.String()] = e
+		}
+		if ep.IPv6 != nil {
+			endpoints[ep.IPv6.String()] = e
+		}
+		ep.Mutex.RUnlock()
+	}
+	return endpoints
+}
+
+// ipamOrphanReason returns the reason why the allocation a is considered
+// orphaned or an empty string if the address is in use. endpoints are the
+// local endpoints indexed by their addresses.
+func (d *Daemon) ipamOrphanReason(a *ipam.Allocation, endpoints map[string]ipamEndpoint, now time.Time) string {
+	if _, ok := endpoints[a.IP.String()]; ok {
+		return ""
+	}
+
+	switch a.Owner {
+	case ipam.OwnerHealth:
+		if a.IP.Equal(node.GetIPv4HealthIP()) || a.IP.Equal(node.GetIPv6HealthIP()) {
+			return ""
+		}
+		return "not used by cilium-health"
+	case ipam.OwnerLoopback:
+		if a.IP.Equal(d.loopbackIPv4) {
+			return ""
+		}
+		return "not used as service loopback address"
+	}
+
+	if now.Sub(a.Time) < ipamOrphanGracePeriod {
+		return ""
+	}
+
+	if id, ok := ipam.OwnerEndpointID(a.Owner); ok {
+		if endpointmanager.LookupCiliumID(id) == nil {
+			return fmt.Sprintf("endpoint %d does not exist", id)
+		}
+		return fmt.Sprintf("not used by endpoint %d", id)
+	}
+
+	if containerID := ipam.OwnerContainerID(a.Owner); containerID != "" {
+		return fmt.Sprintf("no endpoint for container %s", containerID)
+	}
+
+	return "not used by any endpoint"
+}
+
+// newIPAMAllocationModel returns the API model of the allocation a.
+// endpoints are the local endpoints indexed by their addresses.
+func (d *Daemon) newIPAMAllocationModel(a *ipam.Allocation, endpoints map[string]ipamEndpoint, now time.Time) *models.IPAMAllocation {
+	m := &models.IPAMAllocation{
+		IP:             a.IP.String(),
+		Owner:          a.Owner,
+		Pool:           a.Pool,
+		AllocationTime: strfmt.DateTime(a.Time),
+		Orphan:         d.ipamOrphanReason(a, endpoints, now),
+	}
+
+	if e, ok := endpoints[a.IP.String()]; ok {
+		m.EndpointID = int64(e.id)
+		m.EndpointState = models.EndpointState(e.state)
+	}
+
+	return m
+}
+
+// getIPAMAllocations returns the model of all allocated addresses. If
+// orphans is true, only orphaned addresses are returned.
+func (d *Daemon) getIPAMAllocations(orphans bool) []*models.IPAMAllocation {
+	endpoints := getIPAMEndpoints()
+	now := time.Now()
+
+	allocations := []*models.IPAMAllocation{}
+	for _, a := range ipam.GetAllocations() {
+		m := d.newIPAMAllocationModel(a, endpoints, now)
+		if orphans && m.Orphan == "" {
+			continue
+		}
+		allocations = append(allocations, m)
+	}
+
+	return allocations
+}
+
+// updateIPAMMetrics updates the IPAM metrics with the usage of all pools
+// and the number of orphaned addresses
+func (d *Daemon) updateIPAMMetrics() {
+	for _, pool := range ipam.GetPoolsModel() {
+		for family, r := range map[string]*models.IPAMPoolRange{"ipv4": pool.IPV4, "ipv6": pool.IPV6} {
+			if r == nil {
+				continue
+			}
+			metrics.IPAMCapacity.WithLabelValues(pool.Name, family).Set(float64(r.Capacity))
+			metrics.IPAMUsed.WithLabelValues(pool.Name, family).Set(float64(r.Used))
+			metrics.IPAMFree.WithLabelValues(pool.Name, family).Set(float64(r.Free))
+			metrics.IPAMFragmentation.WithLabelValues(pool.Name, family).Set(r.Fragmentation)
+		}
+	}
+
+	metrics.IPAMOrphans.Set(float64(len(d.getIPAMAllocations(true))))
+}
+
+// startIPAMMetrics starts updating the IPAM metrics periodically
+func (d *Daemon) startIPAMMetrics() {
+	controller.NewManager().UpdateController("ipam-metrics",
+		controller.ControllerParams{
+			DoFunc: func() error {
+				d.updateIPAMMetrics()
+				return nil
+			},
+			RunInterval: ipamMetricsInterval,
+		})
+}
+
+// keepRestoredIPAMAllocation returns true if the address allocated by the
+// previous run of the agent is still in use
+func keepRestoredIPAMAllocation(a *ipam.Allocation) bool {
+	switch a.Owner {
+	case ipam.OwnerHealth, ipam.OwnerLoopback:
+		// Allocated again on each start
+		return false
+	}
+
+	// Restored endpoints have allocated their addresses again
+	if _, ok := ipam.OwnerEndpointID(a.Owner); ok {
+		return false
+	}
+
+	if containerID := ipam.OwnerContainerID(a.Owner); containerID != "" {
+		return containerd.IsContainerRunning(containerID)
+	}
+
+	// The owner is unknown, the address is kept and reported as orphan
+	// if it remains unused
+	return true
+}
+
+// reconcileIPAMState releases all addresses allocated by the previous run of
+// the agent which are no longer in use. Must be called after the endpoints
+// have been restored.
+func (d *Daemon) reconcileIPAMState() {
+	for _, a := range ipam.ReconcileState(keepRestoredIPAMAllocation) {
+		log.WithFields(logrus.Fields{
+			logfields.IPAddr: a.IP,
+			"owner":          a.Owner,
+		}).Info("Released address allocated by previous run which is no longer in use")
+	}
+}
@@ -15,16 +15,19 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"net"
 	"os"
 	"reflect"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/cilium/cilium/common/types"
+	"github.com/cilium/cilium/pkg/annotation"
 	"github.com/cilium/cilium/pkg/controller"
 	"github.com/cilium/cilium/pkg/endpoint"
 	"github.com/cilium/cilium/pkg/endpointmanager"
@@ -741,8 +744,28 @@ func (d *Daemon) addK8sServiceV1(svc *v1.Service) {
 	}
 	newSI := types.NewK8sServiceInfo(clusterIP, headless, svc.Labels, svc.Spec.Selector)
 
-	// FIXME: Add support for
-	//  - NodePort
+	algorithm, err := types.NewLBAlgorithm(svc.ObjectMeta.Annotations[annotation.ServiceLBAlgorithm])
+	if err != nil {
+		scopedLog.WithError(err).WithField("annotation", annotation.ServiceLBAlgorithm).Warn("Ignoring invalid load-balancing algorithm annotation")
+		algorithm = types.LBAlgorithmRandom
+	}
+	if err := d.checkLBAlgorithm(algorithm); err != nil {
+		scopedLog.WithError(err).WithField("annotation", annotation.ServiceLBAlgorithm).Warn("Falling back to the default load-balancing algorithm")
+		algorithm = types.LBAlgorithmRandom
+	}
+	newSI.Options.Algorithm = algorithm
+
+	if svc.Spec.SessionAffinity == v1.ServiceAffinityClientIP {
+		if err := d.checkLBSessionAffinity(true); err != nil {
+			scopedLog.WithError(err).Warn("Ignoring session affinity of service")
+		} else {
+			newSI.Options.SessionAffinity = true
+			newSI.Options.SessionAffinityTimeout = types.DefaultSessionAffinityTimeout
+			if cfg := svc.Spec.SessionAffinityConfig; cfg != nil && cfg.ClientIP != nil && cfg.ClientIP.TimeoutSeconds != nil {
+				newSI.Options.SessionAffinityTimeout = time.Duration(*cfg.ClientIP.TimeoutSeconds) * time.Second
+			}
+		}
+	}
 
 	for _, port := range svc.Spec.Ports {
 		p, err := types.NewFEPort(types.L4Type(port.Protocol), uint16(port.Port))
@@ -750,14 +773,33 @@ func (d *Daemon) addK8sServiceV1(svc *v1.Service) {
 			scopedLog.WithError(err).WithField("port", port).Error("Unable to add service port")
 			continue
 		}
+		// The node port is allocated for NodePort and LoadBalancer
+		// services
+		p.NodePort = uint16(port.NodePort)
 		if _, ok := newSI.Ports[types.FEPortName(port.Name)]; !ok {
 			newSI.Ports[types.FEPortName(port.Name)] = p
 		}
 	}
 
+	for _, externalIP := range svc.Spec.ExternalIPs {
+		ip := net.ParseIP(externalIP)
+		if ip == nil {
+			scopedLog.WithField(logfields.IPAddr, externalIP).Warn("Ignoring invalid external IP of service")
+			continue
+		}
+		newSI.ExternalIPs = append(newSI.ExternalIPs, ip)
+	}
+
 	d.loadBalancer.K8sMU.Lock()
 	defer d.loadBalancer.K8sMU.Unlock()
 
+	if oldSI, ok := d.loadBalancer.K8sServices[svcns]; ok {
+		if _, ok := d.loadBalancer.K8sEndpoints[svcns]; ok {
+			nodeAddrs := d.k8sNodeAddresses()
+			d.delStaleK8sSVCs(svcns, oldSI, newSI, nodeAddrs, nodeAddrs)
+		}
+	}
+
 	d.loadBalancer.K8sServices[svcns] = newSI
 
 	d.syncLB(&svcns, nil, nil)
@@ -923,15 +965,186 @@ func areIPsConsistent(ipv4Enabled, isSvcIPv4 bool, svc types.K8sServiceNamespace
 	return nil
 }
 
-func getUniqPorts(svcPorts map[types.FEPortName]*types.FEPort) map[uint16]bool {
-	// We are not discriminating the different L4 protocols on the same L4
-	// port so we create the number of unique sets of service IP + service
-	// port.
-	uniqPorts := map[uint16]bool{}
-	for _, svcPort := range svcPorts {
-		uniqPorts[svcPort.Port] = true
+// k8sFrontend is a frontend of a port of a k8s service
+type k8sFrontend struct {
+	addr     types.L3n4Addr
+	typ      types.LBFrontendType
+	portName types.FEPortName
+}
+
+// getID returns the service ID of the frontend cached in its port fePort, 0
+// if no ID has been allocated yet
+func (fe *k8sFrontend) getID(fePort *types.FEPort) types.ServiceID {
+	if fe.typ == types.LBFrontendClusterIP {
+		return fePort.ID
+	}
+	return fePort.FrontendIDs[fe.addr.String()]
+}
+
+// setID caches the service ID of the frontend in its port fePort
+func (fe *k8sFrontend) setID(fePort *types.FEPort, id types.ServiceID) {
+	if fe.typ == types.LBFrontendClusterIP {
+		fePort.ID = id
+		return
+	}
+	if fePort.FrontendIDs == nil {
+		fePort.FrontendIDs = map[string]types.ServiceID{}
+	}
+	fePort.FrontendIDs[fe.addr.String()] = id
+}
+
+// clusterNodeAddresses returns the addresses of this node and of all other
+// nodes in the cluster on which node ports are exposed, sorted and without
+// duplicates
+func clusterNodeAddresses() []net.IP {
+	candidates := []net.IP{node.GetExternalIPv4(), node.GetInternalIPv4(), node.GetIPv6()}
+	for _, n := range node.GetNodes() {
+		for _, addr := range n.IPAddresses {
+			candidates = append(candidates, addr.IP)
+		}
+	}
+
+	seen := map[string]bool{}
+	addrs := []net.IP{}
+	for _, ip := range candidates {
+		if ip == nil || seen[ip.String()] {
+			continue
+		}
+		seen[ip.String()] = true
+		addrs = append(addrs, ip)
+	}
+	sort.Slice(addrs, func(i, j int) bool { return bytes.Compare(addrs[i].To16(), addrs[j].To16()) < 0 })
+	return addrs
+}
+
+// k8sNodeAddresses returns the node addresses on which the node ports of the
+// k8s services are installed. Must be called with d.loadBalancer.K8sMU held.
+func (d *Daemon) k8sNodeAddresses() []net.IP {
+	if d.loadBalancer.K8sNodeAddrs == nil {
+		d.loadBalancer.K8sNodeAddrs = clusterNodeAddresses()
+	}
+	return d.loadBalancer.K8sNodeAddrs
+}
+
+// syncK8sNodePorts installs the node ports of all k8s services on the
+// addresses of the nodes in the cluster after nodes have been added, updated
+// or removed, so that pods connecting to the node port of any node are
+// load-balanced locally. Node ports of removed addresses are uninstalled.
+func (d *Daemon) syncK8sNodePorts() {
+	d.loadBalancer.K8sMU.Lock()
+	defer d.loadBalancer.K8sMU.Unlock()
+
+	oldAddrs := d.k8sNodeAddresses()
+	newAddrs := clusterNodeAddresses()
+	if ipsEqual(oldAddrs, newAddrs) {
+		return
+	}
+	d.loadBalancer.K8sNodeAddrs = newAddrs
+
+	for svcns, svcInfo := range d.loadBalancer.K8sServices {
+		if !svcInfo.HasNodePort() {
+			continue
+		}
+		se, ok := d.loadBalancer.K8sEndpoints[svcns]
+		if !ok {
+			continue
+		}
+
+		d.delStaleK8sSVCs(svcns, svcInfo, svcInfo, oldAddrs, newAddrs)
+		if err := d.addK8sSVCs(svcns, svcInfo, se); err != nil {
+			log.WithError(err).WithFields(logrus.Fields{
+				logfields.K8sSvcName:   svcns.ServiceName,
+				logfields.K8sNamespace: svcns.Namespace,
+			}).Error("Unable to update node ports of k8s service")
+		}
+	}
+}
+
+// ipsEqual returns true if a and b contain the same IPs in the same order
+func ipsEqual(a, b []net.IP) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// k8sServiceFrontends returns the frontends of all ports of the service: the
+// cluster IP, the node port on each of the node addresses nodeAddrs and the
+// external IPs of the same address family as the cluster IP. We are not
+// discriminating the different L4 protocols on the same L4 port so only one
+// frontend is returned per address and port.
+func k8sServiceFrontends(svcInfo *types.K8sServiceInfo, nodeAddrs []net.IP) []k8sFrontend {
+	isSvcIPv4 := svcInfo.FEIP.To4() != nil
+	seen := map[string]bool{}
+	frontends := []k8sFrontend{}
+
+	add := func(ip net.IP, port *types.L4Addr, typ types.LBFrontendType, portName types.FEPortName) {
+		if (ip.To4() != nil) != isSvcIPv4 {
+			return
+		}
+		addr := types.L3n4Addr{IP: ip, L4Addr: *port}
+		if seen[addr.String()] {
+			return
+		}
+		seen[addr.String()] = true
+		frontends = append(frontends, k8sFrontend{addr: addr, typ: typ, portName: portName})
+	}
+
+	for portName, fePort := range svcInfo.Ports {
+		add(svcInfo.FEIP, fePort.L4Addr, types.LBFrontendClusterIP, portName)
+
+		if fePort.NodePort != 0 {
+			nodePort := types.L4Addr{Protocol: fePort.Protocol, Port: fePort.NodePort}
+			for _, ip := range nodeAddrs {
+				add(ip, &nodePort, types.LBFrontendNodePort, portName)
+			}
+		}
+
+		for _, ip := range svcInfo.ExternalIPs {
+			add(ip, fePort.L4Addr, types.LBFrontendExternalIP, portName)
+		}
+	}
+
+	return frontends
+}
+
+// delK8sFrontends removes the given frontends of the service from the lb maps
+// and releases their service IDs.
+func (d *Daemon) delK8sFrontends(scopedLog *logrus.Entry, svcInfo *types.K8sServiceInfo, frontends []k8sFrontend) {
+	for _, fe := range frontends {
+		var id types.ServiceID
+		if svcPort, ok := svcInfo.Ports[fe.portName]; ok {
+			id = fe.getID(svcPort)
+		}
+
+		if id != 0 {
+			if err := DeleteL3n4AddrIDByUUID(uint32(id)); err != nil {
+				scopedLog.WithError(err).Warn("Error while cleaning service ID")
+			}
+		}
+
+		if err := d.svcDeleteByFrontend(&fe.addr); err != nil {
+			scopedLog.WithError(err).WithField(logfields.Object, logfields.Repr(fe.addr)).
+				Warn("Error deleting service by frontend")
+
+		} else {
+			scopedLog.Debugf("# cilium lb delete-service %s %d 0", fe.addr.IP, fe.addr.Port)
+		}
+
+		if id == 0 {
+			continue
+		}
+		if err := d.RevNATDelete(id); err != nil {
+			scopedLog.WithError(err).WithField(logfields.ServiceID, id).Warn("Error deleting reverse NAT")
+		} else {
+			scopedLog.Debugf("# cilium lb delete-rev-nat %d", id)
+		}
 	}
-	return uniqPorts
 }
 
 func (d *Daemon) delK8sSVCs(svc types.K8sServiceNamespace, svcInfo *types.K8sServiceInfo, se *types.K8sServiceEndpoint) error {
@@ -956,41 +1169,44 @@ func (d *Daemon) delK8sSVCs(svc types.K8sServiceNamespace, svcInfo *types.K8sSer
 		logfields.K8sNamespace: svc.Namespace,
 	})
 
-	repPorts := getUniqPorts(svcInfo.Ports)
-
-	for _, svcPort := range svcInfo.Ports {
-		if !repPorts[svcPort.Port] {
-			continue
-		}
-		repPorts[svcPort.Port] = false
-
-		if svcPort.ID != 0 {
-			if err := DeleteL3n4AddrIDByUUID(uint32(svcPort.ID)); err != nil {
-				scopedLog.WithError(err).Warn("Error while cleaning service ID")
-			}
-		}
-
-		fe, err := types.NewL3n4Addr(svcPort.Protocol, svcInfo.FEIP, svcPort.Port)
-		if err != nil {
-			scopedLog.WithError(err).Error("Error while creating a New L3n4AddrID. Ignoring service")
-			continue
-		}
+	d.delK8sFrontends(scopedLog, svcInfo, k8sServiceFrontends(svcInfo, d.k8sNodeAddresses()))
+	return nil
+}
 
-		if err := d.svcDeleteByFrontend(fe); err != nil {
-			scopedLog.WithError(err).WithField(logfields.Object, logfields.Repr(fe)).
-				Warn("Error deleting service by frontend")
+// delStaleK8sSVCs removes the frontends of the previous version oldInfo of a
+// service on the node addresses oldAddrs which are no longer part of its new
+// version newInfo on the node addresses newAddrs, e.g. because a node port,
+// an external IP or a node has been removed.
+func (d *Daemon) delStaleK8sSVCs(svc types.K8sServiceNamespace, oldInfo, newInfo *types.K8sServiceInfo, oldAddrs, newAddrs []net.IP) {
+	if lb := viper.GetBool("disable-k8s-services"); lb == true {
+		return
+	}
+	if oldInfo.IsHeadless {
+		return
+	}
 
-		} else {
-			scopedLog.Debugf("# cilium lb delete-service %s %d 0", svcInfo.FEIP, svcPort.Port)
+	current := map[string]bool{}
+	if !newInfo.IsHeadless {
+		for _, fe := range k8sServiceFrontends(newInfo, newAddrs) {
+			current[fe.addr.String()] = true
 		}
+	}
 
-		if err := d.RevNATDelete(svcPort.ID); err != nil {
-			scopedLog.WithError(err).WithField(logfields.ServiceID, svcPort.ID).Warn("Error deleting reverse NAT")
-		} else {
-			scopedLog.Debugf("# cilium lb delete-rev-nat %d", svcPort.ID)
+	stale := []k8sFrontend{}
+	for _, fe := range k8sServiceFrontends(oldInfo, oldAddrs) {
+		if !current[fe.addr.String()] {
+			stale = append(stale, fe)
 		}
 	}
-	return nil
+	if len(stale) == 0 {
+		return
+	}
+
+	scopedLog := log.WithFields(logrus.Fields{
+		logfields.K8sSvcName:   svc.ServiceName,
+		logfields.K8sNamespace: svc.Namespace,
+	})
+	d.delK8sFrontends(scopedLog, oldInfo, stale)
 }
 
 func (d *Daemon) addK8sSVCs(svc types.K8sServiceNamespace, svcInfo *types.K8sServiceInfo, se *types.K8sServiceEndpoint) error {
@@ -1015,43 +1231,29 @@ func (d *Daemon) addK8sSVCs(svc types.K8sServiceNamespace, svcInfo *types.K8sSer
 		return err
 	}
 
-	uniqPorts := getUniqPorts(svcInfo.Ports)
-
-	for fePortName, fePort := range svcInfo.Ports {
-		if !uniqPorts[fePort.Port] {
-			continue
-		}
+	for _, fe := range k8sServiceFrontends(svcInfo, d.k8sNodeAddresses()) {
+		fePort := svcInfo.Ports[fe.portName]
+		k8sBEPort := se.Ports[fe.portName]
 
-		k8sBEPort := se.Ports[fePortName]
-		uniqPorts[fePort.Port] = false
-
-		if fePort.ID == 0 {
-			feAddr, err := types.NewL3n4Addr(fePort.Protocol, svcInfo.FEIP, fePort.Port)
-			if err != nil {
-				scopedLog.WithError(err).WithFields(logrus.Fields{
-					logfields.ServiceID: fePortName,
-					logfields.IPAddr:    svcInfo.FEIP,
-					logfields.Port:      fePort.Port,
-					logfields.Protocol:  fePort.Protocol,
-				}).Error("Error while creating a new L3n4Addr. Ignoring service...")
-				continue
-			}
-			feAddrID, err := PutL3n4Addr(*feAddr, 0)
+		id := fe.getID(fePort)
+		if id == 0 {
+			feAddrID, err := PutL3n4Addr(fe.addr, 0)
 			if err != nil {
 				scopedLog.WithError(err).WithFields(logrus.Fields{
-					logfields.ServiceID: fePortName,
-					logfields.IPAddr:    svcInfo.FEIP,
-					logfields.Port:      fePort.Port,
-					logfields.Protocol:  fePort.Protocol,
+					logfields.ServiceID: fe.portName,
+					logfields.IPAddr:    fe.addr.IP,
+					logfields.Port:      fe.addr.Port,
+					logfields.Protocol:  fe.addr.Protocol,
 				}).Error("Error while getting a new service ID. Ignoring service...")
 				continue
 			}
 			scopedLog.WithFields(logrus.Fields{
-				logfields.ServiceName: fePortName,
+				logfields.ServiceName: fe.portName,
 				logfields.ServiceID:   feAddrID.ID,
 				logfields.Object:      logfields.Repr(svc),
 			}).Debug("Got feAddr ID for service")
-			fePort.ID = feAddrID.ID
+			id = feAddrID.ID
+			fe.setID(fePort, id)
 		}
 
 		besValues := []types.LBBackEnd{}
@@ -1066,16 +1268,13 @@ func (d *Daemon) addK8sSVCs(svc types.K8sServiceNamespace, svcInfo *types.K8sSer
 			}
 		}
 
-		fe, err := types.NewL3n4AddrID(fePort.Protocol, svcInfo.FEIP, fePort.Port, fePort.ID)
-		if err != nil {
-			scopedLog.WithError(err).WithFields(logrus.Fields{
-				logfields.IPAddr: svcInfo.FEIP,
-				logfields.Port:   svcInfo.Ports,
-			}).Error("Error while creating a New L3n4AddrID. Ignoring service...")
-			continue
-		}
-		if _, err := d.svcAdd(*fe, besValues, true); err != nil {
-			scopedLog.WithError(err).Error("Error while inserting service in LB map")
+		opts := svcInfo.Options
+		opts.FrontendType = fe.typ
+
+		feAddrID := types.L3n4AddrID{L3n4Addr: fe.addr, ID: id}
+		if _, err := d.svcAdd(feAddrID, besValues, opts, true); err != nil {
+			scopedLog.WithError(err).WithField(logfields.Object, logfields.Repr(fe.addr)).
+				Error("Error while inserting service in LB map")
 		}
 	}
 	return nil
@@ -1574,6 +1773,7 @@ func (d *Daemon) addK8sNodeV1(k8sNode *v1.Node) {
 	}
 
 	node.UpdateNode(ni, n, routeTypes, ownAddr)
+	d.syncK8sNodePorts()
 
 	log.WithFields(logrus.Fields{
 		logfields.K8sNodeID:     ni,
@@ -1606,6 +1806,7 @@ func (d *Daemon) updateK8sNodeV1(_, k8sNode *v1.Node) {
 	}
 
 	node.UpdateNode(ni, newNode, routeTypes, ownAddr)
+	d.syncK8sNodePorts()
 
 	log.WithFields(logrus.Fields{
 		logfields.K8sNodeID:     ni,
@@ -1618,6 +1819,7 @@ func (d *Daemon) deleteK8sNodeV1(k8sNode *v1.Node) {
 	ni := node.Identity{Name: k8sNode.ObjectMeta.Name}
 
 	node.DeleteNode(ni, node.TunnelRoute|node.DirectRoute)
+	d.syncK8sNodePorts()
 
 	log.WithFields(logrus.Fields{
 		logfields.K8sNodeID:     ni,
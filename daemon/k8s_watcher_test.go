@@ -15,9 +15,15 @@
 package main
 
 import (
+	"net"
+	"sort"
 	"time"
 
+	"github.com/cilium/cilium/common/types"
+	"github.com/cilium/cilium/pkg/node"
+
 	. "gopkg.in/check.v1"
+	"k8s.io/api/core/v1"
 )
 
 func (ds *DaemonSuite) TestK8sErrorLogTimeout(c *C) {
@@ -40,3 +46,94 @@ func (ds *DaemonSuite) TestK8sErrorLogTimeout(c *C) {
 	shouldLogTime := startTime.Add(k8sErrLogTimeout).Add(time.Nanosecond)
 	c.Assert(k8sErrorUpdateCheckUnmuteTime(errstr, shouldLogTime), Equals, true)
 }
+
+func (ds *DaemonSuite) TestK8sServiceFrontends(c *C) {
+	// Addresses of this node and of another node of the cluster
+	nodeAddrs := []net.IP{net.ParseIP("10.0.0.1"), net.ParseIP("10.0.0.2"), net.ParseIP("192.168.0.1"), net.ParseIP("f00d::2")}
+
+	svcInfo := types.NewK8sServiceInfo(net.ParseIP("172.20.0.1"), false, nil, nil)
+	http, err := types.NewFEPort(types.TCP, 80)
+	c.Assert(err, IsNil)
+	http.NodePort = 30080
+	svcInfo.Ports["http"] = http
+	// Same port number with a different protocol
+	dns, err := types.NewFEPort(types.UDP, 80)
+	c.Assert(err, IsNil)
+	svcInfo.Ports["dns"] = dns
+	svcInfo.ExternalIPs = []net.IP{net.ParseIP("1.1.1.1"), net.ParseIP("f00d::1")}
+
+	frontends := map[string]types.LBFrontendType{}
+	for _, fe := range k8sServiceFrontends(svcInfo, nodeAddrs) {
+		frontends[fe.addr.String()] = fe.typ
+	}
+
+	addrs := []string{}
+	for addr := range frontends {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+	c.Assert(addrs, DeepEquals, []string{
+		"1.1.1.1:80",
+		"10.0.0.1:30080",
+		"10.0.0.2:30080",
+		"172.20.0.1:80",
+		"192.168.0.1:30080",
+	})
+	c.Assert(frontends["172.20.0.1:80"], Equals, types.LBFrontendClusterIP)
+	c.Assert(frontends["10.0.0.1:30080"], Equals, types.LBFrontendNodePort)
+	c.Assert(frontends["10.0.0.2:30080"], Equals, types.LBFrontendNodePort)
+	c.Assert(frontends["192.168.0.1:30080"], Equals, types.LBFrontendNodePort)
+	c.Assert(frontends["1.1.1.1:80"], Equals, types.LBFrontendExternalIP)
+}
+
+func (ds *DaemonSuite) TestClusterNodeAddresses(c *C) {
+	oldExternal, oldInternal := node.GetExternalIPv4(), node.GetInternalIPv4()
+	defer func() {
+		node.SetExternalIPv4(oldExternal)
+		node.SetInternalIPv4(oldInternal)
+	}()
+	node.SetExternalIPv4(net.ParseIP("192.168.0.1"))
+	node.SetInternalIPv4(net.ParseIP("10.0.0.1"))
+
+	ni := node.Identity{Name: "k8s-node-addresses-test"}
+	node.UpdateNode(ni, &node.Node{
+		Name: ni.Name,
+		IPAddresses: []node.Address{
+			{AddressType: v1.NodeInternalIP, IP: net.ParseIP("10.0.0.2")},
+			// Duplicate of an address of this node
+			{AddressType: v1.NodeExternalIP, IP: net.ParseIP("192.168.0.1")},
+		},
+	}, 0, nil)
+	defer node.DeleteNode(ni, 0)
+
+	addrs := []string{}
+	for _, ip := range clusterNodeAddresses() {
+		if ip.To4() != nil {
+			addrs = append(addrs, ip.String())
+		}
+	}
+	c.Assert(addrs, DeepEquals, []string{"10.0.0.1", "10.0.0.2", "192.168.0.1"})
+}
+
+func (ds *DaemonSuite) TestK8sFrontendID(c *C) {
+	fePort, err := types.NewFEPort(types.TCP, 80)
+	c.Assert(err, IsNil)
+	fePort.NodePort = 30080
+
+	clusterIP := k8sFrontend{
+		addr: types.L3n4Addr{IP: net.ParseIP("172.20.0.1"), L4Addr: *fePort.L4Addr},
+		typ:  types.LBFrontendClusterIP,
+	}
+	nodePort := k8sFrontend{
+		addr: types.L3n4Addr{IP: net.ParseIP("10.0.0.1"), L4Addr: types.L4Addr{Protocol: types.TCP, Port: 30080}},
+		typ:  types.LBFrontendNodePort,
+	}
+	c.Assert(clusterIP.getID(fePort), Equals, types.ServiceID(0))
+	c.Assert(nodePort.getID(fePort), Equals, types.ServiceID(0))
+
+	clusterIP.setID(fePort, 1)
+	nodePort.setID(fePort, 2)
+	c.Assert(fePort.ID, Equals, types.ServiceID(1))
+	c.Assert(clusterIP.getID(fePort), Equals, types.ServiceID(1))
+	c.Assert(nodePort.getID(fePort), Equals, types.ServiceID(2))
+}
@@ -0,0 +1,247 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	. "github.com/cilium/cilium/api/v1/server/restapi/daemon"
+	"github.com/cilium/cilium/pkg/apierror"
+	"github.com/cilium/cilium/pkg/endpoint"
+	"github.com/cilium/cilium/pkg/endpointmanager"
+	"github.com/cilium/cilium/pkg/identity"
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// identityMigrationTimeout is the time to wait for all endpoints to
+	// switch to their new identity after the label prefixes changed
+	identityMigrationTimeout = 2 * time.Minute
+)
+
+var (
+	// labelPrefixMutex serializes label prefix configuration changes
+	labelPrefixMutex lock.Mutex
+)
+
+// identityMigration is the migration of a single endpoint to the identity
+// derived from its labels filtered with the new label prefixes
+type identityMigration struct {
+	ep             *endpoint.Endpoint
+	oldIdentity    *identity.Identity
+	newIdentity    *identity.Identity
+	identityLabels labels.Labels
+	infoLabels     labels.Labels
+	err            error
+}
+
+func (m *identityMigration) getModel() *models.IdentityMigration {
+	mdl := &models.IdentityMigration{
+		EndpointID: int64(m.ep.ID),
+		Labels:     m.identityLabels.GetModel(),
+	}
+
+	if m.oldIdentity != nil {
+		mdl.OldIdentity = int64(m.oldIdentity.ID)
+	}
+
+	if m.newIdentity != nil {
+		mdl.NewIdentity = int64(m.newIdentity.ID)
+	}
+
+	if m.err != nil {
+		mdl.Error = m.err.Error()
+	}
+
+	return mdl
+}
+
+// newIdentityMigration filters the labels of ep with the current label prefix
+// configuration. Reserved labels are never filtered.
+func newIdentityMigration(ep *endpoint.Endpoint) *identityMigration {
+	ep.Mutex.RLock()
+	defer ep.Mutex.RUnlock()
+
+	m := &identityMigration{
+		ep:          ep,
+		oldIdentity: ep.SecurityIdentity,
+	}
+
+	orchestration := labels.Labels{}
+	reserved := labels.Labels{}
+	for _, lbls := range []labels.Labels{ep.OpLabels.OrchestrationIdentity, ep.OpLabels.Disabled, ep.OpLabels.OrchestrationInfo} {
+		for k, v := range lbls {
+			if v.Source == labels.LabelSourceReserved {
+				reserved[k] = v.DeepCopy()
+			} else {
+				orchestration[k] = v.DeepCopy()
+			}
+		}
+	}
+
+	m.identityLabels, m.infoLabels = labels.FilterLabels(orchestration)
+	m.identityLabels.MergeLabels(reserved)
+
+	return m
+}
+
+// wantedIdentityLabels returns the identity labels the endpoint will have
+// after the migration. Custom labels are always part of the identity while
+// disabled labels remain disabled.
+func (m *identityMigration) wantedIdentityLabels() labels.Labels {
+	m.ep.Mutex.RLock()
+	defer m.ep.Mutex.RUnlock()
+
+	wanted := labels.Labels{}
+	for k, v := range m.identityLabels {
+		if _, disabled := m.ep.OpLabels.Disabled[k]; !disabled {
+			wanted[k] = v
+		}
+	}
+	wanted.MergeLabels(m.ep.OpLabels.Custom)
+
+	return wanted
+}
+
+// migrateIdentities filters the labels of all local endpoints again and
+// migrates the endpoints whose identity labels changed to new identities. The
+// new identities are allocated and the policy of all endpoints is updated to
+// account for them before any endpoint switches to its new identity and
+// releases its old identity. Returns the endpoints which changed identity.
+func (d *Daemon) migrateIdentities() []*identityMigration {
+	migrations := []*identityMigration{}
+	changed := []*identityMigration{}
+
+	for _, ep := range endpointmanager.GetEndpoints() {
+		if ep.GetState() == endpoint.StateDisconnected {
+			continue
+		}
+
+		m := newIdentityMigration(ep)
+		migrations = append(migrations, m)
+
+		wanted := m.wantedIdentityLabels()
+		if m.oldIdentity != nil &&
+			string(m.oldIdentity.Labels.SortedList()) == string(wanted.SortedList()) {
+			continue
+		}
+
+		// Allocate the new identity ahead of the endpoint. The
+		// reference is released again after the endpoint has switched
+		// to the new identity.
+		m.newIdentity, _, m.err = identity.AllocateIdentity(wanted)
+		changed = append(changed, m)
+	}
+
+	if len(changed) > 0 {
+		// Wait for all local endpoints to account for the new identities
+		// in their policy
+		d.TriggerPolicyUpdates(true).Wait()
+	}
+
+	for _, m := range migrations {
+		if m.err == nil {
+			m.ep.UpdateLabels(d, m.identityLabels, m.infoLabels)
+		}
+	}
+
+	deadline := time.Now().Add(identityMigrationTimeout)
+	for _, m := range changed {
+		if m.newIdentity == nil {
+			continue
+		}
+
+		for m.ep.GetState() != endpoint.StateDisconnected {
+			m.ep.Mutex.RLock()
+			current := m.ep.GetIdentity()
+			m.ep.Mutex.RUnlock()
+
+			if current == m.newIdentity.ID {
+				break
+			}
+
+			if time.Now().After(deadline) {
+				m.err = fmt.Errorf("timeout while waiting for endpoint to switch to identity %d", m.newIdentity.ID)
+				break
+			}
+
+			time.Sleep(100 * time.Millisecond)
+		}
+
+		if err := m.newIdentity.Release(); err != nil {
+			log.WithError(err).WithFields(logrus.Fields{
+				logfields.EndpointID: m.ep.ID,
+				logfields.Identity:   m.newIdentity.ID,
+			}).Warning("Unable to release identity reference of identity migration")
+		}
+
+		log.WithFields(logrus.Fields{
+			logfields.EndpointID: m.ep.ID,
+			logfields.Identity:   m.newIdentity.ID,
+		}).Info("Migrated endpoint to new identity after label prefix change")
+	}
+
+	return changed
+}
+
+type getConfigLabelPrefixes struct {
+	daemon *Daemon
+}
+
+func NewGetConfigLabelPrefixesHandler(d *Daemon) GetConfigLabelPrefixesHandler {
+	return &getConfigLabelPrefixes{daemon: d}
+}
+
+func (h *getConfigLabelPrefixes) Handle(params GetConfigLabelPrefixesParams) middleware.Responder {
+	log.WithField(logfields.Params, logfields.Repr(params)).Debug("GET /config/label-prefixes request")
+
+	return NewGetConfigLabelPrefixesOK().WithPayload(&models.LabelPrefixConfiguration{
+		Prefixes:  labels.GetLabelPrefixes(),
+		Effective: labels.GetEffectiveLabelPrefixes(),
+	})
+}
+
+type putConfigLabelPrefixes struct {
+	daemon *Daemon
+}
+
+func NewPutConfigLabelPrefixesHandler(d *Daemon) PutConfigLabelPrefixesHandler {
+	return &putConfigLabelPrefixes{daemon: d}
+}
+
+func (h *putConfigLabelPrefixes) Handle(params PutConfigLabelPrefixesParams) middleware.Responder {
+	log.WithField(logfields.Params, logfields.Repr(params)).Debug("PUT /config/label-prefixes request")
+
+	labelPrefixMutex.Lock()
+	defer labelPrefixMutex.Unlock()
+
+	if err := labels.SetLabelPrefixes(params.Configuration.Prefixes); err != nil {
+		return apierror.Error(PutConfigLabelPrefixesInvalidCode, err)
+	}
+
+	migrations := []*models.IdentityMigration{}
+	for _, m := range h.daemon.migrateIdentities() {
+		migrations = append(migrations, m.getModel())
+	}
+
+	return NewPutConfigLabelPrefixesOK().WithPayload(migrations)
+}
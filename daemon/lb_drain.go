@@ -0,0 +1,210 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/cilium/cilium/common/types"
+	"github.com/cilium/cilium/pkg/controller"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/maps/ctmap"
+	"github.com/cilium/cilium/pkg/maps/lbmap"
+	"github.com/cilium/cilium/pkg/u8proto"
+
+	"github.com/sirupsen/logrus"
+)
+
+// lbDrainRunInterval is the interval in which the drain controller looks for
+// draining backends which can be removed from their service
+const lbDrainRunInterval = 5 * time.Second
+
+// syncLBDrain marks the draining backends of svc in the BPF maps so that
+// they no longer receive new connections. The previous draining backends of
+// the service have been removed together with its backends.
+func syncLBDrain(svc types.LBSVC, fe lbmap.ServiceKey) error {
+	bes := svc.ActiveBackends()
+	draining := make([]bool, len(bes))
+	anyDraining := false
+	for i, be := range bes {
+		draining[i] = be.IsDraining()
+		anyDraining = anyDraining || draining[i]
+	}
+	if !anyDraining {
+		return nil
+	}
+
+	return lbmap.UpdateDrain(fe, draining)
+}
+
+// startLBDrain starts the controller removing backends from their service
+// once they have drained.
+func (d *Daemon) startLBDrain() {
+	controller.NewManager().UpdateController("lb-drain",
+		controller.ControllerParams{
+			DoFunc:      d.removeDrainedLBBackends,
+			RunInterval: lbDrainRunInterval,
+		})
+}
+
+// lbBackendConnKey returns the key of the connections load-balanced by the
+// service with the given reverse NAT index to the backend with the given
+// address in the map returned by lbBackendConns
+func lbBackendConnKey(revNAT uint16, ip net.IP, port uint16) string {
+	return strconv.Itoa(int(revNAT)) + "/" + net.JoinHostPort(ip.String(), strconv.Itoa(int(port)))
+}
+
+// lbBackendConns returns the number of TCP connections in flows which have
+// been load-balanced by a service and have not expired at the monotonic time
+// now in seconds by service and backend. Connections of other protocols are
+// not counted as the datapath sends all of their packets to the remaining
+// backends once a backend is draining.
+func lbBackendConns(flows []ctmap.FlowEntry, now uint32) map[string]int {
+	conns := map[string]int{}
+	for i := range flows {
+		f := &flows[i]
+		if f.Proto != u8proto.TCP || f.RevNAT == 0 || f.Related || f.Expired(now) {
+			continue
+		}
+
+		conns[lbBackendConnKey(f.RevNAT, f.DstIP, f.DstPort)]++
+	}
+	return conns
+}
+
+// drainedLBBackends returns the addresses of the draining backends of svc
+// which can be removed, i.e. backends whose deadline has passed or which no
+// longer have any connections of svc. conns is nil if the connections are
+// unknown. If svc has no active backend left which is not draining, the new
+// connections of the draining backends cannot be sent elsewhere, so all of
+// them are removed.
+func drainedLBBackends(svc types.LBSVC, conns map[string]int, now time.Time) map[string]struct{} {
+	remaining := false
+	for _, be := range svc.ActiveBackends() {
+		if !be.IsDraining() {
+			remaining = true
+			break
+		}
+	}
+
+	drained := map[string]struct{}{}
+	for _, be := range svc.BES {
+		if !be.IsDraining() {
+			continue
+		}
+		if !remaining || !now.Before(be.DrainDeadline) ||
+			(conns != nil && conns[lbBackendConnKey(uint16(svc.FE.ID), be.IP, be.Port)] == 0) {
+			drained[be.L3n4Addr.String()] = struct{}{}
+		}
+	}
+	return drained
+}
+
+// drainingLBServices returns a copy of the services with draining backends
+func (d *Daemon) drainingLBServices() []types.LBSVC {
+	d.loadBalancer.BPFMapMU.RLock()
+	defer d.loadBalancer.BPFMapMU.RUnlock()
+
+	svcs := []types.LBSVC{}
+	for _, svc := range d.loadBalancer.SVCMapID {
+		for _, be := range svc.BES {
+			if be.IsDraining() {
+				cpy := *svc
+				cpy.BES = append([]types.LBBackEnd{}, svc.BES...)
+				svcs = append(svcs, cpy)
+				break
+			}
+		}
+	}
+	return svcs
+}
+
+// removeDrainedLBBackends removes all draining backends from their service
+// whose drain deadline has passed or which no longer have any connections.
+func (d *Daemon) removeDrainedLBBackends() error {
+	svcs := d.drainingLBServices()
+	if len(svcs) == 0 {
+		return nil
+	}
+
+	// Without the connections the backends drain until their deadline
+	var conns map[string]int
+	if flows, now, err := d.dumpConntrackFlows(); err != nil {
+		log.WithError(err).Warn("Unable to dump connections of draining service backends")
+	} else {
+		conns = lbBackendConns(flows, now)
+	}
+
+	var errs []error
+	now := time.Now()
+	for _, svc := range svcs {
+		drained := drainedLBBackends(svc, conns, now)
+		if len(drained) == 0 {
+			continue
+		}
+
+		if err := d.removeLBBackends(svc.FE.ID, svc.Sha256, drained); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("unable to remove drained service backends: %v", errs)
+	}
+	return nil
+}
+
+// removeLBBackends removes the draining backends with the given addresses
+// from the service with the given ID and frontend hash. Services which have
+// been deleted or changed their frontend in the meantime are ignored.
+func (d *Daemon) removeLBBackends(id types.ServiceID, sha256 string, drained map[string]struct{}) error {
+	d.loadBalancer.BPFMapMU.Lock()
+	defer d.loadBalancer.BPFMapMU.Unlock()
+
+	cur, ok := d.loadBalancer.SVCMapID[id]
+	if !ok || cur.Sha256 != sha256 {
+		return nil
+	}
+
+	svc := *cur
+	svc.BES = make([]types.LBBackEnd, 0, len(cur.BES))
+	for _, be := range cur.BES {
+		if _, ok := drained[be.L3n4Addr.String()]; ok && be.IsDraining() {
+			log.WithFields(logrus.Fields{
+				logfields.ServiceName: svc.FE.String(),
+				"backend":             be.L3n4Addr.String(),
+			}).Debug("Removing drained service backend")
+			continue
+		}
+		svc.BES = append(svc.BES, be)
+	}
+	if len(svc.BES) == len(cur.BES) {
+		return nil
+	}
+
+	fe, besValues, err := lbmap.LBSVC2ServiceKeynValue(svc)
+	if err != nil {
+		return err
+	}
+	if err := d.addSVC2BPFMap(svc, fe, besValues, false); err != nil {
+		return fmt.Errorf("unable to update backends of service %s: %s", svc.FE.String(), err)
+	}
+
+	d.loadBalancer.AddService(svc)
+	return nil
+}
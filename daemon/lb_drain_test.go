@@ -0,0 +1,78 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net"
+	"time"
+
+	"github.com/cilium/cilium/common/types"
+	"github.com/cilium/cilium/pkg/maps/ctmap"
+	"github.com/cilium/cilium/pkg/u8proto"
+
+	. "gopkg.in/check.v1"
+)
+
+func (ds *DaemonSuite) TestDrainedLBBackends(c *C) {
+	now := time.Unix(1000, 0)
+	backend := func(ip string, protocol types.L4Type, deadline time.Time) types.LBBackEnd {
+		be, err := types.NewLBBackEnd(protocol, net.ParseIP(ip), 80, 0)
+		c.Assert(err, IsNil)
+		be.DrainDeadline = deadline
+		return *be
+	}
+	svc := types.LBSVC{
+		FE: types.L3n4AddrID{ID: 7},
+		BES: []types.LBBackEnd{
+			backend("10.0.0.1", types.TCP, time.Time{}),
+			backend("10.0.0.2", types.TCP, now.Add(time.Minute)),
+			backend("10.0.0.3", types.TCP, now.Add(time.Minute)),
+			backend("10.0.0.4", types.NONE, now.Add(time.Minute)),
+			backend("10.0.0.5", types.TCP, now),
+			backend("10.0.0.6", types.TCP, now.Add(time.Minute)),
+		},
+	}
+
+	flows := []ctmap.FlowEntry{
+		{DstIP: net.ParseIP("10.0.0.2"), DstPort: 80, Proto: u8proto.TCP, RevNAT: 7, Lifetime: 20},
+		// Expired connection
+		{DstIP: net.ParseIP("10.0.0.3"), DstPort: 80, Proto: u8proto.TCP, RevNAT: 7, Lifetime: 5},
+		// Connections of other protocols are not waited for
+		{DstIP: net.ParseIP("10.0.0.3"), DstPort: 80, Proto: u8proto.UDP, RevNAT: 7, Lifetime: 20},
+		{DstIP: net.ParseIP("10.0.0.4"), DstPort: 80, Proto: u8proto.UDP, RevNAT: 7, Lifetime: 20},
+		{DstIP: net.ParseIP("10.0.0.5"), DstPort: 80, Proto: u8proto.TCP, RevNAT: 7, Lifetime: 20},
+		// Connections of another service or not load-balanced
+		{DstIP: net.ParseIP("10.0.0.6"), DstPort: 80, Proto: u8proto.TCP, RevNAT: 8, Lifetime: 20},
+		{DstIP: net.ParseIP("10.0.0.6"), DstPort: 80, Proto: u8proto.TCP, Lifetime: 20},
+	}
+	conns := lbBackendConns(flows, 10)
+
+	c.Assert(drainedLBBackends(svc, conns, now), DeepEquals, map[string]struct{}{
+		"10.0.0.3:80": {},
+		"10.0.0.4:80": {},
+		"10.0.0.5:80": {},
+		"10.0.0.6:80": {},
+	})
+
+	// Backends drain until their deadline if the connections are unknown
+	c.Assert(drainedLBBackends(svc, nil, now), DeepEquals, map[string]struct{}{
+		"10.0.0.5:80": {},
+	})
+
+	// Without a backend left to take over their new connections, all
+	// draining backends are removed
+	svc.BES = svc.BES[1:]
+	c.Assert(drainedLBBackends(svc, conns, now), HasLen, len(svc.BES))
+}
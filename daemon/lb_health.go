@@ -0,0 +1,266 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cilium/cilium/common/types"
+	"github.com/cilium/cilium/pkg/controller"
+	"github.com/cilium/cilium/pkg/lbhealth"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/maps/lbmap"
+	"github.com/cilium/cilium/pkg/metrics"
+
+	"github.com/sirupsen/logrus"
+)
+
+// lbHealthCheckRunInterval is the interval in which the health check
+// controller looks for services which are due for a health check
+const lbHealthCheckRunInterval = time.Second
+
+// lbHealthService is the health check state of a service
+type lbHealthService struct {
+	tracker   *lbhealth.Tracker
+	nextCheck time.Time
+}
+
+// lbHealthChecker runs the health checks of the backends of all services with
+// a health check. It is only used by the health check controller.
+type lbHealthChecker struct {
+	d        *Daemon
+	services map[types.ServiceID]*lbHealthService
+}
+
+// startLBHealthChecks starts the controller running the health checks of the
+// service backends.
+func (d *Daemon) startLBHealthChecks() {
+	checker := &lbHealthChecker{
+		d:        d,
+		services: map[types.ServiceID]*lbHealthService{},
+	}
+
+	controller.NewManager().UpdateController("lb-health-check",
+		controller.ControllerParams{
+			DoFunc:      checker.run,
+			RunInterval: lbHealthCheckRunInterval,
+		})
+}
+
+// dueServices returns a copy of the services whose health check is due and
+// forgets the state of services which no longer have a health check.
+func (c *lbHealthChecker) dueServices(now time.Time) []types.LBSVC {
+	lb := c.d.loadBalancer
+	lb.BPFMapMU.RLock()
+	defer lb.BPFMapMU.RUnlock()
+
+	for id := range c.services {
+		if svc, ok := lb.SVCMapID[id]; !ok || !svc.Options.HealthCheck.IsEnabled() {
+			delete(c.services, id)
+		}
+	}
+
+	due := []types.LBSVC{}
+	for id, svc := range lb.SVCMapID {
+		if !svc.Options.HealthCheck.IsEnabled() {
+			continue
+		}
+
+		state, ok := c.services[id]
+		if !ok {
+			state = &lbHealthService{tracker: lbhealth.NewTracker()}
+			c.services[id] = state
+		}
+		if now.Before(state.nextCheck) {
+			continue
+		}
+		state.nextCheck = now.Add(svc.Options.HealthCheck.Interval)
+
+		cpy := *svc
+		cpy.BES = append([]types.LBBackEnd{}, svc.BES...)
+		due = append(due, cpy)
+	}
+
+	return due
+}
+
+// run checks the backends of all services whose health check is due and
+// updates the lb maps with the backends whose health has changed.
+func (c *lbHealthChecker) run() error {
+	due := c.dueServices(time.Now())
+
+	// Probe all backends concurrently, each probe is bounded by the
+	// timeout of the health check
+	results := make([][]error, len(due))
+	var wg sync.WaitGroup
+	for i := range due {
+		results[i] = make([]error, len(due[i].BES))
+		for j := range due[i].BES {
+			wg.Add(1)
+			go func(i, j int) {
+				defer wg.Done()
+				results[i][j] = lbhealth.Probe(due[i].Options.HealthCheck, due[i].BES[j])
+			}(i, j)
+		}
+	}
+	wg.Wait()
+
+	var errs []error
+	for i, svc := range due {
+		state := c.services[svc.FE.ID]
+
+		addrs := map[string]struct{}{}
+		health := map[string]types.LBBackEndHealth{}
+		for j, be := range svc.BES {
+			addr := be.L3n4Addr.String()
+			addrs[addr] = struct{}{}
+
+			if results[i][j] == nil {
+				metrics.ServiceHealthChecks.WithLabelValues(metrics.LabelValueOutcomeSuccess).Inc()
+			} else {
+				metrics.ServiceHealthChecks.WithLabelValues(metrics.LabelValueOutcomeFail).Inc()
+			}
+
+			health[addr] = state.tracker.Update(svc.Options.HealthCheck, addr, be.Health, results[i][j])
+			if health[addr] != be.Health {
+				log.WithFields(logrus.Fields{
+					logfields.ServiceName: svc.FE.String(),
+					"backend":             addr,
+					"health":              health[addr],
+				}).WithError(results[i][j]).Info("Health of service backend changed")
+			}
+		}
+		state.tracker.Prune(addrs)
+
+		if err := c.d.setLBBackendHealth(svc.FE.ID, svc.Sha256, health); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	c.d.updateLBHealthMetrics()
+
+	if len(errs) > 0 {
+		return fmt.Errorf("unable to update health of service backends: %v", errs)
+	}
+	return nil
+}
+
+// setLBBackendHealth sets the health of the backends of the service with the
+// given ID and frontend hash. health is keyed by the backend address. The lb
+// maps are updated if the set of backends receiving new connections changes.
+// Services which have been deleted or changed their frontend in the meantime
+// are ignored.
+func (d *Daemon) setLBBackendHealth(id types.ServiceID, sha256 string, health map[string]types.LBBackEndHealth) error {
+	d.loadBalancer.BPFMapMU.Lock()
+	defer d.loadBalancer.BPFMapMU.Unlock()
+
+	cur, ok := d.loadBalancer.SVCMapID[id]
+	if !ok || cur.Sha256 != sha256 {
+		return nil
+	}
+
+	svc := *cur
+	svc.BES = make([]types.LBBackEnd, len(cur.BES))
+	changed := false
+	for i, be := range cur.BES {
+		if h, ok := health[be.L3n4Addr.String()]; ok && h != be.Health {
+			be.Health = h
+			changed = true
+		}
+		svc.BES[i] = be
+	}
+	if !changed {
+		return nil
+	}
+
+	if !sameBackends(cur.ActiveBackends(), svc.ActiveBackends()) {
+		fe, besValues, err := lbmap.LBSVC2ServiceKeynValue(svc)
+		if err != nil {
+			return err
+		}
+		if err := d.addSVC2BPFMap(svc, fe, besValues, false); err != nil {
+			return fmt.Errorf("unable to update backends of service %s: %s", svc.FE.String(), err)
+		}
+	}
+
+	d.loadBalancer.AddService(svc)
+	return nil
+}
+
+// inheritLBBackendHealth copies the health of the backends of the service
+// currently known for svc to the backends of svc which are still part of it.
+// New backends of services with a health check have unknown health. Must be
+// called with BPFMapMU held.
+func (d *Daemon) inheritLBBackendHealth(svc *types.LBSVC) {
+	health := map[string]types.LBBackEndHealth{}
+	if old, ok := d.loadBalancer.SVCMap[svc.Sha256]; ok && old.Options.HealthCheck.IsEnabled() {
+		for _, be := range old.BES {
+			health[be.L3n4Addr.String()] = be.Health
+		}
+	}
+
+	for i := range svc.BES {
+		switch {
+		case !svc.Options.HealthCheck.IsEnabled():
+			svc.BES[i].Health = ""
+		case health[svc.BES[i].L3n4Addr.String()] != "":
+			svc.BES[i].Health = health[svc.BES[i].L3n4Addr.String()]
+		default:
+			svc.BES[i].Health = types.LBBackEndHealthUnknown
+		}
+	}
+}
+
+// sameBackends returns true if a and b contain the same backends in the same
+// order
+func sameBackends(a, b []types.LBBackEnd) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].L3n4Addr.String() != b[i].L3n4Addr.String() || a[i].Weight != b[i].Weight {
+			return false
+		}
+	}
+	return true
+}
+
+// updateLBHealthMetrics updates the metrics with the number of backends of
+// each health checked service by health.
+func (d *Daemon) updateLBHealthMetrics() {
+	d.loadBalancer.BPFMapMU.RLock()
+	defer d.loadBalancer.BPFMapMU.RUnlock()
+
+	metrics.ServiceBackends.Reset()
+	for _, svc := range d.loadBalancer.SVCMapID {
+		if !svc.Options.HealthCheck.IsEnabled() {
+			continue
+		}
+
+		counts := map[types.LBBackEndHealth]int{
+			types.LBBackEndHealthUnknown:   0,
+			types.LBBackEndHealthHealthy:   0,
+			types.LBBackEndHealthUnhealthy: 0,
+		}
+		for _, be := range svc.BES {
+			counts[be.Health]++
+		}
+		for health, n := range counts {
+			metrics.ServiceBackends.WithLabelValues(svc.FE.String(), string(health)).Set(float64(n))
+		}
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/common/types"
+	"github.com/cilium/cilium/pkg/controller"
+	"github.com/cilium/cilium/pkg/maps/lbmap"
+	"github.com/cilium/cilium/pkg/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// lbStatsGCInterval is the interval in which the traffic statistics of
+// backends which are no longer part of their service are removed
+const lbStatsGCInterval = time.Minute
+
+// lbBackendStatsID returns the identifier of the traffic statistics of the
+// backend be of svc
+func lbBackendStatsID(svc *types.LBSVC, be types.LBBackEnd) lbmap.StatsID {
+	return lbmap.NewStatsID(uint16(svc.FE.ID), be.IP, be.Port)
+}
+
+// lbStatsModel returns the API model of the traffic statistics s
+func lbStatsModel(s lbmap.StatsValue) *models.ServiceStatistics {
+	return &models.ServiceStatistics{
+		Packets:     int64(s.Packets),
+		Bytes:       int64(s.Bytes),
+		Connections: int64(s.Conns),
+	}
+}
+
+// addLBStatsModel sets the traffic statistics of the frontend and of the
+// backends of svc in model, the model of svc. The statistics of the frontend
+// are the sum of the statistics of its backends.
+func addLBStatsModel(model *models.Service, svc *types.LBSVC, stats map[lbmap.StatsID]lbmap.StatsValue) {
+	total := lbmap.StatsValue{}
+	for i, be := range svc.BES {
+		s := stats[lbBackendStatsID(svc, be)]
+		total.Add(s)
+		model.BackendAddresses[i].Statistics = lbStatsModel(s)
+	}
+	model.Statistics = lbStatsModel(total)
+}
+
+// lbStatsCollector exposes the traffic statistics of the service backends
+// as prometheus metrics.
+type lbStatsCollector struct {
+	d *Daemon
+}
+
+func (c *lbStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- metrics.ServiceBackendPackets
+	ch <- metrics.ServiceBackendBytes
+	ch <- metrics.ServiceBackendConnections
+}
+
+func (c *lbStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	stats, err := lbmap.DumpStats()
+	if err != nil {
+		log.WithError(err).Warn("Unable to retrieve traffic statistics of services")
+		return
+	}
+
+	c.d.loadBalancer.BPFMapMU.RLock()
+	defer c.d.loadBalancer.BPFMapMU.RUnlock()
+
+	for _, svc := range c.d.loadBalancer.SVCMapID {
+		for _, be := range svc.BES {
+			id := lbBackendStatsID(svc, be)
+			s, ok := stats[id]
+			if !ok {
+				continue
+			}
+			// Each backend is reported once
+			delete(stats, id)
+
+			labels := []string{svc.FE.String(), id.Backend}
+			ch <- prometheus.MustNewConstMetric(metrics.ServiceBackendPackets,
+				prometheus.CounterValue, float64(s.Packets), labels...)
+			ch <- prometheus.MustNewConstMetric(metrics.ServiceBackendBytes,
+				prometheus.CounterValue, float64(s.Bytes), labels...)
+			ch <- prometheus.MustNewConstMetric(metrics.ServiceBackendConnections,
+				prometheus.CounterValue, float64(s.Conns), labels...)
+		}
+	}
+}
+
+// startLBStats exposes the traffic statistics of the service backends as
+// metrics and starts the controller removing the statistics of backends
+// which have been removed from their service.
+func (d *Daemon) startLBStats() {
+	if !d.conf.EnableLBStats {
+		return
+	}
+
+	metrics.MustRegister(&lbStatsCollector{d: d})
+
+	controller.NewManager().UpdateController("lb-stats-gc",
+		controller.ControllerParams{
+			DoFunc:      d.gcLBStats,
+			RunInterval: lbStatsGCInterval,
+		})
+}
+
+// gcLBStats removes the traffic statistics of all backends which are no
+// longer part of their service.
+func (d *Daemon) gcLBStats() error {
+	d.loadBalancer.BPFMapMU.RLock()
+	defer d.loadBalancer.BPFMapMU.RUnlock()
+
+	alive := map[lbmap.StatsID]struct{}{}
+	for _, svc := range d.loadBalancer.SVCMapID {
+		for _, be := range svc.BES {
+			alive[lbBackendStatsID(svc, be)] = struct{}{}
+		}
+	}
+
+	if n := lbmap.GCStats(alive); n > 0 {
+		log.WithField("backends", n).Debug("Removed traffic statistics of removed service backends")
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/common/types"
+	"github.com/cilium/cilium/pkg/maps/lbmap"
+
+	. "gopkg.in/check.v1"
+)
+
+func (ds *DaemonSuite) TestAddLBStatsModel(c *C) {
+	fe, err := types.NewL3n4AddrID(types.TCP, net.ParseIP("10.1.0.1"), 80, 7)
+	c.Assert(err, IsNil)
+	svc := &types.LBSVC{FE: *fe}
+	for _, ip := range []string{"10.0.0.1", "10.0.0.2", "f00d::1"} {
+		be, err := types.NewLBBackEnd(types.TCP, net.ParseIP(ip), 8080, 0)
+		c.Assert(err, IsNil)
+		svc.BES = append(svc.BES, *be)
+	}
+
+	stats := map[lbmap.StatsID]lbmap.StatsValue{
+		lbmap.NewStatsID(7, net.ParseIP("10.0.0.1"), 8080): {Packets: 10, Bytes: 1000, Conns: 2},
+		lbmap.NewStatsID(7, net.ParseIP("f00d::1"), 8080):  {Packets: 5, Bytes: 500, Conns: 1},
+		// Statistics of other services and ports are ignored
+		lbmap.NewStatsID(8, net.ParseIP("10.0.0.2"), 8080): {Packets: 1, Bytes: 100},
+		lbmap.NewStatsID(7, net.ParseIP("10.0.0.2"), 80):   {Packets: 1, Bytes: 100},
+	}
+
+	model := svc.GetModel()
+	addLBStatsModel(model, svc, stats)
+
+	c.Assert(model.Statistics, DeepEquals, &models.ServiceStatistics{Packets: 15, Bytes: 1500, Connections: 3})
+	c.Assert(model.BackendAddresses[0].Statistics, DeepEquals, &models.ServiceStatistics{Packets: 10, Bytes: 1000, Connections: 2})
+	c.Assert(model.BackendAddresses[1].Statistics, DeepEquals, &models.ServiceStatistics{})
+	c.Assert(model.BackendAddresses[2].Statistics, DeepEquals, &models.ServiceStatistics{Packets: 5, Bytes: 500, Connections: 1})
+}
@@ -16,11 +16,13 @@ package main
 
 import (
 	"fmt"
+	"time"
 
 	. "github.com/cilium/cilium/api/v1/server/restapi/service"
 	"github.com/cilium/cilium/common/types"
 	"github.com/cilium/cilium/pkg/apierror"
 	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/controller"
 	"github.com/cilium/cilium/pkg/logging/logfields"
 	"github.com/cilium/cilium/pkg/maps/lbmap"
 
@@ -29,18 +31,26 @@ import (
 )
 
 // addSVC2BPFMap adds the given bpf service to the bpf maps. If addRevNAT is set, adds the
-// RevNAT value (feCilium.L3n4Addr) to the lb's RevNAT map for the given feCilium.ID.
-func (d *Daemon) addSVC2BPFMap(feCilium types.L3n4AddrID, feBPF lbmap.ServiceKey,
+// RevNAT value (svc.FE.L3n4Addr) to the lb's RevNAT map for the given svc.FE.ID.
+func (d *Daemon) addSVC2BPFMap(svc types.LBSVC, feBPF lbmap.ServiceKey,
 	besBPF []lbmap.ServiceValue, addRevNAT bool) error {
+	feCilium := svc.FE
 	log.WithField(logfields.ServiceName, feCilium.String()).Debug("adding service to BPF maps")
 
+	// Keep the previous backends of the service to move the clients with
+	// session affinity to the new backend indexes.
+	var oldBES []types.LBBackEnd
+	if oldSVC, ok := d.loadBalancer.SVCMap[feCilium.SHA256Sum()]; ok && oldSVC.FE.ID == feCilium.ID {
+		oldBES = oldSVC.ActiveBackends()
+	}
+
 	// Try to delete service before adding it and ignore errors as it might not exist.
 	err := d.svcDeleteByFrontendLocked(&feCilium.L3n4Addr)
 	if err != nil {
 		log.WithError(err).WithField(logfields.ServiceName, feCilium.L3n4Addr.String()).Debug("error deleting service before adding it")
 	}
 
-	err = lbmap.AddSVC2BPFMap(feBPF, besBPF, addRevNAT, int(feCilium.ID))
+	err = lbmap.AddSVC2BPFMap(feBPF, besBPF, svc.Options.Algorithm, addRevNAT, int(feCilium.ID))
 	if err != nil {
 		if addRevNAT {
 			delete(d.loadBalancer.RevNATMap, feCilium.ID)
@@ -48,6 +58,14 @@ func (d *Daemon) addSVC2BPFMap(feCilium types.L3n4AddrID, feBPF lbmap.ServiceKey
 		return err
 	}
 
+	if err := syncLBAffinity(svc, oldBES); err != nil {
+		log.WithError(err).WithField(logfields.ServiceName, feCilium.String()).Warn("Unable to update session affinity of service")
+	}
+
+	if err := syncLBDrain(svc, feBPF); err != nil {
+		log.WithError(err).WithField(logfields.ServiceName, feCilium.String()).Warn("Unable to update draining backends of service")
+	}
+
 	if addRevNAT {
 		log.WithField(logfields.ServiceName, feCilium.String()).Debug("adding service to RevNATMap")
 		d.loadBalancer.RevNATMap[feCilium.ID] = *feCilium.L3n4Addr.DeepCopy()
@@ -55,16 +73,87 @@ func (d *Daemon) addSVC2BPFMap(feCilium types.L3n4AddrID, feBPF lbmap.ServiceKey
 	return nil
 }
 
+// lbAffinityGCInterval is the interval in which clients with expired session
+// affinity are removed from the BPF maps
+const lbAffinityGCInterval = time.Minute
+
+// startLBAffinityGC starts the controller removing expired session affinity
+// entries from the BPF maps.
+func (d *Daemon) startLBAffinityGC() {
+	if !d.conf.EnableSessionAffinity {
+		return
+	}
+
+	controller.NewManager().UpdateController("lb-affinity-gc",
+		controller.ControllerParams{
+			DoFunc: func() error {
+				now, err := bpf.GetMtime()
+				if err != nil {
+					return err
+				}
+
+				// Prevent the GC from racing with the
+				// remapping of clients on service updates
+				d.loadBalancer.BPFMapMU.RLock()
+				deleted := lbmap.GCAffinity(now)
+				d.loadBalancer.BPFMapMU.RUnlock()
+
+				if deleted > 0 {
+					log.WithField("deleted", deleted).Debug("Removed expired session affinity entries")
+				}
+				return nil
+			},
+			RunInterval: lbAffinityGCInterval,
+		})
+}
+
+// syncLBAffinity enables or disables session affinity for svc in the BPF
+// maps. Clients which have been sent to a backend in oldBES keep their
+// backend if it is still part of svc and are assigned a new backend
+// otherwise.
+func syncLBAffinity(svc types.LBSVC, oldBES []types.LBBackEnd) error {
+	revNATID := uint16(svc.FE.ID)
+	if !svc.Options.SessionAffinity {
+		// The clients of the service are removed by the GC
+		return lbmap.DeleteAffinityMatch(revNATID)
+	}
+
+	if err := lbmap.UpdateAffinityMatch(revNATID, svc.Options.SessionAffinityTimeout); err != nil {
+		return err
+	}
+
+	// Slave indexes start at 1
+	newIdx := map[string]uint16{}
+	for i, be := range svc.ActiveBackends() {
+		newIdx[be.L3n4Addr.String()] = uint16(i + 1)
+	}
+	slaves := map[uint16]uint16{}
+	for i, be := range oldBES {
+		if idx, ok := newIdx[be.L3n4Addr.String()]; ok {
+			slaves[uint16(i+1)] = idx
+		}
+	}
+
+	_, err := lbmap.RemapAffinity(revNATID, svc.FE.IsIPv6(), slaves)
+	return err
+}
+
 // SVCAdd is the public method to add services. We assume the ID provided is not in
 // sync with the KVStore. If that's the, case the service won't be used and an error is
 // returned to the caller.
 //
 // Returns true if service was created.
-func (d *Daemon) SVCAdd(feL3n4Addr types.L3n4AddrID, be []types.LBBackEnd, addRevNAT bool) (bool, error) {
+func (d *Daemon) SVCAdd(feL3n4Addr types.L3n4AddrID, be []types.LBBackEnd, opts types.LBSVCOptions, addRevNAT bool) (bool, error) {
 	log.WithField(logfields.ServiceID, feL3n4Addr.String()).Debug("adding service")
 	if feL3n4Addr.ID == 0 {
 		return false, fmt.Errorf("invalid service ID 0")
 	}
+	if err := d.checkLBAlgorithm(opts.Algorithm); err != nil {
+		return false, err
+	}
+	if err := d.checkLBSessionAffinity(opts.SessionAffinity); err != nil {
+		return false, err
+	}
 	// Check if the service is already registered with this ID.
 	feAddr, err := GetL3n4AddrID(uint32(feL3n4Addr.ID))
 	if err != nil {
@@ -88,17 +177,36 @@ func (d *Daemon) SVCAdd(feL3n4Addr types.L3n4AddrID, be []types.LBBackEnd, addRe
 		return false, fmt.Errorf("service ID %d is already registered to L3n4Addr %s, please choose a different ID", feL3n4Addr.ID, feAddr.String())
 	}
 
-	return d.svcAdd(feL3n4Addr, be, addRevNAT)
+	return d.svcAdd(feL3n4Addr, be, opts, addRevNAT)
+}
+
+// checkLBAlgorithm returns an error if the datapath does not support the
+// given backend selection algorithm
+func (d *Daemon) checkLBAlgorithm(algorithm types.LBAlgorithm) error {
+	if algorithm == types.LBAlgorithmMaglev && !d.conf.EnableMaglev {
+		return fmt.Errorf("load-balancing algorithm %s requires --enable-maglev", algorithm)
+	}
+	return nil
+}
+
+// checkLBSessionAffinity returns an error if session affinity is requested
+// but not supported by the datapath
+func (d *Daemon) checkLBSessionAffinity(sessionAffinity bool) error {
+	if sessionAffinity && !d.conf.EnableSessionAffinity {
+		return fmt.Errorf("session affinity requires --enable-session-affinity")
+	}
+	return nil
 }
 
-// svcAdd adds a service from the given feL3n4Addr (frontend) and LBBackEnd (backends).
+// svcAdd adds a service from the given feL3n4Addr (frontend) and LBBackEnd (backends)
+// configured with the given options.
 // If addRevNAT is set, the RevNAT entry is also created for this particular service.
 // If any of the backend addresses set in bes have a different L3 address type than the
 // one set in fe, it returns an error without modifying the bpf LB map. If any backend
 // entry fails while updating the LB map, the frontend won't be inserted in the LB map
 // therefore there won't be any traffic going to the given backends.
 // All of the backends added will be DeepCopied to the internal load balancer map.
-func (d *Daemon) svcAdd(feL3n4Addr types.L3n4AddrID, bes []types.LBBackEnd, addRevNAT bool) (bool, error) {
+func (d *Daemon) svcAdd(feL3n4Addr types.L3n4AddrID, bes []types.LBBackEnd, opts types.LBSVCOptions, addRevNAT bool) (bool, error) {
 	log.WithFields(logrus.Fields{
 		logfields.ServiceID: feL3n4Addr.String(),
 		logfields.Object:    logfields.Repr(bes),
@@ -112,21 +220,28 @@ func (d *Daemon) svcAdd(feL3n4Addr types.L3n4AddrID, bes []types.LBBackEnd, addR
 	}
 
 	svc := types.LBSVC{
-		FE:     feL3n4Addr,
-		BES:    beCpy,
-		Sha256: feL3n4Addr.L3n4Addr.SHA256Sum(),
+		FE:      feL3n4Addr,
+		BES:     beCpy,
+		Sha256:  feL3n4Addr.L3n4Addr.SHA256Sum(),
+		Options: opts,
 	}
 
-	fe, besValues, err := lbmap.LBSVC2ServiceKeynValue(svc)
+	d.loadBalancer.BPFMapMU.Lock()
+	defer d.loadBalancer.BPFMapMU.Unlock()
+
+	// Backends removed from the service keep serving their established
+	// connections until they have drained
+	if old, ok := d.loadBalancer.SVCMap[svc.Sha256]; ok && d.conf.LBDrainTimeout > 0 {
+		svc.BES = types.DrainBackends(old.BES, svc.BES, time.Now().Add(d.conf.LBDrainTimeout))
+	}
+	d.inheritLBBackendHealth(&svc)
 
+	fe, besValues, err := lbmap.LBSVC2ServiceKeynValue(svc)
 	if err != nil {
 		return false, err
 	}
 
-	d.loadBalancer.BPFMapMU.Lock()
-	defer d.loadBalancer.BPFMapMU.Unlock()
-
-	err = d.addSVC2BPFMap(feL3n4Addr, fe, besValues, addRevNAT)
+	err = d.addSVC2BPFMap(svc, fe, besValues, addRevNAT)
 	if err != nil {
 		return false, err
 	}
@@ -164,6 +279,11 @@ func (h *putServiceID) Handle(params PutServiceIDParams) middleware.Responder {
 		backends = append(backends, *b)
 	}
 
+	opts, err := types.NewLBSVCOptionsFromModel(params.Config)
+	if err != nil {
+		return apierror.Error(PutServiceIDFailureCode, err)
+	}
+
 	revnat := false
 	if params.Config.Flags != nil {
 		revnat = params.Config.Flags.DirectServerReturn
@@ -173,7 +293,7 @@ func (h *putServiceID) Handle(params PutServiceIDParams) middleware.Responder {
 	// Add flag to indicate whether service should be registered in
 	// global key value store
 
-	if created, err := h.d.SVCAdd(frontend, backends, revnat); err != nil {
+	if created, err := h.d.SVCAdd(frontend, backends, opts, revnat); err != nil {
 		return apierror.Error(PutServiceIDFailureCode, err)
 	} else if created {
 		return NewPutServiceIDCreated()
@@ -285,6 +405,12 @@ func (d *Daemon) svcDeleteBPF(svc *types.LBSVC) error {
 		return fmt.Errorf("deleting service failed for %s: %s", svcKey, err)
 	}
 
+	// The clients of the service are removed by the session affinity GC
+	// once the service is no longer matched.
+	if err := lbmap.DeleteAffinityMatch(uint16(svc.FE.ID)); err != nil {
+		return fmt.Errorf("deleting session affinity failed for %s: %s", svcKey, err)
+	}
+
 	return nil
 }
 
@@ -305,7 +431,15 @@ func (h *getServiceID) Handle(params GetServiceIDParams) middleware.Responder {
 	defer d.loadBalancer.BPFMapMU.RUnlock()
 
 	if svc, ok := d.loadBalancer.SVCMapID[types.ServiceID(params.ID)]; ok {
-		return NewGetServiceIDOK().WithPayload(svc.GetModel())
+		model := svc.GetModel()
+		if d.conf.EnableLBStats {
+			if stats, err := lbmap.DumpStats(); err != nil {
+				log.WithError(err).Warn("Unable to retrieve traffic statistics of service")
+			} else {
+				addLBStatsModel(model, svc, stats)
+			}
+		}
+		return NewGetServiceIDOK().WithPayload(model)
 	}
 	return NewGetServiceIDNotFound()
 }
@@ -327,8 +461,9 @@ func (d *Daemon) svcGetBySHA256Sum(feL3n4SHA256Sum string) *types.LBSVC {
 		beCpy = append(beCpy, v)
 	}
 	return &types.LBSVC{
-		FE:  *v.FE.DeepCopy(),
-		BES: beCpy,
+		FE:      *v.FE.DeepCopy(),
+		BES:     beCpy,
+		Options: v.Options,
 	}
 }
 
@@ -493,7 +628,13 @@ func (d *Daemon) SyncLBMap() error {
 				" This entry will be removed from the bpf's LB map.", svc.FE.String(), svc.BES, err)
 		}
 
-		err = d.addSVC2BPFMap(svc.FE, fe, besValues, false)
+		// The session affinity of the service is recorded for the
+		// old ID and moved to the new ID by addSVC2BPFMap.
+		if err := lbmap.DeleteAffinityMatch(uint16(oldID)); err != nil {
+			scopedLog.WithError(err).Warn("Unable to remove old session affinity entry")
+		}
+
+		err = d.addSVC2BPFMap(svc, fe, besValues, false)
 		if err != nil {
 			return fmt.Errorf("Unable to add service FE: %s: %s."+
 				" This entry will be removed from the bpf's LB map.", svc.FE.String(), err)
@@ -521,7 +662,32 @@ func (d *Daemon) SyncLBMap() error {
 			return
 		}
 
+		// The deadline of draining backends is not recorded in the
+		// BPF maps, they drain for the full timeout after a restart
+		slaveKey := lbmap.L3n4Addr2ServiceKey(*fe)
+		slaveKey.SetBackend(svcKey.GetBackend())
+		if lbmap.LookupServiceDrain(slaveKey) {
+			be.DrainDeadline = time.Now().Add(d.conf.LBDrainTimeout)
+		}
+
 		svc := newSVCMap.AddFEnBE(fe, be, svcKey.GetBackend())
+
+		// The algorithm of the service is only recorded in the BPF
+		// maps by the presence of a Maglev lookup table. Lookup tables
+		// of a previous run with Maglev enabled are not restored.
+		masterKey := lbmap.L3n4Addr2ServiceKey(*fe)
+		if d.conf.EnableMaglev && lbmap.LookupServiceMaglev(masterKey) {
+			svc.Options.Algorithm = types.LBAlgorithmMaglev
+		} else {
+			svc.Options.Algorithm = types.LBAlgorithmRandom
+		}
+		if d.conf.EnableSessionAffinity {
+			timeout, ok := lbmap.LookupAffinityMatch(uint16(fe.ID))
+			svc.Options.SessionAffinity = ok
+			svc.Options.SessionAffinityTimeout = timeout
+		}
+		newSVCMap[svc.Sha256] = *svc
+
 		newSVCList = append(newSVCList, svc)
 	}
 
@@ -26,19 +26,26 @@ import (
 	"strings"
 	"time"
 
+	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/api/v1/server"
 	"github.com/cilium/cilium/api/v1/server/restapi"
 	health "github.com/cilium/cilium/cilium-health/launch"
 	"github.com/cilium/cilium/common"
+	"github.com/cilium/cilium/common/types"
 	"github.com/cilium/cilium/daemon/defaults"
 	"github.com/cilium/cilium/daemon/options"
+	"github.com/cilium/cilium/pkg/annotation"
 	"github.com/cilium/cilium/pkg/bpf"
 	"github.com/cilium/cilium/pkg/endpoint"
 	"github.com/cilium/cilium/pkg/endpointmanager"
 	"github.com/cilium/cilium/pkg/envoy"
 	"github.com/cilium/cilium/pkg/flowdebug"
+	"github.com/cilium/cilium/pkg/flowexport"
+	"github.com/cilium/cilium/pkg/identity"
+	"github.com/cilium/cilium/pkg/ipam"
 	"github.com/cilium/cilium/pkg/k8s"
 	"github.com/cilium/cilium/pkg/kvstore"
+	"github.com/cilium/cilium/pkg/kvstore/allocator"
 	"github.com/cilium/cilium/pkg/labels"
 	"github.com/cilium/cilium/pkg/logging"
 	"github.com/cilium/cilium/pkg/logging/logfields"
@@ -96,6 +103,8 @@ var (
 	dockerEndpoint        string
 	enableLogstash        bool
 	enableTracing         bool
+	ipamBlockSize         int
+	ipamClusterRange      string
 	k8sAPIServer          string
 	k8sKubeConfigPath     string
 	kvStore               string
@@ -108,6 +117,7 @@ var (
 	prometheusServeAddr   string
 	singleClusterRoute    bool
 	socketPath            string
+	staticIdentitiesFile  string
 	tracePayloadLen       int
 	useEnvoy              bool // deprecated, value is ignored
 	v4Address             string
@@ -123,6 +133,7 @@ var (
 var (
 	logOpts               = make(map[string]string)
 	kvStoreOpts           = make(map[string]string)
+	ipamPools             = make(map[string]string)
 	containerRuntimesOpts = make(map[string]string)
 	cfgFile               string
 
@@ -337,13 +348,35 @@ func init() {
 		false, "Disable east-west K8s load balancing by cilium")
 	flags.StringVarP(&dockerEndpoint,
 		"docker", "e", workloads.GetRuntimeDefaultOpt(workloads.Docker).Endpoint, "Path to docker runtime socket (DEPRECATED: use container-runtime-endpoint instead)")
+	flags.BoolVar(&config.EnableMaglev,
+		"enable-maglev", false, "Enable the Maglev backend selection algorithm for services requesting it")
+	flags.BoolVar(&config.EnableLBStats,
+		"enable-lb-stats", false, "Enable traffic statistics of service backends")
 	flags.String("enable-policy", endpoint.DefaultEnforcement, "Enable policy enforcement")
+	flags.BoolVar(&config.EnableSessionAffinity,
+		"enable-session-affinity", false, "Enable session affinity for services requesting it")
 	flags.BoolVar(&enableTracing,
 		"enable-tracing", false, "Enable tracing while determining policy (debugging)")
 	flags.String("envoy-log", "/var/log/cilium-envoy.log", "Path to Envoy log")
 	flags.BoolVar(&useEnvoy,
 		"envoy-proxy", true, "This flag is deprecated and will be removed in the next release")
 	flags.MarkHidden("envoy-proxy")
+	flags.StringVar(&config.FlowExport.Collector,
+		"flow-export-collector", "", "Export conntrack entries as flow records to the UDP collector at host:port")
+	flags.DurationVar(&config.FlowExport.Interval,
+		"flow-export-interval", flowexport.DefaultInterval, "Interval in which conntrack entries are exported")
+	flags.StringVar(&config.FlowExport.Protocol,
+		"flow-export-protocol", flowexport.ProtocolIPFIX, "Flow export protocol { "+flowexport.ProtocolIPFIX+" | "+flowexport.ProtocolNetFlow9+" }")
+	flags.BoolVar(&config.FlowGraph,
+		"flow-graph", false, "Aggregate observed flows into a dependency graph of identities and services")
+	flags.IntVar(&config.FlowStoreSize,
+		"flow-store-size", 0, "Number of recent flows to store per endpoint (0 to disable)")
+	flags.IntVar(&ipamBlockSize,
+		"ipam-block-size", ipam.DefaultBlockMaskSize, "Prefix length of the address blocks claimed out of --ipam-cluster-range, must be identical on all nodes")
+	flags.StringVar(&ipamClusterRange,
+		"ipam-cluster-range", "", "Allocate IPv4 addresses in blocks out of this cluster-wide range via the kvstore instead of using a per node range")
+	flags.Var(option.NewNamedMapOptions("ipam-pools", &ipamPools, nil),
+		"ipam-pool", "Named IPAM pool in the form name=cidr[,cidr] selectable via the "+annotation.IPAMPool+" annotation")
 	flags.IntVar(&v4ClusterCidrMaskSize,
 		"ipv4-cluster-cidr-mask-size", 8, "Mask size for the cluster wide CIDR")
 	flags.StringVar(&v4Prefix,
@@ -363,15 +396,19 @@ func init() {
 	flags.BoolVar(&config.KeepTemplates,
 		"keep-bpf-templates", false, "Do not restore BPF template files from binary")
 	flags.StringVar(&kvStore,
-		"kvstore", "", "Key-value store type")
+		"kvstore", "", "Key-value store type { consul | etcd | embedded }")
 	flags.Var(option.NewNamedMapOptions("kvstore-opts", &kvStoreOpts, nil),
 		"kvstore-opt", "Key-value store options")
+	flags.BoolVar(&config.KVStoreLocklessAllocation,
+		"kvstore-lockless-allocation", false, "Allocate identities and address blocks without kvstore locks if supported by the key-value store (requires all agents to support it)")
 	flags.StringVar(&labelPrefixFile,
 		"label-prefix-file", "", "Valid label prefixes file path")
 	flags.StringSliceVar(&validLabels,
 		"labels", []string{}, "List of label prefixes used to determine identity of an endpoint")
 	flags.StringVar(&config.LBInterface,
 		"lb", "", "Enables load balancer mode where load balancer bpf program is attached to the given interface")
+	flags.DurationVar(&config.LBDrainTimeout,
+		"lb-drain-timeout", types.DefaultLBDrainTimeout, "Time for which backends removed from a service keep serving their established connections (0 to disable)")
 	flags.StringVar(&config.LibDir,
 		"lib-dir", defaults.LibraryPath, "Directory path to store runtime build environment")
 	flags.StringSliceVar(&loggers,
@@ -388,6 +425,9 @@ func init() {
 		"nat46-range", node.DefaultNAT46Prefix, "IPv6 prefix to map IPv4 addresses to")
 	flags.BoolVar(&masquerade,
 		"masquerade", true, "Masquerade packets from endpoints leaving the host")
+	flags.StringVar(&config.MonitorSlowConsumerPolicy,
+		"monitor-slow-consumer-policy", models.MonitorStatusSlowConsumerPolicyDropNewest, "Policy of the node monitor for listeners which cannot keep up { "+
+			models.MonitorStatusSlowConsumerPolicyDropNewest+" | "+models.MonitorStatusSlowConsumerPolicyDropOldest+" | "+models.MonitorStatusSlowConsumerPolicyDisconnect+" }")
 	flags.StringVar(&v6Address,
 		"ipv6-node", "auto", "IPv6 address of node")
 	flags.StringVar(&v4Address,
@@ -400,6 +440,8 @@ func init() {
 		"Use a single cluster route instead of per node routes")
 	flags.StringVar(&socketPath,
 		"socket-path", defaults.SockPath, "Sets daemon's socket path to listen for connections")
+	flags.StringVar(&staticIdentitiesFile,
+		"static-identities", "", "Path to JSON file pinning label sets to static numeric identities")
 	flags.StringVar(&config.RunDir,
 		"state-dir", defaults.RuntimePath, "Directory path to store runtime state")
 	flags.StringVarP(&config.Tunnel,
@@ -591,6 +633,27 @@ func initEnv(cmd *cobra.Command) {
 			ModePreFilterNative, ModePreFilterGeneric)
 	}
 
+	if config.LBDrainTimeout < 0 {
+		log.Fatalf("Invalid setting for --lb-drain-timeout, must not be negative")
+	}
+
+	if config.FlowExport.Collector != "" {
+		if err := config.FlowExport.Validate(); err != nil {
+			log.WithError(err).Fatal("Invalid flow export configuration")
+		}
+	}
+
+	switch config.MonitorSlowConsumerPolicy {
+	case models.MonitorStatusSlowConsumerPolicyDropNewest,
+		models.MonitorStatusSlowConsumerPolicyDropOldest,
+		models.MonitorStatusSlowConsumerPolicyDisconnect:
+	default:
+		log.Fatalf("Invalid setting for --monitor-slow-consumer-policy, must be { %s, %s, %s }",
+			models.MonitorStatusSlowConsumerPolicyDropNewest,
+			models.MonitorStatusSlowConsumerPolicyDropOldest,
+			models.MonitorStatusSlowConsumerPolicyDisconnect)
+	}
+
 	scopedLog = log.WithField(logfields.Path, socketPath)
 	socketDir := path.Dir(socketPath)
 	if err := os.MkdirAll(socketDir, defaults.RuntimePathRights); err != nil {
@@ -626,6 +689,12 @@ func initEnv(cmd *cobra.Command) {
 
 	policy.SetPolicyEnabled(strings.ToLower(viper.GetString("enable-policy")))
 
+	if kvStore == kvstore.EmbeddedBackendName {
+		if _, ok := kvStoreOpts[kvstore.EmbeddedPathOption]; !ok {
+			kvStoreOpts[kvstore.EmbeddedPathOption] = filepath.Join(config.StateDir, defaults.EmbeddedKvstoreDB)
+		}
+	}
+
 	if err := kvstore.Setup(kvStore, kvStoreOpts); err != nil {
 		addrkey := fmt.Sprintf("%s.address", kvStore)
 		addr := kvStoreOpts[addrkey]
@@ -635,10 +704,18 @@ func initEnv(cmd *cobra.Command) {
 		}).Fatal("Unable to setup kvstore")
 	}
 
+	allocator.EnableLockless(config.KVStoreLocklessAllocation)
+
 	if err := labels.ParseLabelPrefixCfg(validLabels, labelPrefixFile); err != nil {
 		log.WithError(err).Fatal("Unable to parse Label prefix configuration")
 	}
 
+	if staticIdentitiesFile != "" {
+		if err := identity.LoadStaticIdentities(staticIdentitiesFile); err != nil {
+			log.WithError(err).Fatal("Unable to load static identities")
+		}
+	}
+
 	_, r, err := net.ParseCIDR(nat46prefix)
 	if err != nil {
 		log.WithError(err).WithField(logfields.V6Prefix, nat46prefix).Fatal("Invalid NAT46 prefix")
@@ -701,10 +778,18 @@ func runDaemon() {
 	policy.Init()
 	endpointmanager.EnableConntrackGC(!d.conf.IPv4Disabled, true)
 
+	if err := d.startFlowExport(); err != nil {
+		log.WithError(err).Fatal("Unable to start flow export")
+	}
+	if d.flowGraph != nil || d.flowStore != nil {
+		go d.receiveMonitorEvents()
+	}
+
 	if enableLogstash {
 		go EnableLogstash(logstashAddr, int(logstashProbeTimer))
 	}
 
+	d.nodeMonitor.SetArgs([]string{"--slow-consumer-policy", config.MonitorSlowConsumerPolicy})
 	go d.nodeMonitor.Run(path.Join(defaults.RuntimePath, defaults.EventsPipe))
 
 	// Launch cilium-health in the same namespace as cilium.
@@ -756,6 +841,8 @@ func runDaemon() {
 	// /config/
 	api.DaemonGetConfigHandler = NewGetConfigHandler(d)
 	api.DaemonPatchConfigHandler = NewPatchConfigHandler(d)
+	api.DaemonGetConfigLabelPrefixesHandler = NewGetConfigLabelPrefixesHandler(d)
+	api.DaemonPutConfigLabelPrefixesHandler = NewPutConfigLabelPrefixesHandler(d)
 
 	// /endpoint/
 	api.EndpointGetEndpointHandler = NewGetEndpointHandler(d)
@@ -772,6 +859,7 @@ func runDaemon() {
 
 	// /endpoint/{id}/labels/
 	api.EndpointGetEndpointIDLabelsHandler = NewGetEndpointIDLabelsHandler(d)
+	api.EndpointGetEndpointIDIdentityExplainHandler = NewGetEndpointIDIdentityExplainHandler(d)
 	api.EndpointPutEndpointIDLabelsHandler = NewPutEndpointIDLabelsHandler(d)
 
 	// /endpoint/{id}/log/
@@ -783,6 +871,8 @@ func runDaemon() {
 	// /identity/
 	api.PolicyGetIdentityHandler = newGetIdentityHandler(d)
 	api.PolicyGetIdentityIDHandler = newGetIdentityIDHandler(d)
+	api.PolicyGetIdentityGcHandler = newGetIdentityGCHandler(d)
+	api.PolicyPostIdentityGcHandler = newPostIdentityGCHandler(d)
 
 	// /policy/
 	api.PolicyGetPolicyHandler = newGetPolicyHandler(d)
@@ -807,12 +897,21 @@ func runDaemon() {
 
 	// /ipam/{ip}/
 	api.IPAMPostIPAMHandler = NewPostIPAMHandler(d)
+	api.IPAMGetIPAMHandler = NewGetIPAMHandler(d)
+	api.IPAMGetIPAMIPHandler = NewGetIPAMIPHandler(d)
+	api.IPAMGetIPAMPoolsHandler = NewGetIPAMPoolsHandler(d)
 	api.IPAMPostIPAMIPHandler = NewPostIPAMIPHandler(d)
 	api.IPAMDeleteIPAMIPHandler = NewDeleteIPAMIPHandler(d)
 
 	// /debuginfo
 	api.DaemonGetDebuginfoHandler = NewGetDebugInfoHandler(d)
 
+	// /flows
+	api.FlowsGetFlowsHandler = NewGetFlowsHandler(d)
+
+	// /flows/graph
+	api.FlowsGetFlowsGraphHandler = NewGetFlowsGraphHandler(d)
+
 	server := server.NewServer(api)
 	server.EnabledListeners = []string{"unix"}
 	server.SocketPath = flags.Filename(socketPath)
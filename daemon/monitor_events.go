@@ -0,0 +1,105 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"time"
+
+	"github.com/cilium/cilium/daemon/defaults"
+	"github.com/cilium/cilium/monitor/payload"
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/monitor"
+)
+
+const (
+	// monitorReconnectInterval is the time to wait before reconnecting to
+	// the node monitor after the connection has been lost
+	monitorReconnectInterval = 5 * time.Second
+)
+
+// receiveMonitorEvents connects to the node monitor and passes all trace and
+// drop notifications to the flow graph and flow store of the daemon. The
+// connection is re-established if it is lost, e.g. when the monitor is
+// restarted.
+func (d *Daemon) receiveMonitorEvents() {
+	for {
+		conn, err := net.Dial("unix", defaults.MonitorSockPath)
+		if err != nil {
+			log.WithError(err).Debug("Unable to connect to monitor, retrying")
+			time.Sleep(monitorReconnectInterval)
+			continue
+		}
+
+		err = d.readMonitorEvents(conn)
+		conn.Close()
+		log.WithError(err).Debug("Connection to monitor lost, reconnecting")
+		time.Sleep(monitorReconnectInterval)
+	}
+}
+
+// readMonitorEvents reads events from conn until an error occurs
+func (d *Daemon) readMonitorEvents(conn net.Conn) error {
+	var (
+		meta payload.Meta
+		pl   payload.Payload
+	)
+
+	for {
+		if err := payload.ReadMetaPayload(conn, &meta, &pl); err != nil {
+			return err
+		}
+
+		if pl.Type != payload.EventSample || len(pl.Data) == 0 {
+			continue
+		}
+
+		d.handleMonitorEvent(pl.Data, time.Now())
+	}
+}
+
+// handleMonitorEvent decodes a trace or drop notification and records it.
+// All other event types are ignored.
+func (d *Daemon) handleMonitorEvent(data []byte, now time.Time) {
+	switch data[0] {
+	case monitor.MessageTypeTrace:
+		tn := monitor.TraceNotify{}
+		if err := binary.Read(bytes.NewReader(data), byteorder.Native, &tn); err != nil {
+			log.WithError(err).Debug("Unable to decode trace notification")
+			return
+		}
+		if d.flowGraph != nil {
+			d.flowGraph.AddTrace(&tn, data, now)
+		}
+		if d.flowStore != nil {
+			d.flowStore.AddTrace(&tn, data, now)
+		}
+
+	case monitor.MessageTypeDrop:
+		dn := monitor.DropNotify{}
+		if err := binary.Read(bytes.NewReader(data), byteorder.Native, &dn); err != nil {
+			log.WithError(err).Debug("Unable to decode drop notification")
+			return
+		}
+		if d.flowGraph != nil {
+			d.flowGraph.AddDrop(&dn, data, now)
+		}
+		if d.flowStore != nil {
+			d.flowStore.AddDrop(&dn, data, now)
+		}
+	}
+}
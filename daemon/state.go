@@ -169,7 +169,7 @@ func (d *Daemon) SyncState(dir string, clean bool) error {
 }
 
 func (d *Daemon) allocateIPsLocked(ep *endpoint.Endpoint) error {
-	err := ipam.AllocateIP(ep.IPv6.IP())
+	err := ipam.AllocateIP(ep.IPv6.IP(), ipam.EndpointOwner(ep.ID))
 	if err != nil {
 		// TODO if allocation failed reallocate a new IP address and setup veth
 		// pair accordingly
@@ -184,7 +184,7 @@ func (d *Daemon) allocateIPsLocked(ep *endpoint.Endpoint) error {
 
 	if !d.conf.IPv4Disabled {
 		if ep.IPv4 != nil {
-			if err = ipam.AllocateIP(ep.IPv4.IP()); err != nil {
+			if err = ipam.AllocateIP(ep.IPv4.IP(), ipam.EndpointOwner(ep.ID)); err != nil {
 				return fmt.Errorf("unable to reallocate IPv4 address: %s", err)
 			}
 		}
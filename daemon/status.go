@@ -26,6 +26,7 @@ import (
 	"github.com/cilium/cilium/pkg/workloads/containerd"
 
 	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
 	k8sTypes "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -121,6 +122,8 @@ func (h *getHealthz) getStatus(d *Daemon) models.StatusResponse {
 		sr.Kvstore = &models.Status{State: models.StatusStateOk, Msg: info}
 	}
 
+	sr.KvstoreResync = getKvstoreResyncStatus()
+
 	sr.ContainerRuntime = containerd.Status()
 
 	sr.Kubernetes = d.getK8sStatus()
@@ -159,3 +162,27 @@ func (h *getHealthz) getStatus(d *Daemon) models.StatusResponse {
 
 	return sr
 }
+
+// getKvstoreResyncStatus returns the status of re-creating keys after the
+// kvstore lease has expired
+func getKvstoreResyncStatus() *models.KvstoreResyncStatus {
+	rs := kvstore.GetResyncStatus()
+
+	status := &models.KvstoreResyncStatus{
+		Renewals:   int64(rs.Renewals),
+		Subsystems: make([]*models.KvstoreResyncSubsystem, 0, len(rs.Subsystems)),
+	}
+	if !rs.LastRenewal.IsZero() {
+		status.LastRenewal = strfmt.DateTime(rs.LastRenewal)
+	}
+	for _, s := range rs.Subsystems {
+		status.Subsystems = append(status.Subsystems, &models.KvstoreResyncSubsystem{
+			Name:  s.Name,
+			State: s.State,
+			Keys:  int64(s.Keys),
+			Msg:   s.Error,
+		})
+	}
+
+	return status
+}
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -29,6 +30,7 @@ import (
 	"github.com/cilium/cilium/pkg/launcher"
 	"github.com/cilium/cilium/pkg/lock"
 	"github.com/cilium/cilium/pkg/logging"
+	"github.com/cilium/cilium/pkg/metrics"
 )
 
 var log = logging.DefaultLogger
@@ -53,12 +55,20 @@ type NodeMonitor struct {
 	lostLast uint64
 
 	queue chan []byte
+
+	// The following members are only accessed by setState to turn the
+	// cumulative listener statistics of the monitor into counter
+	// increments
+	listenerDropped map[int64]int64
+	lastListeners   []*models.MonitorListenerStatus
+	disconnected    int64
 }
 
 // NewNodeMonitor returns a new node monitor
 func NewNodeMonitor() *NodeMonitor {
 	nm := &NodeMonitor{
-		queue: make(chan []byte, queueSize),
+		queue:           make(chan []byte, queueSize),
+		listenerDropped: map[int64]int64{},
 	}
 
 	go nm.eventDrainer()
@@ -120,6 +130,49 @@ func (nm *NodeMonitor) setState(state *models.MonitorStatus) {
 	nm.Mutex.Lock()
 	nm.state = state
 	nm.Mutex.Unlock()
+
+	nm.updateListenerMetrics(state)
+}
+
+// counterIncrement returns the increment of a cumulative count from last to
+// cur. The count starts over if the monitor has been restarted.
+func counterIncrement(last, cur int64) int64 {
+	if cur < last {
+		return cur
+	}
+	return cur - last
+}
+
+// updateListenerMetrics exports the per listener statistics of state as
+// metrics. Listeners which are no longer connected are removed.
+func (nm *NodeMonitor) updateListenerMetrics(state *models.MonitorStatus) {
+	metrics.MonitorListenerQueueDepth.Reset()
+	metrics.MonitorListenerAge.Reset()
+
+	if state == nil {
+		return
+	}
+
+	metrics.MonitorListenersDisconnected.Add(float64(counterIncrement(nm.disconnected, state.Disconnected)))
+	nm.disconnected = state.Disconnected
+
+	dropped := make(map[int64]int64, len(state.Listeners))
+	for _, l := range state.Listeners {
+		id, pid := strconv.FormatInt(l.ID, 10), strconv.FormatInt(l.Pid, 10)
+		metrics.MonitorListenerQueueDepth.WithLabelValues(id, pid).Set(float64(l.QueueDepth))
+		metrics.MonitorListenerDropped.WithLabelValues(id, pid).Add(float64(counterIncrement(nm.listenerDropped[l.ID], l.Dropped)))
+		metrics.MonitorListenerAge.WithLabelValues(id, pid).Set(time.Since(time.Time(l.ConnectedSince)).Seconds())
+		dropped[l.ID] = l.Dropped
+	}
+
+	// Listeners which are no longer connected
+	for _, l := range nm.lastListeners {
+		if _, ok := dropped[l.ID]; !ok {
+			metrics.MonitorListenerDropped.DeleteLabelValues(strconv.FormatInt(l.ID, 10), strconv.FormatInt(l.Pid, 10))
+		}
+	}
+	nm.listenerDropped = dropped
+	nm.lastListeners = state.Listeners
 }
 
 // SendEvent sends an event to the node monitor which will then distribute to
@@ -15,10 +15,13 @@
 package main
 
 import (
+	"fmt"
 	"net"
 	"os"
 	"path"
+	"time"
 
+	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/common"
 	"github.com/cilium/cilium/daemon/defaults"
 	"github.com/cilium/cilium/pkg/apisocket"
@@ -42,11 +45,21 @@ var (
 			runNodeMonitor()
 		},
 	}
-	npages int
+	npages             int
+	slowConsumerPolicy string
+	replaySize         int
+	replayAge          time.Duration
 )
 
 func init() {
 	rootCmd.Flags().IntVar(&npages, "num-pages", 64, "Number of pages for ring buffer")
+	rootCmd.Flags().StringVar(&slowConsumerPolicy, "slow-consumer-policy", models.MonitorStatusSlowConsumerPolicyDropNewest,
+		fmt.Sprintf("Policy for listeners which cannot keep up { %s | %s | %s }",
+			models.MonitorStatusSlowConsumerPolicyDropNewest,
+			models.MonitorStatusSlowConsumerPolicyDropOldest,
+			models.MonitorStatusSlowConsumerPolicyDisconnect))
+	rootCmd.Flags().IntVar(&replaySize, "replay-buffer-size", 8192, fmt.Sprintf("Number of events kept for replay to new listeners, at most %d (0 to disable)", queueSize))
+	rootCmd.Flags().DurationVar(&replayAge, "replay-buffer-age", 10*time.Minute, "Maximum age of events kept for replay to new listeners (0 for no limit)")
 }
 
 func execute() {
@@ -61,6 +74,21 @@ func main() {
 }
 
 func runNodeMonitor() {
+	switch slowConsumerPolicy {
+	case models.MonitorStatusSlowConsumerPolicyDropNewest,
+		models.MonitorStatusSlowConsumerPolicyDropOldest,
+		models.MonitorStatusSlowConsumerPolicyDisconnect:
+	default:
+		log.Fatalf("Invalid slow consumer policy %q", slowConsumerPolicy)
+	}
+
+	if replaySize < 0 || replayAge < 0 {
+		log.Fatal("Replay buffer size and age must not be negative")
+	}
+	if replaySize > queueSize {
+		log.Fatalf("Replay buffer size must not exceed the listener queue size of %d", queueSize)
+	}
+
 	eventSockPath := path.Join(defaults.RuntimePath, defaults.EventsPipe)
 	pipe, err := os.OpenFile(eventSockPath, os.O_RDONLY, 0600)
 	if err != nil {
@@ -89,8 +117,28 @@ func runNodeMonitor() {
 	}
 	log.Infof("Serving cilium node monitor at unix://%s", defaults.MonitorSockPath)
 
+	replayLog := log.WithField(logfields.Path, defaults.MonitorReplaySockPath)
+	os.Remove(defaults.MonitorReplaySockPath)
+	replayServer, err := net.Listen("unix", defaults.MonitorReplaySockPath)
+	if err != nil {
+		replayLog.WithError(err).Fatal("Cannot listen on socket")
+	}
+
+	if os.Getuid() == 0 {
+		err := apisocket.SetDefaultPermissions(defaults.MonitorReplaySockPath)
+		if err != nil {
+			replayLog.WithError(err).Fatal("Cannot set default permissions on socket")
+		}
+	}
+	log.Infof("Serving cilium node monitor replay at unix://%s", defaults.MonitorReplaySockPath)
+
+	mutex.Lock()
+	replay = newReplayBuffer(replaySize, replayAge)
+	mutex.Unlock()
+
 	m := Monitor{}
 	go m.handleConnection(server)
+	go m.handleReplayConnection(replayServer)
 
 	m.Run(npages, pipe)
 }
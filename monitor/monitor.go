@@ -19,6 +19,9 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -26,6 +29,9 @@ import (
 	"github.com/cilium/cilium/monitor/payload"
 	"github.com/cilium/cilium/pkg/bpf"
 	"github.com/cilium/cilium/pkg/lock"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/sirupsen/logrus"
 )
 
 const (
@@ -33,23 +39,55 @@ const (
 
 	// queueSize is the size of the message queue
 	queueSize = 65536
+
+	// replayRequestTimeout is the time a new replay connection has to send
+	// its replay request
+	replayRequestTimeout = 5 * time.Second
 )
 
 var (
 	mutex         lock.Mutex
 	listeners     = make(map[*monitorListener]struct{})
 	monitorEvents *bpf.PerCpuEvents
+
+	// lastListenerID is the ID assigned to the most recently connected
+	// listener, protected by mutex
+	lastListenerID uint64
+
+	// disconnected is the number of listeners disconnected by the
+	// disconnect slow consumer policy, protected by mutex
+	disconnected int64
+
+	// replay buffers the most recent messages for listeners connecting to
+	// the replay socket, protected by mutex
+	replay = newReplayBuffer(0, 0)
 )
 
 type monitorListener struct {
-	conn  net.Conn
-	queue chan []byte
+	id          uint64
+	pid         int32
+	conn        net.Conn
+	queue       chan []byte
+	connectedAt time.Time
+
+	// stop is closed when the listener is disconnected for being too slow
+	stop     chan struct{}
+	stopOnce sync.Once
+
+	// dropped is the number of messages dropped for this listener, it
+	// must be accessed atomically
+	dropped uint64
 }
 
 func newMonitorListener(c net.Conn) *monitorListener {
+	lastListenerID++
 	ml := &monitorListener{
-		conn:  c,
-		queue: make(chan []byte, queueSize),
+		id:          lastListenerID,
+		pid:         peerPid(c),
+		conn:        c,
+		queue:       make(chan []byte, queueSize),
+		connectedAt: time.Now(),
+		stop:        make(chan struct{}),
 	}
 
 	go ml.drainQueue()
@@ -57,6 +95,30 @@ func newMonitorListener(c net.Conn) *monitorListener {
 	return ml
 }
 
+// peerPid returns the pid of the process on the other end of the unix socket
+// connection or 0 if it cannot be determined.
+func peerPid(c net.Conn) int32 {
+	uc, ok := c.(*net.UnixConn)
+	if !ok {
+		return 0
+	}
+
+	raw, err := uc.SyscallConn()
+	if err != nil {
+		return 0
+	}
+
+	var cred *syscall.Ucred
+	raw.Control(func(fd uintptr) {
+		cred, err = syscall.GetsockoptUcred(int(fd), syscall.SOL_SOCKET, syscall.SO_PEERCRED)
+	})
+	if err != nil || cred == nil {
+		return 0
+	}
+
+	return cred.Pid
+}
+
 // Monitor structure for centralizing the responsibilities of the main events reader.
 type Monitor struct {
 }
@@ -128,7 +190,19 @@ func (m *Monitor) dumpStat() {
 	n := int64(monitorEvents.Npages)
 	p := int64(monitorEvents.Pagesize)
 	l, u := monitorEvents.Stats()
-	ms := models.MonitorStatus{Cpus: c, Npages: n, Pagesize: p, Lost: int64(l), Unknown: int64(u)}
+	ms := models.MonitorStatus{Cpus: c, Npages: n, Pagesize: p, Lost: int64(l), Unknown: int64(u),
+		SlowConsumerPolicy: slowConsumerPolicy}
+
+	mutex.Lock()
+	ms.Disconnected = disconnected
+	for ml := range listeners {
+		ms.Listeners = append(ms.Listeners, ml.status())
+	}
+	mutex.Unlock()
+
+	sort.Slice(ms.Listeners, func(i, j int) bool {
+		return ms.Listeners[i].ID < ms.Listeners[j].ID
+	})
 
 	mp, err := json.Marshal(ms)
 	if err != nil {
@@ -148,26 +222,90 @@ func (m *Monitor) handleConnection(server net.Listener) {
 		}
 
 		mutex.Lock()
-		listeners[newMonitorListener(conn)] = struct{}{}
-		log.WithField("count.listener", len(listeners)).Info("New monitor connected.")
+		addListener(conn)
 		mutex.Unlock()
 	}
 }
 
+// handleReplayConnection handles all the incoming connections on the replay
+// socket.
+func (m *Monitor) handleReplayConnection(server net.Listener) {
+	for {
+		conn, err := server.Accept()
+		if err != nil {
+			log.WithError(err).Warn("error accepting connection")
+			continue
+		}
+
+		go m.replayAndAddListener(conn)
+	}
+}
+
+// replayAndAddListener reads the payload.ReplayRequest sent by a new replay
+// connection and registers it as listener with the buffered messages of the
+// requested period queued ahead of any live message.
+func (m *Monitor) replayAndAddListener(conn net.Conn) {
+	var req payload.ReplayRequest
+
+	conn.SetReadDeadline(time.Now().Add(replayRequestTimeout))
+	if err := req.ReadBinary(conn); err != nil {
+		log.WithError(err).Warn("Unable to read replay request")
+		conn.Close()
+		return
+	}
+	conn.SetReadDeadline(time.Time{})
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	now := time.Now()
+	msgs := replay.since(now, now.Add(-time.Duration(req.Since)))
+	ml := addListener(conn)
+	replayed := 0
+	for _, msg := range msgs {
+		// Stop once the listener has been disconnected for being too
+		// slow to receive the replay
+		if !ml.enqueue(msg) {
+			break
+		}
+		replayed++
+	}
+
+	log.WithFields(logrus.Fields{
+		"listener": ml.id,
+		"replayed": replayed,
+	}).Info("Replayed buffered events to new monitor.")
+}
+
+// addListener registers a new listener for conn. mutex must be held.
+func addListener(conn net.Conn) *monitorListener {
+	ml := newMonitorListener(conn)
+	listeners[ml] = struct{}{}
+	log.WithFields(logrus.Fields{
+		"count.listener": len(listeners),
+		"listener":       ml.id,
+		"pid":            ml.pid,
+	}).Info("New monitor connected.")
+
+	return ml
+}
+
 // send writes the payload.Meta and the actual payload to the active
 // connections.
 func (m *Monitor) send(pl payload.Payload) {
 	mutex.Lock()
 	defer mutex.Unlock()
-	if len(listeners) == 0 {
+	if len(listeners) == 0 && !replay.enabled() {
 		return
 	}
 
 	buf, err := pl.BuildMessage()
 	if err != nil {
 		log.WithError(err).Error("Unable to send notification to listeners")
+		return
 	}
 
+	replay.add(time.Now(), buf)
 	for ml := range listeners {
 		ml.enqueue(buf)
 	}
@@ -179,26 +317,87 @@ func (ml *monitorListener) remove() {
 	mutex.Unlock()
 }
 
-func (ml *monitorListener) enqueue(msg []byte) {
+// disconnect stops draining the queue of the listener and closes its
+// connection. It may be called multiple times.
+func (ml *monitorListener) disconnect() {
+	ml.stopOnce.Do(func() {
+		close(ml.stop)
+		ml.conn.Close()
+	})
+}
+
+// enqueue queues msg for the listener and applies the slow consumer policy
+// if the queue is full. Returns false if the listener has been disconnected.
+// mutex must be held.
+func (ml *monitorListener) enqueue(msg []byte) bool {
 	select {
 	case ml.queue <- msg:
+		return true
 	default:
+	}
+
+	switch slowConsumerPolicy {
+	case models.MonitorStatusSlowConsumerPolicyDropOldest:
+		select {
+		case <-ml.queue:
+		default:
+		}
+		select {
+		case ml.queue <- msg:
+		default:
+		}
+		atomic.AddUint64(&ml.dropped, 1)
+		log.Debugf("Per listener queue is full, dropping oldest message")
+
+	case models.MonitorStatusSlowConsumerPolicyDisconnect:
+		atomic.AddUint64(&ml.dropped, 1)
+		if _, ok := listeners[ml]; ok {
+			delete(listeners, ml)
+			disconnected++
+			log.WithFields(logrus.Fields{
+				"listener": ml.id,
+				"pid":      ml.pid,
+			}).Warn("Monitor disconnected due to full queue")
+		}
+		ml.disconnect()
+		return false
+
+	default:
+		atomic.AddUint64(&ml.dropped, 1)
 		log.Debugf("Per listener queue is full, dropping message")
 	}
+
+	return true
 }
 
 func (ml *monitorListener) drainQueue() {
 	for {
-		msgBuf := <-ml.queue
-		if _, err := ml.conn.Write(msgBuf); err != nil {
-			ml.conn.Close()
-			ml.remove()
-			log.WithError(err).Warn("Monitor removed due to write failure")
+		select {
+		case msgBuf := <-ml.queue:
+			if _, err := ml.conn.Write(msgBuf); err != nil {
+				ml.conn.Close()
+				ml.remove()
+				log.WithError(err).Warn("Monitor removed due to write failure")
+				return
+			}
+		case <-ml.stop:
 			return
 		}
 	}
 }
 
+// status returns the backpressure statistics of the listener.
+func (ml *monitorListener) status() *models.MonitorListenerStatus {
+	return &models.MonitorListenerStatus{
+		ID:             int64(ml.id),
+		Pid:            int64(ml.pid),
+		ConnectedSince: strfmt.DateTime(ml.connectedAt),
+		QueueDepth:     int64(len(ml.queue)),
+		QueueSize:      int64(cap(ml.queue)),
+		Dropped:        int64(atomic.LoadUint64(&ml.dropped)),
+	}
+}
+
 func (m *Monitor) receiveEvent(es *bpf.PerfEventSample, c int) {
 	pl := payload.Payload{Data: es.DataCopy(), CPU: c, Lost: 0, Type: payload.EventSample}
 	m.send(pl)
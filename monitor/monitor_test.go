@@ -0,0 +1,142 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type MonitorSuite struct{}
+
+var _ = Suite(&MonitorSuite{})
+
+// newTestListener returns a registered listener with a queue of size n which
+// is not drained.
+func newTestListener(n int) (*monitorListener, net.Conn) {
+	server, client := net.Pipe()
+	ml := &monitorListener{
+		conn:  server,
+		queue: make(chan []byte, n),
+		stop:  make(chan struct{}),
+	}
+	listeners[ml] = struct{}{}
+	return ml, client
+}
+
+func (s *MonitorSuite) TearDownTest(c *C) {
+	slowConsumerPolicy = models.MonitorStatusSlowConsumerPolicyDropNewest
+	listeners = make(map[*monitorListener]struct{})
+	disconnected = 0
+}
+
+func (s *MonitorSuite) TestEnqueueDropNewest(c *C) {
+	slowConsumerPolicy = models.MonitorStatusSlowConsumerPolicyDropNewest
+	ml, client := newTestListener(2)
+	defer client.Close()
+
+	ml.enqueue([]byte{1})
+	ml.enqueue([]byte{2})
+	ml.enqueue([]byte{3})
+
+	c.Assert(<-ml.queue, DeepEquals, []byte{1})
+	c.Assert(<-ml.queue, DeepEquals, []byte{2})
+	c.Assert(ml.status().Dropped, Equals, int64(1))
+}
+
+func (s *MonitorSuite) TestEnqueueDropOldest(c *C) {
+	slowConsumerPolicy = models.MonitorStatusSlowConsumerPolicyDropOldest
+	ml, client := newTestListener(2)
+	defer client.Close()
+
+	ml.enqueue([]byte{1})
+	ml.enqueue([]byte{2})
+	ml.enqueue([]byte{3})
+
+	st := ml.status()
+	c.Assert(st.Dropped, Equals, int64(1))
+	c.Assert(st.QueueDepth, Equals, int64(2))
+	c.Assert(st.QueueSize, Equals, int64(2))
+
+	c.Assert(<-ml.queue, DeepEquals, []byte{2})
+	c.Assert(<-ml.queue, DeepEquals, []byte{3})
+}
+
+func (s *MonitorSuite) TestEnqueueDisconnect(c *C) {
+	slowConsumerPolicy = models.MonitorStatusSlowConsumerPolicyDisconnect
+	ml, client := newTestListener(1)
+	defer client.Close()
+
+	c.Assert(ml.enqueue([]byte{1}), Equals, true)
+	_, ok := listeners[ml]
+	c.Assert(ok, Equals, true)
+
+	c.Assert(ml.enqueue([]byte{2}), Equals, false)
+	_, ok = listeners[ml]
+	c.Assert(ok, Equals, false)
+	c.Assert(disconnected, Equals, int64(1))
+
+	select {
+	case <-ml.stop:
+	default:
+		c.Fatal("listener was not stopped")
+	}
+
+	// Further messages for the disconnected listener are dropped
+	c.Assert(ml.enqueue([]byte{3}), Equals, false)
+	c.Assert(disconnected, Equals, int64(1))
+	c.Assert(ml.status().Dropped, Equals, int64(2))
+}
+
+func (s *MonitorSuite) TestReplayBufferSize(c *C) {
+	rb := newReplayBuffer(3, 0)
+	start := time.Now()
+
+	for i := 0; i < 5; i++ {
+		rb.add(start.Add(time.Duration(i)*time.Second), []byte{byte(i)})
+	}
+
+	c.Assert(rb.len(), Equals, 3)
+	c.Assert(rb.since(start, time.Time{}), DeepEquals, [][]byte{{2}, {3}, {4}})
+	c.Assert(rb.since(start, start.Add(3*time.Second)), DeepEquals, [][]byte{{4}})
+}
+
+func (s *MonitorSuite) TestReplayBufferAge(c *C) {
+	rb := newReplayBuffer(10, time.Minute)
+	start := time.Now()
+
+	rb.add(start, []byte{0})
+	rb.add(start.Add(30*time.Second), []byte{1})
+	rb.add(start.Add(90*time.Second), []byte{2})
+	c.Assert(rb.since(start.Add(90*time.Second), time.Time{}), DeepEquals, [][]byte{{1}, {2}})
+
+	c.Assert(rb.since(start.Add(time.Hour), time.Time{}), DeepEquals, [][]byte{})
+	c.Assert(rb.len(), Equals, 0)
+}
+
+func (s *MonitorSuite) TestReplayBufferDisabled(c *C) {
+	rb := newReplayBuffer(0, time.Minute)
+	rb.add(time.Now(), []byte{0})
+
+	c.Assert(rb.enabled(), Equals, false)
+	c.Assert(rb.len(), Equals, 0)
+}
@@ -62,6 +62,25 @@ func (meta *Meta) WriteBinary(w io.Writer) error {
 	return binary.Write(w, byteorder.Native, meta)
 }
 
+// ReplayRequest is sent by readers connecting to the replay socket to request
+// the buffered notifications of a past period before the live ones.
+type ReplayRequest struct {
+	// Since is the period in nanoseconds for which buffered notifications
+	// are requested
+	Since int64
+	_     [24]byte // Reserved 24 bytes for future fields.
+}
+
+// ReadBinary reads the replay request from its binary representation.
+func (req *ReplayRequest) ReadBinary(r io.Reader) error {
+	return binary.Read(r, byteorder.Native, req)
+}
+
+// WriteBinary writes the replay request into its binary representation.
+func (req *ReplayRequest) WriteBinary(w io.Writer) error {
+	return binary.Write(w, byteorder.Native, req)
+}
+
 // Payload is the structure used when copying events from the main monitor.
 type Payload struct {
 	Data []byte
@@ -57,6 +57,21 @@ func (s *PayloadSuite) TestPayload_UnMarshalBinary(c *C) {
 	c.Assert(payload1, comparator.DeepEquals, payload2)
 }
 
+func (s *PayloadSuite) TestReplayRequest_ReadWriteBinary(c *C) {
+	req1 := ReplayRequest{Since: 300000000000}
+
+	var buf bytes.Buffer
+	err := req1.WriteBinary(&buf)
+	c.Assert(err, Equals, nil)
+	c.Assert(buf.Len(), Equals, 32)
+
+	var req2 ReplayRequest
+	err = req2.ReadBinary(&buf)
+	c.Assert(err, Equals, nil)
+
+	c.Assert(req1, comparator.DeepEquals, req2)
+}
+
 func (s *PayloadSuite) TestWriteReadMetaPayload(c *C) {
 	meta1 := Meta{Size: 1234}
 	payload1 := Payload{
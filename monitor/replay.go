@@ -0,0 +1,106 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"time"
+)
+
+// replayEntry is a message kept in the replay buffer
+type replayEntry struct {
+	received time.Time
+	msg      []byte
+}
+
+// replayBuffer is a bounded ring of the most recently sent messages. Messages
+// are evicted when the buffer is full or when they become older than maxAge.
+type replayBuffer struct {
+	entries []replayEntry
+	// next is the index in entries the next message is written to
+	next int
+	// count is the number of valid messages in entries
+	count  int
+	maxAge time.Duration
+}
+
+// newReplayBuffer returns a replay buffer holding at most size messages no
+// older than maxAge. A maxAge of 0 disables the age limit.
+func newReplayBuffer(size int, maxAge time.Duration) *replayBuffer {
+	return &replayBuffer{
+		entries: make([]replayEntry, size),
+		maxAge:  maxAge,
+	}
+}
+
+// add appends msg to the buffer, evicting the oldest message if the buffer is
+// full.
+func (rb *replayBuffer) add(now time.Time, msg []byte) {
+	if len(rb.entries) == 0 {
+		return
+	}
+
+	rb.entries[rb.next] = replayEntry{received: now, msg: msg}
+	rb.next = (rb.next + 1) % len(rb.entries)
+	if rb.count < len(rb.entries) {
+		rb.count++
+	}
+	rb.expire(now)
+}
+
+// expire evicts all messages older than maxAge.
+func (rb *replayBuffer) expire(now time.Time) {
+	if rb.maxAge == 0 {
+		return
+	}
+
+	for rb.count > 0 {
+		oldest := &rb.entries[rb.index(0)]
+		if now.Sub(oldest.received) <= rb.maxAge {
+			return
+		}
+		*oldest = replayEntry{}
+		rb.count--
+	}
+}
+
+// index returns the position in entries of the i-th oldest message.
+func (rb *replayBuffer) index(i int) int {
+	return (rb.next - rb.count + i + len(rb.entries)) % len(rb.entries)
+}
+
+// since returns all buffered messages received after t, oldest first.
+func (rb *replayBuffer) since(now, t time.Time) [][]byte {
+	rb.expire(now)
+
+	msgs := [][]byte{}
+	for i := 0; i < rb.count; i++ {
+		e := rb.entries[rb.index(i)]
+		if e.received.After(t) {
+			msgs = append(msgs, e.msg)
+		}
+	}
+
+	return msgs
+}
+
+// enabled returns true if the buffer can hold any message.
+func (rb *replayBuffer) enabled() bool {
+	return len(rb.entries) > 0
+}
+
+// len returns the number of buffered messages.
+func (rb *replayBuffer) len() int {
+	return rb.count
+}
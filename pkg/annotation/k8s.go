@@ -33,4 +33,13 @@ const (
 	// V6HealthName is the annotation name used to store the IPv6
 	// address of the cilium-health endpoint in the node's annotations.
 	V6HealthName = "io.cilium.network.ipv6-health-ip"
+
+	// IPAMPool is the annotation name used to select the IPAM pool from
+	// which the addresses of a pod are allocated. It can be set on pods
+	// and namespaces, the pod annotation takes precedence.
+	IPAMPool = "io.cilium.network.ipam-pool"
+
+	// ServiceLBAlgorithm is the annotation name used to select the
+	// algorithm selecting the backend of a connection to a service.
+	ServiceLBAlgorithm = "io.cilium.service.lb-algorithm"
 )
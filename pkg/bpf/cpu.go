@@ -0,0 +1,90 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpf
+
+import (
+	"fmt"
+	"io/ioutil"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const possibleCPUsPath = "/sys/devices/system/cpu/possible"
+
+var (
+	possibleCPUsOnce sync.Once
+	possibleCPUs     int
+)
+
+// parseCPUList returns the number of CPUs in a CPU list such as "0-3,5"
+func parseCPUList(list string) (int, error) {
+	n := 0
+	for _, r := range strings.Split(strings.TrimSpace(list), ",") {
+		bounds := strings.SplitN(r, "-", 2)
+		first, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return 0, fmt.Errorf("invalid CPU list %q: %s", list, err)
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = strconv.Atoi(bounds[1]); err != nil {
+				return 0, fmt.Errorf("invalid CPU list %q: %s", list, err)
+			}
+		}
+		if last < first {
+			return 0, fmt.Errorf("invalid CPU list %q", list)
+		}
+		n += last - first + 1
+	}
+	return n, nil
+}
+
+// GetNumPossibleCPUs returns the number of CPUs the kernel may bring online.
+// Per-CPU maps hold a value for each of them.
+func GetNumPossibleCPUs() int {
+	possibleCPUsOnce.Do(func() {
+		list, err := ioutil.ReadFile(possibleCPUsPath)
+		if err == nil {
+			possibleCPUs, err = parseCPUList(string(list))
+		}
+		if err != nil {
+			log.WithError(err).Warn("Unable to determine the number of possible CPUs")
+			possibleCPUs = runtime.NumCPU()
+		}
+	})
+
+	return possibleCPUs
+}
+
+// isPerCPU returns true if maps of the type hold a value for each possible
+// CPU
+func (t MapType) isPerCPU() bool {
+	switch t {
+	case MapTypePerCPUHash, MapTypePerCPUArray, MapTypeLRUPerCPUHash:
+		return true
+	}
+	return false
+}
+
+// valueBufSize returns the size of the buffer required to look up a value of
+// the map. The value of each CPU in per-CPU maps is aligned to 8 bytes.
+func (m *Map) valueBufSize() int {
+	if !m.MapType.isPerCPU() {
+		return int(m.ValueSize)
+	}
+	return GetNumPossibleCPUs() * ((int(m.ValueSize) + 7) &^ 7)
+}
@@ -401,7 +401,7 @@ func (m *Map) DumpWithCallback(cb DumpCallback) error {
 
 	key := make([]byte, m.KeySize)
 	nextKey := make([]byte, m.KeySize)
-	value := make([]byte, m.ValueSize)
+	value := make([]byte, m.valueBufSize())
 
 	if err := m.Open(); err != nil {
 		return err
@@ -461,7 +461,7 @@ func (m *Map) Dump(hash map[string][]string) error {
 func (m *Map) containsEntries() (bool, error) {
 	key := make([]byte, m.KeySize)
 	nextKey := make([]byte, m.KeySize)
-	value := make([]byte, m.ValueSize)
+	value := make([]byte, m.valueBufSize())
 
 	err := GetNextKey(
 		m.fd,
@@ -132,6 +132,17 @@ func statusUnhealthy(s *models.Status) bool {
 	return false
 }
 
+// resyncFailure returns the first subsystem which failed to re-create its
+// keys after the kvstore lease expired
+func resyncFailure(rs *models.KvstoreResyncStatus) *models.KvstoreResyncSubsystem {
+	for _, s := range rs.Subsystems {
+		if s.State == models.KvstoreResyncSubsystemStateFailure {
+			return s
+		}
+	}
+	return nil
+}
+
 // FormatStatusResponseBrief writes a one-line status to the writer. If
 // everything ok, this is "ok", otherwise a message of the form "error in ..."
 func FormatStatusResponseBrief(w io.Writer, sr *models.StatusResponse) {
@@ -144,6 +155,9 @@ func FormatStatusResponseBrief(w io.Writer, sr *models.StatusResponse) {
 		msg = fmt.Sprintf("container runtime: %s", sr.ContainerRuntime.Msg)
 	case statusUnhealthy(sr.Kvstore):
 		msg = fmt.Sprintf("kvstore: %s", sr.Kvstore.Msg)
+	case sr.KvstoreResync != nil && resyncFailure(sr.KvstoreResync) != nil:
+		s := resyncFailure(sr.KvstoreResync)
+		msg = fmt.Sprintf("kvstore resync of %s: %s", s.Name, s.Msg)
 	case sr.Kubernetes != nil && stateUnhealthy(sr.Kubernetes.State):
 		msg = fmt.Sprintf("kubernetes: %s", sr.Kubernetes.Msg)
 	case sr.Cluster != nil && statusUnhealthy(sr.Cluster.CiliumHealth):
@@ -181,6 +195,19 @@ func FormatStatusResponse(w io.Writer, sr *models.StatusResponse, allAddresses,
 	if sr.Kvstore != nil {
 		fmt.Fprintf(w, "KVStore:\t%s\t%s\n", sr.Kvstore.State, sr.Kvstore.Msg)
 	}
+	if rs := sr.KvstoreResync; rs != nil && rs.Renewals > 0 {
+		nOK := 0
+		for _, s := range rs.Subsystems {
+			if s.State == models.KvstoreResyncSubsystemStateOk {
+				nOK++
+			}
+		}
+		fmt.Fprintf(w, "KVStore resync:\t%d/%d re-registered, lease renewed %d times, last %s\n",
+			nOK, len(rs.Subsystems), rs.Renewals, timeSince(time.Time(rs.LastRenewal)))
+		for _, s := range rs.Subsystems {
+			fmt.Fprintf(w, "  %s:\t%s\t%d keys\t%s\n", s.Name, s.State, s.Keys, s.Msg)
+		}
+	}
 	if sr.ContainerRuntime != nil {
 		fmt.Fprintf(w, "ContainerRuntime:\t%s\t%s\n",
 			sr.ContainerRuntime.State, sr.ContainerRuntime.Msg)
@@ -201,6 +228,14 @@ func FormatStatusResponse(w io.Writer, sr *models.StatusResponse, allAddresses,
 		if nm.Lost != 0 || nm.Unknown != 0 {
 			fmt.Fprintf(w, "\t%d events lost, %d unknown notifications\n", nm.Lost, nm.Unknown)
 		}
+		if nm.Disconnected != 0 {
+			fmt.Fprintf(w, "\t%d listeners disconnected (policy %s)\n", nm.Disconnected, nm.SlowConsumerPolicy)
+		}
+		for _, l := range nm.Listeners {
+			fmt.Fprintf(w, "  Listener %d (pid %d):\tqueue %d/%d, %d dropped, connected %s ago\n",
+				l.ID, l.Pid, l.QueueDepth, l.QueueSize, l.Dropped,
+				time.Since(time.Time(l.ConnectedSince)).Truncate(time.Second))
+		}
 	} else {
 		fmt.Fprintf(w, "NodeMonitor:\tDisabled\n")
 	}
@@ -34,3 +34,24 @@ func (c *Client) ConfigPatch(cfg models.Configuration) error {
 	_, err := c.Daemon.PatchConfig(params)
 	return Hint(err)
 }
+
+// ConfigLabelPrefixesGet returns the label prefix configuration of the daemon.
+func (c *Client) ConfigLabelPrefixesGet() (*models.LabelPrefixConfiguration, error) {
+	resp, err := c.Daemon.GetConfigLabelPrefixes(nil)
+	if err != nil {
+		return nil, Hint(err)
+	}
+	return resp.Payload, nil
+}
+
+// ConfigLabelPrefixesPut replaces the label prefixes of the daemon and returns
+// the endpoints which changed identity as a result.
+func (c *Client) ConfigLabelPrefixesPut(prefixes []string) ([]*models.IdentityMigration, error) {
+	cfg := &models.LabelPrefixConfiguration{Prefixes: prefixes}
+	params := daemon.NewPutConfigLabelPrefixesParams().WithConfiguration(cfg)
+	resp, err := c.Daemon.PutConfigLabelPrefixes(params)
+	if err != nil {
+		return nil, Hint(err)
+	}
+	return resp.Payload, nil
+}
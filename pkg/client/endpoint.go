@@ -115,6 +115,17 @@ func (c *Client) EndpointLabelsGet(id string) (*models.LabelConfiguration, error
 	return resp.Payload, nil
 }
 
+// EndpointIdentityExplain returns the explanation of how the identity of an
+// endpoint has been derived from its labels
+func (c *Client) EndpointIdentityExplain(id string) (*models.IdentityExplanation, error) {
+	params := endpoint.NewGetEndpointIDIdentityExplainParams().WithID(id)
+	resp, err := c.Endpoint.GetEndpointIDIdentityExplain(params)
+	if err != nil {
+		return nil, Hint(err)
+	}
+	return resp.Payload, nil
+}
+
 // EndpointLabelsPut modifies endpoint label configuration
 func (c *Client) EndpointLabelsPut(id string, cfg *models.LabelConfigurationModifier) error {
 	params := endpoint.NewPutEndpointIDLabelsParams().WithID(id)
@@ -0,0 +1,57 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"github.com/cilium/cilium/api/v1/client/flows"
+	"github.com/cilium/cilium/api/v1/models"
+)
+
+// FlowGraphGet returns the dependency graph of observed flows. If since is
+// not empty, only edges observed within the given duration are returned.
+func (c *Client) FlowGraphGet(since string) (*models.FlowGraph, error) {
+	params := flows.NewGetFlowsGraphParams()
+	if since != "" {
+		params.SetSince(&since)
+	}
+
+	resp, err := c.Flows.GetFlowsGraph(params)
+	if err != nil {
+		return nil, Hint(err)
+	}
+	return resp.Payload, nil
+}
+
+// FlowsList returns the recently observed flows. Flows are restricted to the
+// given endpoint unless endpointID is 0, to the given verdict unless verdict
+// is empty and to the given duration unless since is empty.
+func (c *Client) FlowsList(endpointID int64, verdict, since string) ([]*models.Flow, error) {
+	params := flows.NewGetFlowsParams()
+	if endpointID != 0 {
+		params.SetEndpoint(&endpointID)
+	}
+	if verdict != "" {
+		params.SetVerdict(&verdict)
+	}
+	if since != "" {
+		params.SetSince(&since)
+	}
+
+	resp, err := c.Flows.GetFlows(params)
+	if err != nil {
+		return nil, Hint(err)
+	}
+	return resp.Payload, nil
+}
@@ -24,14 +24,29 @@ const (
 	AddressFamilyIPv4 = "ipv4"
 )
 
-// IPAMAllocate allocates an IP address out of address family specific pool.
-func (c *Client) IPAMAllocate(family string) (*models.IPAM, error) {
+// IPAMAllocate allocates an IP address out of address family specific pool
+// on behalf of owner. If pool is empty, the pool is selected via the
+// annotations of the Kubernetes pod given in the form namespace/name or the
+// default pool is used.
+func (c *Client) IPAMAllocate(family, pool, pod, owner string) (*models.IPAM, error) {
 	params := ipam.NewPostIPAMParams()
 
 	if family != "" {
 		params.SetFamily(&family)
 	}
 
+	if pool != "" {
+		params.SetPool(&pool)
+	}
+
+	if pod != "" {
+		params.SetK8sPod(&pod)
+	}
+
+	if owner != "" {
+		params.SetOwner(&owner)
+	}
+
 	resp, err := c.IPAM.PostIPAM(params)
 	if err != nil {
 		return nil, Hint(err)
@@ -52,3 +67,33 @@ func (c *Client) IPAMReleaseIP(ip string) error {
 	_, err := c.IPAM.DeleteIPAMIP(params)
 	return Hint(err)
 }
+
+// IPAMGetPools returns all IPAM pools and their usage.
+func (c *Client) IPAMGetPools() ([]*models.IPAMPool, error) {
+	resp, err := c.IPAM.GetIPAMPools(nil)
+	if err != nil {
+		return nil, Hint(err)
+	}
+	return resp.Payload, nil
+}
+
+// IPAMGetAllocations returns all allocated IP addresses. If orphans is true,
+// only addresses which are not used by any endpoint are returned.
+func (c *Client) IPAMGetAllocations(orphans bool) ([]*models.IPAMAllocation, error) {
+	params := ipam.NewGetIPAMParams().WithOrphans(&orphans)
+	resp, err := c.IPAM.GetIPAM(params)
+	if err != nil {
+		return nil, Hint(err)
+	}
+	return resp.Payload, nil
+}
+
+// IPAMGetAllocation returns the allocation of a single IP address.
+func (c *Client) IPAMGetAllocation(ip string) (*models.IPAMAllocation, error) {
+	params := ipam.NewGetIPAMIPParams().WithIP(ip)
+	resp, err := c.IPAM.GetIPAMIP(params)
+	if err != nil {
+		return nil, Hint(err)
+	}
+	return resp.Payload, nil
+}
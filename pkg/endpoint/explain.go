@@ -0,0 +1,106 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpoint
+
+import (
+	"sort"
+
+	"github.com/cilium/cilium/api/v1/models"
+	pkgLabels "github.com/cilium/cilium/pkg/labels"
+)
+
+// explainLabel returns the explanation of a label which has been subject to
+// the label prefix filter. kept is true if the label is currently part of the
+// identity labels of the endpoint.
+func explainLabel(d pkgLabels.FilterDecision, kept bool) *models.LabelExplanation {
+	m := &models.LabelExplanation{
+		Label:  d.Label.String(),
+		Source: d.Label.Source,
+		Kept:   kept,
+	}
+
+	if d.Prefix != nil {
+		m.Prefix = d.Prefix.String()
+	}
+
+	switch {
+	case kept && d.Label.Source == pkgLabels.LabelSourceReserved:
+		m.Reason = "Reserved labels are always used"
+		m.Prefix = ""
+	case d.Kept != kept:
+		m.Reason = "Label prefix configuration changed after the labels were applied"
+	case kept && d.Prefix != nil:
+		m.Reason = "Included by label prefix"
+	case kept:
+		m.Reason = "No inclusive label prefix configured and not ignored"
+	case d.Prefix != nil:
+		m.Reason = "Ignored by label prefix"
+	default:
+		m.Reason = "No inclusive label prefix matched"
+	}
+
+	return m
+}
+
+// GetIdentityExplanationModel returns the explanation of how the identity of
+// the endpoint has been derived from its labels. Each label of the endpoint
+// is listed with the label prefix which decided whether the label is used to
+// determine the identity.
+func (e *Endpoint) GetIdentityExplanationModel() *models.IdentityExplanation {
+	e.Mutex.RLock()
+	defer e.Mutex.RUnlock()
+
+	m := &models.IdentityExplanation{
+		EndpointID: int64(e.ID),
+		Identity:   e.SecurityIdentity.GetModel(),
+		Labels:     []*models.LabelExplanation{},
+		SharedWith: []int64{},
+	}
+
+	for _, l := range e.OpLabels.Custom {
+		m.Labels = append(m.Labels, &models.LabelExplanation{
+			Label:  l.String(),
+			Source: l.Source,
+			Kept:   true,
+			Reason: "Custom label added via API",
+		})
+	}
+
+	for _, l := range e.OpLabels.Disabled {
+		m.Labels = append(m.Labels, &models.LabelExplanation{
+			Label:  l.String(),
+			Source: l.Source,
+			Kept:   false,
+			Reason: "Disabled via API",
+		})
+	}
+
+	orchestration := pkgLabels.Labels{}
+	for k, v := range e.OpLabels.OrchestrationIdentity {
+		orchestration[k] = v
+	}
+	for k, v := range e.OpLabels.OrchestrationInfo {
+		orchestration[k] = v
+	}
+
+	for _, d := range pkgLabels.ExplainLabels(orchestration) {
+		_, kept := e.OpLabels.OrchestrationIdentity[d.Label.Key]
+		m.Labels = append(m.Labels, explainLabel(d, kept))
+	}
+
+	sort.Slice(m.Labels, func(i, j int) bool { return m.Labels[i].Label < m.Labels[j].Label })
+
+	return m
+}
@@ -1031,6 +1031,49 @@ func (e *Endpoint) FormatGlobalEndpointID() string {
 	return strings.Join(metadata, ":")
 }
 
+// syncIPIdentityMapping writes the mapping of the endpoint's IP to its
+// security identity to the key-value store. Returns false if the endpoint
+// has no identity yet or is being disconnected.
+func (e *Endpoint) syncIPIdentityMapping(ipKey string, endpointIP addressing.CiliumIP) (bool, error) {
+	e.Mutex.RLock()
+
+	if e.state == StateDisconnected || e.state == StateDisconnecting {
+		log.WithFields(logrus.Fields{logfields.EndpointState: e.state}).
+			Debugf("not synchronizing endpoint IP with kvstore due to endpoint state")
+		e.Mutex.RUnlock()
+		return false, nil
+	}
+
+	if e.SecurityIdentity == nil {
+		e.Mutex.RUnlock()
+		return false, nil
+	}
+	//identityValue := e.SecurityIdentity.ID.StringID()
+	ipIDPair := identityPkg.IPIdentityPair{
+		IP:       endpointIP.IP(),
+		ID:       e.SecurityIdentity.ID,
+		Metadata: e.FormatGlobalEndpointID(),
+	}
+
+	// Release lock as we do not want to have long-lasting key-value
+	// store operations resulting in lock being held for a long time.
+	e.Mutex.RUnlock()
+
+	marshaledIPIDPair, err := json.Marshal(ipIDPair)
+	if err != nil {
+		return false, err
+	}
+
+	if err := kvstore.Update(ipKey, marshaledIPIDPair, true); err != nil {
+		return false, fmt.Errorf("unable to add endpoint IP '%s' to identity '%s': %s", ipKey, marshaledIPIDPair, err)
+	}
+	return true, nil
+}
+
+func ipIdentityKey(endpointIP addressing.CiliumIP) string {
+	return path.Join(ipcache.IPIdentitiesPath, ipcache.AddressSpace, endpointIP.String())
+}
+
 // This synchronizes the key-value store with a mapping of the endpoint's IP
 // with the numerical ID representing its security identity.
 func (e *Endpoint) runIPIdentitySync(endpointIP addressing.CiliumIP) {
@@ -1040,45 +1083,13 @@ func (e *Endpoint) runIPIdentitySync(endpointIP addressing.CiliumIP) {
 	}
 
 	addressFamily := endpointIP.GetFamilyString()
-	ipKey := path.Join(ipcache.IPIdentitiesPath, ipcache.AddressSpace, endpointIP.String())
+	ipKey := ipIdentityKey(endpointIP)
 
 	e.controllers.UpdateController(fmt.Sprintf("sync-%s-identity-mapping (%d)", addressFamily, e.ID),
 		controller.ControllerParams{
 			DoFunc: func() error {
-
-				e.Mutex.RLock()
-
-				if e.state == StateDisconnected || e.state == StateDisconnecting {
-					log.WithFields(logrus.Fields{logfields.EndpointState: e.state}).
-						Debugf("not synchronizing endpoint IP with kvstore due to endpoint state")
-					e.Mutex.RUnlock()
-					return nil
-				}
-
-				if e.SecurityIdentity == nil {
-					e.Mutex.RUnlock()
-					return nil
-				}
-				//identityValue := e.SecurityIdentity.ID.StringID()
-				ipIDPair := identityPkg.IPIdentityPair{
-					IP:       endpointIP.IP(),
-					ID:       e.SecurityIdentity.ID,
-					Metadata: e.FormatGlobalEndpointID(),
-				}
-
-				// Release lock as we do not want to have long-lasting key-value
-				// store operations resulting in lock being held for a long time.
-				e.Mutex.RUnlock()
-
-				marshaledIPIDPair, err := json.Marshal(ipIDPair)
-				if err != nil {
-					return err
-				}
-
-				if err := kvstore.Update(ipKey, marshaledIPIDPair, true); err != nil {
-					return fmt.Errorf("unable to add endpoint IP '%s' to identity '%s': %s", ipKey, marshaledIPIDPair, err)
-				}
-				return nil
+				_, err := e.syncIPIdentityMapping(ipKey, endpointIP)
+				return err
 			},
 			StopFunc: func() error {
 				if err := kvstore.Delete(ipKey); err != nil {
@@ -1091,6 +1102,35 @@ func (e *Endpoint) runIPIdentitySync(endpointIP addressing.CiliumIP) {
 	)
 }
 
+// SyncIPIdentityMappings writes the mappings of all IPs of the endpoint to its
+// security identity to the key-value store and returns the number of mappings
+// written. It is used to re-create the mappings after the kvstore lease they
+// are attached to has expired.
+func (e *Endpoint) SyncIPIdentityMappings() (int, error) {
+	e.Mutex.RLock()
+	ips := []addressing.CiliumIP{}
+	if e.IPv4 != nil {
+		ips = append(ips, e.IPv4)
+	}
+	if e.IPv6 != nil {
+		ips = append(ips, e.IPv6)
+	}
+	e.Mutex.RUnlock()
+
+	synced := 0
+	for _, endpointIP := range ips {
+		ok, err := e.syncIPIdentityMapping(ipIdentityKey(endpointIP), endpointIP)
+		if err != nil {
+			return synced, err
+		}
+		if ok {
+			synced++
+		}
+	}
+
+	return synced, nil
+}
+
 // SetIdentity resets endpoint's policy identity to 'id'.
 // Caller triggers policy regeneration if needed.
 // Called with e.Mutex Locked
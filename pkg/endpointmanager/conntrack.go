@@ -131,6 +131,51 @@ func EnableConntrackGC(ipv4, ipv6 bool) {
 	}()
 }
 
+// DumpConntrackFlows returns the entries of all CT maps in use, i.e. the
+// global CT maps and the local CT maps of all endpoints with
+// OptionConntrackLocal enabled. Maps which cannot be opened are skipped.
+func DumpConntrackFlows(ipv4, ipv6 bool) ([]ctmap.FlowEntry, error) {
+	maps := map[string]string{}
+	if ipv4 {
+		maps[bpf.MapPath(ctmap.MapName4Global)] = ctmap.MapName4Global
+	}
+	if ipv6 {
+		maps[bpf.MapPath(ctmap.MapName6Global)] = ctmap.MapName6Global
+	}
+
+	for _, e := range GetEndpoints() {
+		e.Mutex.RLock()
+		if e.Consumable != nil && e.Opts.IsEnabled(endpoint.OptionConntrackLocal) {
+			id := strconv.Itoa(int(e.ID))
+			if ipv4 {
+				maps[bpf.MapPath(ctmap.MapName4+id)] = ctmap.MapName4
+			}
+			if ipv6 {
+				maps[bpf.MapPath(ctmap.MapName6+id)] = ctmap.MapName6
+			}
+		}
+		e.Mutex.RUnlock()
+	}
+
+	flows := []ctmap.FlowEntry{}
+	for file, mapType := range maps {
+		m, err := bpf.OpenMap(file)
+		if err != nil {
+			log.WithError(err).WithField(logfields.Path, file).Debug("Unable to open map")
+			continue
+		}
+
+		entries, err := ctmap.DumpFlows(m, mapType)
+		m.Close()
+		if err != nil {
+			return nil, fmt.Errorf("unable to dump CT map %s: %s", file, err)
+		}
+		flows = append(flows, entries...)
+	}
+
+	return flows, nil
+}
+
 // ResetProxyPort modifies the connection tracking table of the given endpoint
 // `e`. It modifies all CT entries that of the CT table local or global, defined
 // by isLocal, that contain:
@@ -0,0 +1,413 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowexport
+
+import (
+	"encoding/binary"
+	"net"
+	"time"
+)
+
+// Information element identifiers as registered with IANA, see
+// https://www.iana.org/assignments/ipfix/ipfix.xhtml. Identifiers up to 127
+// are shared with the NetFlow v9 field types.
+const (
+	ieOctetDeltaCount          = 1
+	iePacketDeltaCount         = 2
+	ieProtocolIdentifier       = 4
+	ieSourceTransportPort      = 7
+	ieSourceIPv4Address        = 8
+	ieDestinationTransportPort = 11
+	ieDestinationIPv4Address   = 12
+	ieFlowEndSysUpTime         = 21
+	ieFlowStartSysUpTime       = 22
+	iePostOctetDeltaCount      = 23
+	iePostPacketDeltaCount     = 24
+	ieSourceIPv6Address        = 27
+	ieDestinationIPv6Address   = 28
+	ieFlowDirection            = 61
+	ieFlowEndReason            = 136
+	ieFlowStartSeconds         = 150
+	ieFlowEndSeconds           = 151
+)
+
+// Enterprise specific information elements carrying the security identities.
+// With NetFlow v9, they are encoded as field types with the most significant
+// bit set.
+const (
+	ieSourceSecurityIdentity      = 1
+	ieDestinationSecurityIdentity = 2
+)
+
+const (
+	// reversePEN is the private enterprise number used to encode reverse
+	// information elements of biflows (RFC 5103)
+	reversePEN = 29305
+
+	// enterpriseBit marks an information element as enterprise specific
+	enterpriseBit = 0x8000
+)
+
+// Values of the flowEndReason information element
+const (
+	endReasonIdleTimeout   = 1
+	endReasonActiveTimeout = 2
+)
+
+// Values of the flowDirection information element
+const (
+	directionIngress = 0
+	directionEgress  = 1
+)
+
+const (
+	ipfixVersion      = 10
+	ipfixHeaderLen    = 16
+	ipfixTemplateSet  = 2
+	netflow9Version   = 9
+	netflow9HeaderLen = 20
+	netflow9Template  = 0
+	setHeaderLen      = 4
+
+	templateIDv4 = 256
+	templateIDv6 = 257
+
+	// maxPacketSize is the maximum size of an export packet, chosen to
+	// avoid IP fragmentation on common MTUs
+	maxPacketSize = 1400
+)
+
+// record is a single flow record as exported to the collector. The forward
+// direction is the direction of the packet which created the CT entry.
+type record struct {
+	ipv6        bool
+	srcIP       net.IP
+	dstIP       net.IP
+	srcPort     uint16
+	dstPort     uint16
+	proto       uint8
+	ingress     bool
+	packets     uint64
+	bytes       uint64
+	revPackets  uint64
+	revBytes    uint64
+	start       time.Time
+	end         time.Time
+	endReason   uint8
+	srcIdentity uint32
+	dstIdentity uint32
+}
+
+func (r *record) direction() uint8 {
+	if r.ingress {
+		return directionIngress
+	}
+	return directionEgress
+}
+
+// field is a field of a template together with the function encoding the
+// field value of a record.
+type field struct {
+	id         uint16
+	length     uint16
+	enterprise uint32
+	put        func(b []byte, r *record)
+}
+
+type template struct {
+	id     uint16
+	fields []field
+}
+
+func (t *template) recordLen() int {
+	l := 0
+	for _, f := range t.fields {
+		l += int(f.length)
+	}
+	return l
+}
+
+func putUint8(get func(r *record) uint8) func(b []byte, r *record) {
+	return func(b []byte, r *record) { b[0] = get(r) }
+}
+
+func putUint16(get func(r *record) uint16) func(b []byte, r *record) {
+	return func(b []byte, r *record) { binary.BigEndian.PutUint16(b, get(r)) }
+}
+
+func putUint32(get func(r *record) uint32) func(b []byte, r *record) {
+	return func(b []byte, r *record) { binary.BigEndian.PutUint32(b, get(r)) }
+}
+
+func putUint64(get func(r *record) uint64) func(b []byte, r *record) {
+	return func(b []byte, r *record) { binary.BigEndian.PutUint64(b, get(r)) }
+}
+
+func putIP(get func(r *record) net.IP) func(b []byte, r *record) {
+	return func(b []byte, r *record) {
+		ip := get(r)
+		if len(b) == net.IPv4len {
+			ip = ip.To4()
+		} else {
+			ip = ip.To16()
+		}
+		copy(b, ip)
+	}
+}
+
+// uptime returns the milliseconds passed between boot and t
+func uptime(boot, t time.Time) uint32 {
+	if t.Before(boot) {
+		return 0
+	}
+	return uint32(t.Sub(boot) / time.Millisecond)
+}
+
+// tupleFields returns the fields identifying a flow which are common to
+// IPFIX and NetFlow v9
+func tupleFields(ipv6 bool) []field {
+	srcIE, dstIE, addrLen := uint16(ieSourceIPv4Address), uint16(ieDestinationIPv4Address), uint16(net.IPv4len)
+	if ipv6 {
+		srcIE, dstIE, addrLen = ieSourceIPv6Address, ieDestinationIPv6Address, net.IPv6len
+	}
+
+	return []field{
+		{id: srcIE, length: addrLen, put: putIP(func(r *record) net.IP { return r.srcIP })},
+		{id: dstIE, length: addrLen, put: putIP(func(r *record) net.IP { return r.dstIP })},
+		{id: ieSourceTransportPort, length: 2, put: putUint16(func(r *record) uint16 { return r.srcPort })},
+		{id: ieDestinationTransportPort, length: 2, put: putUint16(func(r *record) uint16 { return r.dstPort })},
+		{id: ieProtocolIdentifier, length: 1, put: putUint8(func(r *record) uint8 { return r.proto })},
+		{id: ieFlowDirection, length: 1, put: putUint8((*record).direction)},
+	}
+}
+
+func newIPFIXTemplate(id uint16, ipv6 bool, pen uint32) *template {
+	fields := append(tupleFields(ipv6), []field{
+		{id: iePacketDeltaCount, length: 8, put: putUint64(func(r *record) uint64 { return r.packets })},
+		{id: ieOctetDeltaCount, length: 8, put: putUint64(func(r *record) uint64 { return r.bytes })},
+		{id: iePacketDeltaCount, length: 8, enterprise: reversePEN, put: putUint64(func(r *record) uint64 { return r.revPackets })},
+		{id: ieOctetDeltaCount, length: 8, enterprise: reversePEN, put: putUint64(func(r *record) uint64 { return r.revBytes })},
+		{id: ieFlowStartSeconds, length: 4, put: putUint32(func(r *record) uint32 { return uint32(r.start.Unix()) })},
+		{id: ieFlowEndSeconds, length: 4, put: putUint32(func(r *record) uint32 { return uint32(r.end.Unix()) })},
+		{id: ieFlowEndReason, length: 1, put: putUint8(func(r *record) uint8 { return r.endReason })},
+		{id: ieSourceSecurityIdentity, length: 4, enterprise: pen, put: putUint32(func(r *record) uint32 { return r.srcIdentity })},
+		{id: ieDestinationSecurityIdentity, length: 4, enterprise: pen, put: putUint32(func(r *record) uint32 { return r.dstIdentity })},
+	}...)
+
+	return &template{id: id, fields: fields}
+}
+
+func newNetflow9Template(id uint16, ipv6 bool, boot time.Time) *template {
+	fields := append(tupleFields(ipv6), []field{
+		{id: iePacketDeltaCount, length: 8, put: putUint64(func(r *record) uint64 { return r.packets })},
+		{id: ieOctetDeltaCount, length: 8, put: putUint64(func(r *record) uint64 { return r.bytes })},
+		{id: iePostPacketDeltaCount, length: 8, put: putUint64(func(r *record) uint64 { return r.revPackets })},
+		{id: iePostOctetDeltaCount, length: 8, put: putUint64(func(r *record) uint64 { return r.revBytes })},
+		{id: ieFlowStartSysUpTime, length: 4, put: putUint32(func(r *record) uint32 { return uptime(boot, r.start) })},
+		{id: ieFlowEndSysUpTime, length: 4, put: putUint32(func(r *record) uint32 { return uptime(boot, r.end) })},
+		{id: enterpriseBit | ieSourceSecurityIdentity, length: 4, put: putUint32(func(r *record) uint32 { return r.srcIdentity })},
+		{id: enterpriseBit | ieDestinationSecurityIdentity, length: 4, put: putUint32(func(r *record) uint32 { return r.dstIdentity })},
+	}...)
+
+	return &template{id: id, fields: fields}
+}
+
+// packet is an export packet under construction
+type packet struct {
+	buf []byte
+
+	// set is the offset of the set currently being written or -1
+	set   int
+	setID uint16
+
+	// records is the number of template and data records in the packet
+	records int
+
+	// dataRecords is the number of data records in the packet
+	dataRecords int
+}
+
+// encoder encodes flow records into IPFIX or NetFlow v9 export packets
+type encoder struct {
+	protocol string
+	domain   uint32
+	boot     time.Time
+
+	// templates contains the IPv4 and IPv6 templates
+	templates [2]*template
+
+	// sequence is the number of data records (IPFIX) or packets
+	// (NetFlow v9) sent so far
+	sequence uint32
+}
+
+func newEncoder(protocol string, domain, pen uint32, boot time.Time) *encoder {
+	enc := &encoder{
+		protocol: protocol,
+		domain:   domain,
+		boot:     boot,
+	}
+
+	if protocol == ProtocolNetFlow9 {
+		enc.templates[0] = newNetflow9Template(templateIDv4, false, boot)
+		enc.templates[1] = newNetflow9Template(templateIDv6, true, boot)
+	} else {
+		enc.templates[0] = newIPFIXTemplate(templateIDv4, false, pen)
+		enc.templates[1] = newIPFIXTemplate(templateIDv6, true, pen)
+	}
+
+	return enc
+}
+
+func (enc *encoder) headerLen() int {
+	if enc.protocol == ProtocolNetFlow9 {
+		return netflow9HeaderLen
+	}
+	return ipfixHeaderLen
+}
+
+func (enc *encoder) newPacket() *packet {
+	return &packet{
+		buf: make([]byte, enc.headerLen(), maxPacketSize),
+		set: -1,
+	}
+}
+
+func (enc *encoder) openSet(p *packet, id uint16) {
+	if p.set >= 0 && p.setID == id {
+		return
+	}
+	enc.closeSet(p)
+	p.set = len(p.buf)
+	p.setID = id
+	p.buf = append(p.buf, make([]byte, setHeaderLen)...)
+}
+
+func (enc *encoder) closeSet(p *packet) {
+	if p.set < 0 {
+		return
+	}
+	// NetFlow v9 requires flowsets to be padded to a 32 bit boundary
+	if enc.protocol == ProtocolNetFlow9 {
+		for (len(p.buf)-p.set)%4 != 0 {
+			p.buf = append(p.buf, 0)
+		}
+	}
+	binary.BigEndian.PutUint16(p.buf[p.set:], p.setID)
+	binary.BigEndian.PutUint16(p.buf[p.set+2:], uint16(len(p.buf)-p.set))
+	p.set = -1
+}
+
+func (enc *encoder) addTemplates(p *packet) {
+	setID := uint16(ipfixTemplateSet)
+	if enc.protocol == ProtocolNetFlow9 {
+		setID = netflow9Template
+	}
+	enc.openSet(p, setID)
+
+	var b [8]byte
+	for _, t := range enc.templates {
+		binary.BigEndian.PutUint16(b[0:], t.id)
+		binary.BigEndian.PutUint16(b[2:], uint16(len(t.fields)))
+		p.buf = append(p.buf, b[:4]...)
+		for _, f := range t.fields {
+			id := f.id
+			if f.enterprise != 0 {
+				id |= enterpriseBit
+			}
+			binary.BigEndian.PutUint16(b[0:], id)
+			binary.BigEndian.PutUint16(b[2:], f.length)
+			binary.BigEndian.PutUint32(b[4:], f.enterprise)
+			if f.enterprise != 0 {
+				p.buf = append(p.buf, b[:8]...)
+			} else {
+				p.buf = append(p.buf, b[:4]...)
+			}
+		}
+		p.records++
+	}
+}
+
+// fits returns true if a record of template t can be added to p
+func (enc *encoder) fits(p *packet, t *template) bool {
+	l := len(p.buf) + t.recordLen()
+	if p.set < 0 || p.setID != t.id {
+		l += setHeaderLen
+	}
+	// account for the padding of the set when closing it
+	return l+3 <= maxPacketSize
+}
+
+func (enc *encoder) addRecord(p *packet, t *template, r *record) {
+	enc.openSet(p, t.id)
+	off := len(p.buf)
+	p.buf = append(p.buf, make([]byte, t.recordLen())...)
+	for _, f := range t.fields {
+		f.put(p.buf[off:off+int(f.length)], r)
+		off += int(f.length)
+	}
+	p.records++
+	p.dataRecords++
+}
+
+// finish closes the last set of p and writes the message header
+func (enc *encoder) finish(p *packet, now time.Time) []byte {
+	enc.closeSet(p)
+
+	b := p.buf
+	if enc.protocol == ProtocolNetFlow9 {
+		binary.BigEndian.PutUint16(b[0:], netflow9Version)
+		binary.BigEndian.PutUint16(b[2:], uint16(p.records))
+		binary.BigEndian.PutUint32(b[4:], uptime(enc.boot, now))
+		binary.BigEndian.PutUint32(b[8:], uint32(now.Unix()))
+		binary.BigEndian.PutUint32(b[12:], enc.sequence)
+		binary.BigEndian.PutUint32(b[16:], enc.domain)
+		enc.sequence++
+	} else {
+		binary.BigEndian.PutUint16(b[0:], ipfixVersion)
+		binary.BigEndian.PutUint16(b[2:], uint16(len(b)))
+		binary.BigEndian.PutUint32(b[4:], uint32(now.Unix()))
+		binary.BigEndian.PutUint32(b[8:], enc.sequence)
+		binary.BigEndian.PutUint32(b[12:], enc.domain)
+		enc.sequence += uint32(p.dataRecords)
+	}
+
+	return b
+}
+
+// encode returns the export packets for all records. The templates are sent
+// at the beginning of the first packet.
+func (enc *encoder) encode(now time.Time, records []record) [][]byte {
+	packets := [][]byte{}
+
+	p := enc.newPacket()
+	enc.addTemplates(p)
+
+	for _, t := range enc.templates {
+		ipv6 := t.id == templateIDv6
+		for i := range records {
+			if records[i].ipv6 != ipv6 {
+				continue
+			}
+			if !enc.fits(p, t) {
+				packets = append(packets, enc.finish(p, now))
+				p = enc.newPacket()
+			}
+			enc.addRecord(p, t, &records[i])
+		}
+	}
+
+	return append(packets, enc.finish(p, now))
+}
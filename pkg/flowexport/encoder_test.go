@@ -0,0 +1,142 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowexport
+
+import (
+	"encoding/binary"
+	"net"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func testRecord(ipv6 bool) record {
+	start := time.Unix(1500000000, 0)
+	r := record{
+		ipv6:        ipv6,
+		srcIP:       net.ParseIP("10.0.0.1"),
+		dstIP:       net.ParseIP("10.0.0.2"),
+		srcPort:     40000,
+		dstPort:     80,
+		proto:       6,
+		ingress:     true,
+		packets:     10,
+		bytes:       1000,
+		revPackets:  5,
+		revBytes:    500,
+		start:       start,
+		end:         start.Add(time.Minute),
+		endReason:   endReasonIdleTimeout,
+		srcIdentity: 1000,
+		dstIdentity: 2000,
+	}
+	if ipv6 {
+		r.srcIP = net.ParseIP("f00d::1")
+		r.dstIP = net.ParseIP("f00d::2")
+	}
+	return r
+}
+
+func (s *FlowExportSuite) TestEncodeIPFIX(c *C) {
+	now := time.Unix(1500000060, 0)
+	enc := newEncoder(ProtocolIPFIX, 42, DefaultEnterpriseNumber, now)
+	packets := enc.encode(now, []record{testRecord(false)})
+	c.Assert(len(packets), Equals, 1)
+
+	b := packets[0]
+	c.Assert(binary.BigEndian.Uint16(b[0:]), Equals, uint16(ipfixVersion))
+	c.Assert(int(binary.BigEndian.Uint16(b[2:])), Equals, len(b))
+	c.Assert(binary.BigEndian.Uint32(b[4:]), Equals, uint32(now.Unix()))
+	c.Assert(binary.BigEndian.Uint32(b[8:]), Equals, uint32(0))
+	c.Assert(binary.BigEndian.Uint32(b[12:]), Equals, uint32(42))
+
+	// Template set with both templates
+	b = b[ipfixHeaderLen:]
+	c.Assert(binary.BigEndian.Uint16(b[0:]), Equals, uint16(ipfixTemplateSet))
+	setLen := int(binary.BigEndian.Uint16(b[2:]))
+	c.Assert(binary.BigEndian.Uint16(b[4:]), Equals, uint16(templateIDv4))
+	c.Assert(int(binary.BigEndian.Uint16(b[6:])), Equals, len(enc.templates[0].fields))
+
+	// Data set with a single IPv4 record
+	b = b[setLen:]
+	t := enc.templates[0]
+	c.Assert(binary.BigEndian.Uint16(b[0:]), Equals, uint16(templateIDv4))
+	c.Assert(int(binary.BigEndian.Uint16(b[2:])), Equals, setHeaderLen+t.recordLen())
+	b = b[setHeaderLen:]
+	c.Assert(net.IP(b[0:4]).String(), Equals, "10.0.0.1")
+	c.Assert(net.IP(b[4:8]).String(), Equals, "10.0.0.2")
+	c.Assert(binary.BigEndian.Uint16(b[8:]), Equals, uint16(40000))
+	c.Assert(binary.BigEndian.Uint16(b[10:]), Equals, uint16(80))
+	c.Assert(b[12], Equals, uint8(6))
+	c.Assert(b[13], Equals, uint8(directionIngress))
+	c.Assert(binary.BigEndian.Uint64(b[14:]), Equals, uint64(10))
+	c.Assert(binary.BigEndian.Uint64(b[22:]), Equals, uint64(1000))
+	c.Assert(binary.BigEndian.Uint64(b[30:]), Equals, uint64(5))
+	c.Assert(binary.BigEndian.Uint64(b[38:]), Equals, uint64(500))
+	c.Assert(binary.BigEndian.Uint32(b[46:]), Equals, uint32(1500000000))
+	c.Assert(binary.BigEndian.Uint32(b[50:]), Equals, uint32(1500000060))
+	c.Assert(b[54], Equals, uint8(endReasonIdleTimeout))
+	c.Assert(binary.BigEndian.Uint32(b[55:]), Equals, uint32(1000))
+	c.Assert(binary.BigEndian.Uint32(b[59:]), Equals, uint32(2000))
+
+	// The sequence number counts data records
+	packets = enc.encode(now, []record{testRecord(false)})
+	c.Assert(binary.BigEndian.Uint32(packets[0][8:]), Equals, uint32(1))
+}
+
+func (s *FlowExportSuite) TestEncodeNetflow9(c *C) {
+	boot := time.Unix(1500000000, 0)
+	now := boot.Add(2 * time.Minute)
+	enc := newEncoder(ProtocolNetFlow9, 42, 0, boot)
+	packets := enc.encode(now, []record{testRecord(true), testRecord(false)})
+	c.Assert(len(packets), Equals, 1)
+
+	b := packets[0]
+	c.Assert(binary.BigEndian.Uint16(b[0:]), Equals, uint16(netflow9Version))
+	// 2 templates and 2 data records
+	c.Assert(binary.BigEndian.Uint16(b[2:]), Equals, uint16(4))
+	c.Assert(binary.BigEndian.Uint32(b[4:]), Equals, uint32(120000))
+	c.Assert(binary.BigEndian.Uint32(b[16:]), Equals, uint32(42))
+
+	b = b[netflow9HeaderLen:]
+	c.Assert(binary.BigEndian.Uint16(b[0:]), Equals, uint16(netflow9Template))
+	sets := []uint16{}
+	for len(b) > 0 {
+		setLen := int(binary.BigEndian.Uint16(b[2:]))
+		c.Assert(setLen%4, Equals, 0)
+		sets = append(sets, binary.BigEndian.Uint16(b[0:]))
+		b = b[setLen:]
+	}
+	c.Assert(sets, DeepEquals, []uint16{netflow9Template, templateIDv4, templateIDv6})
+
+	packets = enc.encode(now, nil)
+	c.Assert(binary.BigEndian.Uint32(packets[0][12:]), Equals, uint32(1))
+}
+
+func (s *FlowExportSuite) TestEncodeSplit(c *C) {
+	now := time.Now()
+	enc := newEncoder(ProtocolIPFIX, 0, DefaultEnterpriseNumber, now)
+	records := make([]record, 100)
+	for i := range records {
+		records[i] = testRecord(i%2 == 0)
+	}
+
+	packets := enc.encode(now, records)
+	c.Assert(len(packets) > 1, Equals, true)
+	for _, p := range packets {
+		c.Assert(len(p) <= maxPacketSize, Equals, true)
+	}
+	c.Assert(enc.sequence, Equals, uint32(100))
+}
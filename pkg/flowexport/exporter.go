@@ -0,0 +1,289 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowexport
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/cilium/cilium/pkg/identity"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/maps/ctmap"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// ProtocolIPFIX exports flows as IPFIX (RFC 7011)
+	ProtocolIPFIX = "ipfix"
+
+	// ProtocolNetFlow9 exports flows as NetFlow v9 (RFC 3954)
+	ProtocolNetFlow9 = "netflow9"
+
+	// DefaultInterval is the default interval in which flows are exported
+	DefaultInterval = time.Minute
+
+	// DefaultEnterpriseNumber is the private enterprise number used for
+	// the security identity fields if none is configured
+	DefaultEnterpriseNumber = 32473
+)
+
+// Config is the configuration of an Exporter
+type Config struct {
+	// Collector is the host:port of the UDP collector
+	Collector string
+
+	// Protocol is either ProtocolIPFIX or ProtocolNetFlow9
+	Protocol string
+
+	// Interval is the interval in which the CT tables are exported
+	Interval time.Duration
+
+	// ObservationDomain is the observation domain (IPFIX) or source ID
+	// (NetFlow v9) sent in the message header
+	ObservationDomain uint32
+
+	// EnterpriseNumber is the private enterprise number of the IPFIX
+	// information elements carrying the security identities
+	EnterpriseNumber uint32
+}
+
+// Validate returns an error if the configuration is invalid
+func (c *Config) Validate() error {
+	if _, _, err := net.SplitHostPort(c.Collector); err != nil {
+		return fmt.Errorf("invalid collector address %q: %s", c.Collector, err)
+	}
+
+	switch c.Protocol {
+	case ProtocolIPFIX, ProtocolNetFlow9:
+	default:
+		return fmt.Errorf("invalid protocol %q, must be { %s | %s }", c.Protocol, ProtocolIPFIX, ProtocolNetFlow9)
+	}
+
+	if c.Interval <= 0 {
+		return fmt.Errorf("invalid interval %s, must be positive", c.Interval)
+	}
+
+	return nil
+}
+
+// FlowSource returns all current CT entries together with the time of the
+// monotonic clock in seconds used to determine whether an entry has expired.
+type FlowSource func() (flows []ctmap.FlowEntry, now uint32, err error)
+
+// IdentityResolver returns the security identity of the given IP
+type IdentityResolver func(ip net.IP) (identity.NumericIdentity, bool)
+
+// flowState is the state of a flow as of the last export
+type flowState struct {
+	// last is the CT entry as seen in the last export
+	last ctmap.FlowEntry
+
+	// start is the time the flow was first seen
+	start time.Time
+
+	// ended is true when the final record of the flow has been exported
+	// while the CT entry is still waiting for garbage collection
+	ended bool
+}
+
+// Exporter periodically exports the CT entries returned by a FlowSource as
+// flow records. Counters are exported as deltas since the last export. A
+// final record is exported for flows which have expired or have been garbage
+// collected.
+type Exporter struct {
+	mutex   lock.Mutex
+	conf    Config
+	source  FlowSource
+	resolve IdentityResolver
+	enc     *encoder
+	conn    net.Conn
+
+	// flows maps the tuple of a flow to its state
+	flows map[string]*flowState
+}
+
+// NewExporter returns a new exporter sending the flows returned by source to
+// the collector of conf
+func NewExporter(conf Config, source FlowSource, resolve IdentityResolver) (*Exporter, error) {
+	if conf.Interval == 0 {
+		conf.Interval = DefaultInterval
+	}
+	if conf.EnterpriseNumber == 0 {
+		conf.EnterpriseNumber = DefaultEnterpriseNumber
+	}
+	if err := conf.Validate(); err != nil {
+		return nil, err
+	}
+
+	conn, err := net.Dial("udp", conf.Collector)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to collector %s: %s", conf.Collector, err)
+	}
+
+	return &Exporter{
+		conf:    conf,
+		source:  source,
+		resolve: resolve,
+		enc:     newEncoder(conf.Protocol, conf.ObservationDomain, conf.EnterpriseNumber, time.Now()),
+		conn:    conn,
+		flows:   map[string]*flowState{},
+	}, nil
+}
+
+// Interval returns the configured export interval
+func (e *Exporter) Interval() time.Duration {
+	return e.conf.Interval
+}
+
+// Close closes the connection to the collector
+func (e *Exporter) Close() error {
+	return e.conn.Close()
+}
+
+// Export walks all flows of the source once and sends the resulting records to
+// the collector
+func (e *Exporter) Export() error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	flows, mtime, err := e.source()
+	if err != nil {
+		return fmt.Errorf("unable to dump CT entries: %s", err)
+	}
+
+	now := time.Now()
+	records := e.collect(now, flows, mtime)
+	for _, pkt := range e.enc.encode(now, records) {
+		if _, err := e.conn.Write(pkt); err != nil {
+			return fmt.Errorf("unable to send flow records to %s: %s", e.conf.Collector, err)
+		}
+	}
+
+	log.WithFields(logrus.Fields{
+		fieldCollector: e.conf.Collector,
+		fieldProtocol:  e.conf.Protocol,
+		"records":      len(records),
+	}).Debug("Exported flow records")
+
+	return nil
+}
+
+func (e *Exporter) identityOf(ip net.IP) uint32 {
+	if e.resolve == nil {
+		return 0
+	}
+	id, ok := e.resolve(ip)
+	if !ok {
+		return 0
+	}
+	return uint32(id)
+}
+
+func (e *Exporter) newRecord(f *ctmap.FlowEntry, st *flowState, now time.Time) record {
+	srcIdentity := uint32(f.SrcSecID)
+	if srcIdentity == 0 {
+		srcIdentity = e.identityOf(f.SrcIP)
+	}
+
+	return record{
+		ipv6:        f.SrcIP.To4() == nil,
+		srcIP:       f.SrcIP,
+		dstIP:       f.DstIP,
+		srcPort:     f.SrcPort,
+		dstPort:     f.DstPort,
+		proto:       uint8(f.Proto),
+		ingress:     f.Ingress,
+		start:       st.start,
+		end:         now,
+		endReason:   endReasonActiveTimeout,
+		srcIdentity: srcIdentity,
+		dstIdentity: e.identityOf(f.DstIP),
+	}
+}
+
+// collect updates the flow state with the given flows and returns the records
+// to export. mtime is the time of the monotonic clock in seconds used to
+// determine expired entries.
+func (e *Exporter) collect(now time.Time, flows []ctmap.FlowEntry, mtime uint32) []record {
+	records := []record{}
+	seen := make(map[string]struct{}, len(flows))
+
+	for i := range flows {
+		f := &flows[i]
+		key := f.String()
+		seen[key] = struct{}{}
+
+		expired := f.Expired(mtime)
+		st, ok := e.flows[key]
+		if ok && st.ended {
+			if expired {
+				// Final record was already exported, waiting
+				// for the entry to be garbage collected
+				continue
+			}
+			// The entry has been revived by new traffic, the
+			// counters continue from the final record.
+			st.ended = false
+			st.start = now
+		}
+
+		// Counters lower than in the last export indicate that the
+		// entry has been recreated in between.
+		if ok && (f.TxPackets < st.last.TxPackets || f.RxPackets < st.last.RxPackets ||
+			f.TxBytes < st.last.TxBytes || f.RxBytes < st.last.RxBytes) {
+			ok = false
+		}
+
+		if !ok {
+			st = &flowState{start: now}
+			e.flows[key] = st
+		}
+
+		r := e.newRecord(f, st, now)
+		r.packets = f.TxPackets - st.last.TxPackets
+		r.bytes = f.TxBytes - st.last.TxBytes
+		r.revPackets = f.RxPackets - st.last.RxPackets
+		r.revBytes = f.RxBytes - st.last.RxBytes
+		st.last = *f
+
+		if expired {
+			r.endReason = endReasonIdleTimeout
+			st.ended = true
+		} else if ok && r.packets == 0 && r.revPackets == 0 {
+			// No activity since the last export
+			continue
+		}
+
+		records = append(records, r)
+	}
+
+	for key, st := range e.flows {
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		// The entry has been garbage collected, the traffic since the
+		// last export is lost.
+		if !st.ended {
+			r := e.newRecord(&st.last, st, now)
+			r.endReason = endReasonIdleTimeout
+			records = append(records, r)
+		}
+		delete(e.flows, key)
+	}
+
+	return records
+}
@@ -0,0 +1,159 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowexport
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/cilium/cilium/pkg/identity"
+	"github.com/cilium/cilium/pkg/maps/ctmap"
+	"github.com/cilium/cilium/pkg/u8proto"
+
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type FlowExportSuite struct{}
+
+var _ = Suite(&FlowExportSuite{})
+
+func testFlow(tx, rx uint64, lifetime uint32) ctmap.FlowEntry {
+	return ctmap.FlowEntry{
+		SrcIP:     net.ParseIP("10.0.0.1"),
+		DstIP:     net.ParseIP("10.0.0.2"),
+		SrcPort:   40000,
+		DstPort:   80,
+		Proto:     u8proto.TCP,
+		TxPackets: tx,
+		TxBytes:   tx * 100,
+		RxPackets: rx,
+		RxBytes:   rx * 100,
+		Lifetime:  lifetime,
+	}
+}
+
+func newTestExporter() *Exporter {
+	resolve := func(ip net.IP) (identity.NumericIdentity, bool) {
+		if ip.String() == "10.0.0.2" {
+			return 2000, true
+		}
+		return 0, false
+	}
+	return &Exporter{
+		resolve: resolve,
+		flows:   map[string]*flowState{},
+	}
+}
+
+func (s *FlowExportSuite) TestConfigValidate(c *C) {
+	conf := Config{Collector: "127.0.0.1:4739", Protocol: ProtocolIPFIX, Interval: time.Second}
+	c.Assert(conf.Validate(), IsNil)
+
+	conf.Protocol = "sflow"
+	c.Assert(conf.Validate(), Not(IsNil))
+
+	conf.Protocol = ProtocolNetFlow9
+	conf.Collector = "127.0.0.1"
+	c.Assert(conf.Validate(), Not(IsNil))
+}
+
+func (s *FlowExportSuite) TestCollectDeltas(c *C) {
+	e := newTestExporter()
+	start := time.Now()
+
+	f := testFlow(10, 5, 100)
+	f.SrcSecID = 1000
+	records := e.collect(start, []ctmap.FlowEntry{f}, 50)
+	c.Assert(len(records), Equals, 1)
+	c.Assert(records[0].packets, Equals, uint64(10))
+	c.Assert(records[0].revPackets, Equals, uint64(5))
+	c.Assert(records[0].endReason, Equals, uint8(endReasonActiveTimeout))
+	c.Assert(records[0].srcIdentity, Equals, uint32(1000))
+	c.Assert(records[0].dstIdentity, Equals, uint32(2000))
+
+	// No activity, no record
+	records = e.collect(start.Add(time.Minute), []ctmap.FlowEntry{f}, 60)
+	c.Assert(len(records), Equals, 0)
+
+	records = e.collect(start.Add(2*time.Minute), []ctmap.FlowEntry{testFlow(15, 7, 100)}, 70)
+	c.Assert(len(records), Equals, 1)
+	c.Assert(records[0].packets, Equals, uint64(5))
+	c.Assert(records[0].bytes, Equals, uint64(500))
+	c.Assert(records[0].revPackets, Equals, uint64(2))
+	c.Assert(records[0].start, Equals, start)
+
+	// Counter reset, the entry has been recreated
+	now := start.Add(3 * time.Minute)
+	records = e.collect(now, []ctmap.FlowEntry{testFlow(3, 1, 100)}, 80)
+	c.Assert(len(records), Equals, 1)
+	c.Assert(records[0].packets, Equals, uint64(3))
+	c.Assert(records[0].start, Equals, now)
+}
+
+func (s *FlowExportSuite) TestCollectExpire(c *C) {
+	e := newTestExporter()
+	start := time.Now()
+
+	records := e.collect(start, []ctmap.FlowEntry{testFlow(10, 5, 100)}, 50)
+	c.Assert(len(records), Equals, 1)
+
+	// Entry expired but not yet garbage collected
+	records = e.collect(start.Add(time.Minute), []ctmap.FlowEntry{testFlow(12, 5, 100)}, 110)
+	c.Assert(len(records), Equals, 1)
+	c.Assert(records[0].packets, Equals, uint64(2))
+	c.Assert(records[0].endReason, Equals, uint8(endReasonIdleTimeout))
+
+	records = e.collect(start.Add(2*time.Minute), []ctmap.FlowEntry{testFlow(12, 5, 100)}, 120)
+	c.Assert(len(records), Equals, 0)
+
+	// Garbage collected after the final record, nothing to export
+	records = e.collect(start.Add(3*time.Minute), nil, 130)
+	c.Assert(len(records), Equals, 0)
+	c.Assert(len(e.flows), Equals, 0)
+
+	// Garbage collected while still active
+	e.collect(start, []ctmap.FlowEntry{testFlow(10, 5, 200)}, 150)
+	records = e.collect(start.Add(time.Minute), nil, 160)
+	c.Assert(len(records), Equals, 1)
+	c.Assert(records[0].packets, Equals, uint64(0))
+	c.Assert(records[0].endReason, Equals, uint8(endReasonIdleTimeout))
+	c.Assert(len(e.flows), Equals, 0)
+}
+
+func (s *FlowExportSuite) TestExport(c *C) {
+	collector, err := net.ListenPacket("udp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	defer collector.Close()
+
+	source := func() ([]ctmap.FlowEntry, uint32, error) {
+		return []ctmap.FlowEntry{testFlow(10, 5, 100)}, 50, nil
+	}
+	e, err := NewExporter(Config{Collector: collector.LocalAddr().String(), Protocol: ProtocolIPFIX}, source, nil)
+	c.Assert(err, IsNil)
+	defer e.Close()
+	c.Assert(e.Interval(), Equals, DefaultInterval)
+
+	c.Assert(e.Export(), IsNil)
+
+	buf := make([]byte, maxPacketSize)
+	collector.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := collector.ReadFrom(buf)
+	c.Assert(err, IsNil)
+	c.Assert(n > ipfixHeaderLen, Equals, true)
+	c.Assert(e.enc.sequence, Equals, uint32(1))
+}
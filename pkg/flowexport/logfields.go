@@ -0,0 +1,34 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowexport
+
+import (
+	"github.com/cilium/cilium/pkg/logging"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+)
+
+// logging field definitions
+const (
+	// fieldCollector is the address of the collector
+	fieldCollector = "collector"
+
+	// fieldProtocol is the export protocol
+	fieldProtocol = "protocol"
+)
+
+var (
+	// log is the flowexport package logger object.
+	log = logging.DefaultLogger.WithField(logfields.LogSubsys, "flowexport")
+)
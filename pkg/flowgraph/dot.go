@@ -0,0 +1,114 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowgraph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/cilium/cilium/api/v1/models"
+)
+
+// edgeLabel returns the label of an edge consisting of the ports, L7
+// methods and counters
+func edgeLabel(e *models.FlowGraphEdge) string {
+	lines := []string{}
+	if len(e.Ports) > 0 {
+		lines = append(lines, strings.Join(e.Ports, ","))
+	}
+	if len(e.L7Methods) > 0 {
+		lines = append(lines, strings.Join(e.L7Methods, ","))
+	}
+
+	counters := []string{}
+	if e.Connections > 0 {
+		counters = append(counters, fmt.Sprintf("conn=%d", e.Connections))
+	}
+	if e.Forwarded > 0 {
+		counters = append(counters, fmt.Sprintf("fwd=%d", e.Forwarded))
+	}
+	if e.Dropped > 0 {
+		counters = append(counters, fmt.Sprintf("drop=%d", e.Dropped))
+	}
+	if e.L7Requests > 0 {
+		counters = append(counters, fmt.Sprintf("l7=%d", e.L7Requests))
+	}
+	if len(counters) > 0 {
+		lines = append(lines, strings.Join(counters, " "))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// nodeLabel returns the label of an identity node
+func nodeLabel(n *models.FlowGraphNode) string {
+	lines := []string{fmt.Sprintf("identity %d", n.Identity)}
+	lines = append(lines, n.Labels...)
+	for _, svc := range n.Services {
+		lines = append(lines, "service "+svc)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// WriteDOT writes the graph in the Graphviz DOT language to w. Identities
+// are rendered as boxes, services as ellipses. Edges with dropped packets are
+// colored red.
+func WriteDOT(w io.Writer, graph *models.FlowGraph) error {
+	b := bufio.NewWriter(w)
+
+	fmt.Fprintln(b, "digraph flows {")
+	fmt.Fprintln(b, "\tnode [shape=box];")
+
+	for _, n := range graph.Nodes {
+		fmt.Fprintf(b, "\t%s [label=%s];\n",
+			strconv.Quote(fmt.Sprintf("identity:%d", n.Identity)), strconv.Quote(nodeLabel(n)))
+	}
+	for _, e := range graph.Edges {
+		fmt.Fprintf(b, "\t%s -> %s [label=%s%s];\n",
+			strconv.Quote(fmt.Sprintf("identity:%d", e.SourceIdentity)),
+			strconv.Quote(fmt.Sprintf("identity:%d", e.DestinationIdentity)),
+			strconv.Quote(edgeLabel(e)), edgeColor(e))
+	}
+
+	services := map[string]struct{}{}
+	for _, e := range graph.ServiceEdges {
+		services[e.SourceService] = struct{}{}
+		services[e.DestinationService] = struct{}{}
+	}
+	for _, svc := range sortedKeys(services) {
+		fmt.Fprintf(b, "\t%s [label=%s, shape=ellipse];\n",
+			strconv.Quote("service:"+svc), strconv.Quote(svc))
+	}
+	for _, e := range graph.ServiceEdges {
+		fmt.Fprintf(b, "\t%s -> %s [label=%s%s];\n",
+			strconv.Quote("service:"+e.SourceService),
+			strconv.Quote("service:"+e.DestinationService),
+			strconv.Quote(edgeLabel(e)), edgeColor(e))
+	}
+
+	fmt.Fprintln(b, "}")
+
+	return b.Flush()
+}
+
+func edgeColor(e *models.FlowGraphEdge) string {
+	if e.Dropped > 0 {
+		return ", color=red"
+	}
+	return ""
+}
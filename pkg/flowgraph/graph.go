@@ -0,0 +1,455 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowgraph
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/identity"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/maps/ctmap"
+	"github.com/cilium/cilium/pkg/monitor"
+	"github.com/cilium/cilium/pkg/proxy/accesslog"
+	"github.com/cilium/cilium/pkg/u8proto"
+
+	"github.com/go-openapi/strfmt"
+)
+
+const (
+	// DefaultRetention is the default duration after which edges which
+	// have not been observed anymore are removed from the graph
+	DefaultRetention = time.Hour
+)
+
+// IdentityResolver returns the security identity of the given IP
+type IdentityResolver func(ip net.IP) (identity.NumericIdentity, bool)
+
+// ServiceResolver returns the name of the service the given IP is a frontend
+// or backend of, or an empty string if the IP does not belong to a service
+type ServiceResolver func(ip net.IP) string
+
+// LabelsResolver returns the labels of the given security identity
+type LabelsResolver func(id identity.NumericIdentity) []string
+
+type identityPair struct {
+	src, dst identity.NumericIdentity
+}
+
+type servicePair struct {
+	src, dst string
+}
+
+// edge is the aggregated state of all flows between a source and destination
+type edge struct {
+	ports       map[string]struct{}
+	methods     map[string]struct{}
+	connections int64
+	forwarded   int64
+	dropped     int64
+	l7Requests  int64
+	firstSeen   time.Time
+	lastSeen    time.Time
+}
+
+func newEdge(now time.Time) *edge {
+	return &edge{
+		ports:     map[string]struct{}{},
+		methods:   map[string]struct{}{},
+		firstSeen: now,
+	}
+}
+
+// flow is a single observation of a flow from a source to a destination
+type flow struct {
+	srcIdentity identity.NumericIdentity
+	dstIdentity identity.NumericIdentity
+	srcIP       net.IP
+	dstIP       net.IP
+	dstPort     uint16
+	proto       u8proto.U8proto
+
+	// dstService is the service of the destination if it is known from
+	// the observation point, e.g. the proxy
+	dstService string
+
+	// method is the L7 request method
+	method string
+
+	connections, forwarded, dropped, l7Requests int64
+}
+
+// port returns the destination port in the form port/protocol
+func (f *flow) port() string {
+	switch f.proto {
+	case u8proto.TCP, u8proto.UDP:
+		return fmt.Sprintf("%d/%s", f.dstPort, f.proto)
+	case 0:
+		return ""
+	default:
+		return f.proto.String()
+	}
+}
+
+// Graph aggregates trace and drop notifications, CT entries and L7 access log
+// records into a graph of identities communicating with each other. Flows
+// from and to IPs belonging to services are additionally aggregated into a
+// graph of services.
+type Graph struct {
+	mutex lock.RWMutex
+
+	resolveIdentity IdentityResolver
+	resolveService  ServiceResolver
+
+	edges        map[identityPair]*edge
+	serviceEdges map[servicePair]*edge
+
+	// services maps an identity to the services it is a backend of
+	services map[identity.NumericIdentity]map[string]struct{}
+
+	// connections is the set of CT entries seen in the last call to
+	// UpdateConnections, used to count each connection only once
+	connections map[string]struct{}
+}
+
+// NewGraph returns a new empty graph. The resolvers are used to derive the
+// identities and services of flows from their IPs, either may be nil.
+func NewGraph(resolveIdentity IdentityResolver, resolveService ServiceResolver) *Graph {
+	return &Graph{
+		resolveIdentity: resolveIdentity,
+		resolveService:  resolveService,
+		edges:           map[identityPair]*edge{},
+		serviceEdges:    map[servicePair]*edge{},
+		services:        map[identity.NumericIdentity]map[string]struct{}{},
+		connections:     map[string]struct{}{},
+	}
+}
+
+func (g *Graph) identityOf(id identity.NumericIdentity, ip net.IP) identity.NumericIdentity {
+	if id != 0 || ip == nil || g.resolveIdentity == nil {
+		return id
+	}
+	if resolved, ok := g.resolveIdentity(ip); ok {
+		return resolved
+	}
+	return 0
+}
+
+func (g *Graph) serviceOf(ip net.IP) string {
+	if ip == nil || g.resolveService == nil {
+		return ""
+	}
+	return g.resolveService(ip)
+}
+
+func (e *edge) update(f *flow, now time.Time) {
+	if p := f.port(); p != "" {
+		e.ports[p] = struct{}{}
+	}
+	if f.method != "" {
+		e.methods[f.method] = struct{}{}
+	}
+	e.connections += f.connections
+	e.forwarded += f.forwarded
+	e.dropped += f.dropped
+	e.l7Requests += f.l7Requests
+	e.lastSeen = now
+}
+
+// add records a flow in the graph. Must be called with g.mutex held.
+func (g *Graph) add(f *flow, now time.Time) {
+	src := g.identityOf(f.srcIdentity, f.srcIP)
+	dst := g.identityOf(f.dstIdentity, f.dstIP)
+	if src == 0 || dst == 0 {
+		return
+	}
+
+	key := identityPair{src: src, dst: dst}
+	e, ok := g.edges[key]
+	if !ok {
+		e = newEdge(now)
+		g.edges[key] = e
+	}
+	e.update(f, now)
+
+	dstService := f.dstService
+	if dstService == "" {
+		dstService = g.serviceOf(f.dstIP)
+	}
+	if dstService == "" {
+		return
+	}
+
+	if _, ok := g.services[dst]; !ok {
+		g.services[dst] = map[string]struct{}{}
+	}
+	g.services[dst][dstService] = struct{}{}
+
+	srcService := g.serviceOf(f.srcIP)
+	if srcService == "" {
+		return
+	}
+
+	svcKey := servicePair{src: srcService, dst: dstService}
+	se, ok := g.serviceEdges[svcKey]
+	if !ok {
+		se = newEdge(now)
+		g.serviceEdges[svcKey] = se
+	}
+	se.update(f, now)
+}
+
+// AddTrace records a trace notification. Traces of reply packets are
+// ignored as the connection is already recorded in its original direction.
+func (g *Graph) AddTrace(tn *monitor.TraceNotify, data []byte, now time.Time) {
+	if tn.Reason == monitor.TraceReasonCtReply || len(data) < monitor.TraceNotifyLen {
+		return
+	}
+
+	f := flow{
+		srcIdentity: identity.NumericIdentity(tn.SrcLabel),
+		dstIdentity: identity.NumericIdentity(tn.DstLabel),
+		forwarded:   1,
+	}
+	if info := monitor.GetConnectionInfo(data[monitor.TraceNotifyLen:]); info != nil {
+		f.srcIP, f.dstIP = info.SrcIP, info.DstIP
+		f.dstPort, f.proto = info.DstPort, info.Proto
+	}
+
+	g.mutex.Lock()
+	g.add(&f, now)
+	g.mutex.Unlock()
+}
+
+// AddDrop records a drop notification
+func (g *Graph) AddDrop(dn *monitor.DropNotify, data []byte, now time.Time) {
+	if len(data) < monitor.DropNotifyLen {
+		return
+	}
+
+	f := flow{
+		srcIdentity: identity.NumericIdentity(dn.SrcLabel),
+		dstIdentity: identity.NumericIdentity(dn.DstLabel),
+		dropped:     1,
+	}
+	if info := monitor.GetConnectionInfo(data[monitor.DropNotifyLen:]); info != nil {
+		f.srcIP, f.dstIP = info.SrcIP, info.DstIP
+		f.dstPort, f.proto = info.DstPort, info.Proto
+	}
+
+	g.mutex.Lock()
+	g.add(&f, now)
+	g.mutex.Unlock()
+}
+
+// AddLogRecord records an L7 access log record. Only requests are recorded,
+// responses belong to the same edge.
+func (g *Graph) AddLogRecord(r *accesslog.LogRecord, now time.Time) {
+	if r.Type != accesslog.TypeRequest {
+		return
+	}
+
+	f := flow{
+		srcIdentity: identity.NumericIdentity(r.SourceEndpoint.Identity),
+		dstIdentity: identity.NumericIdentity(r.DestinationEndpoint.Identity),
+		srcIP:       endpointIP(&r.SourceEndpoint),
+		dstIP:       endpointIP(&r.DestinationEndpoint),
+		dstPort:     r.DestinationEndpoint.Port,
+		proto:       u8proto.U8proto(r.TransportProtocol),
+		l7Requests:  1,
+	}
+	if r.ServiceInfo != nil {
+		f.dstService = r.ServiceInfo.Name
+	}
+	switch {
+	case r.HTTP != nil:
+		f.method = r.HTTP.Method
+	case r.Kafka != nil:
+		f.method = r.Kafka.APIKey
+	}
+	if r.Verdict != accesslog.VerdictForwarded {
+		f.dropped = 1
+	}
+
+	g.mutex.Lock()
+	g.add(&f, now)
+	g.mutex.Unlock()
+}
+
+func endpointIP(ep *accesslog.EndpointInfo) net.IP {
+	if ep.IPv6 != "" {
+		return net.ParseIP(ep.IPv6)
+	}
+	return net.ParseIP(ep.IPv4)
+}
+
+// UpdateConnections records all CT entries. Each entry is only counted as a
+// connection the first time it is seen, entries which are not present
+// anymore are forgotten.
+func (g *Graph) UpdateConnections(entries []ctmap.FlowEntry, now time.Time) {
+	seen := make(map[string]struct{}, len(entries))
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	for i := range entries {
+		e := &entries[i]
+		if e.Related {
+			continue
+		}
+
+		key := e.String()
+		seen[key] = struct{}{}
+		if _, ok := g.connections[key]; ok {
+			continue
+		}
+
+		f := flow{
+			srcIdentity: e.SrcSecID,
+			srcIP:       e.SrcIP,
+			dstIP:       e.DstIP,
+			dstPort:     e.DstPort,
+			proto:       e.Proto,
+			connections: 1,
+		}
+		g.add(&f, now)
+	}
+
+	g.connections = seen
+}
+
+// Expire removes all edges which have not been observed since before
+func (g *Graph) Expire(before time.Time) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	for key, e := range g.edges {
+		if e.lastSeen.Before(before) {
+			delete(g.edges, key)
+		}
+	}
+	for key, e := range g.serviceEdges {
+		if e.lastSeen.Before(before) {
+			delete(g.serviceEdges, key)
+		}
+	}
+
+	// Forget the services of identities without edges
+	for id := range g.services {
+		found := false
+		for key := range g.edges {
+			if key.dst == id {
+				found = true
+				break
+			}
+		}
+		if !found {
+			delete(g.services, id)
+		}
+	}
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (e *edge) getModel() *models.FlowGraphEdge {
+	return &models.FlowGraphEdge{
+		Ports:       sortedKeys(e.ports),
+		L7Methods:   sortedKeys(e.methods),
+		Connections: e.connections,
+		Forwarded:   e.forwarded,
+		Dropped:     e.dropped,
+		L7Requests:  e.l7Requests,
+		FirstSeen:   strfmt.DateTime(e.firstSeen),
+		LastSeen:    strfmt.DateTime(e.lastSeen),
+	}
+}
+
+// GetModel returns the API model of all edges observed since the given time.
+// Labels of the identities are resolved with the given resolver which may be
+// nil.
+func (g *Graph) GetModel(since time.Time, resolveLabels LabelsResolver) *models.FlowGraph {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	graph := &models.FlowGraph{
+		Nodes:        []*models.FlowGraphNode{},
+		Edges:        []*models.FlowGraphEdge{},
+		ServiceEdges: []*models.FlowGraphEdge{},
+	}
+
+	nodes := map[identity.NumericIdentity]struct{}{}
+	for key, e := range g.edges {
+		if e.lastSeen.Before(since) {
+			continue
+		}
+		m := e.getModel()
+		m.SourceIdentity = int64(key.src)
+		m.DestinationIdentity = int64(key.dst)
+		graph.Edges = append(graph.Edges, m)
+		nodes[key.src] = struct{}{}
+		nodes[key.dst] = struct{}{}
+	}
+
+	for key, e := range g.serviceEdges {
+		if e.lastSeen.Before(since) {
+			continue
+		}
+		m := e.getModel()
+		m.SourceService = key.src
+		m.DestinationService = key.dst
+		graph.ServiceEdges = append(graph.ServiceEdges, m)
+	}
+
+	for id := range nodes {
+		node := &models.FlowGraphNode{
+			Identity: int64(id),
+			Services: sortedKeys(g.services[id]),
+		}
+		if resolveLabels != nil {
+			node.Labels = resolveLabels(id)
+		}
+		graph.Nodes = append(graph.Nodes, node)
+	}
+
+	sort.Slice(graph.Nodes, func(i, j int) bool {
+		return graph.Nodes[i].Identity < graph.Nodes[j].Identity
+	})
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		a, b := graph.Edges[i], graph.Edges[j]
+		if a.SourceIdentity != b.SourceIdentity {
+			return a.SourceIdentity < b.SourceIdentity
+		}
+		return a.DestinationIdentity < b.DestinationIdentity
+	})
+	sort.Slice(graph.ServiceEdges, func(i, j int) bool {
+		a, b := graph.ServiceEdges[i], graph.ServiceEdges[j]
+		if a.SourceService != b.SourceService {
+			return a.SourceService < b.SourceService
+		}
+		return a.DestinationService < b.DestinationService
+	})
+
+	return graph
+}
@@ -0,0 +1,221 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowgraph
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cilium/cilium/pkg/identity"
+	"github.com/cilium/cilium/pkg/maps/ctmap"
+	"github.com/cilium/cilium/pkg/monitor"
+	"github.com/cilium/cilium/pkg/proxy/accesslog"
+	"github.com/cilium/cilium/pkg/u8proto"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type FlowGraphSuite struct{}
+
+var _ = Suite(&FlowGraphSuite{})
+
+var (
+	clientIP = net.ParseIP("10.0.0.1")
+	serverIP = net.ParseIP("10.0.0.2")
+)
+
+func newTestGraph() *Graph {
+	resolveIdentity := func(ip net.IP) (identity.NumericIdentity, bool) {
+		switch ip.String() {
+		case clientIP.String():
+			return 1000, true
+		case serverIP.String():
+			return 2000, true
+		}
+		return 0, false
+	}
+	resolveService := func(ip net.IP) string {
+		switch ip.String() {
+		case clientIP.String():
+			return "default/frontend"
+		case serverIP.String():
+			return "default/backend"
+		}
+		return ""
+	}
+	return NewGraph(resolveIdentity, resolveService)
+}
+
+func testPacket(c *C, src, dst net.IP, sport, dport uint16) []byte {
+	eth := &layers.Ethernet{
+		SrcMAC:       net.HardwareAddr{1, 2, 3, 4, 5, 6},
+		DstMAC:       net.HardwareAddr{1, 2, 3, 4, 5, 7},
+		EthernetType: layers.EthernetTypeIPv4,
+	}
+	ip := &layers.IPv4{
+		Version:  4,
+		TTL:      64,
+		Protocol: layers.IPProtocolTCP,
+		SrcIP:    src,
+		DstIP:    dst,
+	}
+	tcp := &layers.TCP{
+		SrcPort: layers.TCPPort(sport),
+		DstPort: layers.TCPPort(dport),
+		SYN:     true,
+	}
+	buf := gopacket.NewSerializeBuffer()
+	err := gopacket.SerializeLayers(buf, gopacket.SerializeOptions{FixLengths: true}, eth, ip, tcp)
+	c.Assert(err, IsNil)
+
+	return append(make([]byte, monitor.TraceNotifyLen), buf.Bytes()...)
+}
+
+func (s *FlowGraphSuite) TestUpdateConnections(c *C) {
+	g := newTestGraph()
+	now := time.Now()
+	entries := []ctmap.FlowEntry{
+		{SrcIP: clientIP, DstIP: serverIP, SrcPort: 40000, DstPort: 80, Proto: u8proto.TCP},
+		{SrcIP: clientIP, DstIP: serverIP, SrcPort: 40001, DstPort: 80, Proto: u8proto.TCP},
+		{SrcIP: clientIP, DstIP: serverIP, Proto: u8proto.ICMP, Related: true},
+	}
+
+	g.UpdateConnections(entries, now)
+	// Entries seen before are not counted again
+	g.UpdateConnections(entries, now)
+
+	m := g.GetModel(time.Time{}, nil)
+	c.Assert(len(m.Edges), Equals, 1)
+	c.Assert(m.Edges[0].SourceIdentity, Equals, int64(1000))
+	c.Assert(m.Edges[0].DestinationIdentity, Equals, int64(2000))
+	c.Assert(m.Edges[0].Connections, Equals, int64(2))
+	c.Assert(m.Edges[0].Ports, DeepEquals, []string{"80/TCP"})
+
+	c.Assert(len(m.Nodes), Equals, 2)
+	c.Assert(m.Nodes[1].Services, DeepEquals, []string{"default/backend"})
+
+	c.Assert(len(m.ServiceEdges), Equals, 1)
+	c.Assert(m.ServiceEdges[0].SourceService, Equals, "default/frontend")
+	c.Assert(m.ServiceEdges[0].DestinationService, Equals, "default/backend")
+
+	// A connection which disappeared and is recreated is counted again
+	g.UpdateConnections(nil, now)
+	g.UpdateConnections(entries[:1], now)
+	m = g.GetModel(time.Time{}, nil)
+	c.Assert(m.Edges[0].Connections, Equals, int64(3))
+}
+
+func (s *FlowGraphSuite) TestAddLogRecord(c *C) {
+	g := newTestGraph()
+	now := time.Now()
+	r := &accesslog.LogRecord{
+		Type:                accesslog.TypeRequest,
+		SourceEndpoint:      accesslog.EndpointInfo{Identity: 1000, IPv4: clientIP.String()},
+		DestinationEndpoint: accesslog.EndpointInfo{Identity: 2000, IPv4: serverIP.String(), Port: 8080},
+		TransportProtocol:   accesslog.TransportProtocol(u8proto.TCP),
+		Verdict:             accesslog.VerdictForwarded,
+		ServiceInfo:         &accesslog.ServiceInfo{Name: "default/api"},
+		HTTP:                &accesslog.LogRecordHTTP{Method: "GET"},
+	}
+
+	g.AddLogRecord(r, now)
+	r.HTTP = &accesslog.LogRecordHTTP{Method: "POST"}
+	r.Verdict = accesslog.VerdictDenied
+	g.AddLogRecord(r, now)
+	r.Type = accesslog.TypeResponse
+	g.AddLogRecord(r, now)
+
+	m := g.GetModel(time.Time{}, nil)
+	c.Assert(len(m.Edges), Equals, 1)
+	c.Assert(m.Edges[0].L7Requests, Equals, int64(2))
+	c.Assert(m.Edges[0].Dropped, Equals, int64(1))
+	c.Assert(m.Edges[0].L7Methods, DeepEquals, []string{"GET", "POST"})
+	c.Assert(m.Edges[0].Ports, DeepEquals, []string{"8080/TCP"})
+	c.Assert(len(m.ServiceEdges), Equals, 1)
+	c.Assert(m.ServiceEdges[0].DestinationService, Equals, "default/api")
+}
+
+func (s *FlowGraphSuite) TestAddTraceDrop(c *C) {
+	g := newTestGraph()
+	now := time.Now()
+
+	tn := &monitor.TraceNotify{SrcLabel: 1000, Reason: monitor.TraceReasonPolicy}
+	g.AddTrace(tn, testPacket(c, clientIP, serverIP, 40000, 443), now)
+
+	// Replies are ignored
+	reply := &monitor.TraceNotify{SrcLabel: 2000, DstLabel: 1000, Reason: monitor.TraceReasonCtReply}
+	g.AddTrace(reply, testPacket(c, serverIP, clientIP, 443, 40000), now)
+
+	dn := &monitor.DropNotify{SrcLabel: 1000, DstLabel: 2000}
+	g.AddDrop(dn, testPacket(c, clientIP, serverIP, 40000, 22), now)
+
+	m := g.GetModel(time.Time{}, nil)
+	c.Assert(len(m.Edges), Equals, 1)
+	c.Assert(m.Edges[0].SourceIdentity, Equals, int64(1000))
+	c.Assert(m.Edges[0].DestinationIdentity, Equals, int64(2000))
+	c.Assert(m.Edges[0].Forwarded, Equals, int64(1))
+	c.Assert(m.Edges[0].Dropped, Equals, int64(1))
+	c.Assert(m.Edges[0].Ports, DeepEquals, []string{"22/TCP", "443/TCP"})
+}
+
+func (s *FlowGraphSuite) TestExpire(c *C) {
+	g := newTestGraph()
+	now := time.Now()
+	old := now.Add(-2 * time.Hour)
+
+	g.UpdateConnections([]ctmap.FlowEntry{
+		{SrcIP: clientIP, DstIP: serverIP, SrcPort: 40000, DstPort: 80, Proto: u8proto.TCP},
+	}, old)
+	g.UpdateConnections([]ctmap.FlowEntry{
+		{SrcIP: serverIP, DstIP: clientIP, SrcPort: 40000, DstPort: 53, Proto: u8proto.UDP},
+	}, now)
+
+	m := g.GetModel(now.Add(-time.Minute), nil)
+	c.Assert(len(m.Edges), Equals, 1)
+	c.Assert(m.Edges[0].SourceIdentity, Equals, int64(2000))
+
+	g.Expire(now.Add(-DefaultRetention))
+	m = g.GetModel(time.Time{}, nil)
+	c.Assert(len(m.Edges), Equals, 1)
+	c.Assert(m.Nodes[1].Services, HasLen, 0)
+	c.Assert(m.Nodes[0].Services, DeepEquals, []string{"default/frontend"})
+}
+
+func (s *FlowGraphSuite) TestWriteDOT(c *C) {
+	g := newTestGraph()
+	g.UpdateConnections([]ctmap.FlowEntry{
+		{SrcIP: clientIP, DstIP: serverIP, SrcPort: 40000, DstPort: 80, Proto: u8proto.TCP},
+	}, time.Now())
+
+	labels := func(id identity.NumericIdentity) []string {
+		return []string{"k8s:id=" + id.String()}
+	}
+
+	var buf bytes.Buffer
+	c.Assert(WriteDOT(&buf, g.GetModel(time.Time{}, labels)), IsNil)
+	out := buf.String()
+
+	c.Assert(strings.HasPrefix(out, "digraph flows {\n"), Equals, true)
+	c.Assert(strings.Contains(out, `"identity:1000" [label="identity 1000\nk8s:id=1000"];`), Equals, true)
+	c.Assert(strings.Contains(out, `"identity:1000" -> "identity:2000" [label="80/TCP\nconn=1"];`), Equals, true)
+	c.Assert(strings.Contains(out, `"service:default/frontend" -> "service:default/backend"`), Equals, true)
+}
@@ -0,0 +1,330 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowstore
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/identity"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/monitor"
+	"github.com/cilium/cilium/pkg/proxy/accesslog"
+
+	"github.com/go-openapi/strfmt"
+)
+
+const (
+	// DefaultSize is the default number of flows stored per endpoint
+	DefaultSize = 256
+)
+
+// LabelsResolver returns the labels of the given security identity
+type LabelsResolver func(id identity.NumericIdentity) []string
+
+// Filter selects the flows returned by List. Zero values match all flows.
+type Filter struct {
+	// Endpoint restricts the flows to the endpoint with the given ID
+	Endpoint *uint16
+
+	// Verdict restricts the flows to the given verdict
+	Verdict string
+
+	// Since restricts the flows to the ones observed at or after the
+	// given time
+	Since time.Time
+}
+
+// record is a flow as stored in the ring of an endpoint
+type record struct {
+	timestamp time.Time
+	flow      *models.Flow
+}
+
+// ring is a bounded buffer of the most recent flows of an endpoint
+type ring struct {
+	records []record
+	next    int
+}
+
+func (r *ring) add(rec record, size int) {
+	if len(r.records) < size {
+		r.records = append(r.records, rec)
+		return
+	}
+	r.records[r.next] = rec
+	r.next = (r.next + 1) % size
+}
+
+// Store keeps the most recent flows of each endpoint
+type Store struct {
+	mutex lock.RWMutex
+
+	// size is the maximum number of flows stored per endpoint
+	size int
+
+	// nodeAddress is attached to flows observed by the datapath
+	nodeAddress *models.FlowNodeAddress
+
+	rings map[uint16]*ring
+}
+
+// NewStore returns a store which keeps up to size flows per endpoint. Flows
+// observed by the datapath are annotated with nodeAddress.
+func NewStore(size int, nodeAddress *models.FlowNodeAddress) *Store {
+	if size <= 0 {
+		size = DefaultSize
+	}
+	return &Store{
+		size:        size,
+		nodeAddress: nodeAddress,
+		rings:       map[uint16]*ring{},
+	}
+}
+
+func (s *Store) add(endpointID uint16, timestamp time.Time, f *models.Flow) {
+	if endpointID == 0 {
+		return
+	}
+
+	f.EndpointID = int64(endpointID)
+	f.Timestamp = strfmt.DateTime(timestamp)
+
+	s.mutex.Lock()
+	r, ok := s.rings[endpointID]
+	if !ok {
+		r = &ring{}
+		s.rings[endpointID] = r
+	}
+	r.add(record{timestamp: timestamp, flow: f}, s.size)
+	s.mutex.Unlock()
+}
+
+// datapathFlow returns a flow for a packet sampled by the datapath
+func (s *Store) datapathFlow(srcIdentity, dstIdentity uint32, data []byte) *models.Flow {
+	f := &models.Flow{
+		Type:        models.FlowTypeSample,
+		NodeAddress: s.nodeAddress,
+		Source:      &models.FlowEndpoint{Identity: int64(srcIdentity)},
+		Destination: &models.FlowEndpoint{Identity: int64(dstIdentity)},
+	}
+
+	if info := monitor.GetConnectionInfo(data); info != nil {
+		if info.SrcIP.To4() != nil {
+			f.IPVersion = models.FlowIPVersionIPV4
+			f.Source.IPV4, f.Destination.IPV4 = info.SrcIP.String(), info.DstIP.String()
+		} else {
+			f.IPVersion = models.FlowIPVersionIPV6
+			f.Source.IPV6, f.Destination.IPV6 = info.SrcIP.String(), info.DstIP.String()
+		}
+		f.Source.Port = int64(info.SrcPort)
+		f.Destination.Port = int64(info.DstPort)
+		f.TransportProtocol = int64(info.Proto)
+	}
+
+	return f
+}
+
+// AddTrace records a trace notification. The flow is stored for the endpoint
+// delivered to or the endpoint which emitted the notification.
+func (s *Store) AddTrace(tn *monitor.TraceNotify, data []byte, now time.Time) {
+	if len(data) < monitor.TraceNotifyLen {
+		return
+	}
+
+	f := s.datapathFlow(tn.SrcLabel, tn.DstLabel, data[monitor.TraceNotifyLen:])
+	f.Verdict = models.FlowVerdictForwarded
+	f.TraceObservationPoint = tn.ObservationPointName()
+	f.ConnectionState = tn.ConnState()
+
+	endpointID := tn.Source
+	switch tn.ObsPoint {
+	case monitor.TraceToLxc:
+		endpointID = tn.DstID
+		f.ObservationPoint = models.FlowObservationPointIngress
+		f.Destination.ID = int64(tn.DstID)
+	case monitor.TraceFromLxc:
+		f.ObservationPoint = models.FlowObservationPointEgress
+		f.Source.ID = int64(tn.Source)
+	}
+
+	s.add(endpointID, now, f)
+}
+
+// AddDrop records a drop notification for the endpoint which emitted it
+func (s *Store) AddDrop(dn *monitor.DropNotify, data []byte, now time.Time) {
+	if len(data) < monitor.DropNotifyLen {
+		return
+	}
+
+	f := s.datapathFlow(dn.SrcLabel, dn.DstLabel, data[monitor.DropNotifyLen:])
+	f.Verdict = models.FlowVerdictDenied
+	f.DropReason = dn.DropReason()
+	f.Destination.ID = int64(dn.DstID)
+
+	s.add(dn.Source, now, f)
+}
+
+func endpointModel(e *accesslog.EndpointInfo) *models.FlowEndpoint {
+	return &models.FlowEndpoint{
+		ID:       int64(e.ID),
+		IPV4:     e.IPv4,
+		IPV6:     e.IPv6,
+		Port:     int64(e.Port),
+		Identity: int64(e.Identity),
+		Labels:   models.Labels(e.Labels),
+	}
+}
+
+// AddLogRecord records an L7 access log record. Records observed at ingress
+// are stored for the destination endpoint, records observed at egress for
+// the source endpoint.
+func (s *Store) AddLogRecord(r *accesslog.LogRecord, now time.Time) {
+	f := &models.Flow{
+		Type: string(r.Type),
+		NodeAddress: &models.FlowNodeAddress{
+			IPV4: r.NodeAddressInfo.IPv4,
+			IPV6: r.NodeAddressInfo.IPv6,
+		},
+		ObservationPoint:  string(r.ObservationPoint),
+		Source:            endpointModel(&r.SourceEndpoint),
+		Destination:       endpointModel(&r.DestinationEndpoint),
+		Verdict:           string(r.Verdict),
+		Info:              r.Info,
+		Metadata:          r.Metadata,
+		TransportProtocol: int64(r.TransportProtocol),
+		FlowEvent:         string(r.FlowEvent),
+	}
+
+	switch r.IPVersion {
+	case accesslog.VersionIPv4:
+		f.IPVersion = models.FlowIPVersionIPV4
+	case accesslog.VersionIPV6:
+		f.IPVersion = models.FlowIPVersionIPV6
+	}
+
+	if r.ServiceInfo != nil {
+		f.Service = &models.FlowService{
+			Name: r.ServiceInfo.Name,
+			IP:   r.ServiceInfo.IPPort.IP,
+			Port: int64(r.ServiceInfo.IPPort.Port),
+		}
+	}
+
+	if r.HTTP != nil {
+		f.HTTP = &models.FlowHTTP{
+			Code:     int64(r.HTTP.Code),
+			Method:   r.HTTP.Method,
+			Protocol: r.HTTP.Protocol,
+		}
+		if r.HTTP.URL != nil {
+			f.HTTP.URL = r.HTTP.URL.String()
+		}
+		if len(r.HTTP.Headers) > 0 {
+			f.HTTP.Headers = make(map[string]string, len(r.HTTP.Headers))
+			for name, values := range r.HTTP.Headers {
+				f.HTTP.Headers[name] = strings.Join(values, ", ")
+			}
+		}
+	}
+
+	if r.Kafka != nil {
+		f.Kafka = &models.FlowKafka{
+			ErrorCode:     int64(r.Kafka.ErrorCode),
+			APIVersion:    int64(r.Kafka.APIVersion),
+			APIKey:        r.Kafka.APIKey,
+			CorrelationID: int64(r.Kafka.CorrelationID),
+			Topic:         r.Kafka.Topic.Topic,
+		}
+	}
+
+	timestamp := now
+	if t, err := time.Parse(time.RFC3339Nano, r.Timestamp); err == nil {
+		timestamp = t
+	}
+
+	endpointID := r.SourceEndpoint.ID
+	if r.ObservationPoint == accesslog.Ingress {
+		endpointID = r.DestinationEndpoint.ID
+	}
+
+	s.add(uint16(endpointID), timestamp, f)
+}
+
+// RemoveEndpoint removes all flows of the endpoint with the given ID
+func (s *Store) RemoveEndpoint(endpointID uint16) {
+	s.mutex.Lock()
+	delete(s.rings, endpointID)
+	s.mutex.Unlock()
+}
+
+// fillLabels returns a copy of e with the labels of its identity filled in
+// if e does not carry any labels
+func fillLabels(e *models.FlowEndpoint, resolveLabels LabelsResolver) *models.FlowEndpoint {
+	if e == nil || len(e.Labels) > 0 || e.Identity == 0 {
+		return e
+	}
+	labels := resolveLabels(identity.NumericIdentity(e.Identity))
+	if len(labels) == 0 {
+		return e
+	}
+	filled := *e
+	filled.Labels = labels
+	return &filled
+}
+
+// List returns all flows matching filter ordered by the time they were
+// observed. If resolveLabels is not nil, it is used to fill in the labels of
+// sources and destinations observed by the datapath.
+func (s *Store) List(filter Filter, resolveLabels LabelsResolver) []*models.Flow {
+	records := []record{}
+
+	s.mutex.RLock()
+	for id, r := range s.rings {
+		if filter.Endpoint != nil && *filter.Endpoint != id {
+			continue
+		}
+		for _, rec := range r.records {
+			if filter.Verdict != "" && rec.flow.Verdict != filter.Verdict {
+				continue
+			}
+			if rec.timestamp.Before(filter.Since) {
+				continue
+			}
+			records = append(records, rec)
+		}
+	}
+	s.mutex.RUnlock()
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].timestamp.Before(records[j].timestamp)
+	})
+
+	flows := make([]*models.Flow, 0, len(records))
+	for _, rec := range records {
+		f := rec.flow
+		if resolveLabels != nil {
+			c := *f
+			c.Source = fillLabels(f.Source, resolveLabels)
+			c.Destination = fillLabels(f.Destination, resolveLabels)
+			f = &c
+		}
+		flows = append(flows, f)
+	}
+
+	return flows
+}
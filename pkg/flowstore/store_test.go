@@ -0,0 +1,189 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowstore
+
+import (
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/identity"
+	"github.com/cilium/cilium/pkg/monitor"
+	"github.com/cilium/cilium/pkg/proxy/accesslog"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type FlowStoreSuite struct{}
+
+var _ = Suite(&FlowStoreSuite{})
+
+var (
+	clientIP = net.ParseIP("10.0.0.1")
+	serverIP = net.ParseIP("10.0.0.2")
+)
+
+func testPacket(c *C, hdrLen int, src, dst net.IP, sport, dport uint16) []byte {
+	eth := &layers.Ethernet{
+		SrcMAC:       net.HardwareAddr{1, 2, 3, 4, 5, 6},
+		DstMAC:       net.HardwareAddr{1, 2, 3, 4, 5, 7},
+		EthernetType: layers.EthernetTypeIPv4,
+	}
+	ip := &layers.IPv4{
+		Version:  4,
+		TTL:      64,
+		Protocol: layers.IPProtocolTCP,
+		SrcIP:    src,
+		DstIP:    dst,
+	}
+	tcp := &layers.TCP{
+		SrcPort: layers.TCPPort(sport),
+		DstPort: layers.TCPPort(dport),
+		SYN:     true,
+	}
+	buf := gopacket.NewSerializeBuffer()
+	err := gopacket.SerializeLayers(buf, gopacket.SerializeOptions{FixLengths: true}, eth, ip, tcp)
+	c.Assert(err, IsNil)
+
+	return append(make([]byte, hdrLen), buf.Bytes()...)
+}
+
+func (s *FlowStoreSuite) TestAddTraceDrop(c *C) {
+	store := NewStore(0, &models.FlowNodeAddress{IPV4: "192.168.0.1"})
+	now := time.Now()
+
+	tn := &monitor.TraceNotify{
+		ObsPoint: monitor.TraceToLxc,
+		Source:   1,
+		DstID:    10,
+		SrcLabel: 1000,
+		DstLabel: 2000,
+		Reason:   monitor.TraceReasonPolicy,
+	}
+	store.AddTrace(tn, testPacket(c, monitor.TraceNotifyLen, clientIP, serverIP, 40000, 80), now)
+
+	dn := &monitor.DropNotify{Source: 20, SubType: 133, SrcLabel: 2000, DstLabel: 1000}
+	store.AddDrop(dn, testPacket(c, monitor.DropNotifyLen, serverIP, clientIP, 40000, 22), now.Add(time.Second))
+
+	flows := store.List(Filter{}, nil)
+	c.Assert(flows, HasLen, 2)
+
+	f := flows[0]
+	c.Assert(f.EndpointID, Equals, int64(10))
+	c.Assert(f.Type, Equals, models.FlowTypeSample)
+	c.Assert(f.Verdict, Equals, models.FlowVerdictForwarded)
+	c.Assert(f.ObservationPoint, Equals, models.FlowObservationPointIngress)
+	c.Assert(f.TraceObservationPoint, Equals, "to-endpoint")
+	c.Assert(f.ConnectionState, Equals, "new")
+	c.Assert(f.IPVersion, Equals, models.FlowIPVersionIPV4)
+	c.Assert(f.NodeAddress.IPV4, Equals, "192.168.0.1")
+	c.Assert(*f.Source, DeepEquals, models.FlowEndpoint{IPV4: "10.0.0.1", Port: 40000, Identity: 1000})
+	c.Assert(*f.Destination, DeepEquals, models.FlowEndpoint{ID: 10, IPV4: "10.0.0.2", Port: 80, Identity: 2000})
+	c.Assert(f.TransportProtocol, Equals, int64(6))
+
+	f = flows[1]
+	c.Assert(f.EndpointID, Equals, int64(20))
+	c.Assert(f.Verdict, Equals, models.FlowVerdictDenied)
+	c.Assert(f.DropReason, Equals, "Policy denied (L3)")
+	c.Assert(f.Destination.Port, Equals, int64(22))
+}
+
+func (s *FlowStoreSuite) TestAddLogRecord(c *C) {
+	store := NewStore(0, nil)
+	now := time.Now()
+
+	u, err := url.Parse("http://example.com/public")
+	c.Assert(err, IsNil)
+
+	r := &accesslog.LogRecord{
+		Type:                accesslog.TypeRequest,
+		Timestamp:           now.UTC().Format(time.RFC3339Nano),
+		ObservationPoint:    accesslog.Ingress,
+		SourceEndpoint:      accesslog.EndpointInfo{ID: 1, Identity: 1000, IPv4: clientIP.String(), Labels: []string{"k8s:app=client"}},
+		DestinationEndpoint: accesslog.EndpointInfo{ID: 2, Identity: 2000, IPv4: serverIP.String(), Port: 80},
+		TransportProtocol:   6,
+		Verdict:             accesslog.VerdictDenied,
+		HTTP: &accesslog.LogRecordHTTP{
+			Method:   "GET",
+			URL:      u,
+			Protocol: "HTTP/1.1",
+			Headers:  map[string][]string{"Accept": {"text/html", "text/plain"}},
+		},
+	}
+	store.AddLogRecord(r, now)
+
+	flows := store.List(Filter{}, nil)
+	c.Assert(flows, HasLen, 1)
+
+	f := flows[0]
+	c.Assert(f.EndpointID, Equals, int64(2))
+	c.Assert(f.Type, Equals, models.FlowTypeRequest)
+	c.Assert(f.Verdict, Equals, models.FlowVerdictDenied)
+	c.Assert(f.ObservationPoint, Equals, models.FlowObservationPointIngress)
+	c.Assert(f.Source.Labels, DeepEquals, models.Labels{"k8s:app=client"})
+	c.Assert(f.HTTP.URL, Equals, "http://example.com/public")
+	c.Assert(f.HTTP.Headers, DeepEquals, map[string]string{"Accept": "text/html, text/plain"})
+	c.Assert(time.Time(f.Timestamp).Equal(now), Equals, true)
+}
+
+func (s *FlowStoreSuite) TestRing(c *C) {
+	store := NewStore(3, nil)
+	now := time.Now()
+
+	for i := 0; i < 5; i++ {
+		dn := &monitor.DropNotify{Source: 1, SrcLabel: uint32(i)}
+		store.AddDrop(dn, make([]byte, monitor.DropNotifyLen), now.Add(time.Duration(i)*time.Second))
+	}
+
+	flows := store.List(Filter{}, nil)
+	c.Assert(flows, HasLen, 3)
+	for i, f := range flows {
+		c.Assert(f.Source.Identity, Equals, int64(i+2))
+	}
+
+	store.RemoveEndpoint(1)
+	c.Assert(store.List(Filter{}, nil), HasLen, 0)
+}
+
+func (s *FlowStoreSuite) TestList(c *C) {
+	store := NewStore(0, nil)
+	now := time.Now()
+
+	store.AddDrop(&monitor.DropNotify{Source: 1, SrcLabel: 1000}, make([]byte, monitor.DropNotifyLen), now.Add(-time.Hour))
+	store.AddDrop(&monitor.DropNotify{Source: 2, SrcLabel: 1000}, make([]byte, monitor.DropNotifyLen), now)
+	store.AddTrace(&monitor.TraceNotify{ObsPoint: monitor.TraceFromLxc, Source: 1, SrcLabel: 1000}, make([]byte, monitor.TraceNotifyLen), now)
+
+	ep := uint16(1)
+	c.Assert(store.List(Filter{Endpoint: &ep}, nil), HasLen, 2)
+	c.Assert(store.List(Filter{Verdict: models.FlowVerdictDenied}, nil), HasLen, 2)
+	c.Assert(store.List(Filter{Since: now.Add(-time.Minute)}, nil), HasLen, 2)
+	c.Assert(store.List(Filter{Endpoint: &ep, Verdict: models.FlowVerdictDenied, Since: now.Add(-time.Minute)}, nil), HasLen, 0)
+
+	labels := func(id identity.NumericIdentity) []string {
+		return []string{"k8s:id=" + id.String()}
+	}
+	flows := store.List(Filter{Endpoint: &ep}, labels)
+	c.Assert(flows[0].Source.Labels, DeepEquals, models.Labels{"k8s:id=1000"})
+
+	// Labels are not stored
+	flows = store.List(Filter{Endpoint: &ep}, nil)
+	c.Assert(flows[0].Source.Labels, HasLen, 0)
+}
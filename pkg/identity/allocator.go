@@ -15,7 +15,9 @@
 package identity
 
 import (
+	"fmt"
 	"path"
+	"strings"
 	"sync"
 
 	"github.com/cilium/cilium/pkg/kvstore"
@@ -55,6 +57,52 @@ var (
 	IdentitiesPath = path.Join(kvstore.BaseKeyPrefix, "state", "identities", "v1")
 )
 
+func init() {
+	kvstore.RegisterSnapshotCodec(IdentitiesPath, convertIdentityPair)
+}
+
+// convertIdentityPair converts the encoded labels of identity keys. The
+// value of master keys (id/<ID>), the key of slave keys
+// (value/<labels>/<node suffix>) and the key of guard keys (keys/<labels>)
+// contain the labels encoded with kvstore.Encode().
+func convertIdentityPair(key string, value []byte, convert func(string) (string, error)) (string, []byte, error) {
+	idPrefix := path.Join(IdentitiesPath, "id") + "/"
+	valuePrefix := path.Join(IdentitiesPath, "value") + "/"
+	keysPrefix := path.Join(IdentitiesPath, "keys") + "/"
+
+	switch {
+	case strings.HasPrefix(key, idPrefix):
+		lbls, err := convert(string(value))
+		if err != nil {
+			return "", nil, err
+		}
+		return key, []byte(lbls), nil
+
+	case strings.HasPrefix(key, valuePrefix):
+		// The node suffix never contains a slash while the decoded
+		// labels may
+		rest := strings.TrimPrefix(key, valuePrefix)
+		i := strings.LastIndex(rest, "/")
+		if i < 0 {
+			return "", nil, fmt.Errorf("missing node suffix")
+		}
+		lbls, err := convert(rest[:i])
+		if err != nil {
+			return "", nil, err
+		}
+		return valuePrefix + lbls + rest[i:], value, nil
+
+	case strings.HasPrefix(key, keysPrefix):
+		lbls, err := convert(strings.TrimPrefix(key, keysPrefix))
+		if err != nil {
+			return "", nil, err
+		}
+		return keysPrefix + lbls, value, nil
+	}
+
+	return key, value, nil
+}
+
 // IdentityAllocatorOwner is the interface the owner of an identity allocator
 // must implement
 type IdentityAllocatorOwner interface {
@@ -86,14 +134,23 @@ func InitIdentityAllocator(owner IdentityAllocatorOwner) {
 }
 
 // AllocateIdentity allocates an identity described by the specified labels. If
-// an identity for the specified set of labels already exist, the identity is
-// re-used and reference counting is performed, otherwise a new identity is
-// allocated via the kvstore.
+// the labels are pinned to a static identity, the static identity is returned
+// without involving the kvstore. If an identity for the specified set of
+// labels already exist, the identity is re-used and reference counting is
+// performed, otherwise a new identity is allocated via the kvstore.
 func AllocateIdentity(lbls labels.Labels) (*Identity, bool, error) {
 	log.WithFields(logrus.Fields{
 		logfields.IdentityLabels: lbls.String(),
 	}).Debug("Resolving identity")
 
+	if identity := lookupStaticIdentity(lbls); identity != nil {
+		log.WithFields(logrus.Fields{
+			logfields.Identity:       identity.ID,
+			logfields.IdentityLabels: lbls.String(),
+		}).Debug("Resolved static identity")
+		return identity, false, nil
+	}
+
 	id, isNew, err := identityAllocator.Allocate(globalIdentity{lbls})
 	if err != nil {
 		return nil, false, err
@@ -111,6 +168,11 @@ func AllocateIdentity(lbls labels.Labels) (*Identity, bool, error) {
 // Release is the reverse operation of AllocateIdentity() and releases the
 // identity again. This function may result in kvstore operations.
 // After the last user has released the ID, the returned lastUse value is true.
+// Static identities are not reference counted and are never released.
 func (id *Identity) Release() error {
+	if id.ID.IsStaticIdentity() {
+		return nil
+	}
+
 	return identityAllocator.Release(globalIdentity{id.Labels})
 }
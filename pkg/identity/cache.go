@@ -34,6 +34,10 @@ type IdentityCache map[NumericIdentity]labels.LabelArray
 func GetIdentityCache() IdentityCache {
 	cache := IdentityCache{}
 
+	foreachStaticIdentity(func(identity *Identity) {
+		cache[identity.ID] = identity.Labels.LabelArray()
+	})
+
 	identityAllocator.ForeachCache(func(id allocator.ID, val allocator.AllocatorKey) {
 		gi := val.(globalIdentity)
 		cache[NumericIdentity(id)] = gi.LabelArray()
@@ -46,6 +50,10 @@ func GetIdentityCache() IdentityCache {
 func GetIdentities() []*models.Identity {
 	identities := []*models.Identity{}
 
+	foreachStaticIdentity(func(identity *Identity) {
+		identities = append(identities, identity.GetModel())
+	})
+
 	identityAllocator.ForeachCache(func(id allocator.ID, val allocator.AllocatorKey) {
 		if gi, ok := val.(globalIdentity); ok {
 			identity := NewIdentity(NumericIdentity(id), gi.Labels)
@@ -72,8 +80,8 @@ func identityWatcher(owner IdentityAllocatorOwner) {
 }
 
 // LookupIdentity looks up the identity by its labels but does not create it.
-// This function will first search through the local cache and fall back to
-// querying the kvstore.
+// This function will first search through the reserved and static identities
+// as well as the local cache and fall back to querying the kvstore.
 func LookupIdentity(lbls labels.Labels) *Identity {
 	for _, identity := range reservedIdentityCache {
 		if reflect.DeepEqual(identity.Labels, lbls) {
@@ -81,6 +89,10 @@ func LookupIdentity(lbls labels.Labels) *Identity {
 		}
 	}
 
+	if identity := lookupStaticIdentity(lbls); identity != nil {
+		return identity
+	}
+
 	if identityAllocator == nil {
 		return nil
 	}
@@ -104,6 +116,10 @@ func LookupIdentityByID(id NumericIdentity) *Identity {
 		return identity
 	}
 
+	if identity := lookupStaticIdentityByID(id); identity != nil {
+		return identity
+	}
+
 	if identityAllocator == nil {
 		return nil
 	}
@@ -0,0 +1,82 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package identity
+
+import (
+	"fmt"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/kvstore/allocator"
+
+	"github.com/go-openapi/strfmt"
+)
+
+func newGCEntryModel(entry allocator.GCEntry) *models.IdentityGCEntry {
+	m := &models.IdentityGCEntry{
+		ID:      int64(entry.ID),
+		LastUse: strfmt.DateTime(entry.LastUse),
+		Nodes:   entry.Nodes,
+	}
+
+	if gi, ok := entry.Key.(globalIdentity); ok {
+		m.Labels = gi.GetModel()
+	}
+
+	return m
+}
+
+func newGCEntryModels(entries []allocator.GCEntry) []*models.IdentityGCEntry {
+	list := make([]*models.IdentityGCEntry, 0, len(entries))
+	for _, entry := range entries {
+		list = append(list, newGCEntryModel(entry))
+	}
+	return list
+}
+
+// GetGCReport returns the identities which will be released by the next
+// garbage collector run as well as the identities which are only referenced
+// by nodes for which isStale returns true. If isStale is nil, no node is
+// considered stale.
+func GetGCReport(isStale allocator.StaleNodeFunc) (*models.IdentityGCReport, error) {
+	if identityAllocator == nil {
+		return nil, fmt.Errorf("identity allocator not initialized")
+	}
+
+	report, err := identityAllocator.GCReport(isStale)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.IdentityGCReport{
+		Unreferenced:       newGCEntryModels(report.Unreferenced),
+		StaleReferenced:    newGCEntryModels(report.StaleReferenced),
+		StaleNodeDetection: isStale != nil,
+	}, nil
+}
+
+// RunGC runs the identity garbage collector and returns the released
+// identities
+func RunGC() ([]*models.IdentityGCEntry, error) {
+	if identityAllocator == nil {
+		return nil, fmt.Errorf("identity allocator not initialized")
+	}
+
+	released, err := identityAllocator.RunGC()
+	if err != nil {
+		return nil, err
+	}
+
+	return newGCEntryModels(released), nil
+}
@@ -15,6 +15,7 @@
 package identity
 
 import (
+	"strings"
 	"sync"
 	"testing"
 
@@ -83,6 +84,16 @@ func (e *IdentityAllocatorConsulSuite) SetUpTest(c *C) {
 	kvstore.SetupDummy("consul")
 }
 
+type IdentityAllocatorEmbeddedSuite struct {
+	IdentityAllocatorSuite
+}
+
+var _ = Suite(&IdentityAllocatorEmbeddedSuite{})
+
+func (e *IdentityAllocatorEmbeddedSuite) SetUpTest(c *C) {
+	kvstore.SetupDummy(kvstore.EmbeddedBackendName)
+}
+
 type dummyOwner struct{}
 
 func (d dummyOwner) TriggerPolicyUpdates(force bool) *sync.WaitGroup {
@@ -149,3 +160,27 @@ func (ias *IdentityAllocatorSuite) TestAllocator(c *C) {
 	err = id3.Release()
 	c.Assert(err, IsNil)
 }
+
+func (s *IdentityTestSuite) TestConvertIdentityPair(c *C) {
+	upper := func(in string) (string, error) {
+		return strings.ToUpper(in), nil
+	}
+
+	key, value, err := convertIdentityPair(IdentitiesPath+"/id/1000", []byte("k8s:app=web"), upper)
+	c.Assert(err, IsNil)
+	c.Assert(key, Equals, IdentitiesPath+"/id/1000")
+	c.Assert(string(value), Equals, "K8S:APP=WEB")
+
+	key, value, err = convertIdentityPair(IdentitiesPath+"/value/k8s:app.kubernetes.io/name=web/10.0.0.1", []byte("1000"), upper)
+	c.Assert(err, IsNil)
+	c.Assert(key, Equals, IdentitiesPath+"/value/K8S:APP.KUBERNETES.IO/NAME=WEB/10.0.0.1")
+	c.Assert(string(value), Equals, "1000")
+
+	_, _, err = convertIdentityPair(IdentitiesPath+"/value/nosuffix", []byte("1000"), upper)
+	c.Assert(err, Not(IsNil))
+
+	key, value, err = convertIdentityPair(IdentitiesPath+"/keys/k8s:app.kubernetes.io/name=web", []byte("1000"), upper)
+	c.Assert(err, IsNil)
+	c.Assert(key, Equals, IdentitiesPath+"/keys/K8S:APP.KUBERNETES.IO/NAME=WEB")
+	c.Assert(string(value), Equals, "1000")
+}
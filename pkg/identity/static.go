@@ -0,0 +1,181 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package identity
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/lock"
+)
+
+const (
+	// MinimalStaticIdentity is the lowest numeric identity which can be
+	// pinned to a set of labels by the operator. Identities below this
+	// value are reserved for the special reserved.* identities.
+	MinimalStaticIdentity = NumericIdentity(128)
+
+	// MaximalStaticIdentity is the highest numeric identity which can be
+	// pinned to a set of labels by the operator. Identities above this
+	// value are allocated dynamically via the kvstore.
+	MaximalStaticIdentity = MinimalNumericIdentity - 1
+)
+
+// StaticIdentity pins a set of labels to a fixed numeric identity
+type StaticIdentity struct {
+	// ID is the numeric identity to assign to the labels
+	ID NumericIdentity `json:"id"`
+
+	// Labels is the list of labels in the form [source:]key[=value]
+	Labels []string `json:"labels"`
+}
+
+var (
+	staticMutex lock.RWMutex
+
+	// staticIdentities maps the sorted list of labels to the pinned
+	// identity
+	staticIdentities = map[string]*Identity{}
+
+	// staticIdentitiesByID maps the pinned numeric identity to the
+	// identity
+	staticIdentitiesByID = map[NumericIdentity]*Identity{}
+)
+
+// IsStaticIdentity returns whether id is within the range of identities
+// which can be pinned by the operator. Identities in this range are never
+// allocated via the kvstore.
+func (id NumericIdentity) IsStaticIdentity() bool {
+	return id >= MinimalStaticIdentity && id <= MaximalStaticIdentity
+}
+
+// SetStaticIdentities validates the list of static identities and replaces
+// all previously configured static identities with it. The list is rejected
+// as a whole if any of the identities is out of range, contains reserved
+// labels or conflicts with another identity in the list.
+func SetStaticIdentities(static []StaticIdentity) error {
+	byLabels := map[string]*Identity{}
+	byID := map[NumericIdentity]*Identity{}
+
+	for _, s := range static {
+		if !s.ID.IsStaticIdentity() {
+			return fmt.Errorf("static identity %d is outside of the range %d-%d",
+				s.ID, MinimalStaticIdentity, MaximalStaticIdentity)
+		}
+
+		if len(s.Labels) == 0 {
+			return fmt.Errorf("static identity %d has no labels", s.ID)
+		}
+
+		lbls := labels.NewLabelsFromModel(s.Labels)
+		if len(lbls.FindReserved()) > 0 {
+			return fmt.Errorf("static identity %d must not contain reserved labels", s.ID)
+		}
+
+		if _, ok := byID[s.ID]; ok {
+			return fmt.Errorf("static identity %d is defined more than once", s.ID)
+		}
+
+		key := string(lbls.SortedList())
+		if other, ok := byLabels[key]; ok {
+			return fmt.Errorf("static identities %d and %d have the same labels", other.ID, s.ID)
+		}
+
+		identity := NewIdentity(s.ID, lbls)
+		byLabels[key] = identity
+		byID[s.ID] = identity
+	}
+
+	staticMutex.Lock()
+	staticIdentities = byLabels
+	staticIdentitiesByID = byID
+	staticMutex.Unlock()
+
+	log.WithField("count", len(byID)).Info("Configured static identities")
+
+	return nil
+}
+
+// LoadStaticIdentities reads a JSON encoded list of static identities from
+// the file at path and configures them via SetStaticIdentities()
+func LoadStaticIdentities(path string) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var static []StaticIdentity
+	if err := json.Unmarshal(b, &static); err != nil {
+		return fmt.Errorf("unable to parse %s: %s", path, err)
+	}
+
+	return SetStaticIdentities(static)
+}
+
+// GetStaticIdentities returns the list of configured static identities
+// sorted by numeric identity
+func GetStaticIdentities() []StaticIdentity {
+	staticMutex.RLock()
+	static := make([]StaticIdentity, 0, len(staticIdentitiesByID))
+	for id, identity := range staticIdentitiesByID {
+		static = append(static, StaticIdentity{
+			ID:     id,
+			Labels: identity.Labels.GetModel(),
+		})
+	}
+	staticMutex.RUnlock()
+
+	sort.Slice(static, func(i, j int) bool { return static[i].ID < static[j].ID })
+
+	return static
+}
+
+// lookupStaticIdentity returns the static identity pinned to lbls or nil
+func lookupStaticIdentity(lbls labels.Labels) *Identity {
+	staticMutex.RLock()
+	defer staticMutex.RUnlock()
+
+	if identity, ok := staticIdentities[string(lbls.SortedList())]; ok {
+		return NewIdentity(identity.ID, identity.Labels)
+	}
+
+	return nil
+}
+
+// lookupStaticIdentityByID returns the static identity with the numeric
+// identity id or nil
+func lookupStaticIdentityByID(id NumericIdentity) *Identity {
+	staticMutex.RLock()
+	defer staticMutex.RUnlock()
+
+	if identity, ok := staticIdentitiesByID[id]; ok {
+		return NewIdentity(identity.ID, identity.Labels)
+	}
+
+	return nil
+}
+
+// foreachStaticIdentity calls fn for each configured static identity
+func foreachStaticIdentity(fn func(identity *Identity)) {
+	staticMutex.RLock()
+	defer staticMutex.RUnlock()
+
+	for _, identity := range staticIdentitiesByID {
+		fn(identity)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package identity
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cilium/cilium/pkg/labels"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *IdentityTestSuite) TestSetStaticIdentitiesValidation(c *C) {
+	defer SetStaticIdentities(nil)
+
+	app := []string{"k8s:app=foo"}
+
+	c.Assert(SetStaticIdentities([]StaticIdentity{{ID: 4, Labels: app}}), Not(IsNil))
+	c.Assert(SetStaticIdentities([]StaticIdentity{{ID: MinimalNumericIdentity, Labels: app}}), Not(IsNil))
+	c.Assert(SetStaticIdentities([]StaticIdentity{{ID: MinimalStaticIdentity}}), Not(IsNil))
+	c.Assert(SetStaticIdentities([]StaticIdentity{{ID: MinimalStaticIdentity, Labels: []string{"reserved:host"}}}), Not(IsNil))
+	c.Assert(SetStaticIdentities([]StaticIdentity{
+		{ID: MinimalStaticIdentity, Labels: app},
+		{ID: MinimalStaticIdentity, Labels: []string{"k8s:app=bar"}},
+	}), Not(IsNil))
+	c.Assert(SetStaticIdentities([]StaticIdentity{
+		{ID: MinimalStaticIdentity, Labels: app},
+		{ID: MaximalStaticIdentity, Labels: app},
+	}), Not(IsNil))
+
+	// A rejected list must not modify the configured identities
+	c.Assert(GetStaticIdentities(), HasLen, 0)
+
+	c.Assert(SetStaticIdentities([]StaticIdentity{
+		{ID: MaximalStaticIdentity, Labels: app},
+		{ID: MinimalStaticIdentity, Labels: []string{"k8s:app=bar", "k8s:tier=db"}},
+	}), IsNil)
+
+	static := GetStaticIdentities()
+	c.Assert(static, HasLen, 2)
+	c.Assert(static[0].ID, Equals, MinimalStaticIdentity)
+	c.Assert(static[1].ID, Equals, MaximalStaticIdentity)
+}
+
+func (s *IdentityTestSuite) TestStaticIdentities(c *C) {
+	defer SetStaticIdentities(nil)
+
+	dir, err := ioutil.TempDir("", "cilium-static-identities")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "static.json")
+	err = ioutil.WriteFile(path, []byte(`[{"id": 200, "labels": ["k8s:app=dns", "k8s:tier=infra"]}]`), 0600)
+	c.Assert(err, IsNil)
+	c.Assert(LoadStaticIdentities(path), IsNil)
+
+	lbls := labels.NewLabelsFromModel([]string{"k8s:tier=infra", "k8s:app=dns"})
+
+	identity := LookupIdentity(lbls)
+	c.Assert(identity, Not(IsNil))
+	c.Assert(identity.ID, Equals, NumericIdentity(200))
+
+	identity = LookupIdentityByID(NumericIdentity(200))
+	c.Assert(identity, Not(IsNil))
+	c.Assert(identity.Labels, DeepEquals, lbls)
+
+	// Static identities are resolved without the kvstore allocator
+	identity, isNew, err := AllocateIdentity(lbls)
+	c.Assert(err, IsNil)
+	c.Assert(isNew, Equals, false)
+	c.Assert(identity.ID, Equals, NumericIdentity(200))
+	c.Assert(identity.Release(), IsNil)
+
+	c.Assert(LookupIdentityByID(NumericIdentity(201)), IsNil)
+	c.Assert(NumericIdentity(200).IsStaticIdentity(), Equals, true)
+	c.Assert(MinimalNumericIdentity.IsStaticIdentity(), Equals, false)
+	c.Assert(ReservedIdentityHost.IsStaticIdentity(), Equals, false)
+}
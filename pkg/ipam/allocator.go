@@ -17,12 +17,8 @@ package ipam
 import (
 	"errors"
 	"fmt"
-	"math/big"
 	"net"
-
-	"github.com/cilium/cilium/pkg/node"
-
-	k8sAPI "k8s.io/kubernetes/pkg/api"
+	"sort"
 )
 
 // Error definitions
@@ -34,51 +30,90 @@ var (
 	ErrIPv6Disabled = errors.New("IPv6 allocation disabled")
 )
 
-// AllocateIP allocates a IP address.
-func AllocateIP(ip net.IP) error {
+// AllocateIP allocates a IP address on behalf of owner. The address is
+// allocated from the pool whose range contains the address or from the
+// default pool.
+func AllocateIP(ip net.IP, owner string) error {
 	ipamConf.allocatorMutex.Lock()
 	defer ipamConf.allocatorMutex.Unlock()
 
+	// An address reserved for a previous run is taken over by the owner
+	// allocating it again, e.g. a restored endpoint
+	if ipamConf.adoptRestoredLocked(ip, owner) {
+		return nil
+	}
+
+	pool := ipamConf.poolOf(ip)
+
 	if ip.To4() != nil {
-		if ipamConf.IPv4Allocator == nil {
+		if pool.IPv4Allocator == nil {
 			return ErrIPv4Disabled
 		}
 
-		if err := ipamConf.IPv4Allocator.Allocate(ip); err != nil {
+		if err := pool.IPv4Allocator.Allocate(ip); err != nil {
 			return err
 		}
 	} else {
-		if ipamConf.IPv6Allocator == nil {
+		if pool.IPv6Allocator == nil {
 			return ErrIPv6Disabled
 		}
 
-		if err := ipamConf.IPv6Allocator.Allocate(ip); err != nil {
+		if err := pool.IPv6Allocator.Allocate(ip); err != nil {
 			return err
 		}
 	}
 
+	ipamConf.recordLocked(ip, owner)
+	ipamConf.saveLocked()
+
 	return nil
 }
 
 // AllocateIPString is identical to AllocateIP but takes a string
-func AllocateIPString(ipAddr string) error {
+func AllocateIPString(ipAddr, owner string) error {
 	ip := net.ParseIP(ipAddr)
 	if ip == nil {
 		return fmt.Errorf("Invalid IP address: %s", ipAddr)
 	}
 
-	return AllocateIP(ip)
+	return AllocateIP(ip, owner)
 }
 
 // AllocateNext allocates the next available IPv4 and IPv6 address out of the
-// configured address pool. If family is set to "ipv4" or "ipv6", then
-// allocation is limited to the specified address family. If the pool has been
-// drained of addresses, an error will be returned.
-func AllocateNext(family string) (net.IP, net.IP, error) {
+// default address pool on behalf of owner. If family is set to "ipv4" or
+// "ipv6", then allocation is limited to the specified address family. If the
+// pool has been drained of addresses, an error will be returned.
+func AllocateNext(family, owner string) (net.IP, net.IP, error) {
+	return AllocateNextFromPool(DefaultPool, family, owner)
+}
+
+// AllocateNextFromPool is identical to AllocateNext but allocates out of the
+// pool with the given name. Addresses of a family not provided by the pool
+// are allocated from the default pool.
+func AllocateNextFromPool(poolName, family, owner string) (net.IP, net.IP, error) {
 	var ipv4, ipv6 net.IP
 
-	if (family == "ipv6" || family == "") && ipamConf.IPv6Allocator != nil {
-		ipConf, err := ipamConf.IPv6Allocator.AllocateNext()
+	ipamConf.allocatorMutex.RLock()
+	pool, ok := ipamConf.pools[poolName]
+	defaultPool := ipamConf.defaultPool()
+	ipamConf.allocatorMutex.RUnlock()
+
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown IPAM pool %q", poolName)
+	}
+
+	ipv6Allocator := pool.IPv6Allocator
+	if ipv6Allocator == nil {
+		ipv6Allocator = defaultPool.IPv6Allocator
+	}
+
+	ipv4Allocator := pool.IPv4Allocator
+	if ipv4Allocator == nil {
+		ipv4Allocator = defaultPool.IPv4Allocator
+	}
+
+	if (family == "ipv6" || family == "") && ipv6Allocator != nil {
+		ipConf, err := ipv6Allocator.AllocateNext()
 		if err != nil {
 			return nil, nil, err
 		}
@@ -86,15 +121,27 @@ func AllocateNext(family string) (net.IP, net.IP, error) {
 		ipv6 = ipConf
 	}
 
-	if (family == "ipv4" || family == "") && ipamConf.IPv4Allocator != nil {
-		ipConf, err := ipamConf.IPv4Allocator.AllocateNext()
+	if (family == "ipv4" || family == "") && ipv4Allocator != nil {
+		ipConf, err := ipv4Allocator.AllocateNext()
 		if err != nil {
+			if ipv6 != nil {
+				ipv6Allocator.Release(ipv6)
+			}
 			return nil, nil, err
 		}
 
 		ipv4 = ipConf
 	}
 
+	ipamConf.allocatorMutex.Lock()
+	for _, ip := range []net.IP{ipv4, ipv6} {
+		if ip != nil {
+			ipamConf.recordLocked(ip, owner)
+		}
+	}
+	ipamConf.saveLocked()
+	ipamConf.allocatorMutex.Unlock()
+
 	return ipv4, ipv6, nil
 }
 
@@ -103,24 +150,29 @@ func ReleaseIP(ip net.IP) error {
 	ipamConf.allocatorMutex.Lock()
 	defer ipamConf.allocatorMutex.Unlock()
 
+	pool := ipamConf.poolOf(ip)
+
 	if ip.To4() != nil {
-		if ipamConf.IPv4Allocator == nil {
+		if pool.IPv4Allocator == nil {
 			return ErrIPv4Disabled
 		}
 
-		if err := ipamConf.IPv4Allocator.Release(ip); err != nil {
+		if err := pool.IPv4Allocator.Release(ip); err != nil {
 			return err
 		}
 	} else {
-		if ipamConf.IPv6Allocator == nil {
+		if pool.IPv6Allocator == nil {
 			return ErrIPv6Disabled
 		}
 
-		if err := ipamConf.IPv6Allocator.Release(ip); err != nil {
+		if err := pool.IPv6Allocator.Release(ip); err != nil {
 			return err
 		}
 	}
 
+	ipamConf.forgetLocked(ip)
+	ipamConf.saveLocked()
+
 	return nil
 }
 
@@ -134,30 +186,27 @@ func ReleaseIPString(ipAddr string) error {
 	return ReleaseIP(ip)
 }
 
-// Dump dumps the list of allocated IP addresses
+// Dump dumps the list of allocated IP addresses of all pools
 func Dump() ([]string, []string) {
 	ipamConf.allocatorMutex.RLock()
 	defer ipamConf.allocatorMutex.RUnlock()
 
 	allocv4 := []string{}
-	ralv4 := k8sAPI.RangeAllocation{}
-	ipamConf.IPv4Allocator.Snapshot(&ralv4)
-	origIP := big.NewInt(0).SetBytes(node.GetIPv4AllocRange().IP)
-	v4Bits := big.NewInt(0).SetBytes(ralv4.Data)
-	for i := 0; i < v4Bits.BitLen(); i++ {
-		if v4Bits.Bit(i) != 0 {
-			allocv4 = append(allocv4, net.IP(big.NewInt(0).Add(origIP, big.NewInt(int64(uint(i+1)))).Bytes()).String())
-		}
+	allocv6 := []string{}
+
+	names := make([]string, 0, len(ipamConf.pools))
+	for name := range ipamConf.pools {
+		names = append(names, name)
 	}
+	sort.Strings(names)
 
-	allocv6 := []string{}
-	ralv6 := k8sAPI.RangeAllocation{}
-	ipamConf.IPv6Allocator.Snapshot(&ralv6)
-	origIP = big.NewInt(0).SetBytes(node.GetIPv6AllocRange().IP)
-	v6Bits := big.NewInt(0).SetBytes(ralv6.Data)
-	for i := 0; i < v6Bits.BitLen(); i++ {
-		if v6Bits.Bit(i) != 0 {
-			allocv6 = append(allocv6, net.IP(big.NewInt(0).Add(origIP, big.NewInt(int64(uint(i+1)))).Bytes()).String())
+	for _, name := range names {
+		pool := ipamConf.pools[name]
+		if pool.IPv4Allocator != nil {
+			allocv4 = append(allocv4, pool.IPv4Allocator.Dump()...)
+		}
+		if pool.IPv6Allocator != nil {
+			allocv6 = append(allocv6, pool.IPv6Allocator.Dump()...)
 		}
 	}
 
@@ -0,0 +1,586 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cilium/cilium/pkg/kvstore"
+	"github.com/cilium/cilium/pkg/kvstore/allocator"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/maps/tunnel"
+	"github.com/cilium/cilium/pkg/node"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultBlockMaskSize is the default prefix length of the address
+	// blocks allocated out of the cluster range
+	DefaultBlockMaskSize = 26
+
+	// maxBlockMaskSize is the largest supported prefix length of an
+	// address block. Each block must provide at least two addresses.
+	maxBlockMaskSize = 30
+
+	// maxBlockBits limits the number of blocks in the cluster range to
+	// the number of entries in the tunnel map
+	maxBlockBits = 16
+
+	// blockClaimTimeout is the time an allocation waits for a new block
+	// to be claimed via the kvstore
+	blockClaimTimeout = 30 * time.Second
+)
+
+var (
+	// ClusterBlocksPath is the path in the kvstore where the ownership of
+	// address blocks is stored
+	ClusterBlocksPath = path.Join(kvstore.BaseKeyPrefix, "state", "ipam", "v1", "blocks")
+
+	// clusterIPv4Pool is the IPv4 allocator of the default pool if
+	// cluster-wide address allocation is enabled
+	clusterIPv4Pool *clusterPool
+)
+
+// blockKey is the allocator key of an address block. A node owns a block as
+// long as the key with its name and the block index exists in the kvstore.
+// The node IP is part of the key so other nodes can route to the block.
+type blockKey struct {
+	Node   string
+	NodeIP net.IP
+	Index  int
+}
+
+// GetKey encodes a blockKey as string
+func (k blockKey) GetKey() string {
+	return fmt.Sprintf("%s;%s;%d;", k.Node, k.NodeIP, k.Index)
+}
+
+// PutKey decodes a blockKey from its string representation
+func (k blockKey) PutKey(v string) (allocator.AllocatorKey, error) {
+	parts := strings.Split(v, ";")
+	if len(parts) != 4 || parts[3] != "" {
+		return nil, fmt.Errorf("invalid block key %q", v)
+	}
+
+	ip := net.ParseIP(parts[1])
+	if ip == nil {
+		return nil, fmt.Errorf("invalid node IP in block key %q", v)
+	}
+
+	index, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid index in block key %q: %s", v, err)
+	}
+
+	return blockKey{Node: parts[0], NodeIP: ip, Index: index}, nil
+}
+
+// String returns the human readable representation of a blockKey
+func (k blockKey) String() string {
+	return k.GetKey()
+}
+
+// ownedBlock is an address block owned by the local node
+type ownedBlock struct {
+	*cidrRange
+	key blockKey
+	id  allocator.ID
+}
+
+// clusterPool allocates IPv4 addresses out of a cluster-wide range. The range
+// is divided into blocks of equal size. Nodes claim blocks as needed and
+// allocate addresses out of their blocks. The ownership of blocks is
+// maintained with the kvstore allocator, blocks of nodes which fail to renew
+// their kvstore lease are released by the allocator garbage collector.
+type clusterPool struct {
+	clusterRange  *net.IPNet
+	blockMaskSize int
+	numBlocks     int
+	nodeName      string
+	nodeIP        net.IP
+	allocator     *allocator.Allocator
+
+	// mutex protects blocks and pending
+	mutex lock.Mutex
+
+	// blocks is the list of blocks owned by the local node. The first
+	// block is the primary block which is never released.
+	blocks []*ownedBlock
+
+	// pending contains the indices of the blocks which are being claimed
+	// or released via the kvstore. The kvstore operations are performed
+	// without holding mutex.
+	pending map[int]bool
+
+	// releaseWg waits for the blocks being released
+	releaseWg sync.WaitGroup
+
+	// remoteMutex protects remoteBlocks
+	remoteMutex lock.RWMutex
+
+	// remoteBlocks is the list of blocks owned by other nodes
+	remoteBlocks map[allocator.ID]blockKey
+
+	// setTunnel and deleteTunnel maintain the tunnel endpoints of remote
+	// blocks
+	setTunnel    func(prefix, endpoint net.IP) error
+	deleteTunnel func(prefix net.IP) error
+}
+
+// validateClusterRange checks whether the cluster range can be divided into
+// blocks of the given prefix length
+func validateClusterRange(clusterRange *net.IPNet, blockMaskSize int) error {
+	if clusterRange.IP.To4() == nil {
+		return fmt.Errorf("cluster range %s is not an IPv4 range", clusterRange)
+	}
+
+	ones, _ := clusterRange.Mask.Size()
+	switch {
+	case blockMaskSize > maxBlockMaskSize:
+		return fmt.Errorf("block size /%d is too small, at most /%d is supported", blockMaskSize, maxBlockMaskSize)
+	case blockMaskSize <= ones:
+		return fmt.Errorf("block size /%d must be smaller than cluster range %s", blockMaskSize, clusterRange)
+	case blockMaskSize-ones > maxBlockBits:
+		return fmt.Errorf("cluster range %s contains more than %d blocks of size /%d",
+			clusterRange, 1<<maxBlockBits, blockMaskSize)
+	}
+
+	return nil
+}
+
+// newClusterPool creates a pool allocating blocks out of clusterRange on
+// behalf of the given node and claims the blocks the node owned before.
+func newClusterPool(clusterRange *net.IPNet, blockMaskSize int, nodeName string, nodeIP net.IP) (*clusterPool, error) {
+	if err := validateClusterRange(clusterRange, blockMaskSize); err != nil {
+		return nil, err
+	}
+
+	if nodeIP == nil {
+		return nil, fmt.Errorf("node IPv4 address is unknown")
+	}
+
+	ones, _ := clusterRange.Mask.Size()
+	numBlocks := 1 << uint(blockMaskSize-ones)
+
+	a, err := allocator.NewAllocator(ClusterBlocksPath, blockKey{},
+		allocator.WithMin(1), allocator.WithMax(allocator.ID(numBlocks)),
+		allocator.WithSuffix(nodeName))
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize block allocator: %s", err)
+	}
+
+	p := &clusterPool{
+		clusterRange: &net.IPNet{
+			IP:   clusterRange.IP.Mask(clusterRange.Mask).To4(),
+			Mask: clusterRange.Mask,
+		},
+		blockMaskSize: blockMaskSize,
+		numBlocks:     numBlocks,
+		nodeName:      nodeName,
+		nodeIP:        nodeIP,
+		allocator:     a,
+		pending:       map[int]bool{},
+		remoteBlocks:  map[allocator.ID]blockKey{},
+		setTunnel:     tunnel.SetTunnelEndpoint,
+		deleteTunnel:  tunnel.DeleteTunnelEndpoint,
+	}
+
+	if err := p.restoreBlocks(); err != nil {
+		a.Delete()
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// InitClusterPool enables cluster-wide allocation of IPv4 addresses. The
+// addresses are allocated out of blocks of size /blockMaskSize claimed from
+// clusterRange via the kvstore. The primary block of the node is returned, it
+// must be used as IPv4 allocation range of the node. Init() must be called
+// afterwards.
+func InitClusterPool(clusterRange *net.IPNet, blockMaskSize int) (*net.IPNet, error) {
+	p, err := newClusterPool(clusterRange, blockMaskSize, node.GetName(), node.GetExternalIPv4().To4())
+	if err != nil {
+		return nil, err
+	}
+
+	go p.watchBlocks()
+
+	clusterIPv4Pool = p
+
+	return p.primaryBlock(), nil
+}
+
+// ClusterPoolEnabled returns true if IPv4 addresses are allocated out of a
+// cluster-wide range
+func ClusterPoolEnabled() bool {
+	return clusterIPv4Pool != nil
+}
+
+// blockCIDR returns the CIDR of the block with the given ID
+func (p *clusterPool) blockCIDR(id allocator.ID) *net.IPNet {
+	base := binary.BigEndian.Uint32(p.clusterRange.IP)
+	ip := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(ip, base+uint32(id-1)<<uint(32-p.blockMaskSize))
+
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(p.blockMaskSize, 32)}
+}
+
+// primaryBlock returns the CIDR of the primary block
+func (p *clusterPool) primaryBlock() *net.IPNet {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.blocks[0].cidr
+}
+
+// restoreBlocks claims all blocks which are still owned by the node, e.g.
+// after a restart of the agent. A new block is claimed if the node does not
+// own any block.
+func (p *clusterPool) restoreBlocks() error {
+	keys := []blockKey{}
+	p.allocator.ForeachCache(func(id allocator.ID, k allocator.AllocatorKey) {
+		if key, ok := k.(blockKey); ok && key.Node == p.nodeName && key.NodeIP.Equal(p.nodeIP) {
+			keys = append(keys, key)
+		}
+	})
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Index < keys[j].Index })
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for _, key := range keys {
+		b, err := p.claimBlock(key)
+		if err != nil {
+			return err
+		}
+		p.blocks = append(p.blocks, b)
+	}
+
+	if len(p.blocks) == 0 {
+		b, err := p.claimBlock(p.newBlockKey())
+		if err != nil {
+			return err
+		}
+		p.blocks = append(p.blocks, b)
+	}
+
+	return nil
+}
+
+// newBlockKey returns the key for a new block using the lowest index not in
+// use by the node and not being claimed or released. Must be called with
+// mutex held.
+func (p *clusterPool) newBlockKey() blockKey {
+	used := map[int]bool{}
+	for _, b := range p.blocks {
+		used[b.key.Index] = true
+	}
+
+	index := 0
+	for used[index] || p.pending[index] {
+		index++
+	}
+
+	return blockKey{Node: p.nodeName, NodeIP: p.nodeIP, Index: index}
+}
+
+// claimBlock claims the block for key via the kvstore. The block is not added
+// to the list of owned blocks.
+func (p *clusterPool) claimBlock(key blockKey) (*ownedBlock, error) {
+	allocated := 0
+	p.allocator.ForeachCache(func(allocator.ID, allocator.AllocatorKey) { allocated++ })
+	if allocated >= p.numBlocks {
+		if id, _ := p.allocator.Get(key); id == allocator.NoID {
+			return nil, fmt.Errorf("no address block available in cluster range %s", p.clusterRange)
+		}
+	}
+
+	id, _, err := p.allocator.Allocate(key)
+	if err != nil {
+		return nil, fmt.Errorf("unable to claim address block: %s", err)
+	}
+
+	b := &ownedBlock{
+		cidrRange: newCIDRRange(p.blockCIDR(id)),
+		key:       key,
+		id:        id,
+	}
+
+	log.WithFields(logrus.Fields{
+		logfields.V4Prefix: b.cidr,
+		"index":            key.Index,
+	}).Info("Claimed address block")
+
+	return b, nil
+}
+
+// claimNewBlock claims a new block without holding mutex and adds it to the
+// list of owned blocks. Gives up waiting after blockClaimTimeout, the block
+// is still added once it has been claimed.
+func (p *clusterPool) claimNewBlock() error {
+	p.mutex.Lock()
+	key := p.newBlockKey()
+	p.pending[key.Index] = true
+	p.mutex.Unlock()
+
+	claimed := make(chan error, 1)
+	go func() {
+		b, err := p.claimBlock(key)
+
+		p.mutex.Lock()
+		delete(p.pending, key.Index)
+		if err == nil {
+			p.blocks = append(p.blocks, b)
+		}
+		p.mutex.Unlock()
+
+		claimed <- err
+	}()
+
+	select {
+	case err := <-claimed:
+		return err
+	case <-time.After(blockClaimTimeout):
+		return fmt.Errorf("timeout while claiming address block")
+	}
+}
+
+// releaseBlock removes the block at position i from the list of owned blocks
+// and releases it via the kvstore in the background. Must be called with
+// mutex held.
+func (p *clusterPool) releaseBlock(i int) {
+	b := p.blocks[i]
+	p.blocks = append(p.blocks[:i], p.blocks[i+1:]...)
+	p.pending[b.key.Index] = true
+
+	p.releaseWg.Add(1)
+	go func() {
+		defer p.releaseWg.Done()
+
+		err := p.allocator.Release(b.key)
+
+		p.mutex.Lock()
+		delete(p.pending, b.key.Index)
+		p.mutex.Unlock()
+
+		if err != nil {
+			log.WithError(err).WithField(logfields.V4Prefix, b.cidr).Warning("Unable to release address block")
+			return
+		}
+
+		log.WithField(logfields.V4Prefix, b.cidr).Info("Released address block")
+	}()
+}
+
+// findBlock returns the position of the owned block containing ip or -1.
+// Must be called with mutex held.
+func (p *clusterPool) findBlock(ip net.IP) int {
+	for i, b := range p.blocks {
+		if b.cidr.Contains(ip) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Allocate allocates ip. The address must be part of a block owned by the
+// node.
+func (p *clusterPool) Allocate(ip net.IP) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	i := p.findBlock(ip)
+	if i < 0 {
+		return fmt.Errorf("%s is not part of an address block owned by this node", ip)
+	}
+
+	return p.blocks[i].Allocate(ip)
+}
+
+// allocateNextLocked allocates the next available address out of the blocks
+// owned by the node. Must be called with mutex held.
+func (p *clusterPool) allocateNextLocked() net.IP {
+	for _, b := range p.blocks {
+		if b.Free() > 0 {
+			if ip, err := b.AllocateNext(); err == nil {
+				return ip
+			}
+		}
+	}
+	return nil
+}
+
+// AllocateNext allocates the next available address. A new block is claimed
+// if all blocks owned by the node are exhausted.
+func (p *clusterPool) AllocateNext() (net.IP, error) {
+	p.mutex.Lock()
+	ip := p.allocateNextLocked()
+	p.mutex.Unlock()
+	if ip != nil {
+		return ip, nil
+	}
+
+	if err := p.claimNewBlock(); err != nil {
+		return nil, err
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	// The new block may have been exhausted by concurrent allocations
+	if ip = p.allocateNextLocked(); ip == nil {
+		return nil, fmt.Errorf("no address available in the address blocks of this node")
+	}
+
+	return ip, nil
+}
+
+// Release releases ip. Blocks other than the primary block are released in
+// the background as soon as none of their addresses is allocated.
+func (p *clusterPool) Release(ip net.IP) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	i := p.findBlock(ip)
+	if i < 0 {
+		return fmt.Errorf("%s is not part of an address block owned by this node", ip)
+	}
+
+	b := p.blocks[i]
+	if err := b.Release(ip); err != nil {
+		return err
+	}
+
+	if i > 0 && b.Free() == b.Capacity() {
+		p.releaseBlock(i)
+	}
+
+	return nil
+}
+
+// Free returns the number of addresses available in the blocks owned by the
+// node
+func (p *clusterPool) Free() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	free := 0
+	for _, b := range p.blocks {
+		free += b.Free()
+	}
+	return free
+}
+
+// Capacity returns the number of addresses of all blocks owned by the node
+func (p *clusterPool) Capacity() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	capacity := 0
+	for _, b := range p.blocks {
+		capacity += b.Capacity()
+	}
+	return capacity
+}
+
+// LargestFree returns the largest number of consecutive addresses which can
+// still be allocated out of a single block owned by the node
+func (p *clusterPool) LargestFree() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	largest := 0
+	for _, b := range p.blocks {
+		if free := b.LargestFree(); free > largest {
+			largest = free
+		}
+	}
+	return largest
+}
+
+// Dump returns the list of allocated addresses of all blocks owned by the
+// node
+func (p *clusterPool) Dump() []string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	allocated := []string{}
+	for _, b := range p.blocks {
+		allocated = append(allocated, b.Dump()...)
+	}
+	return allocated
+}
+
+// watchBlocks maintains the tunnel endpoints of all blocks owned by other
+// nodes
+func (p *clusterPool) watchBlocks() {
+	for event := range p.allocator.Events {
+		p.handleBlockEvent(event)
+	}
+}
+
+func (p *clusterPool) handleBlockEvent(event allocator.AllocatorEvent) {
+	cidr := p.blockCIDR(event.ID)
+	scopedLog := log.WithField(logfields.V4Prefix, cidr)
+
+	switch event.Typ {
+	case kvstore.EventTypeCreate, kvstore.EventTypeModify:
+		key, ok := event.Key.(blockKey)
+		if !ok || key.Node == p.nodeName {
+			return
+		}
+
+		p.remoteMutex.Lock()
+		p.remoteBlocks[event.ID] = key
+		p.remoteMutex.Unlock()
+
+		scopedLog = scopedLog.WithFields(logrus.Fields{
+			logfields.NodeName: key.Node,
+			logfields.IPAddr:   key.NodeIP,
+		})
+		if err := p.setTunnel(cidr.IP, key.NodeIP); err != nil {
+			scopedLog.WithError(err).Warning("Unable to set tunnel endpoint of address block")
+		} else {
+			scopedLog.Debug("Set tunnel endpoint of address block")
+		}
+
+	case kvstore.EventTypeDelete:
+		p.remoteMutex.Lock()
+		_, ok := p.remoteBlocks[event.ID]
+		delete(p.remoteBlocks, event.ID)
+		p.remoteMutex.Unlock()
+
+		if !ok {
+			return
+		}
+
+		if err := p.deleteTunnel(cidr.IP); err != nil {
+			scopedLog.WithError(err).Warning("Unable to delete tunnel endpoint of address block")
+		} else {
+			scopedLog.Debug("Deleted tunnel endpoint of address block")
+		}
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"net"
+	"path"
+
+	"github.com/cilium/cilium/pkg/kvstore"
+	"github.com/cilium/cilium/pkg/kvstore/allocator"
+
+	. "gopkg.in/check.v1"
+)
+
+type ClusterPoolSuite struct {
+	pools []*clusterPool
+}
+
+var _ = Suite(&ClusterPoolSuite{})
+
+func (s *ClusterPoolSuite) SetUpTest(c *C) {
+	kvstore.SetupDummy(kvstore.EmbeddedBackendName)
+}
+
+func (s *ClusterPoolSuite) TearDownTest(c *C) {
+	for _, p := range s.pools {
+		p.releaseWg.Wait()
+		p.allocator.Delete()
+	}
+	s.pools = nil
+	kvstore.DeletePrefix(ClusterBlocksPath)
+	kvstore.Close()
+}
+
+func (s *ClusterPoolSuite) newPool(c *C, nodeName, nodeIP string) *clusterPool {
+	p, err := newClusterPool(mustParseCIDR(c, "10.100.0.0/24"), 28, nodeName, net.ParseIP(nodeIP).To4())
+	c.Assert(err, IsNil)
+	s.pools = append(s.pools, p)
+	return p
+}
+
+func (s *ClusterPoolSuite) TestBlockKey(c *C) {
+	key := blockKey{Node: "node1", NodeIP: net.ParseIP("192.0.2.1"), Index: 3}
+	c.Assert(key.GetKey(), Equals, "node1;192.0.2.1;3;")
+
+	k, err := blockKey{}.PutKey(key.GetKey())
+	c.Assert(err, IsNil)
+	c.Assert(k.(blockKey).Node, Equals, "node1")
+	c.Assert(k.(blockKey).NodeIP.Equal(key.NodeIP), Equals, true)
+	c.Assert(k.(blockKey).Index, Equals, 3)
+
+	for _, invalid := range []string{"", "node1;192.0.2.1;3", "node1;foo;3;", "node1;192.0.2.1;foo;"} {
+		_, err = blockKey{}.PutKey(invalid)
+		c.Assert(err, Not(IsNil), Commentf("%q", invalid))
+	}
+}
+
+func (s *ClusterPoolSuite) TestValidateClusterRange(c *C) {
+	c.Assert(validateClusterRange(mustParseCIDR(c, "10.0.0.0/8"), 24), IsNil)
+	c.Assert(validateClusterRange(mustParseCIDR(c, "10.0.0.0/8"), 8), Not(IsNil))
+	c.Assert(validateClusterRange(mustParseCIDR(c, "10.0.0.0/8"), 26), Not(IsNil))
+	c.Assert(validateClusterRange(mustParseCIDR(c, "10.0.0.0/24"), 31), Not(IsNil))
+	c.Assert(validateClusterRange(mustParseCIDR(c, "fd00::/64"), 80), Not(IsNil))
+}
+
+func (s *ClusterPoolSuite) TestBlockCIDR(c *C) {
+	p := &clusterPool{clusterRange: mustParseCIDR(c, "10.100.0.0/24"), blockMaskSize: 28}
+	c.Assert(p.blockCIDR(1).String(), Equals, "10.100.0.0/28")
+	c.Assert(p.blockCIDR(16).String(), Equals, "10.100.0.240/28")
+}
+
+func (s *ClusterPoolSuite) TestAllocate(c *C) {
+	p := s.newPool(c, "node1", "192.0.2.1")
+	c.Assert(len(p.blocks), Equals, 1)
+	primary := p.primaryBlock()
+	c.Assert(mustParseCIDR(c, "10.100.0.0/24").Contains(primary.IP), Equals, true)
+	c.Assert(p.Capacity(), Equals, 14)
+
+	allocated := []net.IP{}
+	for i := 0; i < 14; i++ {
+		ip, err := p.AllocateNext()
+		c.Assert(err, IsNil)
+		c.Assert(primary.Contains(ip), Equals, true)
+		allocated = append(allocated, ip)
+	}
+	c.Assert(p.Free(), Equals, 0)
+
+	// The primary block is exhausted, a second block is claimed
+	ip, err := p.AllocateNext()
+	c.Assert(err, IsNil)
+	c.Assert(primary.Contains(ip), Equals, false)
+	c.Assert(len(p.blocks), Equals, 2)
+	c.Assert(p.Capacity(), Equals, 28)
+	c.Assert(len(p.Dump()), Equals, 15)
+
+	// Addresses outside of the owned blocks cannot be allocated
+	c.Assert(p.Allocate(net.ParseIP("10.200.0.1")), Not(IsNil))
+
+	// The second block is released with its last address. Its index is
+	// not reused until the release has completed.
+	second := p.blocks[1]
+	c.Assert(p.Release(ip), IsNil)
+	c.Assert(len(p.blocks), Equals, 1)
+	p.mutex.Lock()
+	c.Assert(p.newBlockKey().Index, Not(Equals), second.key.Index)
+	p.mutex.Unlock()
+
+	p.releaseWg.Wait()
+	c.Assert(p.pending, HasLen, 0)
+	id, err := p.allocator.GetNoCache(second.key)
+	c.Assert(err, IsNil)
+	c.Assert(id, Equals, allocator.NoID)
+
+	// The primary block is never released
+	for _, ip := range allocated {
+		c.Assert(p.Release(ip), IsNil)
+	}
+	c.Assert(len(p.blocks), Equals, 1)
+	c.Assert(p.Free(), Equals, 14)
+}
+
+func (s *ClusterPoolSuite) TestRestore(c *C) {
+	p1 := s.newPool(c, "node1", "192.0.2.1")
+	c.Assert(p1.claimNewBlock(), IsNil)
+	c.Assert(p1.pending, HasLen, 0)
+
+	// A restarted agent claims the blocks it owned before
+	p2 := s.newPool(c, "node1", "192.0.2.1")
+	c.Assert(len(p2.blocks), Equals, 2)
+	c.Assert(p2.blocks[0].cidr.String(), Equals, p1.blocks[0].cidr.String())
+	c.Assert(p2.blocks[1].cidr.String(), Equals, p1.blocks[1].cidr.String())
+
+	// A node with a different address claims new blocks
+	p3 := s.newPool(c, "node1", "192.0.2.2")
+	c.Assert(len(p3.blocks), Equals, 1)
+	c.Assert(p3.blocks[0].cidr.String(), Not(Equals), p1.blocks[0].cidr.String())
+}
+
+func (s *ClusterPoolSuite) TestRemoteBlocks(c *C) {
+	p1 := s.newPool(c, "node1", "192.0.2.1")
+	p2 := s.newPool(c, "node2", "192.0.2.2")
+	c.Assert(p2.primaryBlock().String(), Not(Equals), p1.primaryBlock().String())
+
+	tunnels := map[string]string{}
+	p1.setTunnel = func(prefix, endpoint net.IP) error {
+		tunnels[prefix.String()] = endpoint.String()
+		return nil
+	}
+	p1.deleteTunnel = func(prefix net.IP) error {
+		delete(tunnels, prefix.String())
+		return nil
+	}
+
+	remote := p2.blocks[0]
+	p1.handleBlockEvent(allocator.AllocatorEvent{Typ: kvstore.EventTypeCreate, ID: remote.id, Key: remote.key})
+	// Blocks of the local node are not routed via the tunnel
+	local := p1.blocks[0]
+	p1.handleBlockEvent(allocator.AllocatorEvent{Typ: kvstore.EventTypeCreate, ID: local.id, Key: local.key})
+	c.Assert(tunnels, DeepEquals, map[string]string{remote.cidr.IP.String(): "192.0.2.2"})
+
+	// The blocks of a node which failed to renew its lease are released
+	// by the garbage collector
+	err := kvstore.DeletePrefix(path.Join(ClusterBlocksPath, "value", remote.key.GetKey()))
+	c.Assert(err, IsNil)
+	_, err = p1.allocator.RunGC()
+	c.Assert(err, IsNil)
+	v, err := kvstore.Get(path.Join(ClusterBlocksPath, "id", remote.id.String()))
+	c.Assert(err, IsNil)
+	c.Assert(v, IsNil)
+
+	p1.handleBlockEvent(allocator.AllocatorEvent{Typ: kvstore.EventTypeDelete, ID: remote.id})
+	c.Assert(tunnels, DeepEquals, map[string]string{})
+}
@@ -26,7 +26,6 @@ import (
 	"github.com/containernetworking/cni/plugins/ipam/host-local/backend/allocator"
 	"github.com/sirupsen/logrus"
 	"github.com/vishvananda/netlink"
-	"k8s.io/kubernetes/pkg/registry/core/service/ipallocator"
 )
 
 var (
@@ -80,7 +79,7 @@ func reserveLocalRoutes(ipam *Config) {
 			}).Info("Marking local route as no-alloc in node allocation prefix")
 
 			for ip := r.Dst.IP.Mask(r.Dst.Mask); r.Dst.Contains(ip); nextIP(ip) {
-				ipam.IPv4Allocator.Allocate(ip)
+				ipam.defaultPool().IPv4Allocator.Allocate(ip)
 			}
 		}
 	}
@@ -115,13 +114,22 @@ func Init() error {
 				},
 			},
 		},
-		IPv6Allocator: ipallocator.NewCIDRRange(node.GetIPv6AllocRange()),
+		allocations: map[string]*Allocation{},
+		restored:    map[string]*Allocation{},
 	}
 
 	// Since docker doesn't support IPv6 only and there's always an IPv4
 	// address we can set up ipam for IPv4. More info:
 	// https://github.com/docker/libnetwork/pull/826
-	ipamConf.IPv4Allocator = ipallocator.NewCIDRRange(node.GetIPv4AllocRange())
+	defaultPool := newPool(DefaultPool, node.GetIPv4AllocRange(), node.GetIPv6AllocRange())
+	if clusterIPv4Pool != nil {
+		// IPv4 addresses are allocated out of the blocks claimed
+		// from the cluster range, the node allocation range is the
+		// primary block
+		defaultPool.IPv4Range = clusterIPv4Pool.clusterRange
+		defaultPool.IPv4Allocator = clusterIPv4Pool
+	}
+	ipamConf.pools = map[string]*Pool{DefaultPool: defaultPool}
 	ipamConf.IPAMConfig.Routes = append(ipamConf.IPAMConfig.Routes,
 		// IPv4
 		cniTypes.Route{
@@ -138,13 +146,13 @@ func Init() error {
 	allocRange := node.GetIPv4AllocRange()
 	nodeIP := node.GetExternalIPv4()
 	if allocRange.Contains(nodeIP) {
-		err := ipamConf.IPv4Allocator.Allocate(nodeIP)
+		err := defaultPool.IPv4Allocator.Allocate(nodeIP)
 		if err != nil {
 			log.WithError(err).WithField(logfields.IPAddr, nodeIP).Debug("Unable to reserve IPv4 router address")
 		}
 	}
 
-	internalIP, err := ipamConf.IPv4Allocator.AllocateNext()
+	internalIP, err := defaultPool.IPv4Allocator.AllocateNext()
 	if err != nil {
 		return fmt.Errorf("Unable to allocate internal IPv4 node IP: %s", err)
 	}
@@ -157,14 +165,14 @@ func Init() error {
 	allocRange = node.GetIPv6AllocRange()
 	for _, ip6 := range []net.IP{node.GetIPv6()} {
 		if allocRange.Contains(ip6) {
-			err := ipamConf.IPv6Allocator.Allocate(ip6)
+			err := defaultPool.IPv6Allocator.Allocate(ip6)
 			if err != nil {
 				log.WithError(err).WithField(logfields.IPAddr, ip6).Debug("Unable to reserve IPv6 address")
 			}
 		}
 	}
 
-	routerIP, err := ipamConf.IPv6Allocator.AllocateNext()
+	routerIP, err := defaultPool.IPv6Allocator.AllocateNext()
 	if err != nil {
 		return fmt.Errorf("Unable to allocate IPv6 router IP: %s", err)
 	}
@@ -50,15 +50,15 @@ func (s *IPAMSuite) TestLock(c *C) {
 	c.Assert(err, IsNil)
 
 	// Forcefully release possible allocated IPs
-	err = ipamConf.IPv4Allocator.Release(epipv4.IP())
+	err = ipamConf.defaultPool().IPv4Allocator.Release(epipv4.IP())
 	c.Assert(err, IsNil)
-	err = ipamConf.IPv6Allocator.Release(epipv6.IP())
+	err = ipamConf.defaultPool().IPv6Allocator.Release(epipv6.IP())
 	c.Assert(err, IsNil)
 
 	// Let's allocate the IP first so we can see the tests failing
-	err = ipamConf.IPv4Allocator.Allocate(epipv4.IP())
+	err = ipamConf.defaultPool().IPv4Allocator.Allocate(epipv4.IP())
 	c.Assert(err, IsNil)
 
-	err = ipamConf.IPv4Allocator.Release(epipv4.IP())
+	err = ipamConf.defaultPool().IPv4Allocator.Release(epipv4.IP())
 	c.Assert(err, IsNil)
 }
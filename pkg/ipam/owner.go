@@ -0,0 +1,68 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	// OwnerHealth is the owner of the addresses of the cilium-health
+	// endpoint
+	OwnerHealth = "health"
+
+	// OwnerLoopback is the owner of the IPv4 service loopback address
+	OwnerLoopback = "loopback"
+
+	ownerPrefixContainer = "container:"
+	ownerPrefixEndpoint  = "endpoint:"
+)
+
+// ContainerOwner returns the owner of an address allocated for the
+// container with the given ID
+func ContainerOwner(containerID string) string {
+	return ownerPrefixContainer + containerID
+}
+
+// EndpointOwner returns the owner of an address used by the endpoint with
+// the given ID
+func EndpointOwner(id uint16) string {
+	return ownerPrefixEndpoint + strconv.FormatUint(uint64(id), 10)
+}
+
+// OwnerContainerID returns the container ID of an owner returned by
+// ContainerOwner or an empty string if the owner is not a container
+func OwnerContainerID(owner string) string {
+	if !strings.HasPrefix(owner, ownerPrefixContainer) {
+		return ""
+	}
+	return strings.TrimPrefix(owner, ownerPrefixContainer)
+}
+
+// OwnerEndpointID returns the endpoint ID of an owner returned by
+// EndpointOwner. ok is false if the owner is not an endpoint.
+func OwnerEndpointID(owner string) (id uint16, ok bool) {
+	if !strings.HasPrefix(owner, ownerPrefixEndpoint) {
+		return 0, false
+	}
+
+	n, err := strconv.ParseUint(strings.TrimPrefix(owner, ownerPrefixEndpoint), 10, 16)
+	if err != nil {
+		return 0, false
+	}
+
+	return uint16(n), true
+}
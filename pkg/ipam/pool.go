@@ -0,0 +1,229 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/cilium/cilium/api/v1/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultPool is the name of the pool allocating from the node
+	// allocation ranges. It is used if no pool is requested.
+	DefaultPool = "default"
+
+	// maxPoolHostBits is the maximum number of host bits of a pool range
+	// supported by the allocator
+	maxPoolHostBits = 30
+)
+
+// newPool returns a new pool allocating from the given ranges. Either range
+// may be nil.
+func newPool(name string, ipv4Range, ipv6Range *net.IPNet) *Pool {
+	p := &Pool{
+		Name:      name,
+		IPv4Range: ipv4Range,
+		IPv6Range: ipv6Range,
+	}
+
+	if ipv4Range != nil {
+		p.IPv4Allocator = newCIDRRange(ipv4Range)
+	}
+
+	if ipv6Range != nil {
+		p.IPv6Allocator = newCIDRRange(ipv6Range)
+	}
+
+	return p
+}
+
+// ranges returns all ranges of the pool
+func (p *Pool) ranges() []*net.IPNet {
+	ranges := []*net.IPNet{}
+	for _, r := range []*net.IPNet{p.IPv4Range, p.IPv6Range} {
+		if r != nil {
+			ranges = append(ranges, r)
+		}
+	}
+	return ranges
+}
+
+// contains returns true if ip is part of one of the ranges of the pool
+func (p *Pool) contains(ip net.IP) bool {
+	for _, r := range p.ranges() {
+		if r.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultPool returns the default pool
+func (c *Config) defaultPool() *Pool {
+	return c.pools[DefaultPool]
+}
+
+// poolOf returns the pool whose ranges contain ip. The default pool is
+// returned if no pool contains ip. Must be called with allocatorMutex held.
+func (c *Config) poolOf(ip net.IP) *Pool {
+	for _, p := range c.pools {
+		if p.Name != DefaultPool && p.contains(ip) {
+			return p
+		}
+	}
+	return c.defaultPool()
+}
+
+// ParsePoolRanges parses a comma separated list of at most one IPv4 and one
+// IPv6 CIDR
+func ParsePoolRanges(value string) (ipv4Range, ipv6Range *net.IPNet, err error) {
+	for _, s := range strings.Split(value, ",") {
+		_, cidr, err := net.ParseCIDR(strings.TrimSpace(s))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if cidr.IP.To4() != nil {
+			if ipv4Range != nil {
+				return nil, nil, fmt.Errorf("more than one IPv4 range specified")
+			}
+			ipv4Range = cidr
+		} else {
+			if ipv6Range != nil {
+				return nil, nil, fmt.Errorf("more than one IPv6 range specified")
+			}
+			ipv6Range = cidr
+		}
+	}
+
+	return ipv4Range, ipv6Range, nil
+}
+
+func overlaps(a, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
+
+// AddPool adds a named pool allocating from the given ranges. At least one
+// range must be specified. The ranges must not overlap with the ranges of
+// any other pool.
+func AddPool(name string, ipv4Range, ipv6Range *net.IPNet) error {
+	if name == "" {
+		return fmt.Errorf("pool name must not be empty")
+	}
+
+	if ipv4Range == nil && ipv6Range == nil {
+		return fmt.Errorf("pool %s has no range", name)
+	}
+
+	for _, r := range []*net.IPNet{ipv4Range, ipv6Range} {
+		if r == nil {
+			continue
+		}
+
+		ones, bits := r.Mask.Size()
+		if bits-ones > maxPoolHostBits {
+			return fmt.Errorf("range %s of pool %s is too large, at most %d host bits are supported",
+				r, name, maxPoolHostBits)
+		}
+	}
+
+	ipamConf.allocatorMutex.Lock()
+	defer ipamConf.allocatorMutex.Unlock()
+
+	if _, ok := ipamConf.pools[name]; ok {
+		return fmt.Errorf("pool %s already exists", name)
+	}
+
+	for _, other := range ipamConf.pools {
+		for _, r := range other.ranges() {
+			for _, n := range []*net.IPNet{ipv4Range, ipv6Range} {
+				if n != nil && overlaps(r, n) {
+					return fmt.Errorf("range %s of pool %s overlaps with range %s of pool %s",
+						n, name, r, other.Name)
+				}
+			}
+		}
+	}
+
+	ipamConf.pools[name] = newPool(name, ipv4Range, ipv6Range)
+
+	log.WithFields(logrus.Fields{
+		"pool":      name,
+		"ipv4Range": ipv4Range,
+		"ipv6Range": ipv6Range,
+	}).Info("Added IPAM pool")
+
+	return nil
+}
+
+// PoolExists returns true if a pool with the given name exists
+func PoolExists(name string) bool {
+	ipamConf.allocatorMutex.RLock()
+	_, ok := ipamConf.pools[name]
+	ipamConf.allocatorMutex.RUnlock()
+	return ok
+}
+
+func newPoolRangeModel(r *net.IPNet, alloc rangeAllocator) *models.IPAMPoolRange {
+	if r == nil || alloc == nil {
+		return nil
+	}
+
+	capacity := int64(alloc.Capacity())
+	free := int64(alloc.Free())
+
+	return &models.IPAMPoolRange{
+		Cidr:          r.String(),
+		Capacity:      capacity,
+		Used:          capacity - free,
+		Free:          free,
+		Fragmentation: fragmentation(alloc),
+	}
+}
+
+// fragmentation returns the fraction of the free addresses of alloc which
+// are not part of the largest range of consecutive free addresses
+func fragmentation(alloc rangeAllocator) float64 {
+	free := alloc.Free()
+	if free == 0 {
+		return 0
+	}
+	return 1 - float64(alloc.LargestFree())/float64(free)
+}
+
+// GetPoolsModel returns the API model of all pools sorted by name
+func GetPoolsModel() []*models.IPAMPool {
+	ipamConf.allocatorMutex.RLock()
+	defer ipamConf.allocatorMutex.RUnlock()
+
+	pools := make([]*models.IPAMPool, 0, len(ipamConf.pools))
+	for _, p := range ipamConf.pools {
+		pools = append(pools, &models.IPAMPool{
+			Name: p.Name,
+			IPV4: newPoolRangeModel(p.IPv4Range, p.IPv4Allocator),
+			IPV6: newPoolRangeModel(p.IPv6Range, p.IPv6Allocator),
+		})
+	}
+
+	sort.Slice(pools, func(i, j int) bool { return pools[i].Name < pools[j].Name })
+
+	return pools
+}
@@ -0,0 +1,144 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"net"
+
+	"github.com/cilium/cilium/pkg/node"
+
+	. "gopkg.in/check.v1"
+)
+
+func mustParseCIDR(c *C, s string) *net.IPNet {
+	_, cidr, err := net.ParseCIDR(s)
+	c.Assert(err, IsNil)
+	return cidr
+}
+
+func (s *IPAMSuite) TestParsePoolRanges(c *C) {
+	v4, v6, err := ParsePoolRanges("192.168.10.0/24")
+	c.Assert(err, IsNil)
+	c.Assert(v4.String(), Equals, "192.168.10.0/24")
+	c.Assert(v6, IsNil)
+
+	v4, v6, err = ParsePoolRanges("fd00:10::/112, 192.168.10.0/24")
+	c.Assert(err, IsNil)
+	c.Assert(v4.String(), Equals, "192.168.10.0/24")
+	c.Assert(v6.String(), Equals, "fd00:10::/112")
+
+	_, _, err = ParsePoolRanges("192.168.10.0/24,192.168.11.0/24")
+	c.Assert(err, Not(IsNil))
+
+	_, _, err = ParsePoolRanges("fd00:10::/112,fd00:11::/112")
+	c.Assert(err, Not(IsNil))
+
+	_, _, err = ParsePoolRanges("foo")
+	c.Assert(err, Not(IsNil))
+}
+
+func (s *IPAMSuite) TestAddPool(c *C) {
+	node.InitDefaultPrefix("")
+	c.Assert(Init(), IsNil)
+
+	c.Assert(AddPool("", mustParseCIDR(c, "192.168.10.0/24"), nil), Not(IsNil))
+	c.Assert(AddPool("empty", nil, nil), Not(IsNil))
+	c.Assert(AddPool("large", nil, mustParseCIDR(c, "fd00:10::/64")), Not(IsNil))
+
+	c.Assert(AddPool("blue", mustParseCIDR(c, "192.168.10.0/24"), nil), IsNil)
+	c.Assert(PoolExists("blue"), Equals, true)
+	c.Assert(PoolExists("green"), Equals, false)
+
+	// Duplicate name
+	c.Assert(AddPool("blue", mustParseCIDR(c, "192.168.11.0/24"), nil), Not(IsNil))
+	// Overlap with pool blue
+	c.Assert(AddPool("green", mustParseCIDR(c, "192.168.10.128/25"), nil), Not(IsNil))
+	// Overlap with the default pool
+	c.Assert(AddPool("green", nil, node.GetIPv6AllocRange()), Not(IsNil))
+
+	pools := GetPoolsModel()
+	c.Assert(len(pools), Equals, 2)
+	c.Assert(pools[0].Name, Equals, "blue")
+	c.Assert(pools[0].IPV4.Cidr, Equals, "192.168.10.0/24")
+	c.Assert(pools[0].IPV4.Capacity, Equals, int64(254))
+	c.Assert(pools[0].IPV6, IsNil)
+	c.Assert(pools[1].Name, Equals, DefaultPool)
+}
+
+func (s *IPAMSuite) TestAllocateNextFromPool(c *C) {
+	node.InitDefaultPrefix("")
+	c.Assert(Init(), IsNil)
+
+	blue := mustParseCIDR(c, "192.168.10.0/24")
+	c.Assert(AddPool("blue", blue, nil), IsNil)
+
+	_, _, err := AllocateNextFromPool("unknown", "", "")
+	c.Assert(err, Not(IsNil))
+
+	ipv4, ipv6, err := AllocateNextFromPool("blue", "", "")
+	c.Assert(err, IsNil)
+	c.Assert(blue.Contains(ipv4), Equals, true)
+	// The pool has no IPv6 range, the address is taken from the default pool
+	c.Assert(node.GetIPv6AllocRange().Contains(ipv6), Equals, true)
+
+	pools := GetPoolsModel()
+	c.Assert(pools[0].Name, Equals, "blue")
+	c.Assert(pools[0].IPV4.Used, Equals, int64(1))
+
+	// The address is released to the pool containing it
+	c.Assert(ReleaseIP(ipv4), IsNil)
+	c.Assert(ReleaseIP(ipv6), IsNil)
+	c.Assert(GetPoolsModel()[0].IPV4.Used, Equals, int64(0))
+
+	// Explicit allocation uses the pool containing the address
+	c.Assert(AllocateIP(ipv4, ""), IsNil)
+	c.Assert(GetPoolsModel()[0].IPV4.Used, Equals, int64(1))
+	c.Assert(ReleaseIP(ipv4), IsNil)
+}
+
+func (s *IPAMSuite) TestLargestFreeRun(c *C) {
+	c.Assert(largestFreeRun(nil, 10), Equals, 10)
+	c.Assert(largestFreeRun([]byte{0xff}, 8), Equals, 0)
+	// Bits 0 and 4 set
+	c.Assert(largestFreeRun([]byte{0x11}, 8), Equals, 3)
+	// Bit 9 set, the first byte holds the most significant bits
+	c.Assert(largestFreeRun([]byte{0x02, 0x00}, 16), Equals, 9)
+	c.Assert(largestFreeRun([]byte{0x02, 0x00}, 12), Equals, 9)
+}
+
+func (s *IPAMSuite) TestPoolFragmentation(c *C) {
+	node.InitDefaultPrefix("")
+	c.Assert(Init(), IsNil)
+
+	blue := mustParseCIDR(c, "192.168.10.0/29")
+	c.Assert(AddPool("blue", blue, nil), IsNil)
+
+	pool := GetPoolsModel()[0]
+	c.Assert(pool.IPV4.Capacity, Equals, int64(6))
+	c.Assert(pool.IPV4.Free, Equals, int64(6))
+	c.Assert(pool.IPV4.Fragmentation, Equals, float64(0))
+
+	// Allocating an address in the middle splits the free addresses
+	c.Assert(AllocateIP(net.ParseIP("192.168.10.3"), ""), IsNil)
+	pool = GetPoolsModel()[0]
+	c.Assert(pool.IPV4.Used, Equals, int64(1))
+	c.Assert(pool.IPV4.Free, Equals, int64(5))
+	c.Assert(pool.IPV4.Fragmentation, Equals, 1-float64(3)/float64(5))
+
+	a := GetAllocation(net.ParseIP("192.168.10.3"))
+	c.Assert(a, Not(IsNil))
+	c.Assert(a.Pool, Equals, "blue")
+	c.Assert(GetAllocation(net.ParseIP("192.168.10.4")), IsNil)
+}
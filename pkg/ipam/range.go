@@ -0,0 +1,91 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"math/big"
+	"net"
+
+	k8sAPI "k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/registry/core/service/ipallocator"
+)
+
+// cidrRange allocates the addresses of a single CIDR. The first and the last
+// address of the CIDR are never allocated.
+type cidrRange struct {
+	*ipallocator.Range
+	cidr *net.IPNet
+}
+
+func newCIDRRange(cidr *net.IPNet) *cidrRange {
+	return &cidrRange{
+		Range: ipallocator.NewCIDRRange(cidr),
+		cidr:  cidr,
+	}
+}
+
+// Capacity returns the number of addresses in the range which can be
+// allocated
+func (r *cidrRange) Capacity() int {
+	capacity := ipallocator.RangeSize(r.cidr) - 2
+	if capacity < 0 {
+		return 0
+	}
+	return int(capacity)
+}
+
+// LargestFree returns the largest number of consecutive addresses which can
+// still be allocated
+func (r *cidrRange) LargestFree() int {
+	ral := k8sAPI.RangeAllocation{}
+	r.Snapshot(&ral)
+	return largestFreeRun(ral.Data, r.Capacity())
+}
+
+// largestFreeRun returns the length of the longest run of unset bits among
+// the first n bits of the big-endian bitmap data
+func largestFreeRun(data []byte, n int) int {
+	largest, run := 0, 0
+	for i := 0; i < n; i++ {
+		idx := len(data) - 1 - i/8
+		if idx >= 0 && data[idx]&(1<<uint(i%8)) != 0 {
+			run = 0
+			continue
+		}
+
+		run++
+		if run > largest {
+			largest = run
+		}
+	}
+	return largest
+}
+
+// Dump returns the list of allocated addresses
+func (r *cidrRange) Dump() []string {
+	allocated := []string{}
+
+	ral := k8sAPI.RangeAllocation{}
+	r.Snapshot(&ral)
+	origIP := big.NewInt(0).SetBytes(r.cidr.IP)
+	bits := big.NewInt(0).SetBytes(ral.Data)
+	for i := 0; i < bits.BitLen(); i++ {
+		if bits.Bit(i) != 0 {
+			allocated = append(allocated, net.IP(big.NewInt(0).Add(origIP, big.NewInt(int64(uint(i+1)))).Bytes()).String())
+		}
+	}
+
+	return allocated
+}
@@ -0,0 +1,306 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/cilium/cilium/pkg/logging/logfields"
+
+	"github.com/sirupsen/logrus"
+)
+
+// StateFileName is the name of the file in the state directory the
+// allocations are persisted to
+const StateFileName = "ipam.json"
+
+// stateFile is the content of the state file
+type stateFile struct {
+	Allocations []*Allocation `json:"allocations"`
+}
+
+// allocatorOf returns the allocator of the pool containing ip or nil if
+// the address family is disabled. Must be called with allocatorMutex held.
+func (c *Config) allocatorOf(ip net.IP) rangeAllocator {
+	pool := c.poolOf(ip)
+	if ip.To4() != nil {
+		return pool.IPv4Allocator
+	}
+	return pool.IPv6Allocator
+}
+
+// recordLocked records the allocation of ip by owner. Must be called with
+// allocatorMutex held.
+func (c *Config) recordLocked(ip net.IP, owner string) {
+	c.allocations[ip.String()] = &Allocation{
+		IP:    ip,
+		Owner: owner,
+		Pool:  c.poolOf(ip).Name,
+		Time:  time.Now(),
+	}
+}
+
+// forgetLocked removes the allocation record of ip. Must be called with
+// allocatorMutex held.
+func (c *Config) forgetLocked(ip net.IP) {
+	delete(c.allocations, ip.String())
+	delete(c.restored, ip.String())
+}
+
+// adoptRestoredLocked records owner as the owner of ip if ip has been
+// reserved for a previous run and returns true in that case. Must be called
+// with allocatorMutex held.
+func (c *Config) adoptRestoredLocked(ip net.IP, owner string) bool {
+	a, ok := c.restored[ip.String()]
+	if !ok {
+		return false
+	}
+
+	// Restored allocations are never modified as ReconcileState accesses
+	// them without holding the mutex
+	alloc := *a
+	alloc.Owner = owner
+	delete(c.restored, ip.String())
+	c.allocations[ip.String()] = &alloc
+	c.saveLocked()
+
+	return true
+}
+
+// sortAllocations sorts allocations by address, IPv4 addresses first
+func sortAllocations(allocations []*Allocation) {
+	sort.Slice(allocations, func(i, j int) bool {
+		a, b := allocations[i].IP, allocations[j].IP
+		if (a.To4() != nil) != (b.To4() != nil) {
+			return a.To4() != nil
+		}
+		return bytes.Compare(a.To16(), b.To16()) < 0
+	})
+}
+
+// allAllocationsLocked returns copies of all allocations including the ones
+// restored from a previous run, sorted by address. Must be called with
+// allocatorMutex held.
+func (c *Config) allAllocationsLocked() []*Allocation {
+	allocations := make([]*Allocation, 0, len(c.allocations)+len(c.restored))
+	for _, m := range []map[string]*Allocation{c.allocations, c.restored} {
+		for _, a := range m {
+			alloc := *a
+			allocations = append(allocations, &alloc)
+		}
+	}
+	sortAllocations(allocations)
+	return allocations
+}
+
+// saveLocked persists all allocations to the state file. Addresses restored
+// from a previous run remain part of the state until they have been
+// reconciled. Must be called with allocatorMutex held.
+func (c *Config) saveLocked() {
+	if c.statePath == "" {
+		return
+	}
+
+	scopedLog := log.WithField(logfields.Path, c.statePath)
+
+	content, err := json.MarshalIndent(&stateFile{Allocations: c.allAllocationsLocked()}, "", "  ")
+	if err != nil {
+		scopedLog.WithError(err).Warn("Unable to encode IPAM state")
+		return
+	}
+
+	// Write to a temporary file first so a crash never leaves a
+	// partially written state file behind
+	tmpPath := c.statePath + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, content, 0600); err != nil {
+		scopedLog.WithError(err).Warn("Unable to write IPAM state")
+		return
+	}
+
+	if err := os.Rename(tmpPath, c.statePath); err != nil {
+		scopedLog.WithError(err).Warn("Unable to write IPAM state")
+	}
+}
+
+// readState reads the allocations persisted in the state file at path
+func readState(path string) ([]*Allocation, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	state := stateFile{}
+	if err := json.Unmarshal(content, &state); err != nil {
+		return nil, err
+	}
+
+	return state.Allocations, nil
+}
+
+// InitState persists all allocations to the state file at path. If restore
+// is true, the allocations persisted by a previous run are read from the
+// file first. Restored addresses remain reserved until ReconcileState is
+// called unless they are allocated or released again in the meantime.
+func InitState(path string, restore bool) error {
+	ipamConf.allocatorMutex.Lock()
+	defer ipamConf.allocatorMutex.Unlock()
+
+	ipamConf.statePath = path
+
+	var err error
+	if restore {
+		var allocations []*Allocation
+		allocations, err = readState(path)
+		if os.IsNotExist(err) {
+			err = nil
+		}
+
+		for _, a := range allocations {
+			if a.IP == nil {
+				continue
+			}
+
+			scopedLog := log.WithFields(logrus.Fields{
+				logfields.IPAddr: a.IP,
+				"owner":          a.Owner,
+			})
+
+			if _, ok := ipamConf.allocations[a.IP.String()]; ok {
+				scopedLog.Debug("Address of previous run has already been allocated again")
+				continue
+			}
+
+			allocator := ipamConf.allocatorOf(a.IP)
+			if allocator == nil {
+				scopedLog.Warn("Unable to restore IPAM allocation: address family disabled")
+				continue
+			}
+
+			if err := allocator.Allocate(a.IP); err != nil {
+				scopedLog.WithError(err).Warn("Unable to restore IPAM allocation")
+				continue
+			}
+
+			a.Pool = ipamConf.poolOf(a.IP).Name
+			ipamConf.restored[a.IP.String()] = a
+		}
+	}
+
+	ipamConf.saveLocked()
+
+	return err
+}
+
+// ReconcileState reconciles all addresses restored by InitState which have
+// not been allocated or released since. keep is called for each of them and
+// decides whether the address remains allocated on behalf of its previous
+// owner or whether it is released. The list of released allocations is
+// returned.
+func ReconcileState(keep func(a *Allocation) bool) []*Allocation {
+	ipamConf.allocatorMutex.RLock()
+	restored := make([]*Allocation, 0, len(ipamConf.restored))
+	for _, a := range ipamConf.restored {
+		restored = append(restored, a)
+	}
+	ipamConf.allocatorMutex.RUnlock()
+
+	sortAllocations(restored)
+
+	// keep may query the container runtime, do not hold the mutex
+	verdicts := make(map[*Allocation]bool, len(restored))
+	for _, a := range restored {
+		verdicts[a] = keep(a)
+	}
+
+	ipamConf.allocatorMutex.Lock()
+	defer ipamConf.allocatorMutex.Unlock()
+
+	released := []*Allocation{}
+	for _, a := range restored {
+		// The address may have been taken over or released while
+		// the mutex was not held
+		if ipamConf.restored[a.IP.String()] != a {
+			continue
+		}
+
+		delete(ipamConf.restored, a.IP.String())
+
+		if verdicts[a] {
+			ipamConf.allocations[a.IP.String()] = a
+			continue
+		}
+
+		if allocator := ipamConf.allocatorOf(a.IP); allocator != nil {
+			if err := allocator.Release(a.IP); err != nil {
+				log.WithError(err).WithField(logfields.IPAddr, a.IP).Warn("Unable to release restored IPAM allocation")
+			}
+		}
+
+		released = append(released, a)
+	}
+
+	ipamConf.saveLocked()
+
+	return released
+}
+
+// SetOwner changes the owner of the allocated address ip. Nothing is done
+// if the address has not been allocated.
+func SetOwner(ip net.IP, owner string) {
+	ipamConf.allocatorMutex.Lock()
+	defer ipamConf.allocatorMutex.Unlock()
+
+	if ipamConf.adoptRestoredLocked(ip, owner) {
+		return
+	}
+
+	if a, ok := ipamConf.allocations[ip.String()]; ok && a.Owner != owner {
+		a.Owner = owner
+		ipamConf.saveLocked()
+	}
+}
+
+// GetAllocations returns all allocated addresses sorted by address,
+// including the addresses of a previous run which have not been reconciled
+// yet. Addresses reserved internally, e.g. the router address, are not
+// included.
+func GetAllocations() []*Allocation {
+	ipamConf.allocatorMutex.RLock()
+	defer ipamConf.allocatorMutex.RUnlock()
+
+	return ipamConf.allAllocationsLocked()
+}
+
+// GetAllocation returns the allocation of ip or nil if the address has not
+// been allocated
+func GetAllocation(ip net.IP) *Allocation {
+	ipamConf.allocatorMutex.RLock()
+	defer ipamConf.allocatorMutex.RUnlock()
+
+	for _, m := range []map[string]*Allocation{ipamConf.allocations, ipamConf.restored} {
+		if a, ok := m[ip.String()]; ok {
+			alloc := *a
+			return &alloc
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/cilium/cilium/pkg/node"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *IPAMSuite) TestOwner(c *C) {
+	c.Assert(OwnerContainerID(ContainerOwner("abcd")), Equals, "abcd")
+	c.Assert(OwnerContainerID(EndpointOwner(10)), Equals, "")
+	c.Assert(OwnerContainerID(OwnerHealth), Equals, "")
+
+	id, ok := OwnerEndpointID(EndpointOwner(10))
+	c.Assert(ok, Equals, true)
+	c.Assert(id, Equals, uint16(10))
+
+	_, ok = OwnerEndpointID(ContainerOwner("10"))
+	c.Assert(ok, Equals, false)
+	_, ok = OwnerEndpointID("endpoint:foo")
+	c.Assert(ok, Equals, false)
+}
+
+func (s *IPAMSuite) TestStateRestore(c *C) {
+	dir, err := ioutil.TempDir("", "cilium-ipam-state")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, StateFileName)
+
+	node.InitDefaultPrefix("")
+	c.Assert(Init(), IsNil)
+	c.Assert(InitState(path, true), IsNil)
+
+	running4, running6, err := AllocateNext("", ContainerOwner("running"))
+	c.Assert(err, IsNil)
+	stopped4, _, err := AllocateNext("ipv4", ContainerOwner("stopped"))
+	c.Assert(err, IsNil)
+	ep4, _, err := AllocateNext("ipv4", "")
+	c.Assert(err, IsNil)
+	SetOwner(ep4, EndpointOwner(10))
+	health4, _, err := AllocateNext("ipv4", OwnerHealth)
+	c.Assert(err, IsNil)
+	released4, _, err := AllocateNext("ipv4", ContainerOwner("released"))
+	c.Assert(err, IsNil)
+	c.Assert(ReleaseIP(released4), IsNil)
+
+	byOwner := func(allocations []*Allocation) map[string]*Allocation {
+		m := map[string]*Allocation{}
+		for _, a := range allocations {
+			m[a.Owner+"/"+a.IP.String()] = a
+		}
+		return m
+	}
+
+	allocations, err := readState(path)
+	c.Assert(err, IsNil)
+	c.Assert(allocations, HasLen, 5)
+	persisted := byOwner(allocations)
+	a, ok := persisted[ContainerOwner("running")+"/"+running4.String()]
+	c.Assert(ok, Equals, true)
+	c.Assert(a.Pool, Equals, DefaultPool)
+	c.Assert(persisted[ContainerOwner("running")+"/"+running6.String()], Not(IsNil))
+	c.Assert(persisted[EndpointOwner(10)+"/"+ep4.String()], Not(IsNil))
+	c.Assert(persisted[ContainerOwner("released")+"/"+released4.String()], IsNil)
+
+	// Simulate a restart of the agent
+	c.Assert(Init(), IsNil)
+	c.Assert(InitState(path, true), IsNil)
+	c.Assert(GetAllocations(), HasLen, 5)
+
+	// Restored addresses are reserved until reconciled
+	for _, ip := range []net.IP{running4, running6, stopped4, ep4, health4} {
+		c.Assert(ipamConf.allocatorOf(ip).Allocate(ip), Not(IsNil))
+	}
+
+	// A restored endpoint takes over its address
+	c.Assert(AllocateIP(ep4, EndpointOwner(10)), IsNil)
+
+	kept := map[string]bool{}
+	released := ReconcileState(func(a *Allocation) bool {
+		kept[a.IP.String()] = true
+		return a.Owner == ContainerOwner("running")
+	})
+	c.Assert(kept, HasLen, 4)
+	c.Assert(kept[ep4.String()], Equals, false)
+	c.Assert(released, HasLen, 2)
+	c.Assert(byOwner(released)[ContainerOwner("stopped")+"/"+stopped4.String()], Not(IsNil))
+	c.Assert(byOwner(released)[OwnerHealth+"/"+health4.String()], Not(IsNil))
+
+	// Released addresses can be allocated again
+	c.Assert(AllocateIP(stopped4, ContainerOwner("new")), IsNil)
+	c.Assert(AllocateIP(health4, OwnerHealth), IsNil)
+
+	allocations = GetAllocations()
+	c.Assert(allocations, HasLen, 5)
+	current := byOwner(allocations)
+	c.Assert(current[ContainerOwner("running")+"/"+running4.String()], Not(IsNil))
+	c.Assert(current[ContainerOwner("running")+"/"+running6.String()], Not(IsNil))
+	c.Assert(current[EndpointOwner(10)+"/"+ep4.String()], Not(IsNil))
+	c.Assert(current[ContainerOwner("new")+"/"+stopped4.String()], Not(IsNil))
+	c.Assert(current[OwnerHealth+"/"+health4.String()], Not(IsNil))
+
+	// IPv4 addresses are sorted first
+	c.Assert(allocations[4].IP.Equal(running6), Equals, true)
+
+	allocations, err = readState(path)
+	c.Assert(err, IsNil)
+	c.Assert(byOwner(allocations), HasLen, 5)
+	for key, a := range byOwner(allocations) {
+		c.Assert(current[key], Not(IsNil))
+		c.Assert(a.Time.Equal(current[key].Time), Equals, true)
+	}
+}
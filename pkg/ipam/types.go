@@ -15,18 +15,95 @@
 package ipam
 
 import (
+	"net"
+	"time"
+
 	"github.com/cilium/cilium/pkg/lock"
 
 	"github.com/containernetworking/cni/plugins/ipam/host-local/backend/allocator"
-	"k8s.io/kubernetes/pkg/registry/core/service/ipallocator"
 )
 
 // Config is the IPAM configuration used for a particular IPAM type.
 type Config struct {
-	IPAMConfig    allocator.IPAMConfig
-	IPv6Allocator *ipallocator.Range
-	IPv4Allocator *ipallocator.Range
+	IPAMConfig allocator.IPAMConfig
+
+	// pools is the list of address pools indexed by name. The pool
+	// DefaultPool always exists and allocates from the node allocation
+	// ranges.
+	pools map[string]*Pool
+
+	// allocations is the list of allocated addresses indexed by the
+	// string representation of the address
+	allocations map[string]*Allocation
+
+	// restored is the list of addresses allocated by a previous run of
+	// the agent which are reserved until they have been reconciled
+	restored map[string]*Allocation
+
+	// statePath is the path of the file allocations are persisted to or
+	// an empty string if allocations are not persisted
+	statePath string
 
 	// mutex covers access to all members of this struct
 	allocatorMutex lock.RWMutex
 }
+
+// Pool is a named pool of IPv4 and IPv6 addresses. A pool does not need to
+// provide addresses of both families. Addresses of a family not provided by
+// a pool are allocated from the default pool.
+type Pool struct {
+	// Name is the name of the pool
+	Name string
+
+	// IPv4Range is the range IPv4 addresses are allocated from or nil
+	IPv4Range *net.IPNet
+
+	// IPv6Range is the range IPv6 addresses are allocated from or nil
+	IPv6Range *net.IPNet
+
+	IPv4Allocator rangeAllocator
+	IPv6Allocator rangeAllocator
+}
+
+// Allocation is an allocated address and its owner
+type Allocation struct {
+	// IP is the allocated address
+	IP net.IP `json:"ip"`
+
+	// Owner is the owner of the address as returned by ContainerOwner,
+	// EndpointOwner or one of the Owner constants. It is empty if the
+	// owner is unknown.
+	Owner string `json:"owner,omitempty"`
+
+	// Pool is the name of the pool the address has been allocated from
+	Pool string `json:"pool"`
+
+	// Time is the time the address has been allocated
+	Time time.Time `json:"time"`
+}
+
+// rangeAllocator allocates addresses out of the range of a pool
+type rangeAllocator interface {
+	// Allocate allocates a specific address
+	Allocate(ip net.IP) error
+
+	// AllocateNext allocates the next available address
+	AllocateNext() (net.IP, error)
+
+	// Release releases an address
+	Release(ip net.IP) error
+
+	// Free returns the number of addresses which can still be allocated
+	Free() int
+
+	// Capacity returns the total number of addresses which can be
+	// allocated
+	Capacity() int
+
+	// LargestFree returns the largest number of consecutive addresses
+	// which can still be allocated
+	LargestFree() int
+
+	// Dump returns the list of allocated addresses
+	Dump() []string
+}
@@ -121,6 +121,22 @@ type IDMap map[ID]AllocatorKey
 // 2.2 Create a new master key with the condition that it may not exist
 // 2.3 Create a new slave key
 //
+// If lockless allocation has been enabled with EnableLockless() and the
+// backend supports CapabilityCreateIfExists and CapabilityDeleteOnZeroCount,
+// no kvstore locks are used. Instead, a guard key (basePath/keys/key1) holding
+// the ID is maintained next to each master key:
+// - A new master key, its guard key and the slave key are created in a single
+//   transaction with CreateOnlyWithGuard() which fails if the master key or
+//   the guard key exists, i.e. if the ID is in use or if another node has
+//   allocated an ID for the key
+// - A slave key for an existing ID is created with CreateIfGuarded() in a
+//   single transaction conditional on the guard key holding the ID. The
+//   guard key is rewritten as part of the transaction.
+// - Master keys created without guard key, e.g. by agents which did not use
+//   lockless allocation, are guarded with CreateGuard() on startup and when
+//   the key is first used. The guard key is only created if the master key
+//   still holds the key.
+//
 // 1.1. If found, increment and return (no kvstore interactions)
 // 2. Lookup ID by key in local cache or via first slave key found in kvstore
 // 2.1
@@ -133,6 +149,13 @@ type IDMap map[ID]AllocatorKey
 //     key, the key is no longer found by Get()
 //  3. If the node goes down, all slave keys of that node are removed after
 //     the TTL expires (auto release).
+//
+// Garbage collection:
+//  Master keys without slave keys are removed periodically. If allocation is
+//  lockless, the master key and its guard key are removed with
+//  DeleteOnZeroCount() which fails if the guard key has been rewritten, i.e.
+//  if a slave key has been created, since the slave keys have been counted.
+//  Otherwise the master key is locked while checking for slave keys.
 type Allocator struct {
 	// Events is a channel which will receive AllocatorEvent as IDs are
 	// added, modified or removed from the allocator
@@ -170,6 +193,10 @@ type Allocator struct {
 	// being derived from the basePrefix.
 	valuePrefix string
 
+	// keysPrefix is the kvstore key prefix for all guard keys of lockless
+	// allocation. It is being derived from the basePrefix.
+	keysPrefix string
+
 	// lockPrefix is the prefix to use for all kvstore locks. This prefix
 	// is different from the idPrefix and valuePrefix to simplify watching
 	// for ID and key changes.
@@ -192,7 +219,7 @@ type Allocator struct {
 	suffix string
 
 	// lockless is true if allocation can be done lockless. This depends on
-	// the underlying kvstore backend
+	// EnableLockless() and on the underlying kvstore backend
 	lockless bool
 
 	// backoffTemplate is the backoff configuration while allocating
@@ -204,11 +231,41 @@ type Allocator struct {
 
 	// stopGC is the channel used to stop the garbage collector
 	stopGC chan struct{}
+
+	// lastUseMutex protects lastUse
+	lastUseMutex lock.Mutex
+
+	// lastUse is the last time a reference to an ID has been observed
+	lastUse map[ID]time.Time
+}
+
+var (
+	// locklessMutex protects locklessEnabled
+	locklessMutex lock.RWMutex
+
+	// locklessEnabled is true if allocators may allocate IDs without
+	// kvstore locks
+	locklessEnabled bool
+)
+
+// EnableLockless enables or disables lockless allocation for all allocators
+// created afterwards. Lockless allocation is only used if supported by the
+// kvstore backend. It must only be enabled once all agents sharing the
+// kvstore support it as agents using kvstore locks are not synchronized
+// with agents allocating IDs lockless.
+func EnableLockless(enable bool) {
+	locklessMutex.Lock()
+	locklessEnabled = enable
+	locklessMutex.Unlock()
 }
 
 func locklessCapability() bool {
+	locklessMutex.RLock()
+	enabled := locklessEnabled
+	locklessMutex.RUnlock()
+
 	required := kvstore.CapabilityCreateIfExists | kvstore.CapabilityDeleteOnZeroCount
-	return kvstore.GetCapabilities()&required == required
+	return enabled && kvstore.GetCapabilities()&required == required
 }
 
 // AllocatorOption is the base type for allocator options
@@ -238,6 +295,7 @@ func NewAllocator(basePath string, typ AllocatorKey, opts ...AllocatorOption) (*
 		basePrefix:  basePath,
 		idPrefix:    path.Join(basePath, "id"),
 		valuePrefix: path.Join(basePath, "value"),
+		keysPrefix:  path.Join(basePath, "keys"),
 		lockPrefix:  path.Join(basePath, "locks"),
 		min:         1,
 		max:         ID(^uint64(0)),
@@ -245,6 +303,7 @@ func NewAllocator(basePath string, typ AllocatorKey, opts ...AllocatorOption) (*
 		stopGC:      make(chan struct{}, 0),
 		suffix:      uuid.NewUUID().String()[:10],
 		cache:       IDMap{},
+		lastUse:     map[ID]time.Time{},
 		lockless:    locklessCapability(),
 		Events:      make(AllocatorEventChan, 1024),
 		backoffTemplate: backoff.Exponential{
@@ -273,8 +332,14 @@ func NewAllocator(basePath string, typ AllocatorKey, opts ...AllocatorOption) (*
 		return nil, err
 	}
 
+	if a.lockless {
+		a.guardMasterKeys()
+	}
+
 	a.startGC()
 
+	kvstore.RegisterResync(a.resyncName(), a.syncLocalKeys)
+
 	return a, nil
 }
 
@@ -295,6 +360,7 @@ func WithMax(id ID) AllocatorOption {
 
 // Delete deletes an allocator and stops the garbage collector
 func (a *Allocator) Delete() {
+	kvstore.UnregisterResync(a.resyncName())
 	close(a.stopGC)
 	a.stopWatch()
 	close(a.Events)
@@ -389,6 +455,105 @@ func (a *Allocator) createValueNodeKey(key string, newID ID) error {
 	return nil
 }
 
+func (a *Allocator) resyncName() string {
+	return "allocator-" + a.basePrefix
+}
+
+// syncLocalKeys re-creates the slave keys of all keys in local use after the
+// lease they were attached to has expired. Master keys which have been
+// released by the garbage collector in the meantime are re-created as well.
+func (a *Allocator) syncLocalKeys() (int, error) {
+	ids := a.localKeys.getVerified()
+	failed := 0
+
+	for k, id := range ids {
+		scopedLog := log.WithFields(logrus.Fields{fieldKey: k, fieldID: id})
+
+		if a.lockless {
+			if !a.syncGuardedKey(scopedLog, k, id) {
+				failed++
+			}
+			continue
+		}
+
+		// The slave key is created first to prevent the garbage
+		// collector from releasing the master key
+		if err := a.createValueNodeKey(k, id); err != nil {
+			scopedLog.WithError(err).Warning("Unable to re-create slave key")
+			failed++
+			continue
+		}
+
+		keyPath := path.Join(a.idPrefix, id.String())
+		if err := kvstore.CreateOnly(keyPath, []byte(k), false); err == nil {
+			scopedLog.Info("Re-created master key released while lease was expired")
+			continue
+		}
+
+		value, err := kvstore.Get(keyPath)
+		switch {
+		case err != nil:
+			scopedLog.WithError(err).Warning("Unable to verify master key")
+			failed++
+		case value == nil:
+			scopedLog.Warning("Unable to re-create master key")
+			failed++
+		case string(value) != k:
+			scopedLog.WithField(fieldValue, string(value)).
+				Error("ID has been allocated to a different key while lease was expired")
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return len(ids) - failed, fmt.Errorf("unable to re-create %d of %d keys", failed, len(ids))
+	}
+
+	return len(ids), nil
+}
+
+// syncGuardedKey re-creates the slave key of a key in local use if
+// allocation is lockless. The master key and the guard key are re-created as
+// well if they have been released by the garbage collector in the meantime.
+// Returns false if the keys could not be re-created.
+func (a *Allocator) syncGuardedKey(scopedLog *logrus.Entry, k string, id ID) bool {
+	strID := []byte(id.String())
+	valueKey := path.Join(a.valuePrefix, k, a.suffix)
+	if err := a.createGuardedValueKey(k, id, valueKey); err == nil {
+		return true
+	}
+
+	keyPath := path.Join(a.idPrefix, id.String())
+	if err := kvstore.CreateOnlyWithGuard(a.guardKey(k), strID, keyPath, []byte(k), valueKey, strID, true); err == nil {
+		scopedLog.Info("Re-created master key released while lease was expired")
+		return true
+	}
+
+	guarded, err := a.getGuarded(k)
+	if err != nil {
+		scopedLog.WithError(err).Warning("Unable to verify guard key")
+		return false
+	}
+	if guarded != NoID && guarded != id {
+		scopedLog.WithField(fieldValue, guarded).
+			Error("A different ID has been allocated to the key while lease was expired")
+		return false
+	}
+
+	value, err := kvstore.Get(keyPath)
+	switch {
+	case err != nil:
+		scopedLog.WithError(err).Warning("Unable to verify master key")
+	case value != nil && string(value) != k:
+		scopedLog.WithField(fieldValue, string(value)).
+			Error("ID has been allocated to a different key while lease was expired")
+	default:
+		scopedLog.Warning("Unable to re-create slave key")
+	}
+
+	return false
+}
+
 // AllocatorKey is the interface to implement in order for a type to be used as
 // key for the allocator
 type AllocatorKey interface {
@@ -493,11 +658,185 @@ func (a *Allocator) lockedAllocate(key AllocatorKey) (ID, bool, error) {
 		return 0, false, fmt.Errorf("slave key creation failed '%s': %s", k, err)
 	}
 
+	// mark the key as verified in the local cache
+	if err := a.localKeys.verify(k); err != nil {
+		log.WithError(err).Error("BUG: Unable to verify local key")
+	}
+
 	lock.Unlock()
 
 	return id, true, nil
 }
 
+// slavePrefix returns the prefix of all slave keys of a key
+func (a *Allocator) slavePrefix(key string) string {
+	return path.Join(a.valuePrefix, key) + "/"
+}
+
+// guardKey returns the guard key of a key used by lockless allocation
+func (a *Allocator) guardKey(key string) string {
+	return path.Join(a.keysPrefix, key)
+}
+
+// getGuarded returns the ID held by the guard key of a key. Returns NoID if
+// the guard key does not exist.
+func (a *Allocator) getGuarded(key string) (ID, error) {
+	value, err := kvstore.Get(a.guardKey(key))
+	if err != nil || value == nil {
+		return NoID, err
+	}
+
+	id, err := strconv.ParseUint(string(value), 10, 64)
+	if err != nil {
+		return NoID, fmt.Errorf("unable to parse value '%s': %s", value, err)
+	}
+
+	return ID(id), nil
+}
+
+// createGuard guards the master key of the ID allocated to a key if the
+// master key has been created without guard key. Fails if the master key
+// no longer holds the key or if the key is guarded already.
+func (a *Allocator) createGuard(key string, id ID) error {
+	strID := []byte(id.String())
+	return kvstore.CreateGuard(a.guardKey(key), strID, path.Join(a.idPrefix, id.String()), []byte(key))
+}
+
+// guardMasterKeys guards all master keys which have been created without
+// guard key, i.e. by agents which did not use lockless allocation
+func (a *Allocator) guardMasterKeys() {
+	allocated, err := kvstore.ListPrefix(a.idPrefix)
+	if err != nil {
+		log.WithError(err).Warning("Unable to list master keys to guard")
+		return
+	}
+
+	guards, err := kvstore.ListPrefix(a.keysPrefix)
+	if err != nil {
+		log.WithError(err).Warning("Unable to list guard keys")
+		return
+	}
+
+	guarded := 0
+	for key, v := range allocated {
+		if _, ok := guards[a.guardKey(string(v))]; ok {
+			continue
+		}
+
+		id := a.keyToID(key, false)
+		if id == NoID {
+			continue
+		}
+
+		if err := a.createGuard(string(v), id); err == nil {
+			guarded++
+		}
+	}
+
+	if guarded > 0 {
+		log.WithField("keys", guarded).Info("Guarded master keys created without guard key")
+	}
+}
+
+// createGuardedValueKey creates the slave key valueKey of a key conditional
+// on the guard key holding the ID. Master keys without guard key are
+// guarded first.
+func (a *Allocator) createGuardedValueKey(key string, id ID, valueKey string) error {
+	strID := []byte(id.String())
+	err := kvstore.CreateIfGuarded(a.guardKey(key), strID, valueKey, strID, true)
+	if err == nil {
+		return nil
+	}
+
+	if a.createGuard(key, id) != nil {
+		return err
+	}
+
+	return kvstore.CreateIfGuarded(a.guardKey(key), strID, valueKey, strID, true)
+}
+
+// locklessAllocate allocates an ID for a key relying on transactions of the
+// kvstore instead of locks. Must only be used if lockless allocation is
+// enabled and the backend supports CapabilityCreateIfExists and
+// CapabilityDeleteOnZeroCount.
+func (a *Allocator) locklessAllocate(key AllocatorKey) (ID, bool, error) {
+	kvstore.Trace("Allocating key in kvstore without lock", nil, logrus.Fields{fieldKey: key})
+
+	// fetch first key that matches /value/<key> while ignoring the
+	// node suffix
+	value, err := a.Get(key)
+	if err != nil {
+		return 0, false, err
+	}
+
+	k := key.GetKey()
+	valueKey := path.Join(a.valuePrefix, k, a.suffix)
+
+	// The ID remains allocated to the key until the garbage collector
+	// removes the guard key even if no slave key is left
+	if value == 0 {
+		if value, err = a.getGuarded(k); err != nil {
+			return 0, false, err
+		}
+	}
+
+	if value != 0 {
+		_, err := a.localKeys.allocate(k, value)
+		if err != nil {
+			return 0, false, fmt.Errorf("unable to reserve local key '%s': %s", k, err)
+		}
+
+		// The master key may be released by another node in the
+		// meantime, only create the slave key if the guard key still
+		// holds the ID
+		if err := a.createGuardedValueKey(k, value, valueKey); err != nil {
+			a.localKeys.release(k)
+			return 0, false, fmt.Errorf("unable to create slave key '%s': %s", k, err)
+		}
+
+		if err := a.localKeys.verify(k); err != nil {
+			log.WithError(err).Error("BUG: Unable to verify local key")
+		}
+
+		return value, false, nil
+	}
+
+	id, strID := a.selectAvailableID()
+	if id == 0 {
+		return 0, false, fmt.Errorf("no more available IDs in configured space")
+	}
+
+	kvstore.Trace("Selected available key", nil, logrus.Fields{fieldID: id})
+
+	oldID, err := a.localKeys.allocate(k, id)
+	if err != nil {
+		return 0, false, fmt.Errorf("unable to reserve local key '%s': %s", k, err)
+	}
+
+	// Another local writer beat us to allocating an ID for the same key,
+	// start over
+	if id != oldID {
+		a.localKeys.release(k)
+		return 0, false, fmt.Errorf("another writer has allocated this key")
+	}
+
+	// create /keys/<key>, /id/<ID> and /value/<key>/<node> and fail if
+	// the ID is already in use or if another node has allocated an ID for
+	// the key
+	keyPath := path.Join(a.idPrefix, strID)
+	err = kvstore.CreateOnlyWithGuard(a.guardKey(k), []byte(strID), keyPath, []byte(k), valueKey, []byte(strID), true)
+	if err != nil {
+		a.localKeys.release(k)
+		return 0, false, fmt.Errorf("unable to create master key '%s': %s", keyPath, err)
+	}
+
+	if err := a.localKeys.verify(k); err != nil {
+		log.WithError(err).Error("BUG: Unable to verify local key")
+	}
+
+	return id, true, nil
+}
+
 // Allocate will retrieve the ID for the provided key. If no ID has been
 // allocated for this key yet, a key will be allocated. If allocation fails,
 // most likely due to a parallel allocation of the same ID by another user,
@@ -523,6 +862,7 @@ func (a *Allocator) Allocate(key AllocatorKey) (ID, bool, error) {
 		a.mutex.Lock()
 		a.nextCache[val] = key
 		a.mutex.Unlock()
+		a.markUsed(val, time.Now())
 		return val, false, nil
 	}
 
@@ -533,12 +873,16 @@ func (a *Allocator) Allocate(key AllocatorKey) (ID, bool, error) {
 	boff.Name = key.String()
 
 	for attempt := 0; attempt < maxAllocAttempts; attempt++ {
-		// FIXME: Add non-locking variant
-		value, isNew, err = a.lockedAllocate(key)
+		if a.lockless {
+			value, isNew, err = a.locklessAllocate(key)
+		} else {
+			value, isNew, err = a.lockedAllocate(key)
+		}
 		if err == nil {
 			a.mutex.Lock()
 			a.nextCache[value] = key
 			a.mutex.Unlock()
+			a.markUsed(value, time.Now())
 			return value, isNew, nil
 		}
 
@@ -631,57 +975,15 @@ func (a *Allocator) Release(key AllocatorKey) (err error) {
 		if err := kvstore.Delete(valueKey); err != nil {
 			log.WithError(err).WithFields(logrus.Fields{fieldKey: key}).Warning("Ignoring node specific ID")
 		}
-
-		// if a.lockless {
-		// FIXME: etcd 3.3 will make it possible to do a lockless
-		// cleanup of the ID and release it right away. For now we rely
-		// on the GC to kick in a release unused IDs.
-		// }
 	}
 
 	return
 }
 
-func (a *Allocator) runGC() error {
-	// fetch list of all /id/ keys
-	allocated, err := kvstore.ListPrefix(a.idPrefix)
-	if err != nil {
-		return fmt.Errorf("list failed: %s", err)
-	}
-
-	// iterate over /id/
-	for key, v := range allocated {
-		// if a.lockless {
-		// FIXME: Add DeleteOnZeroCount support
-		// }
-
-		lock, err := a.lockPath(key)
-		if err != nil {
-			continue
-		}
-
-		// fetch list of all /value/<key> keys
-		uses, err := kvstore.ListPrefix(path.Join(a.valuePrefix, string(v)))
-		if err != nil {
-			lock.Unlock()
-			continue
-		}
-
-		// if ID has no user, delete it
-		if len(uses) == 0 {
-			kvstore.Delete(key)
-		}
-
-		lock.Unlock()
-	}
-
-	return nil
-}
-
 func (a *Allocator) startGC() {
 	go func(a *Allocator) {
 		for {
-			if err := a.runGC(); err != nil {
+			if _, err := a.RunGC(); err != nil {
 				log.WithError(err).WithFields(logrus.Fields{fieldPrefix: a.idPrefix}).
 					Debug("Unable to run garbage collector")
 			}
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"math/rand"
 	"path"
+	"sync"
 	"testing"
 	"time"
 
@@ -66,6 +67,21 @@ func (e *AllocatorConsulSuite) TearDownTest(c *C) {
 	kvstore.Close()
 }
 
+type AllocatorEmbeddedSuite struct {
+	AllocatorSuite
+}
+
+var _ = Suite(&AllocatorEmbeddedSuite{})
+
+func (e *AllocatorEmbeddedSuite) SetUpTest(c *C) {
+	kvstore.SetupDummy(kvstore.EmbeddedBackendName)
+}
+
+func (e *AllocatorEmbeddedSuite) TearDownTest(c *C) {
+	kvstore.DeletePrefix(testPrefix)
+	kvstore.Close()
+}
+
 type TestType string
 
 func (t TestType) GetKey() string { return string(t) }
@@ -204,7 +220,7 @@ func testAllocator(c *C, maxID ID, allocatorName string, suffix string) {
 	}
 
 	// running the GC should not evict any entries
-	allocator.runGC()
+	allocator.RunGC()
 
 	v, err := kvstore.ListPrefix(allocator.idPrefix)
 	c.Assert(err, IsNil)
@@ -216,7 +232,7 @@ func testAllocator(c *C, maxID ID, allocatorName string, suffix string) {
 	}
 
 	// running the GC should evict all entries
-	allocator.runGC()
+	allocator.RunGC()
 
 	v, err = kvstore.ListPrefix(allocator.idPrefix)
 	c.Assert(err, IsNil)
@@ -242,6 +258,321 @@ func (s *AllocatorSuite) TestKeyToID(c *C) {
 	c.Assert(a.keyToID(path.Join(a.idPrefix, "10"), false), Equals, ID(10))
 }
 
+func (e *AllocatorEmbeddedSuite) TestLocklessAllocation(c *C) {
+	EnableLockless(true)
+	defer EnableLockless(false)
+
+	allocatorName := randStringRunes(12)
+	a, err := NewAllocator(allocatorName, TestType(""), WithMax(256), WithSuffix("a"))
+	c.Assert(err, IsNil)
+	defer a.Delete()
+	c.Assert(a.lockless, Equals, true)
+
+	b, err := NewAllocator(allocatorName, TestType(""), WithMax(256), WithSuffix("b"))
+	c.Assert(err, IsNil)
+	defer b.Delete()
+
+	key := TestType("key")
+	id, isNew, err := a.Allocate(key)
+	c.Assert(err, IsNil)
+	c.Assert(isNew, Equals, true)
+
+	// the ID is in use by a, another allocator must not allocate a
+	// different ID for the same key
+	newID, strID := b.selectAvailableID()
+	err = kvstore.CreateOnlyWithGuard(b.guardKey(string(key)), []byte(strID), path.Join(b.idPrefix, strID), []byte(key),
+		path.Join(b.valuePrefix, string(key), b.suffix), []byte(strID), true)
+	c.Assert(err, Not(IsNil))
+	v, err := kvstore.Get(path.Join(b.idPrefix, newID.String()))
+	c.Assert(err, IsNil)
+	c.Assert(v, IsNil)
+
+	id2, isNew, err := b.Allocate(key)
+	c.Assert(err, IsNil)
+	c.Assert(isNew, Equals, false)
+	c.Assert(id2, Equals, id)
+
+	// the garbage collector keeps the master key while b is still using
+	// the ID
+	keyPath := path.Join(a.idPrefix, id.String())
+	c.Assert(a.Release(key), IsNil)
+	_, err = a.RunGC()
+	c.Assert(err, IsNil)
+	v, err = kvstore.Get(keyPath)
+	c.Assert(err, IsNil)
+	c.Assert(v, Not(IsNil))
+
+	c.Assert(b.Release(key), IsNil)
+	_, err = a.RunGC()
+	c.Assert(err, IsNil)
+	v, err = kvstore.Get(keyPath)
+	c.Assert(err, IsNil)
+	c.Assert(v, IsNil)
+	v, err = kvstore.Get(a.guardKey(string(key)))
+	c.Assert(err, IsNil)
+	c.Assert(v, IsNil)
+
+	// a slave key can no longer be created for the released ID
+	err = kvstore.CreateIfGuarded(a.guardKey(string(key)), []byte(id.String()),
+		path.Join(a.valuePrefix, string(key), a.suffix), []byte(id.String()), true)
+	c.Assert(err, Not(IsNil))
+}
+
+func (e *AllocatorEmbeddedSuite) TestLocklessConcurrentAllocation(c *C) {
+	const (
+		nodes  = 4
+		rounds = 5
+	)
+
+	EnableLockless(true)
+	defer EnableLockless(false)
+
+	allocatorName := randStringRunes(12)
+	allocators := make([]*Allocator, nodes)
+	for i := range allocators {
+		a, err := NewAllocator(allocatorName, TestType(""), WithMax(256), WithSuffix(fmt.Sprintf("node%d", i)))
+		c.Assert(err, IsNil)
+		defer a.Delete()
+		c.Assert(a.lockless, Equals, true)
+		a.backoffTemplate.Max = 50 * time.Millisecond
+		allocators[i] = a
+	}
+
+	keys := []TestType{}
+	for i := 0; i < 8; i++ {
+		keys = append(keys, TestType(fmt.Sprintf("key%d", i)))
+	}
+
+	// release unused IDs while the keys are being allocated and released
+	stop := make(chan struct{})
+	gcDone := make(chan struct{})
+	go func() {
+		defer close(gcDone)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				allocators[0].RunGC()
+			}
+		}
+	}()
+
+	ids := make([]map[TestType]ID, nodes)
+	errs := make(chan error, nodes*rounds*len(keys))
+	wg := sync.WaitGroup{}
+	for i, a := range allocators {
+		ids[i] = map[TestType]ID{}
+		wg.Add(1)
+		go func(a *Allocator, ids map[TestType]ID) {
+			defer wg.Done()
+			for round := 0; round < rounds; round++ {
+				for _, key := range keys {
+					id, _, err := a.Allocate(key)
+					if err != nil {
+						errs <- err
+						continue
+					}
+					ids[key] = id
+					// the keys of the last round remain in use
+					if round < rounds-1 {
+						a.Release(key)
+					}
+				}
+			}
+		}(a, ids[i])
+	}
+	wg.Wait()
+	close(stop)
+	<-gcDone
+	close(errs)
+
+	for err := range errs {
+		c.Assert(err, IsNil)
+	}
+
+	// all nodes use the same ID for a key and each key has its own ID
+	used := map[ID]TestType{}
+	for _, key := range keys {
+		id := ids[0][key]
+		c.Assert(id, Not(Equals), NoID)
+		for i := 1; i < nodes; i++ {
+			c.Assert(ids[i][key], Equals, id, Commentf("key %s on node %d", key, i))
+		}
+		_, ok := used[id]
+		c.Assert(ok, Equals, false, Commentf("ID %d of key %s already in use", id, key))
+		used[id] = key
+
+		v, err := kvstore.Get(path.Join(allocators[0].idPrefix, id.String()))
+		c.Assert(err, IsNil)
+		c.Assert(string(v), Equals, string(key))
+		v, err = kvstore.Get(allocators[0].guardKey(string(key)))
+		c.Assert(err, IsNil)
+		c.Assert(string(v), Equals, id.String())
+	}
+}
+
+func (e *AllocatorEmbeddedSuite) TestLocklessAllocationWithoutGuard(c *C) {
+	allocatorName := randStringRunes(12)
+
+	// IDs allocated by an allocator using kvstore locks have no guard key
+	locked, err := NewAllocator(allocatorName, TestType(""), WithMax(256), WithSuffix("a"))
+	c.Assert(err, IsNil)
+	defer locked.Delete()
+	c.Assert(locked.lockless, Equals, false)
+
+	existing := TestType("existing")
+	existingID, _, err := locked.Allocate(existing)
+	c.Assert(err, IsNil)
+	v, err := kvstore.Get(locked.guardKey(string(existing)))
+	c.Assert(err, IsNil)
+	c.Assert(v, IsNil)
+
+	EnableLockless(true)
+	defer EnableLockless(false)
+
+	// the master keys present on startup are guarded
+	lockless, err := NewAllocator(allocatorName, TestType(""), WithMax(256), WithSuffix("b"))
+	c.Assert(err, IsNil)
+	defer lockless.Delete()
+	c.Assert(lockless.lockless, Equals, true)
+
+	v, err = kvstore.Get(lockless.guardKey(string(existing)))
+	c.Assert(err, IsNil)
+	c.Assert(string(v), Equals, existingID.String())
+
+	id, isNew, err := lockless.Allocate(existing)
+	c.Assert(err, IsNil)
+	c.Assert(isNew, Equals, false)
+	c.Assert(id, Equals, existingID)
+
+	// master keys created later on are guarded on first use
+	later := TestType("later")
+	laterID, _, err := locked.Allocate(later)
+	c.Assert(err, IsNil)
+	v, err = kvstore.Get(lockless.guardKey(string(later)))
+	c.Assert(err, IsNil)
+	c.Assert(v, IsNil)
+
+	id, isNew, err = lockless.Allocate(later)
+	c.Assert(err, IsNil)
+	c.Assert(isNew, Equals, false)
+	c.Assert(id, Equals, laterID)
+
+	v, err = kvstore.Get(lockless.guardKey(string(later)))
+	c.Assert(err, IsNil)
+	c.Assert(string(v), Equals, laterID.String())
+
+	// a master key which has been released is not guarded
+	c.Assert(lockless.createGuard("released", ID(42)), Not(IsNil))
+	v, err = kvstore.Get(lockless.guardKey("released"))
+	c.Assert(err, IsNil)
+	c.Assert(v, IsNil)
+}
+
+func (e *AllocatorEmbeddedSuite) TestSyncLocalKeys(c *C) {
+	e.testSyncLocalKeys(c)
+}
+
+func (e *AllocatorEmbeddedSuite) TestLocklessSyncLocalKeys(c *C) {
+	EnableLockless(true)
+	defer EnableLockless(false)
+
+	e.testSyncLocalKeys(c)
+}
+
+func (e *AllocatorEmbeddedSuite) testSyncLocalKeys(c *C) {
+	allocatorName := randStringRunes(12)
+	a, err := NewAllocator(allocatorName, TestType(""), WithMax(256), WithSuffix("a"))
+	c.Assert(err, IsNil)
+	defer a.Delete()
+
+	key := TestType("key")
+	id, _, err := a.Allocate(key)
+	c.Assert(err, IsNil)
+
+	keyPath := path.Join(a.idPrefix, id.String())
+	valueKey := path.Join(a.valuePrefix, string(key), a.suffix)
+
+	// simulate the expiry of the lease and a subsequent run of the
+	// garbage collector of another node
+	c.Assert(kvstore.Delete(valueKey), IsNil)
+	released, err := a.RunGC()
+	c.Assert(err, IsNil)
+	c.Assert(released, HasLen, 1)
+
+	n, err := a.syncLocalKeys()
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, 1)
+
+	v, err := kvstore.Get(keyPath)
+	c.Assert(err, IsNil)
+	c.Assert(string(v), Equals, string(key))
+	v, err = kvstore.Get(valueKey)
+	c.Assert(err, IsNil)
+	c.Assert(string(v), Equals, id.String())
+
+	// the ID has been allocated to a different key in the meantime
+	c.Assert(kvstore.Delete(valueKey), IsNil)
+	_, err = a.RunGC()
+	c.Assert(err, IsNil)
+	c.Assert(kvstore.Set(keyPath, []byte("other")), IsNil)
+	n, err = a.syncLocalKeys()
+	c.Assert(err, Not(IsNil))
+	c.Assert(n, Equals, 0)
+}
+
+func (e *AllocatorEmbeddedSuite) TestGCReport(c *C) {
+	allocatorName := randStringRunes(12)
+	a, err := NewAllocator(allocatorName, TestType(""), WithMax(256), WithSuffix("a"))
+	c.Assert(err, IsNil)
+	defer a.Delete()
+
+	used, _, err := a.Allocate(TestType("used"))
+	c.Assert(err, IsNil)
+	unused, _, err := a.Allocate(TestType("unused"))
+	c.Assert(err, IsNil)
+	c.Assert(a.Release(TestType("unused")), IsNil)
+
+	// simulate a reference of a node which has left the cluster
+	stale, _, err := a.Allocate(TestType("stale"))
+	c.Assert(err, IsNil)
+	c.Assert(a.Release(TestType("stale")), IsNil)
+	c.Assert(kvstore.Set(path.Join(a.valuePrefix, "stale", "gone"), []byte(stale.String())), IsNil)
+
+	isStale := func(suffix string) bool { return suffix == "gone" }
+
+	report, err := a.GCReport(isStale)
+	c.Assert(err, IsNil)
+	c.Assert(report.Unreferenced, HasLen, 1)
+	c.Assert(report.Unreferenced[0].ID, Equals, unused)
+	c.Assert(report.Unreferenced[0].Key, Equals, TestType("unused"))
+	c.Assert(report.Unreferenced[0].LastUse.IsZero(), Equals, false)
+	c.Assert(report.StaleReferenced, HasLen, 1)
+	c.Assert(report.StaleReferenced[0].ID, Equals, stale)
+	c.Assert(report.StaleReferenced[0].Nodes, DeepEquals, []string{"gone"})
+
+	// without stale node detection, only unreferenced IDs are reported
+	report, err = a.GCReport(nil)
+	c.Assert(err, IsNil)
+	c.Assert(report.Unreferenced, HasLen, 1)
+	c.Assert(report.StaleReferenced, HasLen, 0)
+
+	released, err := a.RunGC()
+	c.Assert(err, IsNil)
+	c.Assert(released, HasLen, 1)
+	c.Assert(released[0].ID, Equals, unused)
+
+	report, err = a.GCReport(isStale)
+	c.Assert(err, IsNil)
+	c.Assert(report.Unreferenced, HasLen, 0)
+	c.Assert(report.StaleReferenced, HasLen, 1)
+
+	v, err := kvstore.Get(path.Join(a.idPrefix, used.String()))
+	c.Assert(err, IsNil)
+	c.Assert(string(v), Equals, "used")
+}
+
 // The following tests are currently disabled as they are not 100% reliable in
 // the Jenkins CI
 //
@@ -292,7 +623,7 @@ func (s *AllocatorSuite) TestKeyToID(c *C) {
 //	}
 //
 //	// running the GC should evict all entries
-//	allocator.runGC()
+//	allocator.RunGC()
 //
 //	v, err := kvstore.ListPrefix(allocator.idPrefix)
 //	c.Assert(err, IsNil)
@@ -0,0 +1,256 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package allocator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cilium/cilium/pkg/kvstore"
+
+	"github.com/sirupsen/logrus"
+)
+
+// GCEntry describes an allocated ID as seen by the garbage collector
+type GCEntry struct {
+	// ID is the allocated ID
+	ID ID
+
+	// Key is the key associated with the ID
+	Key AllocatorKey
+
+	// LastUse is the last time a reference to the ID has been observed by
+	// this allocator. It is zero if no reference has been observed since
+	// the allocator was created.
+	LastUse time.Time
+
+	// Nodes is the list of suffixes of all nodes referencing the ID
+	Nodes []string
+}
+
+// GCReport lists the IDs which are candidates for garbage collection
+type GCReport struct {
+	// Unreferenced is the list of IDs without any slave key. These IDs
+	// will be released by the next garbage collector run.
+	Unreferenced []GCEntry
+
+	// StaleReferenced is the list of IDs which are only referenced by
+	// stale nodes. These IDs are not released until the slave keys of the
+	// stale nodes have been removed.
+	StaleReferenced []GCEntry
+}
+
+// StaleNodeFunc returns true if the node with the given suffix is no longer
+// part of the cluster
+type StaleNodeFunc func(suffix string) bool
+
+// markUsed records that a reference to id has been observed at time t
+func (a *Allocator) markUsed(id ID, t time.Time) {
+	a.lastUseMutex.Lock()
+	if t.After(a.lastUse[id]) {
+		a.lastUse[id] = t
+	}
+	a.lastUseMutex.Unlock()
+}
+
+func (a *Allocator) getLastUse(id ID) time.Time {
+	a.lastUseMutex.Lock()
+	defer a.lastUseMutex.Unlock()
+	return a.lastUse[id]
+}
+
+func (a *Allocator) forgetLastUse(id ID) {
+	a.lastUseMutex.Lock()
+	delete(a.lastUse, id)
+	a.lastUseMutex.Unlock()
+}
+
+// listReferences returns the suffixes of all nodes referencing a key indexed
+// by the key
+func (a *Allocator) listReferences() (map[string][]string, error) {
+	slaves, err := kvstore.ListPrefix(a.valuePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := a.valuePrefix + "/"
+	refs := map[string][]string{}
+	for slaveKey := range slaves {
+		// The node suffix never contains a slash while the key may
+		rest := strings.TrimPrefix(slaveKey, prefix)
+		i := strings.LastIndex(rest, "/")
+		if i < 0 {
+			continue
+		}
+		refs[rest[:i]] = append(refs[rest[:i]], rest[i+1:])
+	}
+
+	return refs, nil
+}
+
+// newGCEntry returns the GCEntry of an ID with the master key value v
+func (a *Allocator) newGCEntry(id ID, v []byte, nodes []string) (GCEntry, error) {
+	key, err := a.keyType.PutKey(string(v))
+	if err != nil {
+		return GCEntry{}, err
+	}
+
+	sort.Strings(nodes)
+
+	return GCEntry{
+		ID:      id,
+		Key:     key,
+		LastUse: a.getLastUse(id),
+		Nodes:   nodes,
+	}, nil
+}
+
+// GCReport returns the IDs which would be released by the garbage collector
+// and the IDs which are only referenced by stale nodes. If isStale is nil,
+// no node is considered stale.
+func (a *Allocator) GCReport(isStale StaleNodeFunc) (*GCReport, error) {
+	allocated, err := kvstore.ListPrefix(a.idPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("list failed: %s", err)
+	}
+
+	refs, err := a.listReferences()
+	if err != nil {
+		return nil, fmt.Errorf("list failed: %s", err)
+	}
+
+	now := time.Now()
+	report := &GCReport{}
+
+	for key, v := range allocated {
+		id := a.keyToID(key, false)
+		if id == NoID {
+			continue
+		}
+
+		nodes := refs[string(v)]
+		if len(nodes) > 0 {
+			a.markUsed(id, now)
+		}
+
+		stale := false
+		if len(nodes) > 0 && isStale != nil {
+			stale = true
+			for _, node := range nodes {
+				if !isStale(node) {
+					stale = false
+					break
+				}
+			}
+		}
+
+		if len(nodes) > 0 && !stale {
+			continue
+		}
+
+		entry, err := a.newGCEntry(id, v, nodes)
+		if err != nil {
+			log.WithError(err).WithFields(logrus.Fields{fieldID: id}).Warning("Unable to decode key")
+			continue
+		}
+
+		if len(nodes) == 0 {
+			report.Unreferenced = append(report.Unreferenced, entry)
+		} else {
+			report.StaleReferenced = append(report.StaleReferenced, entry)
+		}
+	}
+
+	sortGCEntries(report.Unreferenced)
+	sortGCEntries(report.StaleReferenced)
+
+	return report, nil
+}
+
+// RunGC runs the garbage collector and removes all master keys which are no
+// longer backed by a slave key. The released IDs are returned.
+func (a *Allocator) RunGC() ([]GCEntry, error) {
+	// fetch list of all /id/ keys
+	allocated, err := kvstore.ListPrefix(a.idPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("list failed: %s", err)
+	}
+
+	released := []GCEntry{}
+	now := time.Now()
+
+	// iterate over /id/
+	for key, v := range allocated {
+		id := a.keyToID(key, false)
+
+		if a.lockless {
+			// fails if the ID is still in use, no lock required
+			if err := kvstore.DeleteOnZeroCount(a.guardKey(string(v)), key, a.slavePrefix(string(v))); err == nil {
+				released = a.appendReleased(released, id, v)
+			}
+			continue
+		}
+
+		lock, err := a.lockPath(key)
+		if err != nil {
+			continue
+		}
+
+		// fetch list of all /value/<key> keys
+		uses, err := kvstore.ListPrefix(a.slavePrefix(string(v)))
+		if err != nil {
+			lock.Unlock()
+			continue
+		}
+
+		// if ID has no user, delete it
+		if len(uses) == 0 {
+			if err := kvstore.Delete(key); err == nil {
+				released = a.appendReleased(released, id, v)
+			}
+		} else if id != NoID {
+			a.markUsed(id, now)
+		}
+
+		lock.Unlock()
+	}
+
+	sortGCEntries(released)
+
+	return released, nil
+}
+
+// appendReleased appends the released ID to list and forgets its last use
+func (a *Allocator) appendReleased(list []GCEntry, id ID, v []byte) []GCEntry {
+	if id == NoID {
+		return list
+	}
+
+	entry, err := a.newGCEntry(id, v, nil)
+	a.forgetLastUse(id)
+	if err != nil {
+		return list
+	}
+
+	log.WithFields(logrus.Fields{fieldID: id, fieldKey: entry.Key}).Debug("Released unused ID")
+
+	return append(list, entry)
+}
+
+func sortGCEntries(entries []GCEntry) {
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+}
@@ -91,6 +91,21 @@ func (lk *localKeys) lookupID(id ID) string {
 	return ""
 }
 
+// getVerified returns all verified keys and their IDs
+func (lk *localKeys) getVerified() map[string]ID {
+	lk.RLock()
+	defer lk.RUnlock()
+
+	ids := map[string]ID{}
+	for key, k := range lk.keys {
+		if k.verified {
+			ids[key] = k.val
+		}
+	}
+
+	return ids
+}
+
 // use increments the refcnt of the key and returns its value
 func (lk *localKeys) use(key string) ID {
 	lk.Lock()
@@ -131,6 +131,26 @@ type BackendOperations interface {
 	// CreateIfExists creates a key with the value only if key condKey exists
 	CreateIfExists(condKey, key string, value []byte, lease bool) error
 
+	// CreateOnlyWithGuard atomically creates guardKey with guardValue, key
+	// with value and refKey with refValue or fails if guardKey or key
+	// already exists. Only refKey is attached to the lease.
+	CreateOnlyWithGuard(guardKey string, guardValue []byte, key string, value []byte, refKey string, refValue []byte, lease bool) error
+
+	// CreateIfGuarded atomically creates key with value only if guardKey
+	// holds guardValue. guardKey is rewritten as part of the operation so
+	// that a concurrent DeleteOnZeroCount() of guardKey fails.
+	CreateIfGuarded(guardKey string, guardValue []byte, key string, value []byte, lease bool) error
+
+	// CreateGuard atomically creates guardKey with guardValue only if key
+	// holds value and guardKey does not exist yet. This allows to guard
+	// keys which have been created without guard key.
+	CreateGuard(guardKey string, guardValue []byte, key string, value []byte) error
+
+	// DeleteOnZeroCount atomically deletes guardKey and key if no key
+	// matching condPrefix exists. Fails if guardKey has been modified
+	// since the keys matching condPrefix have been counted.
+	DeleteOnZeroCount(guardKey, key, condPrefix string) error
+
 	// ListPrefix returns a list of keys matching the prefix
 	ListPrefix(prefix string) (KeyValuePairs, error)
 
@@ -15,7 +15,13 @@
 package kvstore
 
 import (
+	"encoding/json"
+	"time"
+
 	"github.com/cilium/cilium/common"
+	"github.com/cilium/cilium/common/types"
+
+	"github.com/sirupsen/logrus"
 )
 
 // deleteLegacyPrefixes removes old kvstore prefixes of non-persistent keys
@@ -35,3 +41,40 @@ func deleteLegacyPrefixes() {
 	// Delete all keys in old services prefix
 	DeletePrefix(common.ServicePathV1)
 }
+
+// GetValue returns the JSON encoded value of key. Use Get() instead.
+func GetValue(key string) (json.RawMessage, error) {
+	started := time.Now()
+	v, err := Client().GetValue(key)
+	trackOperation("GetValue", key, started, err)
+	Trace("GetValue", err, logrus.Fields{fieldKey: key, fieldValue: string(v)})
+	return v, err
+}
+
+// SetValue sets the JSON encoded value of key. Use Set() instead.
+func SetValue(key string, value interface{}) error {
+	started := time.Now()
+	err := Client().SetValue(key, value)
+	trackOperation("SetValue", key, started, err)
+	Trace("SetValue", err, logrus.Fields{fieldKey: key, fieldValue: value})
+	return err
+}
+
+// GetMaxID returns the maximum possible free ID stored at key
+func GetMaxID(key string, firstID uint32) (uint32, error) {
+	started := time.Now()
+	id, err := Client().GetMaxID(key, firstID)
+	trackOperation("GetMaxID", key, started, err)
+	Trace("GetMaxID", err, logrus.Fields{fieldKey: key, fieldValue: id})
+	return id, err
+}
+
+// GASNewL3n4AddrID gets and sets a new ID for lAddrID below basePath starting
+// with baseID
+func GASNewL3n4AddrID(basePath string, baseID uint32, lAddrID *types.L3n4AddrID) error {
+	started := time.Now()
+	err := Client().GASNewL3n4AddrID(basePath, baseID, lAddrID)
+	trackOperation("GASNewL3n4AddrID", basePath, started, err)
+	Trace("GASNewL3n4AddrID", err, logrus.Fields{fieldPrefix: basePath, fieldValue: lAddrID})
+	return err
+}
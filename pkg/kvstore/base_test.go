@@ -185,6 +185,135 @@ func (s *BaseTests) TestCreateOnly(c *C) {
 	c.Assert(val, DeepEquals, testValue(2))
 }
 
+func (s *BaseTests) TestCreateOnlyWithGuard(c *C) {
+	prefix := "unit-test/"
+	guardPrefix := prefix + "guard/"
+	refPrefix := prefix + "ref/"
+
+	DeletePrefix(prefix)
+	defer DeletePrefix(prefix)
+
+	c.Assert(CreateOnlyWithGuard(testKey(guardPrefix, 0), testValue(0), testKey(prefix, 0), testValue(0), testKey(refPrefix, 0), testValue(0), false), IsNil)
+
+	for _, key := range []string{testKey(guardPrefix, 0), testKey(prefix, 0), testKey(refPrefix, 0)} {
+		val, err := Get(key)
+		c.Assert(err, IsNil)
+		c.Assert(val, DeepEquals, testValue(0))
+	}
+
+	// key 0 exists already
+	c.Assert(CreateOnlyWithGuard(testKey(guardPrefix, 1), testValue(1), testKey(prefix, 0), testValue(1), testKey(refPrefix, 1), testValue(1), false), Not(IsNil))
+
+	// guard key 0 exists already
+	c.Assert(CreateOnlyWithGuard(testKey(guardPrefix, 0), testValue(1), testKey(prefix, 1), testValue(1), testKey(refPrefix, 1), testValue(1), false), Not(IsNil))
+
+	for _, key := range []string{testKey(guardPrefix, 1), testKey(prefix, 1), testKey(refPrefix, 1)} {
+		val, err := Get(key)
+		c.Assert(err, IsNil)
+		c.Assert(val, IsNil)
+	}
+
+	val, err := Get(testKey(guardPrefix, 0))
+	c.Assert(err, IsNil)
+	c.Assert(val, DeepEquals, testValue(0))
+}
+
+func (s *BaseTests) TestCreateIfGuarded(c *C) {
+	prefix := "unit-test/"
+	guardPrefix := prefix + "guard/"
+	refPrefix := prefix + "ref/"
+
+	DeletePrefix(prefix)
+	defer DeletePrefix(prefix)
+
+	// guard key does not exist
+	c.Assert(CreateIfGuarded(testKey(guardPrefix, 0), testValue(0), testKey(refPrefix, 0), testValue(0), false), Not(IsNil))
+
+	c.Assert(CreateOnly(testKey(guardPrefix, 0), testValue(0), false), IsNil)
+
+	// guard key holds a different value
+	c.Assert(CreateIfGuarded(testKey(guardPrefix, 0), testValue(1), testKey(refPrefix, 0), testValue(0), false), Not(IsNil))
+
+	val, err := Get(testKey(refPrefix, 0))
+	c.Assert(err, IsNil)
+	c.Assert(val, IsNil)
+
+	c.Assert(CreateIfGuarded(testKey(guardPrefix, 0), testValue(0), testKey(refPrefix, 0), testValue(0), false), IsNil)
+
+	val, err = Get(testKey(refPrefix, 0))
+	c.Assert(err, IsNil)
+	c.Assert(val, DeepEquals, testValue(0))
+
+	val, err = Get(testKey(guardPrefix, 0))
+	c.Assert(err, IsNil)
+	c.Assert(val, DeepEquals, testValue(0))
+}
+
+func (s *BaseTests) TestCreateGuard(c *C) {
+	prefix := "unit-test/"
+	guardPrefix := prefix + "guard/"
+
+	DeletePrefix(prefix)
+	defer DeletePrefix(prefix)
+
+	// key does not exist
+	c.Assert(CreateGuard(testKey(guardPrefix, 0), testValue(0), testKey(prefix, 0), testValue(0)), Not(IsNil))
+
+	c.Assert(CreateOnly(testKey(prefix, 0), testValue(0), false), IsNil)
+
+	// key holds a different value
+	c.Assert(CreateGuard(testKey(guardPrefix, 0), testValue(0), testKey(prefix, 0), testValue(1)), Not(IsNil))
+
+	val, err := Get(testKey(guardPrefix, 0))
+	c.Assert(err, IsNil)
+	c.Assert(val, IsNil)
+
+	c.Assert(CreateGuard(testKey(guardPrefix, 0), testValue(0), testKey(prefix, 0), testValue(0)), IsNil)
+
+	val, err = Get(testKey(guardPrefix, 0))
+	c.Assert(err, IsNil)
+	c.Assert(val, DeepEquals, testValue(0))
+
+	// guard key already exists
+	c.Assert(CreateGuard(testKey(guardPrefix, 0), testValue(1), testKey(prefix, 0), testValue(0)), Not(IsNil))
+
+	val, err = Get(testKey(guardPrefix, 0))
+	c.Assert(err, IsNil)
+	c.Assert(val, DeepEquals, testValue(0))
+}
+
+func (s *BaseTests) TestDeleteOnZeroCount(c *C) {
+	prefix := "unit-test/"
+	guardPrefix := prefix + "guard/"
+	refPrefix := prefix + "ref/"
+
+	DeletePrefix(prefix)
+	defer DeletePrefix(prefix)
+
+	c.Assert(CreateOnlyWithGuard(testKey(guardPrefix, 0), testValue(0), testKey(prefix, 0), testValue(0), testKey(refPrefix, 0), testValue(0), false), IsNil)
+
+	// refPrefix is in use, key 0 must not be deleted
+	c.Assert(DeleteOnZeroCount(testKey(guardPrefix, 0), testKey(prefix, 0), refPrefix), Not(IsNil))
+
+	for _, key := range []string{testKey(guardPrefix, 0), testKey(prefix, 0)} {
+		val, err := Get(key)
+		c.Assert(err, IsNil)
+		c.Assert(val, DeepEquals, testValue(0))
+	}
+
+	c.Assert(Delete(testKey(refPrefix, 0)), IsNil)
+	c.Assert(DeleteOnZeroCount(testKey(guardPrefix, 0), testKey(prefix, 0), refPrefix), IsNil)
+
+	for _, key := range []string{testKey(guardPrefix, 0), testKey(prefix, 0)} {
+		val, err := Get(key)
+		c.Assert(err, IsNil)
+		c.Assert(val, IsNil)
+	}
+
+	// no key can be created guarded by the deleted guard key
+	c.Assert(CreateIfGuarded(testKey(guardPrefix, 0), testValue(0), testKey(refPrefix, 0), testValue(0), false), Not(IsNil))
+}
+
 func drainEvents(w *Watcher) {
 	for len(w.Events) > 0 {
 		<-w.Events
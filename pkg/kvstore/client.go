@@ -37,6 +37,10 @@ func initClient(module backendModule) error {
 		return err
 	}
 
+	if prefix := module.getConfig()[KeyPrefixOption]; prefix != "" {
+		c = newPrefixedClient(c, prefix)
+	}
+
 	defaultClient = c
 
 	deleteLegacyPrefixes()
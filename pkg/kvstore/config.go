@@ -16,14 +16,124 @@ package kvstore
 
 import (
 	"fmt"
+	"os"
+	"strings"
 	"sync"
 )
 
+const (
+	// CertFileOption is the option specifying the path to the client
+	// certificate used to authenticate against the kvstore
+	CertFileOption = "cert-file"
+
+	// KeyFileOption is the option specifying the path to the private key
+	// of the client certificate
+	KeyFileOption = "key-file"
+
+	// CAFileOption is the option specifying the path to the CA bundle used
+	// to verify the certificate of the kvstore
+	CAFileOption = "ca-file"
+
+	// UsernameOption is the option specifying the username used to
+	// authenticate against the kvstore
+	UsernameOption = "username"
+
+	// PasswordOption is the option specifying the password used to
+	// authenticate against the kvstore
+	PasswordOption = "password"
+
+	// TokenOption is the option specifying the token used to authenticate
+	// against the kvstore
+	TokenOption = "token"
+
+	// KeyPrefixOption is the option specifying a prefix which is prepended
+	// to all keys. It allows several clusters to share a kvstore.
+	KeyPrefixOption = "key-prefix"
+)
+
 var (
 	// selectedModule is the name of the selected backend module
 	selectedModule string
 )
 
+// tlsOptions returns the options to configure TLS supported by all backends
+// which communicate with a remote kvstore
+func tlsOptions() backendOptions {
+	return backendOptions{
+		CertFileOption: &backendOption{
+			description: "Path to the client certificate",
+			validate:    validateFile,
+		},
+		KeyFileOption: &backendOption{
+			description: "Path to the private key of the client certificate",
+			validate:    validateFile,
+		},
+		CAFileOption: &backendOption{
+			description: "Path to the CA bundle to verify the server certificate",
+			validate:    validateFile,
+		},
+	}
+}
+
+// keyPrefixOption returns the option to configure the key prefix
+func keyPrefixOption() *backendOption {
+	return &backendOption{
+		description: "Prefix prepended to all keys",
+		validate:    validateKeyPrefix,
+	}
+}
+
+// mergeOptions returns a single set of options containing all options of the
+// given sets
+func mergeOptions(sets ...backendOptions) backendOptions {
+	result := backendOptions{}
+	for _, set := range sets {
+		for key, opt := range set {
+			result[key] = opt
+		}
+	}
+	return result
+}
+
+func validateFile(value string) error {
+	if value == "" {
+		return nil
+	}
+
+	_, err := os.Stat(value)
+	return err
+}
+
+func validateKeyPrefix(value string) error {
+	if strings.HasPrefix(value, "/") {
+		return fmt.Errorf("key prefix must not start with a slash")
+	}
+
+	return nil
+}
+
+// validateCombinedOpts validates options which depend on each other
+func validateCombinedOpts(opts map[string]string) error {
+	if (opts[CertFileOption] == "") != (opts[KeyFileOption] == "") {
+		return fmt.Errorf("%s and %s must be specified together", CertFileOption, KeyFileOption)
+	}
+
+	if opts[PasswordOption] != "" && opts[UsernameOption] == "" {
+		return fmt.Errorf("%s requires %s", PasswordOption, UsernameOption)
+	}
+
+	return nil
+}
+
+// get returns the value of the option with the given key or an empty string
+// if the option is not supported
+func (o backendOptions) get(key string) string {
+	if opt, ok := o[key]; ok {
+		return opt.value
+	}
+	return ""
+}
+
 // setOpts validates the specified options against the selected backend and
 // then modifies the configuration
 func setOpts(opts map[string]string, supportedOpts backendOptions) error {
@@ -46,6 +156,13 @@ func setOpts(opts map[string]string, supportedOpts backendOptions) error {
 
 	}
 
+	if errors == 0 {
+		if err := validateCombinedOpts(opts); err != nil {
+			log.Errorf("invalid kvstore configuration: %s", err)
+			errors++
+		}
+	}
+
 	// if errors have occurred, print the supported configuration keys to
 	// the log
 	if errors > 0 {
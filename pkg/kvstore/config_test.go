@@ -0,0 +1,64 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kvstore
+
+import (
+	"io/ioutil"
+	"os"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *independentSuite) TestSetOpts(c *C) {
+	f, err := ioutil.TempFile("", "cilium-kvstore-cert")
+	c.Assert(err, IsNil)
+	f.Close()
+	defer os.Remove(f.Name())
+
+	opts := func() backendOptions {
+		return mergeOptions(tlsOptions(), backendOptions{
+			UsernameOption:  &backendOption{},
+			PasswordOption:  &backendOption{},
+			KeyPrefixOption: keyPrefixOption(),
+		})
+	}
+
+	supported := opts()
+	c.Assert(setOpts(map[string]string{
+		CertFileOption:  f.Name(),
+		KeyFileOption:   f.Name(),
+		UsernameOption:  "user",
+		PasswordOption:  "secret",
+		KeyPrefixOption: "cluster1",
+	}, supported), IsNil)
+	c.Assert(supported.get(CertFileOption), Equals, f.Name())
+	c.Assert(supported.get(KeyPrefixOption), Equals, "cluster1")
+	c.Assert(supported.get(TokenOption), Equals, "")
+
+	// Options not supported by the backend
+	c.Assert(setOpts(map[string]string{TokenOption: "foo"}, opts()), Not(IsNil))
+
+	// Missing file
+	c.Assert(setOpts(map[string]string{CAFileOption: "/does/not/exist"}, opts()), Not(IsNil))
+
+	// Certificate without key
+	c.Assert(setOpts(map[string]string{CertFileOption: f.Name()}, opts()), Not(IsNil))
+
+	// Password without username
+	c.Assert(setOpts(map[string]string{PasswordOption: "secret"}, opts()), Not(IsNil))
+
+	// Absolute key prefix
+	c.Assert(setOpts(map[string]string{KeyPrefixOption: "/cluster1"}, opts()), Not(IsNil))
+}
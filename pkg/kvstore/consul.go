@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"path"
 	"strconv"
 	"strings"
@@ -30,6 +31,7 @@ import (
 	"github.com/cilium/cilium/pkg/logging/logfields"
 
 	consulAPI "github.com/hashicorp/consul/api"
+	"github.com/hashicorp/go-cleanhttp"
 	"github.com/sirupsen/logrus"
 )
 
@@ -54,11 +56,21 @@ var (
 	consulDummyAddress = "127.0.0.1:8501"
 
 	module = &consulModule{
-		opts: backendOptions{
+		opts: mergeOptions(tlsOptions(), backendOptions{
 			optAddress: &backendOption{
 				description: "Addresses of consul cluster",
 			},
-		},
+			UsernameOption: &backendOption{
+				description: "Username for HTTP basic authentication",
+			},
+			PasswordOption: &backendOption{
+				description: "Password for HTTP basic authentication",
+			},
+			TokenOption: &backendOption{
+				description: "ACL token",
+			},
+			KeyPrefixOption: keyPrefixOption(),
+		}),
 	}
 )
 
@@ -92,15 +104,26 @@ func (c *consulModule) newClient() (BackendOperations, error) {
 		}
 
 		addr := consulAddr.value
+		scheme := ""
 		consulSplitAddr := strings.Split(addr, "://")
 		if len(consulSplitAddr) == 2 {
+			scheme = consulSplitAddr[0]
 			addr = consulSplitAddr[1]
 		} else if len(consulSplitAddr) == 1 {
 			addr = consulSplitAddr[0]
 		}
 
-		c.config = consulAPI.DefaultConfig()
-		c.config.Address = addr
+		config := consulAPI.DefaultConfig()
+		config.Address = addr
+		if scheme != "" {
+			config.Scheme = scheme
+		}
+
+		if err := applyConsulOptions(config, c.opts); err != nil {
+			return nil, err
+		}
+
+		c.config = config
 	}
 
 	client, err := newConsulClient(c.config)
@@ -111,6 +134,37 @@ func (c *consulModule) newClient() (BackendOperations, error) {
 	return client, nil
 }
 
+// applyConsulOptions applies the authentication and TLS options to config
+func applyConsulOptions(config *consulAPI.Config, opts backendOptions) error {
+	if username := opts.get(UsernameOption); username != "" {
+		config.HttpAuth = &consulAPI.HttpBasicAuth{
+			Username: username,
+			Password: opts.get(PasswordOption),
+		}
+	}
+
+	if token := opts.get(TokenOption); token != "" {
+		config.Token = token
+	}
+
+	reloader, err := newTLSReloader(opts.get(CertFileOption), opts.get(KeyFileOption), opts.get(CAFileOption))
+	if err != nil {
+		return err
+	}
+
+	if reloader != nil {
+		// Each new connection is established with the certificates
+		// loaded at that time
+		transport := cleanhttp.DefaultPooledTransport()
+		transport.DialTLS = reloader.dialTLS
+		config.HttpClient = &http.Client{Transport: transport}
+		config.Scheme = "https"
+		reloader.startController()
+	}
+
+	return nil
+}
+
 var (
 	maxRetries = 30
 )
@@ -412,17 +466,17 @@ func (c *consulClient) Watch(w *Watcher) {
 						newPair.Key, newPair.CreateIndex, newPair.ModifyIndex)
 				}
 
-				w.Events <- KeyValueEvent{
+				w.emit(KeyValueEvent{
 					Typ:   EventTypeCreate,
 					Key:   newPair.Key,
 					Value: newPair.Value,
-				}
+				})
 			} else if oldPair.ModifyIndex != newPair.ModifyIndex {
-				w.Events <- KeyValueEvent{
+				w.emit(KeyValueEvent{
 					Typ:   EventTypeModify,
 					Key:   newPair.Key,
 					Value: newPair.Value,
-				}
+				})
 			}
 
 			// Everything left on localState will be assumed to
@@ -432,11 +486,11 @@ func (c *consulClient) Watch(w *Watcher) {
 		}
 
 		for k, deletedPair := range localState {
-			w.Events <- KeyValueEvent{
+			w.emit(KeyValueEvent{
 				Typ:   EventTypeDelete,
 				Key:   deletedPair.Key,
 				Value: deletedPair.Value,
-			}
+			})
 			delete(localState, k)
 		}
 
@@ -447,7 +501,7 @@ func (c *consulClient) Watch(w *Watcher) {
 
 		// Initial list operation has been completed, signal this
 		if qo.WaitIndex == 0 {
-			w.Events <- KeyValueEvent{Typ: EventTypeListDone}
+			w.emit(KeyValueEvent{Typ: EventTypeListDone})
 		}
 
 		select {
@@ -582,6 +636,112 @@ func (c *consulClient) CreateIfExists(condKey, key string, value []byte, lease b
 	return nil
 }
 
+// CreateOnlyWithGuard creates guardKey with guardValue, key with value and
+// refKey with refValue or fails if guardKey or key already exists. Only
+// refKey is attached to the lease.
+func (c *consulClient) CreateOnlyWithGuard(guardKey string, guardValue []byte, key string, value []byte, refKey string, refValue []byte, lease bool) error {
+	// Consul does not support transactions spanning multiple keys with
+	// a session attached
+	//
+	// Lock the guard key to serialize with CreateIfGuarded() and
+	// DeleteOnZeroCount() calls using the same guard key
+	l, err := LockPath(guardKey)
+	if err != nil {
+		return fmt.Errorf("unable to lock guardKey for CreateOnlyWithGuard: %s", err)
+	}
+
+	defer l.Unlock()
+
+	if err := c.CreateOnly(guardKey, guardValue, false); err != nil {
+		return err
+	}
+
+	if err := c.CreateOnly(key, value, false); err != nil {
+		c.Delete(guardKey)
+		return err
+	}
+
+	if err := c.Update(refKey, refValue, lease); err != nil {
+		c.Delete(key)
+		c.Delete(guardKey)
+		return err
+	}
+
+	return nil
+}
+
+// CreateIfGuarded creates key with value only if guardKey holds guardValue
+func (c *consulClient) CreateIfGuarded(guardKey string, guardValue []byte, key string, value []byte, lease bool) error {
+	// Lock the guard key to serialize with DeleteOnZeroCount() calls
+	// using the same guard key
+	l, err := LockPath(guardKey)
+	if err != nil {
+		return fmt.Errorf("unable to lock guardKey for CreateIfGuarded: %s", err)
+	}
+
+	defer l.Unlock()
+
+	v, err := c.Get(guardKey)
+	if err != nil {
+		return err
+	}
+	if v == nil || string(v) != string(guardValue) {
+		return fmt.Errorf("guard key does not match")
+	}
+
+	return c.Update(key, value, lease)
+}
+
+// CreateGuard creates guardKey with guardValue only if key holds value and
+// guardKey does not exist yet
+func (c *consulClient) CreateGuard(guardKey string, guardValue []byte, key string, value []byte) error {
+	// Lock the guard key to serialize with DeleteOnZeroCount() calls
+	// using the same guard key
+	l, err := LockPath(guardKey)
+	if err != nil {
+		return fmt.Errorf("unable to lock guardKey for CreateGuard: %s", err)
+	}
+
+	defer l.Unlock()
+
+	v, err := c.Get(key)
+	if err != nil {
+		return err
+	}
+	if v == nil || string(v) != string(value) {
+		return fmt.Errorf("key does not match")
+	}
+
+	return c.CreateOnly(guardKey, guardValue, false)
+}
+
+// DeleteOnZeroCount deletes guardKey and key if no key matching condPrefix
+// exists
+func (c *consulClient) DeleteOnZeroCount(guardKey, key, condPrefix string) error {
+	// Lock the guard key to serialize with CreateIfGuarded() calls using
+	// the same guard key
+	l, err := LockPath(guardKey)
+	if err != nil {
+		return fmt.Errorf("unable to lock guardKey for DeleteOnZeroCount: %s", err)
+	}
+
+	defer l.Unlock()
+
+	keys, _, err := c.KV().Keys(condPrefix, "", nil)
+	if err != nil {
+		return err
+	}
+	if len(keys) > 0 {
+		return fmt.Errorf("delete was unsuccessful")
+	}
+
+	if err := c.Delete(key); err != nil {
+		return err
+	}
+
+	return c.Delete(guardKey)
+}
+
 // ListPrefix returns a map of matching keys
 func (c *consulClient) ListPrefix(prefix string) (KeyValuePairs, error) {
 	pairs, _, err := c.KV().List(prefix, nil)
@@ -615,8 +775,17 @@ func (c *consulClient) KeepAlive(lease interface{}) error {
 		return fmt.Errorf("argument not a LeaseID")
 	}
 
-	_, _, err := c.Session().Renew(id, nil)
-	return err
+	entry, _, err := c.Session().Renew(id, nil)
+	if err != nil {
+		return err
+	}
+
+	// Renew returns no entry if the session no longer exists
+	if entry == nil {
+		return ErrLeaseExpired
+	}
+
+	return nil
 }
 
 // DeleteLease deletes a lease
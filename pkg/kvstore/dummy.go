@@ -22,6 +22,7 @@ func SetupDummy(dummyBackend string) {
 	}
 
 	module.setConfigDummy()
+	selectedModule = module.getName()
 
 	if err := initClient(module); err != nil {
 		log.WithError(err).Panic("Unable to initialize kvstore client")
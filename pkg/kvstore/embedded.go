@@ -0,0 +1,1030 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kvstore
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cilium/cilium/common"
+	"github.com/cilium/cilium/common/types"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// EmbeddedBackendName is the name of the embedded backend
+	EmbeddedBackendName = "embedded"
+
+	// EmbeddedPathOption is the option specifying the path of the database
+	// file of the embedded backend
+	EmbeddedPathOption = "embedded.path"
+
+	// embeddedCompactThreshold is the number of records appended to the
+	// journal after which the database file is rewritten to only contain
+	// the current keys
+	embeddedCompactThreshold = 10000
+
+	// embeddedLeaseGCInterval is the interval in which expired leases and
+	// their keys are removed
+	embeddedLeaseGCInterval = time.Second
+)
+
+type embeddedModule struct {
+	opts backendOptions
+
+	// inMemory is true if the database is not persisted to disk
+	inMemory bool
+}
+
+var (
+	embeddedInstance = &embeddedModule{
+		opts: backendOptions{
+			EmbeddedPathOption: &backendOption{
+				description: "Path to the database file",
+			},
+			KeyPrefixOption: keyPrefixOption(),
+		},
+	}
+)
+
+func init() {
+	// register embedded module for use
+	registerBackend(EmbeddedBackendName, embeddedInstance)
+}
+
+func (m *embeddedModule) getName() string {
+	return EmbeddedBackendName
+}
+
+func (m *embeddedModule) setConfigDummy() {
+	m.inMemory = true
+}
+
+func (m *embeddedModule) setConfig(opts map[string]string) error {
+	m.inMemory = false
+	return setOpts(opts, m.opts)
+}
+
+func (m *embeddedModule) getConfig() map[string]string {
+	return getOpts(m.opts)
+}
+
+func (m *embeddedModule) newClient() (BackendOperations, error) {
+	if m.inMemory {
+		return newEmbeddedClient("")
+	}
+
+	dbPath := m.opts[EmbeddedPathOption].value
+	if dbPath == "" {
+		return nil, fmt.Errorf("invalid embedded configuration, please specify %s option", EmbeddedPathOption)
+	}
+
+	return newEmbeddedClient(dbPath)
+}
+
+// embeddedEntry is the value of a key and the lease it is attached to
+type embeddedEntry struct {
+	value []byte
+
+	// lease is the ID of the lease the key is attached to or 0 if the key
+	// is not attached to a lease
+	lease uint64
+}
+
+type embeddedLease struct {
+	ttl     time.Duration
+	expires time.Time
+	keys    map[string]struct{}
+}
+
+// embeddedRecord is a single modification as written to the journal
+type embeddedRecord struct {
+	Op    string `json:"op"`
+	Key   string `json:"key"`
+	Value []byte `json:"value,omitempty"`
+}
+
+const (
+	embeddedOpSet    = "set"
+	embeddedOpDelete = "delete"
+)
+
+// embeddedWatch is the queue of events of a watcher which have not been
+// delivered yet. Events are queued while holding the client mutex and are
+// delivered by Watch() so that slow watchers never block modifications.
+type embeddedWatch struct {
+	prefix string
+	mutex  lock.Mutex
+	queue  []KeyValueEvent
+	wakeup chan struct{}
+}
+
+func (w *embeddedWatch) push(event KeyValueEvent) {
+	w.mutex.Lock()
+	w.queue = append(w.queue, event)
+	w.mutex.Unlock()
+
+	select {
+	case w.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+func (w *embeddedWatch) pop() []KeyValueEvent {
+	w.mutex.Lock()
+	events := w.queue
+	w.queue = nil
+	w.mutex.Unlock()
+	return events
+}
+
+// embeddedClient is a kvstore backend which keeps all keys in memory and
+// persists keys which are not attached to a lease in a journal file. Keys
+// attached to a lease are not persisted as the lease does not survive a
+// restart of the agent.
+type embeddedClient struct {
+	mutex lock.RWMutex
+
+	// path is the path of the database file, empty if the database is
+	// only kept in memory
+	path string
+
+	entries     map[string]*embeddedEntry
+	leases      map[uint64]*embeddedLease
+	lastLeaseID uint64
+	watches     map[*embeddedWatch]struct{}
+	locks       map[string]chan struct{}
+
+	// lockFile is held with an exclusive flock for the lifetime of the
+	// client to prevent other processes from opening the database
+	lockFile *os.File
+
+	// journal is the database file opened for appending
+	journal *os.File
+
+	// journalRecords is the number of records appended since the last
+	// compaction
+	journalRecords int
+
+	stop chan struct{}
+}
+
+// newEmbeddedClient opens the database at dbPath or creates it if it does
+// not exist yet. If dbPath is empty, the database is only kept in memory.
+func newEmbeddedClient(dbPath string) (*embeddedClient, error) {
+	e := &embeddedClient{
+		path:    dbPath,
+		entries: map[string]*embeddedEntry{},
+		leases:  map[uint64]*embeddedLease{},
+		watches: map[*embeddedWatch]struct{}{},
+		locks:   map[string]chan struct{}{},
+		stop:    make(chan struct{}),
+	}
+
+	if dbPath != "" {
+		if err := e.open(); err != nil {
+			return nil, err
+		}
+	}
+
+	go e.runLeaseGC()
+
+	return e, nil
+}
+
+// open locks and loads the database file and rewrites it to drop records
+// which have been superseded or were only partially written
+func (e *embeddedClient) open() error {
+	lockFile, err := os.OpenFile(e.path+".lock", os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to open lock file: %s", err)
+	}
+	if err := unix.Flock(int(lockFile.Fd()), unix.LOCK_EX|unix.LOCK_NB); err != nil {
+		lockFile.Close()
+		return fmt.Errorf("database %s is in use by another process: %s", e.path, err)
+	}
+	e.lockFile = lockFile
+
+	if err := e.load(); err != nil {
+		e.closeFiles()
+		return err
+	}
+
+	if err := e.compact(); err != nil {
+		e.closeFiles()
+		return err
+	}
+
+	log.WithFields(logrus.Fields{
+		logfields.Path:  e.path,
+		fieldNumEntries: len(e.entries),
+	}).Info("Opened embedded kvstore database")
+
+	return nil
+}
+
+// load replays all records of the database file
+func (e *embeddedClient) load() error {
+	f, err := os.Open(e.path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("unable to open database: %s", err)
+	}
+	defer f.Close()
+
+	decoder := json.NewDecoder(f)
+	for {
+		var r embeddedRecord
+		if err := decoder.Decode(&r); err == io.EOF {
+			return nil
+		} else if err != nil {
+			// A partially written record at the end of the file is
+			// the result of the agent being killed while writing,
+			// all previous records are intact
+			log.WithError(err).WithField(logfields.Path, e.path).
+				Warning("Ignoring corrupted records at end of embedded kvstore database")
+			return nil
+		}
+
+		switch r.Op {
+		case embeddedOpSet:
+			e.entries[r.Key] = &embeddedEntry{value: r.Value}
+		case embeddedOpDelete:
+			delete(e.entries, r.Key)
+		default:
+			return fmt.Errorf("unknown operation %q in database %s", r.Op, e.path)
+		}
+	}
+}
+
+// compact rewrites the database file with the keys which are not attached to
+// a lease and reopens it for appending
+func (e *embeddedClient) compact() error {
+	keys := make([]string, 0, len(e.entries))
+	for key, entry := range e.entries {
+		if entry.lease == 0 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	tmpPath := e.path + ".tmp"
+	f, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to create database: %s", err)
+	}
+
+	encoder := json.NewEncoder(f)
+	for _, key := range keys {
+		if err = encoder.Encode(embeddedRecord{Op: embeddedOpSet, Key: key, Value: e.entries[key].value}); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	f.Close()
+	if err == nil {
+		err = os.Rename(tmpPath, e.path)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("unable to write database: %s", err)
+	}
+
+	journal, err := os.OpenFile(e.path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to open database: %s", err)
+	}
+
+	if e.journal != nil {
+		e.journal.Close()
+	}
+	e.journal = journal
+	e.journalRecords = 0
+
+	return nil
+}
+
+// appendLocked appends a record to the journal. The journal is compacted
+// once embeddedCompactThreshold records have been appended.
+func (e *embeddedClient) appendLocked(r embeddedRecord) error {
+	if e.journal == nil {
+		return nil
+	}
+
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if _, err := e.journal.Write(append(b, '\n')); err != nil {
+		return fmt.Errorf("unable to write to database: %s", err)
+	}
+
+	e.journalRecords++
+	if e.journalRecords >= embeddedCompactThreshold {
+		if err := e.compact(); err != nil {
+			log.WithError(err).WithField(logfields.Path, e.path).
+				Warning("Unable to compact embedded kvstore database")
+		}
+	}
+
+	return nil
+}
+
+func (e *embeddedClient) closeFiles() {
+	if e.journal != nil {
+		e.journal.Sync()
+		e.journal.Close()
+		e.journal = nil
+	}
+	if e.lockFile != nil {
+		unix.Flock(int(e.lockFile.Fd()), unix.LOCK_UN)
+		e.lockFile.Close()
+		e.lockFile = nil
+	}
+}
+
+func copyValue(value []byte) []byte {
+	if value == nil {
+		return nil
+	}
+	c := make([]byte, len(value))
+	copy(c, value)
+	return c
+}
+
+// notifyLocked queues an event for all watchers of a matching prefix
+func (e *embeddedClient) notifyLocked(typ EventType, key string, value []byte) {
+	for w := range e.watches {
+		if strings.HasPrefix(key, w.prefix) {
+			w.push(KeyValueEvent{Typ: typ, Key: key, Value: copyValue(value)})
+		}
+	}
+}
+
+// leaseIDLocked returns the ID of the default lease if lease is true
+func (e *embeddedClient) leaseIDLocked(lease bool) (uint64, error) {
+	if !lease {
+		return 0, nil
+	}
+
+	id, ok := leaseInstance.(uint64)
+	if !ok {
+		return 0, fmt.Errorf("argument not a lease ID")
+	}
+	if _, ok := e.leases[id]; !ok {
+		return 0, fmt.Errorf("lease %d not found", id)
+	}
+
+	return id, nil
+}
+
+// setLocked creates or modifies a key and attaches it to the lease with the
+// given ID unless leaseID is 0
+func (e *embeddedClient) setLocked(key string, value []byte, leaseID uint64) error {
+	old, exists := e.entries[key]
+
+	// Leased keys are not persisted, remove a previously persisted
+	// value so it is not restored after a restart
+	if leaseID == 0 {
+		if err := e.appendLocked(embeddedRecord{Op: embeddedOpSet, Key: key, Value: value}); err != nil {
+			return err
+		}
+	} else if exists && old.lease == 0 {
+		if err := e.appendLocked(embeddedRecord{Op: embeddedOpDelete, Key: key}); err != nil {
+			return err
+		}
+	}
+
+	if exists && old.lease != 0 && old.lease != leaseID {
+		if l, ok := e.leases[old.lease]; ok {
+			delete(l.keys, key)
+		}
+	}
+	if leaseID != 0 {
+		e.leases[leaseID].keys[key] = struct{}{}
+	}
+
+	e.entries[key] = &embeddedEntry{value: copyValue(value), lease: leaseID}
+
+	if exists {
+		e.notifyLocked(EventTypeModify, key, value)
+	} else {
+		e.notifyLocked(EventTypeCreate, key, value)
+	}
+
+	return nil
+}
+
+func (e *embeddedClient) deleteLocked(key string) error {
+	old, exists := e.entries[key]
+	if !exists {
+		return nil
+	}
+
+	if old.lease == 0 {
+		if err := e.appendLocked(embeddedRecord{Op: embeddedOpDelete, Key: key}); err != nil {
+			return err
+		}
+	} else if l, ok := e.leases[old.lease]; ok {
+		delete(l.keys, key)
+	}
+
+	delete(e.entries, key)
+	e.notifyLocked(EventTypeDelete, key, old.value)
+
+	return nil
+}
+
+// sortedKeysLocked returns all keys matching prefix in lexical order
+func (e *embeddedClient) sortedKeysLocked(prefix string) []string {
+	keys := []string{}
+	for key := range e.entries {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type embeddedLock struct {
+	client *embeddedClient
+	path   string
+}
+
+// Unlock releases the lock
+func (l *embeddedLock) Unlock() error {
+	l.client.mutex.Lock()
+	defer l.client.mutex.Unlock()
+
+	held, ok := l.client.locks[l.path]
+	if !ok {
+		return fmt.Errorf("path %s is not locked", l.path)
+	}
+	delete(l.client.locks, l.path)
+	close(held)
+
+	return nil
+}
+
+// LockPath locks the provided path. As the database can only be opened by a
+// single process, the lock is held in memory.
+func (e *embeddedClient) LockPath(path string) (kvLocker, error) {
+	timeout := time.After(lockTimeout)
+
+	for {
+		e.mutex.Lock()
+		held, ok := e.locks[path]
+		if !ok {
+			e.locks[path] = make(chan struct{})
+			e.mutex.Unlock()
+			return &embeddedLock{client: e, path: path}, nil
+		}
+		e.mutex.Unlock()
+
+		select {
+		case <-held:
+		case <-timeout:
+			return nil, fmt.Errorf("timeout while waiting for lock")
+		}
+	}
+}
+
+// FIXME: Obsolete, remove
+func (e *embeddedClient) GetValue(k string) (json.RawMessage, error) {
+	value, err := e.Get(k)
+	if value == nil {
+		return nil, err
+	}
+	return json.RawMessage(value), err
+}
+
+// FIXME: Obsolete, remove
+func (e *embeddedClient) SetValue(k string, v interface{}) error {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return e.Set(k, value)
+}
+
+// FIXME: Obsolete, remove
+func (e *embeddedClient) InitializeFreeID(path string, firstID uint32) error {
+	value, err := json.Marshal(firstID)
+	if err != nil {
+		return err
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if _, ok := e.entries[path]; ok {
+		// FreeID already set
+		return nil
+	}
+	return e.setLocked(path, value, 0)
+}
+
+// FIXME: Obsolete, remove
+func (e *embeddedClient) GetMaxID(key string, firstID uint32) (uint32, error) {
+	if err := e.InitializeFreeID(key, firstID); err != nil {
+		return 0, err
+	}
+
+	value, err := e.GetValue(key)
+	if err != nil {
+		return 0, err
+	}
+
+	var freeID uint32
+	if err := json.Unmarshal(value, &freeID); err != nil {
+		return 0, err
+	}
+	return freeID, nil
+}
+
+// FIXME: Obsolete, remove
+func (e *embeddedClient) SetMaxID(key string, firstID, maxID uint32) error {
+	return e.SetValue(key, maxID)
+}
+
+// FIXME: Obsolete, remove
+func (e *embeddedClient) setMaxL3n4AddrID(maxID uint32) error {
+	return e.SetMaxID(common.LastFreeServiceIDKeyPath, common.FirstFreeServiceID, maxID)
+}
+
+// GASNewL3n4AddrID gets the next available ServiceID and sets it in lAddrID. After
+// assigning the ServiceID to lAddrID it sets the ServiceID + 1 in
+// common.LastFreeServiceIDKeyPath path.
+//
+// FIXME: Obsolete, remove
+func (e *embeddedClient) GASNewL3n4AddrID(basePath string, baseID uint32, lAddrID *types.L3n4AddrID) error {
+	setIDtoL3n4Addr := func(id uint32) error {
+		lAddrID.ID = types.ServiceID(id)
+		keyPath := path.Join(basePath, strconv.FormatUint(uint64(lAddrID.ID), 10))
+		if err := e.SetValue(keyPath, lAddrID); err != nil {
+			return err
+		}
+		return e.setMaxL3n4AddrID(id + 1)
+	}
+
+	acquireFreeID := func(firstID uint32, incID *uint32) (bool, error) {
+		keyPath := path.Join(basePath, strconv.FormatUint(uint64(*incID), 10))
+
+		locker, err := e.LockPath(getLockPath(keyPath))
+		if err != nil {
+			return false, err
+		}
+		defer locker.Unlock()
+
+		value, err := e.GetValue(keyPath)
+		if err != nil {
+			return false, err
+		}
+		if value == nil {
+			return false, setIDtoL3n4Addr(*incID)
+		}
+		var l3n4AddrID types.L3n4AddrID
+		if err := json.Unmarshal(value, &l3n4AddrID); err != nil {
+			return false, err
+		}
+		if l3n4AddrID.ID == 0 {
+			log.WithField(logfields.Identity, *incID).Info("Recycling Service ID")
+			return false, setIDtoL3n4Addr(*incID)
+		}
+
+		*incID++
+		if *incID > common.MaxSetOfServiceID {
+			*incID = common.FirstFreeServiceID
+		}
+		if firstID == *incID {
+			return false, fmt.Errorf("reached maximum set of serviceIDs available")
+		}
+		// Only retry if we have incremented the service ID
+		return true, nil
+	}
+
+	beginning := baseID
+	for {
+		retry, err := acquireFreeID(beginning, &baseID)
+		if err != nil {
+			return err
+		} else if !retry {
+			return nil
+		}
+	}
+}
+
+// Watch starts watching for changes in a prefix. All keys matching the
+// prefix are reported as created first, followed by EventTypeListDone.
+func (e *embeddedClient) Watch(w *Watcher) {
+	watch := &embeddedWatch{
+		prefix: w.prefix,
+		wakeup: make(chan struct{}, 1),
+	}
+
+	// List the current keys and register the watch atomically so no
+	// modification can be missed in between
+	e.mutex.Lock()
+	for _, key := range e.sortedKeysLocked(w.prefix) {
+		watch.queue = append(watch.queue, KeyValueEvent{
+			Typ:   EventTypeCreate,
+			Key:   key,
+			Value: copyValue(e.entries[key].value),
+		})
+	}
+	watch.queue = append(watch.queue, KeyValueEvent{Typ: EventTypeListDone})
+	e.watches[watch] = struct{}{}
+	e.mutex.Unlock()
+
+	defer func() {
+		e.mutex.Lock()
+		delete(e.watches, watch)
+		e.mutex.Unlock()
+		close(w.Events)
+	}()
+
+	for {
+		for _, event := range watch.pop() {
+			queued := time.Now()
+			select {
+			case w.Events <- event:
+				w.trackEvent(event, queued)
+			case <-w.stopWatch:
+				return
+			}
+		}
+
+		select {
+		case <-watch.wakeup:
+		case <-w.stopWatch:
+			return
+		}
+	}
+}
+
+// Status returns the path of the database and the number of keys
+func (e *embeddedClient) Status() (string, error) {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	location := e.path
+	if location == "" {
+		location = "in-memory"
+	}
+	return fmt.Sprintf("Embedded: %s - %d keys", location, len(e.entries)), nil
+}
+
+// DeletePrefix deletes all keys matching the prefix
+func (e *embeddedClient) DeletePrefix(prefix string) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	for _, key := range e.sortedKeysLocked(prefix) {
+		if err := e.deleteLocked(key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Set sets value of key
+func (e *embeddedClient) Set(key string, value []byte) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	return e.setLocked(key, value, 0)
+}
+
+// Delete deletes a key
+func (e *embeddedClient) Delete(key string) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	return e.deleteLocked(key)
+}
+
+// Get returns value of key
+func (e *embeddedClient) Get(key string) ([]byte, error) {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	if entry, ok := e.entries[key]; ok {
+		return copyValue(entry.value), nil
+	}
+	return nil, nil
+}
+
+// GetPrefix returns the first key which matches the prefix
+func (e *embeddedClient) GetPrefix(prefix string) ([]byte, error) {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	keys := e.sortedKeysLocked(prefix)
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	return copyValue(e.entries[keys[0]].value), nil
+}
+
+// Update creates or updates a key with the value
+func (e *embeddedClient) Update(key string, value []byte, lease bool) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	leaseID, err := e.leaseIDLocked(lease)
+	if err != nil {
+		return err
+	}
+
+	return e.setLocked(key, value, leaseID)
+}
+
+// CreateOnly creates a key with the value and will fail if the key already exists
+func (e *embeddedClient) CreateOnly(key string, value []byte, lease bool) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	leaseID, err := e.leaseIDLocked(lease)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := e.entries[key]; ok {
+		return fmt.Errorf("key already exists")
+	}
+
+	return e.setLocked(key, value, leaseID)
+}
+
+// CreateIfExists creates a key with the value only if key condKey exists
+func (e *embeddedClient) CreateIfExists(condKey, key string, value []byte, lease bool) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	leaseID, err := e.leaseIDLocked(lease)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := e.entries[condKey]; !ok {
+		return fmt.Errorf("conditional key not present")
+	}
+
+	// An existing key is replaced as done by etcd. This allows to take
+	// over a slave key of a previous run with a new lease.
+	return e.setLocked(key, value, leaseID)
+}
+
+// prefixInUseLocked returns true if any key matches prefix
+func (e *embeddedClient) prefixInUseLocked(prefix string) bool {
+	for key := range e.entries {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateOnlyWithGuard atomically creates guardKey with guardValue, key with
+// value and refKey with refValue or fails if guardKey or key already exists.
+// Only refKey is attached to the lease.
+func (e *embeddedClient) CreateOnlyWithGuard(guardKey string, guardValue []byte, key string, value []byte, refKey string, refValue []byte, lease bool) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	leaseID, err := e.leaseIDLocked(lease)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := e.entries[guardKey]; ok {
+		return fmt.Errorf("guard key already exists")
+	}
+	if _, ok := e.entries[key]; ok {
+		return fmt.Errorf("key already exists")
+	}
+
+	if err := e.setLocked(guardKey, guardValue, 0); err != nil {
+		return err
+	}
+
+	if err := e.setLocked(key, value, 0); err != nil {
+		return err
+	}
+
+	return e.setLocked(refKey, refValue, leaseID)
+}
+
+// CreateIfGuarded atomically creates key with value only if guardKey holds
+// guardValue
+func (e *embeddedClient) CreateIfGuarded(guardKey string, guardValue []byte, key string, value []byte, lease bool) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	leaseID, err := e.leaseIDLocked(lease)
+	if err != nil {
+		return err
+	}
+
+	guard, ok := e.entries[guardKey]
+	if !ok || !bytes.Equal(guard.value, guardValue) {
+		return fmt.Errorf("guard key does not match")
+	}
+
+	// An existing key is replaced as done by etcd. This allows to take
+	// over a slave key of a previous run with a new lease.
+	return e.setLocked(key, value, leaseID)
+}
+
+// CreateGuard atomically creates guardKey with guardValue only if key holds
+// value and guardKey does not exist yet
+func (e *embeddedClient) CreateGuard(guardKey string, guardValue []byte, key string, value []byte) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	entry, ok := e.entries[key]
+	if !ok || !bytes.Equal(entry.value, value) {
+		return fmt.Errorf("key does not match")
+	}
+	if _, ok := e.entries[guardKey]; ok {
+		return fmt.Errorf("guard key already exists")
+	}
+
+	return e.setLocked(guardKey, guardValue, 0)
+}
+
+// DeleteOnZeroCount atomically deletes guardKey and key if no key matching
+// condPrefix exists
+func (e *embeddedClient) DeleteOnZeroCount(guardKey, key, condPrefix string) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.prefixInUseLocked(condPrefix) {
+		return fmt.Errorf("conditional prefix in use")
+	}
+
+	if err := e.deleteLocked(key); err != nil {
+		return err
+	}
+
+	return e.deleteLocked(guardKey)
+}
+
+// ListPrefix returns a map of matching keys
+func (e *embeddedClient) ListPrefix(prefix string) (KeyValuePairs, error) {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	p := KeyValuePairs{}
+	for key, entry := range e.entries {
+		if strings.HasPrefix(key, prefix) {
+			p[key] = copyValue(entry.value)
+		}
+	}
+
+	return p, nil
+}
+
+// CreateLease creates a new lease with the given ttl
+func (e *embeddedClient) CreateLease(ttl time.Duration) (interface{}, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.lastLeaseID++
+	e.leases[e.lastLeaseID] = &embeddedLease{
+		ttl:     ttl,
+		expires: time.Now().Add(ttl),
+		keys:    map[string]struct{}{},
+	}
+
+	return e.lastLeaseID, nil
+}
+
+// KeepAlive keeps a lease created with CreateLease alive
+func (e *embeddedClient) KeepAlive(lease interface{}) error {
+	id, ok := lease.(uint64)
+	if !ok {
+		return fmt.Errorf("argument not a lease ID")
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	l, ok := e.leases[id]
+	if !ok {
+		return ErrLeaseExpired
+	}
+	l.expires = time.Now().Add(l.ttl)
+
+	return nil
+}
+
+// deleteLeaseLocked deletes a lease and all keys attached to it
+func (e *embeddedClient) deleteLeaseLocked(id uint64) {
+	l, ok := e.leases[id]
+	if !ok {
+		return
+	}
+
+	keys := make([]string, 0, len(l.keys))
+	for key := range l.keys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		e.deleteLocked(key)
+	}
+
+	delete(e.leases, id)
+}
+
+// DeleteLease deletes a lease
+func (e *embeddedClient) DeleteLease(lease interface{}) error {
+	id, ok := lease.(uint64)
+	if !ok {
+		return fmt.Errorf("argument not a lease ID")
+	}
+
+	e.mutex.Lock()
+	e.deleteLeaseLocked(id)
+	e.mutex.Unlock()
+
+	return nil
+}
+
+// expireLeases deletes all leases which expired before now
+func (e *embeddedClient) expireLeases(now time.Time) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	for id, l := range e.leases {
+		if l.expires.Before(now) {
+			log.WithField(fieldLease, id).Debug("Lease expired, deleting attached keys")
+			e.deleteLeaseLocked(id)
+		}
+	}
+}
+
+func (e *embeddedClient) runLeaseGC() {
+	for {
+		select {
+		case <-time.After(embeddedLeaseGCInterval):
+			e.expireLeases(time.Now())
+		case <-e.stop:
+			return
+		}
+	}
+}
+
+func (e *embeddedClient) closeClient() {
+	close(e.stop)
+
+	e.mutex.Lock()
+	e.closeFiles()
+	e.mutex.Unlock()
+}
+
+// GetCapabilities returns the capabilities of the backend
+func (e *embeddedClient) GetCapabilities() Capabilities {
+	return Capabilities(CapabilityCreateIfExists | CapabilityDeleteOnZeroCount)
+}
+
+// Encode encodes a binary slice into a character set that the backend supports
+func (e *embeddedClient) Encode(in []byte) string {
+	return base64.URLEncoding.EncodeToString([]byte(in))
+}
+
+// Decode decodes a key previously encoded back into the original binary slice
+func (e *embeddedClient) Decode(in string) ([]byte, error) {
+	return base64.URLEncoding.DecodeString(in)
+}
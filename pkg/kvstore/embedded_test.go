@@ -0,0 +1,193 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kvstore
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+type EmbeddedSuite struct {
+	BaseTests
+}
+
+var _ = Suite(&EmbeddedSuite{})
+
+func (e *EmbeddedSuite) SetUpTest(c *C) {
+	SetupDummy(EmbeddedBackendName)
+}
+
+func (e *EmbeddedSuite) TearDownTest(c *C) {
+	Close()
+}
+
+func (e *EmbeddedSuite) TestLeaseExpiry(c *C) {
+	prefix := "unit-test/"
+
+	DeletePrefix(prefix)
+	defer DeletePrefix(prefix)
+
+	w := ListAndWatch("testLeaseExpiry", prefix, 100)
+	expectEvent(c, w, EventTypeListDone, "", nil)
+
+	c.Assert(Update(testKey(prefix, 0), testValue(0), true), IsNil)
+	c.Assert(Update(testKey(prefix, 1), testValue(1), false), IsNil)
+	expectEvent(c, w, EventTypeCreate, testKey(prefix, 0), testValue(0))
+	expectEvent(c, w, EventTypeCreate, testKey(prefix, 1), testValue(1))
+
+	// Leases which have been kept alive do not expire
+	client := Client().(*embeddedClient)
+	client.expireLeases(time.Now())
+	val, err := Get(testKey(prefix, 0))
+	c.Assert(err, IsNil)
+	c.Assert(val, DeepEquals, testValue(0))
+
+	client.expireLeases(time.Now().Add(LeaseTTL + time.Second))
+	expectEvent(c, w, EventTypeDelete, testKey(prefix, 0), testValue(0))
+
+	val, err = Get(testKey(prefix, 0))
+	c.Assert(err, IsNil)
+	c.Assert(val, IsNil)
+
+	val, err = Get(testKey(prefix, 1))
+	c.Assert(err, IsNil)
+	c.Assert(val, DeepEquals, testValue(1))
+
+	c.Assert(KeepAlive(leaseInstance), Not(IsNil))
+
+	w.Stop()
+}
+
+func (e *EmbeddedSuite) TestCreateIfExistsReplaces(c *C) {
+	prefix := "unit-test/"
+
+	DeletePrefix(prefix)
+	defer DeletePrefix(prefix)
+
+	c.Assert(Set(testKey(prefix, 0), testValue(0)), IsNil)
+	c.Assert(CreateIfExists(testKey(prefix, 0), testKey(prefix, 1), testValue(1), true), IsNil)
+
+	// An existing key is replaced as done by etcd
+	c.Assert(CreateIfExists(testKey(prefix, 0), testKey(prefix, 1), testValue(2), true), IsNil)
+	val, err := Get(testKey(prefix, 1))
+	c.Assert(err, IsNil)
+	c.Assert(val, DeepEquals, testValue(2))
+}
+
+// embeddedPersistenceSuite tests the persistence of the embedded backend
+// without going through the default client
+type embeddedPersistenceSuite struct {
+	dir string
+}
+
+var _ = Suite(&embeddedPersistenceSuite{})
+
+func (s *embeddedPersistenceSuite) SetUpTest(c *C) {
+	dir, err := ioutil.TempDir("", "cilium-kvstore-embedded")
+	c.Assert(err, IsNil)
+	s.dir = dir
+}
+
+func (s *embeddedPersistenceSuite) TearDownTest(c *C) {
+	os.RemoveAll(s.dir)
+}
+
+func (s *embeddedPersistenceSuite) TestReopen(c *C) {
+	dbPath := filepath.Join(s.dir, "kvstore.db")
+
+	e, err := newEmbeddedClient(dbPath)
+	c.Assert(err, IsNil)
+
+	// The database can only be opened once
+	_, err = newEmbeddedClient(dbPath)
+	c.Assert(err, Not(IsNil))
+
+	lease, err := e.CreateLease(time.Minute)
+	c.Assert(err, IsNil)
+	e.mutex.Lock()
+	leaseID, _ := lease.(uint64)
+	c.Assert(e.setLocked("foo/leased", []byte("leased"), leaseID), IsNil)
+	e.mutex.Unlock()
+
+	c.Assert(e.Set("foo/a", []byte("a")), IsNil)
+	c.Assert(e.Set("foo/b", []byte("b")), IsNil)
+	c.Assert(e.Set("foo/b", []byte("b2")), IsNil)
+	c.Assert(e.Delete("foo/a"), IsNil)
+	c.Assert(e.Set("foo/c", []byte("c")), IsNil)
+	c.Assert(e.CreateIfExists("foo/c", "foo/d", []byte("d"), false), IsNil)
+	e.closeClient()
+
+	// Simulate a record which was partially written before a crash
+	f, err := os.OpenFile(dbPath, os.O_WRONLY|os.O_APPEND, 0600)
+	c.Assert(err, IsNil)
+	_, err = f.WriteString(`{"op":"set","key":"foo/e","val`)
+	c.Assert(err, IsNil)
+	f.Close()
+
+	e, err = newEmbeddedClient(dbPath)
+	c.Assert(err, IsNil)
+	defer e.closeClient()
+
+	pairs, err := e.ListPrefix("foo/")
+	c.Assert(err, IsNil)
+	c.Assert(pairs, DeepEquals, KeyValuePairs{
+		"foo/b": []byte("b2"),
+		"foo/c": []byte("c"),
+		"foo/d": []byte("d"),
+	})
+
+	// The database has been compacted while opening
+	content, err := ioutil.ReadFile(dbPath)
+	c.Assert(err, IsNil)
+	c.Assert(string(content), Equals,
+		`{"op":"set","key":"foo/b","value":"YjI="}`+"\n"+
+			`{"op":"set","key":"foo/c","value":"Yw=="}`+"\n"+
+			`{"op":"set","key":"foo/d","value":"ZA=="}`+"\n")
+}
+
+func (s *embeddedPersistenceSuite) TestLeasedOverwrite(c *C) {
+	dbPath := filepath.Join(s.dir, "kvstore.db")
+
+	e, err := newEmbeddedClient(dbPath)
+	c.Assert(err, IsNil)
+
+	lease, err := e.CreateLease(time.Minute)
+	c.Assert(err, IsNil)
+
+	// A persisted key which is overwritten by a leased key must not be
+	// restored after a restart
+	c.Assert(e.Set("foo/a", []byte("a")), IsNil)
+	e.mutex.Lock()
+	c.Assert(e.setLocked("foo/a", []byte("leased"), lease.(uint64)), IsNil)
+	e.mutex.Unlock()
+
+	c.Assert(e.DeleteLease(lease), IsNil)
+	val, err := e.Get("foo/a")
+	c.Assert(err, IsNil)
+	c.Assert(val, IsNil)
+	e.closeClient()
+
+	e, err = newEmbeddedClient(dbPath)
+	c.Assert(err, IsNil)
+	defer e.closeClient()
+
+	val, err = e.Get("foo/a")
+	c.Assert(err, IsNil)
+	c.Assert(val, IsNil)
+}
@@ -35,6 +35,7 @@ import (
 	"github.com/hashicorp/go-version"
 	"github.com/sirupsen/logrus"
 	ctx "golang.org/x/net/context"
+	"google.golang.org/grpc"
 )
 
 const (
@@ -56,14 +57,21 @@ var (
 	etcdDummyAddress = "http://127.0.0.1:4002"
 
 	etcdInstance = &etcdModule{
-		opts: backendOptions{
+		opts: mergeOptions(tlsOptions(), backendOptions{
 			addrOption: &backendOption{
 				description: "Addresses of etcd cluster",
 			},
 			cfgOption: &backendOption{
 				description: "Path to etcd configuration file",
 			},
-		},
+			UsernameOption: &backendOption{
+				description: "Username to authenticate with",
+			},
+			PasswordOption: &backendOption{
+				description: "Password to authenticate with",
+			},
+			KeyPrefixOption: keyPrefixOption(),
+		}),
 	}
 )
 
@@ -85,27 +93,55 @@ func (e *etcdModule) getConfig() map[string]string {
 }
 
 func (e *etcdModule) newClient() (BackendOperations, error) {
-	endpointsOpt, endpointsSet := e.opts[addrOption]
-	configPathOpt, configSet := e.opts[cfgOption]
-	configPath := ""
+	endpoints := e.opts.get(addrOption)
+	configPath := e.opts.get(cfgOption)
 
 	if e.config == nil {
-		if !endpointsSet && !configSet {
+		if endpoints == "" && configPath == "" {
 			return nil, fmt.Errorf("invalid etcd configuration, %s or %s must be specified", cfgOption, addrOption)
 		}
 
 		e.config = &client.Config{}
 
-		if endpointsSet {
-			e.config.Endpoints = []string{endpointsOpt.value}
+		if endpoints != "" {
+			e.config.Endpoints = []string{endpoints}
 		}
+	}
+
+	return newEtcdClient(e.config, configPath, e.opts)
+}
 
-		if configSet {
-			configPath = configPathOpt.value
+// applyEtcdOptions applies the authentication and TLS options to config. The
+// options take precedence over the configuration file.
+func applyEtcdOptions(config *client.Config, opts backendOptions) error {
+	if username := opts.get(UsernameOption); username != "" {
+		config.Username = username
+		config.Password = opts.get(PasswordOption)
+	}
+
+	reloader, err := newTLSReloader(opts.get(CertFileOption), opts.get(KeyFileOption), opts.get(CAFileOption))
+	if err != nil {
+		return err
+	}
+
+	if reloader != nil {
+		for _, ep := range config.Endpoints {
+			if strings.HasPrefix(ep, "http://") {
+				return fmt.Errorf("endpoint %s must use https when TLS options are specified", ep)
+			}
 		}
+
+		// The TLS configuration is copied by the client and only used
+		// to authenticate with username and password. All other
+		// connections are established with the reloadable
+		// credentials.
+		config.TLS = reloader.clientConfig()
+		config.DialOptions = append(config.DialOptions,
+			grpc.WithTransportCredentials(&reloadingCredentials{reloader: reloader}))
+		reloader.startController()
 	}
 
-	return newEtcdClient(e.config, configPath)
+	return nil
 }
 
 func init() {
@@ -157,7 +193,7 @@ func (e *etcdClient) renewSession() error {
 	return nil
 }
 
-func newEtcdClient(config *client.Config, cfgPath string) (BackendOperations, error) {
+func newEtcdClient(config *client.Config, cfgPath string, opts backendOptions) (BackendOperations, error) {
 	var (
 		c   *client.Client
 		err error
@@ -169,6 +205,9 @@ func newEtcdClient(config *client.Config, cfgPath string) (BackendOperations, er
 		}
 	}
 	if config != nil {
+		if err := applyEtcdOptions(config, opts); err != nil {
+			return nil, err
+		}
 		if config.DialTimeout == 0 {
 			config.DialTimeout = 10 * time.Second
 		}
@@ -495,11 +534,11 @@ reList:
 				localCache.MarkInUse(key.Key)
 				scopedLog.Debugf("Emiting list result as %v event for %s=%v", t, key.Key, key.Value)
 
-				w.Events <- KeyValueEvent{
+				w.emit(KeyValueEvent{
 					Key:   string(key.Key),
 					Value: key.Value,
 					Typ:   t,
-				}
+				})
 			}
 		}
 
@@ -518,12 +557,12 @@ reList:
 			}
 
 			scopedLog.Debugf("Emiting EventTypeDelete event for %s", k)
-			w.Events <- event
+			w.emit(event)
 		})
 
 		// Only send the list signal once
 		if !listSignalSent {
-			w.Events <- KeyValueEvent{Typ: EventTypeListDone}
+			w.emit(KeyValueEvent{Typ: EventTypeListDone})
 			listSignalSent = true
 		}
 
@@ -589,7 +628,7 @@ reList:
 
 					scopedLog.Debugf("Emiting %v event for %s=%v", event.Typ, event.Key, event.Value)
 
-					w.Events <- event
+					w.emit(event)
 				}
 			}
 		}
@@ -718,24 +757,115 @@ func (e *etcdClient) CreateIfExists(condKey, key string, value []byte, lease boo
 	return nil
 }
 
-// FIXME: When we rebase to etcd 3.3
-//
-// DeleteOnZeroCount deletes the key if no matching keys for prefix exist
-//func (e *etcdClient) DeleteOnZeroCount(key, prefix string) error {
-//	txnresp, err := e.client.Txn(ctx.TODO()).
-//		If(client.Compare(client.Version(prefix).WithPrefix(), "=", 0)).
-//		Then(client.OpDelete(key)).
-//		Commit()
-//	if err != nil {
-//		return err
-//	}
-//
-//	if txnresp.Succeeded == false {
-//		return fmt.Errorf("delete was unsuccessful")
-//	}
+// CreateOnlyWithGuard atomically creates guardKey with guardValue, key with
+// value and refKey with refValue or fails if guardKey or key already exists.
+// Only refKey is attached to the lease.
+func (e *etcdClient) CreateOnlyWithGuard(guardKey string, guardValue []byte, key string, value []byte, refKey string, refValue []byte, lease bool) error {
+	req, err := createOpPut(refKey, refValue, lease)
+	if err != nil {
+		return err
+	}
+
+	txnresp, err := e.client.Txn(ctx.TODO()).
+		If(client.Compare(client.Version(guardKey), "=", 0), client.Compare(client.Version(key), "=", 0)).
+		Then(client.OpPut(guardKey, string(guardValue)), client.OpPut(key, string(value)), *req).
+		Commit()
+	if err != nil {
+		return err
+	}
+
+	if txnresp.Succeeded == false {
+		return fmt.Errorf("create was unsuccessful")
+	}
+
+	return nil
+}
+
+// CreateIfGuarded atomically creates key with value only if guardKey holds
+// guardValue. guardKey is rewritten as part of the transaction which bumps
+// its modification revision so that a concurrent DeleteOnZeroCount() of
+// guardKey fails.
+func (e *etcdClient) CreateIfGuarded(guardKey string, guardValue []byte, key string, value []byte, lease bool) error {
+	req, err := createOpPut(key, value, lease)
+	if err != nil {
+		return err
+	}
+
+	txnresp, err := e.client.Txn(ctx.TODO()).
+		If(client.Compare(client.Value(guardKey), "=", string(guardValue))).
+		Then(*req, client.OpPut(guardKey, string(guardValue))).
+		Commit()
+	if err != nil {
+		return err
+	}
+
+	if txnresp.Succeeded == false {
+		return fmt.Errorf("create was unsuccessful")
+	}
+
+	return nil
+}
+
+// CreateGuard atomically creates guardKey with guardValue only if key holds
+// value and guardKey does not exist yet
+func (e *etcdClient) CreateGuard(guardKey string, guardValue []byte, key string, value []byte) error {
+	txnresp, err := e.client.Txn(ctx.TODO()).
+		If(client.Compare(client.Value(key), "=", string(value)), client.Compare(client.Version(guardKey), "=", 0)).
+		Then(client.OpPut(guardKey, string(guardValue))).
+		Commit()
+	if err != nil {
+		return err
+	}
+
+	if txnresp.Succeeded == false {
+		return fmt.Errorf("create was unsuccessful")
+	}
+
+	return nil
+}
+
+// DeleteOnZeroCount atomically deletes guardKey and key if no key matching
+// condPrefix exists. Fails if guardKey has been modified since the keys
+// matching condPrefix have been counted.
 //
-//	return nil
-//}
+// etcd 3.2 cannot compare the number of keys in a range as part of a
+// transaction. Instead, the modification revision of guardKey is read
+// together with the number of keys matching condPrefix at the same revision
+// and the deletion is made conditional on guardKey not having been modified
+// since. This relies on all keys matching condPrefix being created with
+// CreateOnlyWithGuard() or CreateIfGuarded() on guardKey.
+func (e *etcdClient) DeleteOnZeroCount(guardKey, key, condPrefix string) error {
+	getR, err := e.client.Txn(ctx.TODO()).
+		Then(client.OpGet(guardKey), client.OpGet(condPrefix, client.WithPrefix(), client.WithCountOnly())).
+		Commit()
+	if err != nil {
+		return err
+	}
+
+	if getR.Responses[1].GetResponseRange().Count > 0 {
+		return fmt.Errorf("conditional prefix in use")
+	}
+
+	// A guard key which does not exist has a modification revision of 0
+	guardRev := int64(0)
+	if kvs := getR.Responses[0].GetResponseRange().Kvs; len(kvs) > 0 {
+		guardRev = kvs[0].ModRevision
+	}
+
+	txnresp, err := e.client.Txn(ctx.TODO()).
+		If(client.Compare(client.ModRevision(guardKey), "=", guardRev)).
+		Then(client.OpDelete(guardKey), client.OpDelete(key)).
+		Commit()
+	if err != nil {
+		return err
+	}
+
+	if txnresp.Succeeded == false {
+		return fmt.Errorf("delete was unsuccessful")
+	}
+
+	return nil
+}
 
 // ListPrefix returns a map of matching keys
 func (e *etcdClient) ListPrefix(prefix string) (KeyValuePairs, error) {
@@ -766,6 +896,10 @@ func (e *etcdClient) KeepAlive(lease interface{}) error {
 	}
 
 	_, err := e.client.KeepAliveOnce(ctx.TODO(), r.ID)
+	if err == v3rpcErrors.ErrLeaseNotFound {
+		return ErrLeaseExpired
+	}
+
 	return err
 }
 
@@ -786,7 +920,7 @@ func (e *etcdClient) closeClient() {
 
 // GetCapabilities returns the capabilities of the backend
 func (e *etcdClient) GetCapabilities() Capabilities {
-	return Capabilities(CapabilityCreateIfExists)
+	return Capabilities(CapabilityCreateIfExists | CapabilityDeleteOnZeroCount)
 }
 
 // Encode encodes a binary slice into a character set that the backend supports
@@ -73,6 +73,7 @@ type Watcher struct {
 
 	name      string
 	prefix    string
+	scope     string
 	stopWatch stopChan
 
 	stopped bool
@@ -96,6 +97,7 @@ func ListAndWatch(name, prefix string, chanSize int) *Watcher {
 	w := &Watcher{
 		name:      name,
 		prefix:    prefix,
+		scope:     GetScopeFromKey(prefix),
 		Events:    make(EventChan, chanSize),
 		stopWatch: make(stopChan, 0),
 	}
@@ -15,6 +15,7 @@
 package kvstore
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -36,22 +37,33 @@ var (
 	// small value to account for temporary errors while communicating with
 	// the KVstore.
 	RetryInterval = 1 * time.Minute
+
+	// ErrLeaseExpired is returned by KeepAlive if the lease no longer
+	// exists in the kvstore
+	ErrLeaseExpired = errors.New("lease expired")
 )
 
 // CreateLease creates a new lease with the given ttl
 func CreateLease(ttl time.Duration) (interface{}, error) {
+	started := time.Now()
 	lease, err := Client().CreateLease(ttl)
+	trackOperationScope("CreateLease", ScopeLease, started, err)
 	Trace("CreateLease", err, logrus.Fields{fieldTTL: ttl, fieldLease: lease})
 	return lease, err
 }
 
 // KeepAlive keeps a lease created with CreateLease alive
 func KeepAlive(lease interface{}) error {
+	started := time.Now()
 	err := Client().KeepAlive(lease)
+	trackOperationScope("KeepAlive", ScopeLease, started, err)
 	Trace("KeepAlive", err, logrus.Fields{fieldLease: lease})
 	return err
 }
 
+// renewDefaultLease creates a new default lease and deletes the previous
+// lease. If a previous lease existed, all registered subsystems are asked to
+// re-create the keys which were attached to it.
 func renewDefaultLease() error {
 	l, err := CreateLease(LeaseTTL)
 	if err != nil {
@@ -59,7 +71,8 @@ func renewDefaultLease() error {
 	}
 
 	leaseMutex.Lock()
-	if leaseInstance != nil {
+	renewed := leaseInstance != nil
+	if renewed {
 		defaultClient.DeleteLease(leaseInstance)
 	}
 	leaseInstance = l
@@ -70,13 +83,23 @@ func renewDefaultLease() error {
 		controller.ControllerParams{
 			DoFunc: func() error {
 				leaseMutex.RLock()
-				defer leaseMutex.RUnlock()
+				err := KeepAlive(leaseInstance)
+				leaseMutex.RUnlock()
+
+				if err == ErrLeaseExpired {
+					log.Warning("Lease expired, creating new lease")
+					return renewDefaultLease()
+				}
 
-				return KeepAlive(leaseInstance)
+				return err
 			},
 			RunInterval: KeepAliveInterval,
 		},
 	)
 
+	if renewed {
+		resyncLeasedKeys()
+	}
+
 	return nil
 }
@@ -15,6 +15,8 @@
 package kvstore
 
 import (
+	"time"
+
 	"github.com/sirupsen/logrus"
 )
 
@@ -28,7 +30,9 @@ const (
 	// CapabilityCreateIfExists is true if CreateIfExists is functional
 	CapabilityCreateIfExists Capabilities = 1 << 0
 
-	// CapabilityDeleteOnZeroCount is true if DeleteOnZeroCount is functional
+	// CapabilityDeleteOnZeroCount is true if DeleteOnZeroCount,
+	// CreateOnlyWithGuard and CreateIfGuarded are atomic without relying
+	// on locks
 	CapabilityDeleteOnZeroCount Capabilities = 1 << 1
 
 	// BaseKeyPrefix is the base prefix that should be used for all keys
@@ -37,63 +41,124 @@ const (
 
 // Get returns value of key
 func Get(key string) ([]byte, error) {
+	started := time.Now()
 	v, err := Client().Get(key)
+	trackOperation("Get", key, started, err)
 	Trace("Get", err, logrus.Fields{fieldKey: key, fieldValue: string(v)})
 	return v, err
 }
 
 // GetPrefix returns the first key which matches the prefix
 func GetPrefix(prefix string) ([]byte, error) {
+	started := time.Now()
 	v, err := Client().GetPrefix(prefix)
+	trackOperation("GetPrefix", prefix, started, err)
 	Trace("GetPrefix", err, logrus.Fields{fieldPrefix: prefix, fieldValue: string(v)})
 	return v, err
 }
 
 // ListPrefix returns the list of keys matching the prefix
 func ListPrefix(prefix string) (KeyValuePairs, error) {
+	started := time.Now()
 	v, err := Client().ListPrefix(prefix)
+	trackOperation("ListPrefix", prefix, started, err)
 	Trace("ListPrefix", err, logrus.Fields{fieldPrefix: prefix, fieldNumEntries: len(v)})
 	return v, err
 }
 
 // CreateOnly atomically creates a key or fails if it already exists
 func CreateOnly(key string, value []byte, lease bool) error {
+	started := time.Now()
 	err := Client().CreateOnly(key, value, lease)
+	trackOperation("CreateOnly", key, started, err)
 	Trace("CreateOnly", err, logrus.Fields{fieldKey: key, fieldValue: string(value), fieldAttachLease: lease})
 	return err
 }
 
 // Update creates or updates a key value pair
 func Update(key string, value []byte, lease bool) error {
+	started := time.Now()
 	err := Client().Update(key, value, lease)
+	trackOperation("Update", key, started, err)
 	Trace("Update", err, logrus.Fields{fieldKey: key, fieldValue: string(value), fieldAttachLease: lease})
 	return err
 }
 
 // CreateIfExists creates a key with the value only if key condKey exists
 func CreateIfExists(condKey, key string, value []byte, lease bool) error {
+	started := time.Now()
 	err := Client().CreateIfExists(condKey, key, value, lease)
+	trackOperation("CreateIfExists", key, started, err)
 	Trace("CreateIfExists", err, logrus.Fields{fieldKey: key, fieldValue: string(value), fieldCondition: condKey, fieldAttachLease: lease})
 	return err
 }
 
+// CreateOnlyWithGuard atomically creates guardKey with guardValue, key with
+// value and refKey with refValue or fails if guardKey or key already exists.
+// Only refKey is attached to the lease.
+func CreateOnlyWithGuard(guardKey string, guardValue []byte, key string, value []byte, refKey string, refValue []byte, lease bool) error {
+	started := time.Now()
+	err := Client().CreateOnlyWithGuard(guardKey, guardValue, key, value, refKey, refValue, lease)
+	trackOperation("CreateOnlyWithGuard", key, started, err)
+	Trace("CreateOnlyWithGuard", err, logrus.Fields{fieldKey: key, fieldValue: string(value), "refKey": refKey, fieldCondition: guardKey, fieldAttachLease: lease})
+	return err
+}
+
+// CreateIfGuarded atomically creates key with value only if guardKey holds
+// guardValue. guardKey is rewritten as part of the operation so that a
+// concurrent DeleteOnZeroCount() of guardKey fails.
+func CreateIfGuarded(guardKey string, guardValue []byte, key string, value []byte, lease bool) error {
+	started := time.Now()
+	err := Client().CreateIfGuarded(guardKey, guardValue, key, value, lease)
+	trackOperation("CreateIfGuarded", key, started, err)
+	Trace("CreateIfGuarded", err, logrus.Fields{fieldKey: key, fieldValue: string(value), fieldCondition: guardKey, fieldAttachLease: lease})
+	return err
+}
+
+// CreateGuard atomically creates guardKey with guardValue only if key holds
+// value and guardKey does not exist yet
+func CreateGuard(guardKey string, guardValue []byte, key string, value []byte) error {
+	started := time.Now()
+	err := Client().CreateGuard(guardKey, guardValue, key, value)
+	trackOperation("CreateGuard", key, started, err)
+	Trace("CreateGuard", err, logrus.Fields{fieldKey: guardKey, fieldValue: string(guardValue), fieldCondition: key})
+	return err
+}
+
+// DeleteOnZeroCount atomically deletes guardKey and key if no key matching
+// condPrefix exists. Fails if guardKey has been modified since the keys
+// matching condPrefix have been counted.
+func DeleteOnZeroCount(guardKey, key, condPrefix string) error {
+	started := time.Now()
+	err := Client().DeleteOnZeroCount(guardKey, key, condPrefix)
+	trackOperation("DeleteOnZeroCount", key, started, err)
+	Trace("DeleteOnZeroCount", err, logrus.Fields{fieldKey: key, fieldCondition: condPrefix, "guardKey": guardKey})
+	return err
+}
+
 // Set sets the value of a key
 func Set(key string, value []byte) error {
+	started := time.Now()
 	err := Client().Set(key, value)
+	trackOperation("Set", key, started, err)
 	Trace("Set", err, logrus.Fields{fieldKey: key, fieldValue: string(value)})
 	return err
 }
 
 // Delete deletes a key
 func Delete(key string) error {
+	started := time.Now()
 	err := Client().Delete(key)
+	trackOperation("Delete", key, started, err)
 	Trace("Delete", err, logrus.Fields{fieldKey: key})
 	return err
 }
 
 // DeletePrefix deletes all keys matching a prefix
 func DeletePrefix(prefix string) error {
+	started := time.Now()
 	err := Client().DeletePrefix(prefix)
+	trackOperation("DeletePrefix", prefix, started, err)
 	Trace("DeletePrefix", err, logrus.Fields{fieldPrefix: prefix})
 	return err
 }
@@ -90,9 +90,11 @@ type Lock struct {
 //
 // It is required to call Unlock() on the returned Lock to unlock
 func LockPath(path string) (l *Lock, err error) {
+	started := time.Now()
 	kvstoreLocks.lock(path)
 
 	lock, err := Client().LockPath(path)
+	trackOperation("LockPath", path, started, err)
 	if err != nil {
 		kvstoreLocks.unlock(path)
 		Trace("Failed to lock", err, logrus.Fields{fieldKey: path})
@@ -111,7 +113,9 @@ func (l *Lock) Unlock() error {
 	}
 
 	// Unlock kvstore mutex first
+	started := time.Now()
 	err := l.kvLock.Unlock()
+	trackOperation("Unlock", l.path, started, err)
 
 	// unlock local lock even if kvstore cannot be unlocked
 	kvstoreLocks.unlock(l.path)
@@ -0,0 +1,115 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kvstore
+
+import (
+	"strings"
+	"time"
+
+	"github.com/cilium/cilium/common"
+	"github.com/cilium/cilium/pkg/metrics"
+)
+
+const (
+	// ScopeIdentities is the scope of all keys used by the identity
+	// allocator
+	ScopeIdentities = "identities"
+
+	// ScopeIPCache is the scope of all keys used by the ipcache
+	ScopeIPCache = "ipcache"
+
+	// ScopeIPAM is the scope of all keys used for cluster-wide address
+	// allocation
+	ScopeIPAM = "ipam"
+
+	// ScopeNodes is the scope of all keys used for node registration
+	ScopeNodes = "nodes"
+
+	// ScopeServices is the scope of all keys used for services
+	ScopeServices = "services"
+
+	// ScopeLease is the scope of lease operations which are not related
+	// to a key
+	ScopeLease = "lease"
+
+	// ScopeOther is the scope of all keys not matching any other scope
+	ScopeOther = "other"
+)
+
+// keyScopes maps the first path component below the state prefix to the
+// scope reported in metrics
+var keyScopes = map[string]string{
+	"identities": ScopeIdentities,
+	"ip":         ScopeIPCache,
+	"ipam":       ScopeIPAM,
+	"nodes":      ScopeNodes,
+	"services":   ScopeServices,
+}
+
+// GetScopeFromKey returns the scope of a kvstore key or prefix. The number of
+// scopes is bounded so the scope can be used as metrics label.
+func GetScopeFromKey(key string) string {
+	statePrefix := BaseKeyPrefix + "/state/"
+	if !strings.HasPrefix(key, statePrefix) {
+		// Services are still stored in the legacy operational path
+		if strings.HasPrefix(key, common.OperationalPath+"/Services") {
+			return ScopeServices
+		}
+		return ScopeOther
+	}
+
+	component := strings.TrimPrefix(key, statePrefix)
+	if i := strings.Index(component, "/"); i >= 0 {
+		component = component[:i]
+	}
+
+	if scope, ok := keyScopes[component]; ok {
+		return scope
+	}
+
+	return ScopeOther
+}
+
+// trackOperation records the duration and outcome of an operation started
+// at start on key
+func trackOperation(operation, key string, start time.Time, err error) {
+	trackOperationScope(operation, GetScopeFromKey(key), start, err)
+}
+
+// trackOperationScope records the duration and outcome of an operation
+// started at start in the given scope
+func trackOperationScope(operation, scope string, start time.Time, err error) {
+	outcome := metrics.LabelValueOutcomeSuccess
+	if err != nil {
+		outcome = metrics.LabelValueOutcomeFail
+	}
+
+	metrics.KVStoreOperationsDuration.WithLabelValues(operation, scope, outcome).Observe(time.Since(start).Seconds())
+}
+
+// trackEvent records a watch event which has been queued for the watcher.
+// queued is the time at which the backend started queueing the event.
+func (w *Watcher) trackEvent(event KeyValueEvent, queued time.Time) {
+	metrics.KVStoreEventsQueued.WithLabelValues(w.scope, event.Typ.String()).Inc()
+	metrics.KVStoreWatchLag.WithLabelValues(w.scope).Set(time.Since(queued).Seconds())
+}
+
+// emit queues event for the watcher and records it in the metrics. This
+// blocks until the watcher has accepted the event.
+func (w *Watcher) emit(event KeyValueEvent) {
+	queued := time.Now()
+	w.Events <- event
+	w.trackEvent(event, queued)
+}
@@ -0,0 +1,36 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kvstore
+
+import (
+	"github.com/cilium/cilium/common"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *independentSuite) TestGetScopeFromKey(c *C) {
+	c.Assert(GetScopeFromKey("cilium/state/identities/v1/id/1000"), Equals, ScopeIdentities)
+	c.Assert(GetScopeFromKey("cilium/state/identities/v1"), Equals, ScopeIdentities)
+	c.Assert(GetScopeFromKey("cilium/state/ip/v1/default/10.0.0.1"), Equals, ScopeIPCache)
+	c.Assert(GetScopeFromKey("cilium/state/nodes/v1/node1"), Equals, ScopeNodes)
+	c.Assert(GetScopeFromKey("cilium/state/ipam/v1/blocks/id/1"), Equals, ScopeIPAM)
+	c.Assert(GetScopeFromKey("cilium/state/services/v1/foo"), Equals, ScopeServices)
+	c.Assert(GetScopeFromKey(common.ServicePathV1), Equals, ScopeServices)
+	c.Assert(GetScopeFromKey(common.ServiceIDKeyPath), Equals, ScopeServices)
+	c.Assert(GetScopeFromKey("cilium/state/unknown/v1"), Equals, ScopeOther)
+	c.Assert(GetScopeFromKey("cilium/state/"), Equals, ScopeOther)
+	c.Assert(GetScopeFromKey("foo/bar"), Equals, ScopeOther)
+	c.Assert(GetScopeFromKey(""), Equals, ScopeOther)
+}
@@ -0,0 +1,166 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kvstore
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/cilium/cilium/common/types"
+)
+
+// prefixedClient wraps a backend client and prepends a prefix to all keys.
+// Keys returned by the backend are reported without the prefix so users of
+// the kvstore package are not aware of the prefix.
+type prefixedClient struct {
+	BackendOperations
+	prefix string
+}
+
+// newPrefixedClient returns a client which prepends prefix to all keys
+// before passing them to c. The prefix is separated from the keys by a slash.
+func newPrefixedClient(c BackendOperations, prefix string) BackendOperations {
+	return &prefixedClient{
+		BackendOperations: c,
+		prefix:            strings.TrimSuffix(prefix, "/") + "/",
+	}
+}
+
+func (p *prefixedClient) key(key string) string {
+	return p.prefix + key
+}
+
+func (p *prefixedClient) stripKey(key string) string {
+	return strings.TrimPrefix(key, p.prefix)
+}
+
+func (p *prefixedClient) GetValue(k string) (json.RawMessage, error) {
+	return p.BackendOperations.GetValue(p.key(k))
+}
+
+func (p *prefixedClient) SetValue(k string, v interface{}) error {
+	return p.BackendOperations.SetValue(p.key(k), v)
+}
+
+func (p *prefixedClient) InitializeFreeID(path string, firstID uint32) error {
+	return p.BackendOperations.InitializeFreeID(p.key(path), firstID)
+}
+
+func (p *prefixedClient) GetMaxID(key string, firstID uint32) (uint32, error) {
+	return p.BackendOperations.GetMaxID(p.key(key), firstID)
+}
+
+func (p *prefixedClient) SetMaxID(key string, firstID, maxID uint32) error {
+	return p.BackendOperations.SetMaxID(p.key(key), firstID, maxID)
+}
+
+func (p *prefixedClient) GASNewL3n4AddrID(basePath string, baseID uint32, lAddrID *types.L3n4AddrID) error {
+	return p.BackendOperations.GASNewL3n4AddrID(p.key(basePath), baseID, lAddrID)
+}
+
+func (p *prefixedClient) LockPath(path string) (kvLocker, error) {
+	return p.BackendOperations.LockPath(p.key(path))
+}
+
+func (p *prefixedClient) Get(key string) ([]byte, error) {
+	return p.BackendOperations.Get(p.key(key))
+}
+
+func (p *prefixedClient) GetPrefix(prefix string) ([]byte, error) {
+	return p.BackendOperations.GetPrefix(p.key(prefix))
+}
+
+func (p *prefixedClient) Set(key string, value []byte) error {
+	return p.BackendOperations.Set(p.key(key), value)
+}
+
+func (p *prefixedClient) Delete(key string) error {
+	return p.BackendOperations.Delete(p.key(key))
+}
+
+func (p *prefixedClient) DeletePrefix(path string) error {
+	return p.BackendOperations.DeletePrefix(p.key(path))
+}
+
+func (p *prefixedClient) Update(key string, value []byte, lease bool) error {
+	return p.BackendOperations.Update(p.key(key), value, lease)
+}
+
+func (p *prefixedClient) CreateOnly(key string, value []byte, lease bool) error {
+	return p.BackendOperations.CreateOnly(p.key(key), value, lease)
+}
+
+func (p *prefixedClient) CreateIfExists(condKey, key string, value []byte, lease bool) error {
+	return p.BackendOperations.CreateIfExists(p.key(condKey), p.key(key), value, lease)
+}
+
+func (p *prefixedClient) CreateOnlyWithGuard(guardKey string, guardValue []byte, key string, value []byte, refKey string, refValue []byte, lease bool) error {
+	return p.BackendOperations.CreateOnlyWithGuard(p.key(guardKey), guardValue, p.key(key), value, p.key(refKey), refValue, lease)
+}
+
+func (p *prefixedClient) CreateIfGuarded(guardKey string, guardValue []byte, key string, value []byte, lease bool) error {
+	return p.BackendOperations.CreateIfGuarded(p.key(guardKey), guardValue, p.key(key), value, lease)
+}
+
+func (p *prefixedClient) CreateGuard(guardKey string, guardValue []byte, key string, value []byte) error {
+	return p.BackendOperations.CreateGuard(p.key(guardKey), guardValue, p.key(key), value)
+}
+
+func (p *prefixedClient) DeleteOnZeroCount(guardKey, key, condPrefix string) error {
+	return p.BackendOperations.DeleteOnZeroCount(p.key(guardKey), p.key(key), p.key(condPrefix))
+}
+
+func (p *prefixedClient) ListPrefix(prefix string) (KeyValuePairs, error) {
+	pairs, err := p.BackendOperations.ListPrefix(p.key(prefix))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(KeyValuePairs, len(pairs))
+	for k, v := range pairs {
+		result[p.stripKey(k)] = v
+	}
+
+	return result, nil
+}
+
+// Watch watches the prefixed prefix of w and forwards all events to w with
+// the prefix removed from the keys
+func (p *prefixedClient) Watch(w *Watcher) {
+	inner := &Watcher{
+		// Unbuffered so the watch lag reported by the backend
+		// reflects the consumer of w
+		Events:    make(EventChan),
+		name:      w.name,
+		prefix:    p.key(w.prefix),
+		scope:     w.scope,
+		stopWatch: w.stopWatch,
+	}
+
+	go p.BackendOperations.Watch(inner)
+
+	defer close(w.Events)
+
+	// The backend closes the inner channel after the watcher has been
+	// stopped. Events received after the stop are discarded.
+	for event := range inner.Events {
+		event.Key = p.stripKey(event.Key)
+
+		select {
+		case w.Events <- event:
+		case <-w.stopWatch:
+		}
+	}
+}
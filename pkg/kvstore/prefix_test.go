@@ -0,0 +1,61 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kvstore
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+// EmbeddedPrefixSuite runs the backend agnostic tests against the embedded
+// backend with a key prefix configured
+type EmbeddedPrefixSuite struct {
+	BaseTests
+}
+
+var _ = Suite(&EmbeddedPrefixSuite{})
+
+const testKeyPrefix = "cluster1"
+
+func (e *EmbeddedPrefixSuite) SetUpTest(c *C) {
+	SetupDummy(EmbeddedBackendName)
+	defaultClient = newPrefixedClient(defaultClient, testKeyPrefix)
+}
+
+func (e *EmbeddedPrefixSuite) TearDownTest(c *C) {
+	Close()
+}
+
+func (e *EmbeddedPrefixSuite) TestKeysArePrefixed(c *C) {
+	prefix := "unit-test/"
+
+	DeletePrefix(prefix)
+	defer DeletePrefix(prefix)
+
+	backend := defaultClient.(*prefixedClient).BackendOperations
+
+	c.Assert(Set(testKey(prefix, 0), testValue(0)), IsNil)
+
+	val, err := backend.Get(testKeyPrefix + "/" + testKey(prefix, 0))
+	c.Assert(err, IsNil)
+	c.Assert(val, DeepEquals, testValue(0))
+
+	val, err = backend.Get(testKey(prefix, 0))
+	c.Assert(err, IsNil)
+	c.Assert(val, IsNil)
+
+	pairs, err := ListPrefix(prefix)
+	c.Assert(err, IsNil)
+	c.Assert(pairs, DeepEquals, KeyValuePairs{testKey(prefix, 0): testValue(0)})
+}
@@ -0,0 +1,171 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kvstore
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cilium/cilium/pkg/controller"
+	"github.com/cilium/cilium/pkg/lock"
+)
+
+const (
+	// ResyncStatePending is the state of a subsystem which has not yet
+	// re-created its keys after the lease has been renewed
+	ResyncStatePending = "Pending"
+
+	// ResyncStateOk is the state of a subsystem which has re-created all
+	// of its keys
+	ResyncStateOk = "Ok"
+
+	// ResyncStateFailure is the state of a subsystem which failed to
+	// re-create its keys. The attempt is retried.
+	ResyncStateFailure = "Failure"
+)
+
+// ResyncFunc must re-create all keys of a subsystem which are attached to the
+// default lease from local state. It returns the number of keys re-created.
+type ResyncFunc func() (int, error)
+
+// ResyncSubsystemStatus is the status of a single subsystem re-creating its
+// keys
+type ResyncSubsystemStatus struct {
+	// Name is the name of the subsystem as passed to RegisterResync()
+	Name string
+
+	// State is one of ResyncStatePending, ResyncStateOk or
+	// ResyncStateFailure
+	State string
+
+	// Keys is the number of keys re-created in the last attempt
+	Keys int
+
+	// Error is the error of the last failed attempt
+	Error string
+}
+
+// ResyncStatus is the status of the re-creation of leased keys after the
+// default lease has been replaced
+type ResyncStatus struct {
+	// Renewals is the number of times the default lease has been replaced
+	// since the client was set up
+	Renewals int
+
+	// LastRenewal is the time the default lease has last been replaced
+	LastRenewal time.Time
+
+	// Subsystems is the status of all registered subsystems sorted by name
+	Subsystems []ResyncSubsystemStatus
+}
+
+type resyncer struct {
+	fn     ResyncFunc
+	status ResyncSubsystemStatus
+}
+
+var (
+	resyncMutex lock.RWMutex
+	resyncers   = map[string]*resyncer{}
+	renewals    int
+	lastRenewal time.Time
+)
+
+func resyncControllerName(name string) string {
+	return fmt.Sprintf("kvstore-resync-%s", name)
+}
+
+// RegisterResync registers a subsystem which owns keys attached to the default
+// lease. When the lease expires, all keys attached to it are removed by the
+// kvstore. fn is called after a new lease has been created to re-create the
+// keys and is retried until it succeeds.
+func RegisterResync(name string, fn ResyncFunc) {
+	resyncMutex.Lock()
+	resyncers[name] = &resyncer{
+		fn:     fn,
+		status: ResyncSubsystemStatus{Name: name, State: ResyncStateOk},
+	}
+	resyncMutex.Unlock()
+}
+
+// UnregisterResync removes a subsystem registered with RegisterResync()
+func UnregisterResync(name string) {
+	resyncMutex.Lock()
+	delete(resyncers, name)
+	resyncMutex.Unlock()
+
+	kvstoreControllers.RemoveController(resyncControllerName(name))
+}
+
+// resyncLeasedKeys is called after the default lease has been replaced and
+// starts a controller for each registered subsystem to re-create its keys
+func resyncLeasedKeys() {
+	resyncMutex.Lock()
+	defer resyncMutex.Unlock()
+
+	renewals++
+	lastRenewal = time.Now()
+
+	log.WithField("subsystems", len(resyncers)).Info("Lease renewed, re-creating leased keys")
+
+	for name, r := range resyncers {
+		name, r := name, r
+		r.status.State = ResyncStatePending
+		r.status.Keys = 0
+		r.status.Error = ""
+
+		kvstoreControllers.UpdateController(resyncControllerName(name),
+			controller.ControllerParams{
+				DoFunc: func() error {
+					keys, err := r.fn()
+
+					resyncMutex.Lock()
+					r.status.Keys = keys
+					if err != nil {
+						r.status.State = ResyncStateFailure
+						r.status.Error = err.Error()
+					} else {
+						r.status.State = ResyncStateOk
+						r.status.Error = ""
+					}
+					resyncMutex.Unlock()
+
+					return err
+				},
+			},
+		)
+	}
+}
+
+// GetResyncStatus returns the status of the re-creation of leased keys
+func GetResyncStatus() ResyncStatus {
+	resyncMutex.RLock()
+	defer resyncMutex.RUnlock()
+
+	s := ResyncStatus{
+		Renewals:    renewals,
+		LastRenewal: lastRenewal,
+		Subsystems:  make([]ResyncSubsystemStatus, 0, len(resyncers)),
+	}
+	for _, r := range resyncers {
+		s.Subsystems = append(s.Subsystems, r.status)
+	}
+	sort.Slice(s.Subsystems, func(i, j int) bool {
+		return s.Subsystems[i].Name < s.Subsystems[j].Name
+	})
+
+	return s
+}
@@ -0,0 +1,67 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kvstore
+
+import (
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func (e *EmbeddedSuite) TestResync(c *C) {
+	prefix := "unit-test/"
+
+	DeletePrefix(prefix)
+	defer DeletePrefix(prefix)
+
+	RegisterResync("unit-test", func() (int, error) {
+		return 1, Update(testKey(prefix, 0), testValue(0), true)
+	})
+	defer UnregisterResync("unit-test")
+
+	c.Assert(Update(testKey(prefix, 0), testValue(0), true), IsNil)
+
+	renewalsBefore := GetResyncStatus().Renewals
+
+	// The leased key is removed when the lease expires
+	Client().(*embeddedClient).expireLeases(time.Now().Add(LeaseTTL + time.Second))
+	val, err := Get(testKey(prefix, 0))
+	c.Assert(err, IsNil)
+	c.Assert(val, IsNil)
+
+	c.Assert(KeepAlive(leaseInstance), Equals, ErrLeaseExpired)
+	c.Assert(renewDefaultLease(), IsNil)
+
+	var status ResyncSubsystemStatus
+	for i := 0; i < 100; i++ {
+		for _, s := range GetResyncStatus().Subsystems {
+			if s.Name == "unit-test" {
+				status = s
+			}
+		}
+		if status.State == ResyncStateOk {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	c.Assert(status, DeepEquals, ResyncSubsystemStatus{Name: "unit-test", State: ResyncStateOk, Keys: 1})
+	c.Assert(GetResyncStatus().Renewals, Equals, renewalsBefore+1)
+
+	// The key has been re-created and is attached to the new lease
+	val, err = Get(testKey(prefix, 0))
+	c.Assert(err, IsNil)
+	c.Assert(val, DeepEquals, testValue(0))
+	c.Assert(KeepAlive(leaseInstance), IsNil)
+}
@@ -0,0 +1,279 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kvstore
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cilium/cilium/pkg/lock"
+)
+
+const (
+	// SnapshotVersion is the version of the snapshot format written by
+	// WriteSnapshot
+	SnapshotVersion = 1
+)
+
+// SnapshotPair is a single key and its value in a snapshot
+type SnapshotPair struct {
+	Key   string `json:"key"`
+	Value []byte `json:"value"`
+}
+
+// Snapshot is a portable copy of all keys below a prefix. Parts of keys and
+// values which have been encoded with Encode() are stored decoded so that a
+// snapshot can be imported into a backend with a different encoding.
+type Snapshot struct {
+	// Version is the version of the snapshot format
+	Version int `json:"version"`
+
+	// Created is the time the snapshot was created
+	Created time.Time `json:"created"`
+
+	// Backend is the name of the backend the snapshot was exported from
+	Backend string `json:"backend"`
+
+	// Prefix is the prefix of all keys in the snapshot
+	Prefix string `json:"prefix"`
+
+	// Pairs is the list of keys and values, sorted by key
+	Pairs []SnapshotPair `json:"pairs"`
+}
+
+// SnapshotCodec rewrites the parts of a key and its value which have been
+// encoded with Encode(), using convert on each encoded part. On export,
+// convert decodes from the backend encoding, on import it encodes into the
+// backend encoding.
+type SnapshotCodec func(key string, value []byte, convert func(string) (string, error)) (string, []byte, error)
+
+var (
+	snapshotCodecsMutex lock.RWMutex
+	snapshotCodecs      = map[string]SnapshotCodec{}
+)
+
+// RegisterSnapshotCodec registers a codec for all keys below prefix. Users
+// of Encode() must register a codec so their keys remain valid when imported
+// into a backend with a different encoding.
+func RegisterSnapshotCodec(prefix string, codec SnapshotCodec) {
+	snapshotCodecsMutex.Lock()
+	snapshotCodecs[prefix] = codec
+	snapshotCodecsMutex.Unlock()
+}
+
+// convertPair applies the codec of the longest registered prefix matching
+// key, if any
+func convertPair(key string, value []byte, convert func(string) (string, error)) (string, []byte, error) {
+	snapshotCodecsMutex.RLock()
+	defer snapshotCodecsMutex.RUnlock()
+
+	var (
+		codec   SnapshotCodec
+		longest = -1
+	)
+	for prefix, c := range snapshotCodecs {
+		if strings.HasPrefix(key, prefix) && len(prefix) > longest {
+			codec, longest = c, len(prefix)
+		}
+	}
+
+	if codec == nil {
+		return key, value, nil
+	}
+
+	newKey, newValue, err := codec(key, value, convert)
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to convert key %s: %s", key, err)
+	}
+	return newKey, newValue, nil
+}
+
+func decodeString(in string) (string, error) {
+	out, err := Decode(in)
+	return string(out), err
+}
+
+func encodeString(in string) (string, error) {
+	return Encode([]byte(in)), nil
+}
+
+// ExportSnapshot returns a snapshot of all keys below prefix
+func ExportSnapshot(prefix string) (*Snapshot, error) {
+	pairs, err := ListPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Snapshot{
+		Version: SnapshotVersion,
+		Created: time.Now().UTC(),
+		Backend: selectedModule,
+		Prefix:  prefix,
+		Pairs:   make([]SnapshotPair, 0, len(pairs)),
+	}
+
+	for key, value := range pairs {
+		key, value, err = convertPair(key, value, decodeString)
+		if err != nil {
+			return nil, err
+		}
+		s.Pairs = append(s.Pairs, SnapshotPair{Key: key, Value: value})
+	}
+
+	sort.Slice(s.Pairs, func(i, j int) bool {
+		return s.Pairs[i].Key < s.Pairs[j].Key
+	})
+
+	return s, nil
+}
+
+// WriteSnapshot writes the snapshot to w
+func WriteSnapshot(w io.Writer, s *Snapshot) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(s)
+}
+
+// ReadSnapshot reads a snapshot written by WriteSnapshot from r
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	s := &Snapshot{}
+	if err := json.NewDecoder(r).Decode(s); err != nil {
+		return nil, fmt.Errorf("unable to decode snapshot: %s", err)
+	}
+
+	if s.Version != SnapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d, expected %d", s.Version, SnapshotVersion)
+	}
+
+	for _, p := range s.Pairs {
+		if !strings.HasPrefix(p.Key, s.Prefix) {
+			return nil, fmt.Errorf("key %s outside of snapshot prefix %s", p.Key, s.Prefix)
+		}
+	}
+
+	return s, nil
+}
+
+// SnapshotDiff is the difference between a snapshot and the keys in the
+// kvstore
+type SnapshotDiff struct {
+	// Added are the keys of the snapshot which do not exist in the
+	// kvstore
+	Added []string
+
+	// Modified are the keys of the snapshot which exist in the kvstore
+	// with a different value
+	Modified []string
+
+	// Removed are the keys in the kvstore below the snapshot prefix which
+	// do not exist in the snapshot
+	Removed []string
+
+	// Unchanged is the number of keys with identical values
+	Unchanged int
+}
+
+// Empty returns true if the kvstore does not contain any key below the
+// snapshot prefix
+func (d *SnapshotDiff) Empty() bool {
+	return len(d.Modified) == 0 && len(d.Removed) == 0 && d.Unchanged == 0
+}
+
+// encodePairs returns the snapshot pairs in the encoding of the backend
+func encodePairs(s *Snapshot) (KeyValuePairs, error) {
+	pairs := KeyValuePairs{}
+	for _, p := range s.Pairs {
+		key, value, err := convertPair(p.Key, p.Value, encodeString)
+		if err != nil {
+			return nil, err
+		}
+		pairs[key] = value
+	}
+	return pairs, nil
+}
+
+// DiffSnapshot compares the snapshot with the keys in the kvstore
+func DiffSnapshot(s *Snapshot) (*SnapshotDiff, error) {
+	wanted, err := encodePairs(s)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := ListPrefix(s.Prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &SnapshotDiff{}
+	for key, value := range wanted {
+		currentValue, ok := current[key]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, key)
+		case !bytes.Equal(currentValue, value):
+			diff.Modified = append(diff.Modified, key)
+		default:
+			diff.Unchanged++
+		}
+	}
+	for key := range current {
+		if _, ok := wanted[key]; !ok {
+			diff.Removed = append(diff.Removed, key)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Modified)
+	sort.Strings(diff.Removed)
+
+	return diff, nil
+}
+
+// ImportSnapshot creates all keys of the snapshot. The kvstore must not
+// contain any key below the snapshot prefix. Keys are created without a
+// lease.
+func ImportSnapshot(s *Snapshot) error {
+	diff, err := DiffSnapshot(s)
+	if err != nil {
+		return err
+	}
+	if !diff.Empty() {
+		return fmt.Errorf("kvstore already contains keys below prefix %s", s.Prefix)
+	}
+
+	pairs, err := encodePairs(s)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(pairs))
+	for key := range pairs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if err := CreateOnly(key, pairs[key], false); err != nil {
+			return fmt.Errorf("unable to create key %s: %s", key, err)
+		}
+	}
+
+	return nil
+}
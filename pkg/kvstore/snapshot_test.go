@@ -0,0 +1,105 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kvstore
+
+import (
+	"bytes"
+	"strings"
+
+	. "gopkg.in/check.v1"
+)
+
+const snapshotTestPrefix = "snapshot-test/"
+
+// convertTestPair converts the last component of keys below
+// snapshot-test/encoded/
+func convertTestPair(key string, value []byte, convert func(string) (string, error)) (string, []byte, error) {
+	i := strings.LastIndex(key, "/")
+	encoded, err := convert(key[i+1:])
+	if err != nil {
+		return "", nil, err
+	}
+	return key[:i+1] + encoded, value, nil
+}
+
+func (e *EmbeddedSuite) TestSnapshot(c *C) {
+	RegisterSnapshotCodec(snapshotTestPrefix+"encoded/", convertTestPair)
+
+	DeletePrefix(snapshotTestPrefix)
+	defer DeletePrefix(snapshotTestPrefix)
+
+	encodedKey := snapshotTestPrefix + "encoded/" + Encode([]byte("k8s:app=web"))
+	c.Assert(Set(snapshotTestPrefix+"a", []byte("1")), IsNil)
+	c.Assert(Set(snapshotTestPrefix+"b", []byte{0, 1, 2}), IsNil)
+	c.Assert(Set(encodedKey, []byte("3")), IsNil)
+
+	s, err := ExportSnapshot(snapshotTestPrefix)
+	c.Assert(err, IsNil)
+	c.Assert(s.Version, Equals, SnapshotVersion)
+	c.Assert(s.Backend, Equals, EmbeddedBackendName)
+	c.Assert(s.Pairs, DeepEquals, []SnapshotPair{
+		{Key: snapshotTestPrefix + "a", Value: []byte("1")},
+		{Key: snapshotTestPrefix + "b", Value: []byte{0, 1, 2}},
+		{Key: snapshotTestPrefix + "encoded/k8s:app=web", Value: []byte("3")},
+	})
+
+	var buf bytes.Buffer
+	c.Assert(WriteSnapshot(&buf, s), IsNil)
+	read, err := ReadSnapshot(&buf)
+	c.Assert(err, IsNil)
+	c.Assert(read.Pairs, DeepEquals, s.Pairs)
+
+	// The kvstore is identical to the snapshot
+	diff, err := DiffSnapshot(read)
+	c.Assert(err, IsNil)
+	c.Assert(diff, DeepEquals, &SnapshotDiff{Unchanged: 3})
+	c.Assert(ImportSnapshot(read), Not(IsNil))
+
+	c.Assert(Set(snapshotTestPrefix+"a", []byte("2")), IsNil)
+	c.Assert(Delete(snapshotTestPrefix+"b"), IsNil)
+	c.Assert(Set(snapshotTestPrefix+"c", []byte("4")), IsNil)
+	diff, err = DiffSnapshot(read)
+	c.Assert(err, IsNil)
+	c.Assert(diff, DeepEquals, &SnapshotDiff{
+		Added:     []string{snapshotTestPrefix + "b"},
+		Modified:  []string{snapshotTestPrefix + "a"},
+		Removed:   []string{snapshotTestPrefix + "c"},
+		Unchanged: 1,
+	})
+
+	// Import into an empty store restores all keys
+	c.Assert(DeletePrefix(snapshotTestPrefix), IsNil)
+	c.Assert(ImportSnapshot(read), IsNil)
+
+	pairs, err := ListPrefix(snapshotTestPrefix)
+	c.Assert(err, IsNil)
+	c.Assert(pairs, DeepEquals, KeyValuePairs{
+		snapshotTestPrefix + "a": []byte("1"),
+		snapshotTestPrefix + "b": []byte{0, 1, 2},
+		encodedKey:               []byte("3"),
+	})
+}
+
+func (s *independentSuite) TestReadSnapshot(c *C) {
+	_, err := ReadSnapshot(strings.NewReader(`{"version": 2, "prefix": "cilium", "pairs": []}`))
+	c.Assert(err, ErrorMatches, "unsupported snapshot version 2.*")
+
+	_, err = ReadSnapshot(strings.NewReader(`{"version": 1, "prefix": "cilium", "pairs": [{"key": "foo", "value": ""}]}`))
+	c.Assert(err, ErrorMatches, "key foo outside of snapshot prefix cilium")
+
+	snapshot, err := ReadSnapshot(strings.NewReader(`{"version": 1, "prefix": "cilium", "pairs": [{"key": "cilium/foo", "value": "YmFy"}]}`))
+	c.Assert(err, IsNil)
+	c.Assert(snapshot.Pairs, DeepEquals, []SnapshotPair{{Key: "cilium/foo", Value: []byte("bar")}})
+}
@@ -0,0 +1,231 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kvstore
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"time"
+
+	"github.com/cilium/cilium/pkg/controller"
+	"github.com/cilium/cilium/pkg/lock"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/credentials"
+)
+
+var (
+	// TLSReloadInterval is the interval in which the certificate files
+	// are checked for changes
+	TLSReloadInterval = time.Minute
+)
+
+// tlsReloader holds the client certificate and CA bundle used to connect to
+// the kvstore. The files are reloaded whenever they change so certificates
+// can be rotated without restarting the agent. Each new connection uses the
+// certificates loaded at the time the connection is established.
+type tlsReloader struct {
+	certFile string
+	keyFile  string
+	caFile   string
+
+	mutex    lock.RWMutex
+	cert     *tls.Certificate
+	rootCAs  *x509.CertPool
+	modTimes map[string]time.Time
+}
+
+// newTLSReloader returns a tlsReloader for the given files or nil if no file
+// is specified. An error is returned if the files cannot be loaded.
+func newTLSReloader(certFile, keyFile, caFile string) (*tlsReloader, error) {
+	if certFile == "" && keyFile == "" && caFile == "" {
+		return nil, nil
+	}
+
+	r := &tlsReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		caFile:   caFile,
+		modTimes: map[string]time.Time{},
+	}
+
+	if _, err := r.reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// files returns all configured files
+func (r *tlsReloader) files() []string {
+	files := []string{}
+	for _, f := range []string{r.certFile, r.keyFile, r.caFile} {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// reload loads the certificate files if any of them has been modified since
+// the last load and returns true if the files have been loaded. The
+// previously loaded certificates remain in use if loading fails.
+func (r *tlsReloader) reload() (bool, error) {
+	modTimes := map[string]time.Time{}
+	changed := false
+
+	r.mutex.RLock()
+	for _, f := range r.files() {
+		info, err := os.Stat(f)
+		if err != nil {
+			r.mutex.RUnlock()
+			return false, err
+		}
+		modTimes[f] = info.ModTime()
+		if !info.ModTime().Equal(r.modTimes[f]) {
+			changed = true
+		}
+	}
+	r.mutex.RUnlock()
+
+	if !changed {
+		return false, nil
+	}
+
+	var cert *tls.Certificate
+	if r.certFile != "" {
+		c, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+		if err != nil {
+			return false, fmt.Errorf("unable to load client certificate: %s", err)
+		}
+		cert = &c
+	}
+
+	var rootCAs *x509.CertPool
+	if r.caFile != "" {
+		pem, err := ioutil.ReadFile(r.caFile)
+		if err != nil {
+			return false, err
+		}
+
+		rootCAs = x509.NewCertPool()
+		if !rootCAs.AppendCertsFromPEM(pem) {
+			return false, fmt.Errorf("no certificate found in %s", r.caFile)
+		}
+	}
+
+	r.mutex.Lock()
+	r.cert = cert
+	r.rootCAs = rootCAs
+	r.modTimes = modTimes
+	r.mutex.Unlock()
+
+	return true, nil
+}
+
+// startController starts a controller which periodically reloads the
+// certificate files
+func (r *tlsReloader) startController() {
+	kvstoreControllers.UpdateController("kvstore-tls-reload",
+		controller.ControllerParams{
+			DoFunc: func() error {
+				reloaded, err := r.reload()
+				if err != nil {
+					return err
+				}
+
+				if reloaded {
+					log.WithFields(logrus.Fields{
+						"certFile": r.certFile,
+						"caFile":   r.caFile,
+					}).Info("Reloaded kvstore TLS certificates")
+				}
+
+				return nil
+			},
+			RunInterval: TLSReloadInterval,
+		},
+	)
+}
+
+// clientConfig returns a new TLS configuration using the currently loaded
+// certificates
+func (r *tlsReloader) clientConfig() *tls.Config {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		RootCAs:    r.rootCAs,
+	}
+
+	if r.cert != nil {
+		config.Certificates = []tls.Certificate{*r.cert}
+	}
+
+	return config
+}
+
+// dialTLS establishes a TLS connection using the currently loaded
+// certificates. It can be used as DialTLS function of an http.Transport.
+func (r *tlsReloader) dialTLS(network, addr string) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	return tls.DialWithDialer(dialer, network, addr, r.clientConfig())
+}
+
+// reloadingCredentials are gRPC transport credentials which perform each
+// handshake with the certificates currently loaded by the tlsReloader
+type reloadingCredentials struct {
+	reloader   *tlsReloader
+	serverName string
+}
+
+func (c *reloadingCredentials) tls() credentials.TransportCredentials {
+	config := c.reloader.clientConfig()
+	config.ServerName = c.serverName
+	return credentials.NewTLS(config)
+}
+
+// ClientHandshake performs the TLS handshake with the current certificates
+func (c *reloadingCredentials) ClientHandshake(ctx context.Context, addr string, rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return c.tls().ClientHandshake(ctx, addr, rawConn)
+}
+
+// ServerHandshake is not supported as the credentials are only used by
+// clients
+func (c *reloadingCredentials) ServerHandshake(rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return nil, nil, fmt.Errorf("server handshake not supported")
+}
+
+// Info returns the protocol information of the credentials
+func (c *reloadingCredentials) Info() credentials.ProtocolInfo {
+	return c.tls().Info()
+}
+
+// Clone returns a copy of the credentials
+func (c *reloadingCredentials) Clone() credentials.TransportCredentials {
+	return &reloadingCredentials{reloader: c.reloader, serverName: c.serverName}
+}
+
+// OverrideServerName overrides the name used to verify the server certificate
+func (c *reloadingCredentials) OverrideServerName(serverName string) error {
+	c.serverName = serverName
+	return nil
+}
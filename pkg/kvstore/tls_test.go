@@ -0,0 +1,114 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kvstore
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+// writeTestCert writes a self-signed certificate with the given common name
+// and its key to dir and sets the modification time of both files to mtime
+func writeTestCert(c *C, dir, cn string, mtime time.Time) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	c.Assert(err, IsNil)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	c.Assert(err, IsNil)
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	c.Assert(err, IsNil)
+
+	certFile := filepath.Join(dir, "client.crt")
+	keyFile := filepath.Join(dir, "client.key")
+
+	err = ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	c.Assert(err, IsNil)
+	err = ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600)
+	c.Assert(err, IsNil)
+
+	c.Assert(os.Chtimes(certFile, mtime, mtime), IsNil)
+	c.Assert(os.Chtimes(keyFile, mtime, mtime), IsNil)
+
+	return certFile, keyFile
+}
+
+func subjectOf(c *C, r *tlsReloader) string {
+	config := r.clientConfig()
+	c.Assert(config.Certificates, HasLen, 1)
+	cert, err := x509.ParseCertificate(config.Certificates[0].Certificate[0])
+	c.Assert(err, IsNil)
+	return cert.Subject.CommonName
+}
+
+func (s *independentSuite) TestTLSReloader(c *C) {
+	r, err := newTLSReloader("", "", "")
+	c.Assert(err, IsNil)
+	c.Assert(r, IsNil)
+
+	dir, err := ioutil.TempDir("", "cilium-kvstore-tls")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+
+	mtime := time.Now().Add(-time.Minute)
+	certFile, keyFile := writeTestCert(c, dir, "first", mtime)
+
+	// The certificate also serves as CA bundle
+	r, err = newTLSReloader(certFile, keyFile, certFile)
+	c.Assert(err, IsNil)
+	c.Assert(r, Not(IsNil))
+	c.Assert(subjectOf(c, r), Equals, "first")
+	c.Assert(r.clientConfig().RootCAs, Not(IsNil))
+
+	// Unmodified files are not reloaded
+	reloaded, err := r.reload()
+	c.Assert(err, IsNil)
+	c.Assert(reloaded, Equals, false)
+
+	// Rotated certificates are picked up
+	writeTestCert(c, dir, "second", mtime.Add(time.Second))
+	reloaded, err = r.reload()
+	c.Assert(err, IsNil)
+	c.Assert(reloaded, Equals, true)
+	c.Assert(subjectOf(c, r), Equals, "second")
+
+	// Invalid files keep the previous certificate in use
+	c.Assert(ioutil.WriteFile(keyFile, []byte("invalid"), 0600), IsNil)
+	c.Assert(os.Chtimes(keyFile, mtime.Add(2*time.Second), mtime.Add(2*time.Second)), IsNil)
+	_, err = r.reload()
+	c.Assert(err, Not(IsNil))
+	c.Assert(subjectOf(c, r), Equals, "second")
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/cilium/cilium/common"
@@ -31,6 +32,14 @@ var (
 	log                  = logging.DefaultLogger
 	validLabelPrefixesMU lock.RWMutex
 	validLabelPrefixes   *labelPrefixCfg // Label prefixes used to filter from all labels
+
+	// labelPrefixFile is the label prefix file the configuration has been
+	// read from
+	labelPrefixFile string
+
+	// labelPrefixes is the list of label prefixes appended to the label
+	// prefixes of the label prefix file or the default label prefixes
+	labelPrefixes []string
 )
 
 const (
@@ -117,6 +126,10 @@ func ParseLabelPrefixCfg(prefixes []string, file string) error {
 	}
 
 	for _, label := range prefixes {
+		if label == "" {
+			return fmt.Errorf("empty label prefix")
+		}
+
 		p, err := parseLabelPrefix(label)
 		if err != nil {
 			return err
@@ -129,16 +142,60 @@ func ParseLabelPrefixCfg(prefixes []string, file string) error {
 		cfg.LabelPrefixes = append(cfg.LabelPrefixes, p)
 	}
 
+	validLabelPrefixesMU.Lock()
 	validLabelPrefixes = cfg
+	labelPrefixFile = file
+	labelPrefixes = append([]string{}, prefixes...)
+	validLabelPrefixesMU.Unlock()
 
 	log.Info("Valid label prefix configuration:")
-	for _, l := range validLabelPrefixes.LabelPrefixes {
+	for _, l := range cfg.LabelPrefixes {
 		log.Infof(" - %s", l)
 	}
 
 	return nil
 }
 
+// SetLabelPrefixes replaces the list of label prefixes which has been passed
+// to ParseLabelPrefixCfg() and re-reads the label prefix file. The
+// configuration is only replaced if it is valid. Labels of existing endpoints
+// are not filtered again, it is up to the caller to update them.
+func SetLabelPrefixes(prefixes []string) error {
+	validLabelPrefixesMU.RLock()
+	file := labelPrefixFile
+	validLabelPrefixesMU.RUnlock()
+
+	return ParseLabelPrefixCfg(prefixes, file)
+}
+
+// GetLabelPrefixes returns the list of label prefixes which has been passed to
+// ParseLabelPrefixCfg() or SetLabelPrefixes()
+func GetLabelPrefixes() []string {
+	validLabelPrefixesMU.RLock()
+	defer validLabelPrefixesMU.RUnlock()
+
+	return append([]string{}, labelPrefixes...)
+}
+
+// GetEffectiveLabelPrefixes returns all label prefixes in effect including the
+// default label prefixes or the label prefixes read from the label prefix
+// file
+func GetEffectiveLabelPrefixes() []string {
+	validLabelPrefixesMU.RLock()
+	defer validLabelPrefixesMU.RUnlock()
+
+	prefixes := []string{}
+	if validLabelPrefixes == nil {
+		return prefixes
+	}
+
+	for _, p := range validLabelPrefixes.LabelPrefixes {
+		prefixes = append(prefixes, p.String())
+	}
+
+	return prefixes
+}
+
 // labelPrefixCfg is the label prefix configuration to filter labels of started
 // containers.
 // +k8s:openapi-gen=false
@@ -217,42 +274,58 @@ func readLabelPrefixCfgFrom(fileName string) (*labelPrefixCfg, error) {
 	return &lpc, nil
 }
 
-func (cfg *labelPrefixCfg) filterLabels(lbls Labels) (identityLabels, informationLabels Labels) {
-	validLabelPrefixesMU.RLock()
-	defer validLabelPrefixesMU.RUnlock()
-
-	identityLabels = Labels{}
-	informationLabels = Labels{}
-	for k, v := range lbls {
-		included, ignored := 0, 0
-
-		for _, p := range cfg.LabelPrefixes {
-			if m, len := p.matches(v); m {
-				if p.Ignore {
-					// save length of shortest matching ignore
-					if ignored == 0 || len < ignored {
-						ignored = len
-					}
-				} else {
-					// save length of longest matching include
-					if len > included {
-						included = len
-					}
+// decide returns whether the label is part of the identity and the label
+// prefix which decided it. The returned label prefix is nil if the decision
+// was not made by a specific label prefix.
+func (cfg *labelPrefixCfg) decide(l *Label) (bool, *LabelPrefix) {
+	included, ignored := 0, 0
+	var includedBy, ignoredBy *LabelPrefix
+
+	for _, p := range cfg.LabelPrefixes {
+		if m, len := p.matches(l); m {
+			if p.Ignore {
+				// save length of shortest matching ignore
+				if ignored == 0 || len < ignored {
+					ignored = len
+					ignoredBy = p
+				}
+			} else {
+				// save length of longest matching include
+				if len > included {
+					included = len
+					includedBy = p
 				}
 			}
 		}
+	}
+
+	// A label is accepted if :
+	// - No inclusive LabelPrefix (Ignore flag not set) is
+	//   configured and label is not ignored.
+	// - An inclusive LabelPrefix matches the label
+	// - If both an inclusive and ignore LabelPrefix match, the
+	//   label is accepted if the matching section in the label
+	//   is greater than the ignored matching section in label,
+	//   e.g. when evaluating the label foo.bar, the prefix rules
+	//   {!foo, foo.bar} will cause the label to be accepted
+	//   because the inclusive prefix matches over a longer section.
+	if (!cfg.whitelist && ignored == 0) || included > ignored {
+		return true, includedBy
+	}
+
+	if ignored == 0 {
+		// no inclusive LabelPrefix matched
+		return false, nil
+	}
+
+	return false, ignoredBy
+}
 
-		// A label is accepted if :
-		// - No inclusive LabelPrefix (Ignore flag not set) is
-		//   configured and label is not ignored.
-		// - An inclusive LabelPrefix matches the label
-		// - If both an inclusive and ignore LabelPrefix match, the
-		//   label is accepted if the matching section in the label
-		//   is greater than the ignored matching section in label,
-		//   e.g. when evaluating the label foo.bar, the prefix rules
-		//   {!foo, foo.bar} will cause the label to be accepted
-		//   because the inclusive prefix matches over a longer section.
-		if (!cfg.whitelist && ignored == 0) || included > ignored {
+func (cfg *labelPrefixCfg) filterLabels(lbls Labels) (identityLabels, informationLabels Labels) {
+	identityLabels = Labels{}
+	informationLabels = Labels{}
+	for k, v := range lbls {
+		if kept, _ := cfg.decide(v); kept {
 			// Just want to make sure we don't have labels deleted in
 			// on side and disappearing in the other side...
 			identityLabels[k] = v.DeepCopy()
@@ -263,9 +336,58 @@ func (cfg *labelPrefixCfg) filterLabels(lbls Labels) (identityLabels, informatio
 	return identityLabels, informationLabels
 }
 
+// FilterDecision is the decision of the label prefix filter for a single
+// label
+// +k8s:deepcopy-gen=false
+// +k8s:openapi-gen=false
+type FilterDecision struct {
+	// Label is the label the decision was made for
+	Label *Label
+
+	// Kept is true if the label is used to determine the identity
+	Kept bool
+
+	// Prefix is the label prefix which decided about the label. It is nil
+	// if no label prefix matched the label, i.e. the label was kept
+	// because no inclusive label prefix is configured or the label was
+	// dropped because no inclusive label prefix matched it.
+	Prefix *LabelPrefix
+}
+
+func (cfg *labelPrefixCfg) explainLabels(lbls Labels) []FilterDecision {
+	keys := make([]string, 0, len(lbls))
+	for k := range lbls {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	decisions := make([]FilterDecision, 0, len(lbls))
+	for _, k := range keys {
+		kept, prefix := cfg.decide(lbls[k])
+		decisions = append(decisions, FilterDecision{
+			Label:  lbls[k].DeepCopy(),
+			Kept:   kept,
+			Prefix: prefix,
+		})
+	}
+	return decisions
+}
+
 // FilterLabels returns Labels from the given labels that have the same source and the
 // same prefix as one of lpc valid prefixes, as well as labels that do not match
 // the aforementioned filtering criteria.
 func FilterLabels(lbls Labels) (identityLabels, informationLabels Labels) {
+	validLabelPrefixesMU.RLock()
+	defer validLabelPrefixesMU.RUnlock()
+
 	return validLabelPrefixes.filterLabels(lbls)
 }
+
+// ExplainLabels returns the decision of the label prefix filter for each of
+// the given labels, sorted by label key.
+func ExplainLabels(lbls Labels) []FilterDecision {
+	validLabelPrefixesMU.RLock()
+	defer validLabelPrefixesMU.RUnlock()
+
+	return validLabelPrefixes.explainLabels(lbls)
+}
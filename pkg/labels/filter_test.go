@@ -69,3 +69,76 @@ func (s *LabelsPrefCfgSuite) TestFilterLabels(c *C) {
 	allLabels["id.lizards"].Source = "I can change this and doesn't affect any one"
 	c.Assert(filtered, comparator.DeepEquals, wanted)
 }
+
+func (s *LabelsPrefCfgSuite) TestExplainLabels(c *C) {
+	cfg := defaultLabelPrefixCfg()
+	p, err := parseLabelPrefix("app")
+	c.Assert(err, IsNil)
+	cfg.LabelPrefixes = append(cfg.LabelPrefixes, p)
+	cfg.whitelist = true
+
+	lbls := Map2Labels(map[string]string{
+		"app":                         "web",
+		"io.kubernetes.pod.name":      "web-1234",
+		"io.kubernetes.pod.namespace": "default",
+		"tier":                        "frontend",
+	}, LabelSourceK8s)
+
+	decisions := cfg.explainLabels(lbls)
+	c.Assert(decisions, HasLen, 4)
+
+	// decisions are sorted by label key
+	c.Assert(decisions[0].Label.Key, Equals, "app")
+	c.Assert(decisions[0].Kept, Equals, true)
+	c.Assert(decisions[0].Prefix.String(), Equals, ":app")
+
+	c.Assert(decisions[1].Label.Key, Equals, "io.kubernetes.pod.name")
+	c.Assert(decisions[1].Kept, Equals, false)
+	c.Assert(decisions[1].Prefix.String(), Equals, "!:io.kubernetes")
+
+	c.Assert(decisions[2].Label.Key, Equals, "io.kubernetes.pod.namespace")
+	c.Assert(decisions[2].Kept, Equals, true)
+	c.Assert(decisions[2].Prefix.Ignore, Equals, false)
+
+	// no inclusive prefix matched
+	c.Assert(decisions[3].Label.Key, Equals, "tier")
+	c.Assert(decisions[3].Kept, Equals, false)
+	c.Assert(decisions[3].Prefix, IsNil)
+
+	identityLabels, _ := cfg.filterLabels(lbls)
+	for _, d := range decisions {
+		_, ok := identityLabels[d.Label.Key]
+		c.Assert(ok, Equals, d.Kept)
+	}
+}
+
+func (s *LabelsPrefCfgSuite) TestSetLabelPrefixes(c *C) {
+	// restore the package wide configuration for other tests
+	validLabelPrefixesMU.RLock()
+	oldCfg, oldFile, oldPrefixes := validLabelPrefixes, labelPrefixFile, labelPrefixes
+	validLabelPrefixesMU.RUnlock()
+	defer func() {
+		validLabelPrefixesMU.Lock()
+		validLabelPrefixes, labelPrefixFile, labelPrefixes = oldCfg, oldFile, oldPrefixes
+		validLabelPrefixesMU.Unlock()
+	}()
+
+	c.Assert(ParseLabelPrefixCfg([]string{"k8s:app"}, ""), IsNil)
+	c.Assert(GetLabelPrefixes(), DeepEquals, []string{"k8s:app"})
+
+	lbls := Map2Labels(map[string]string{"app": "web", "tier": "frontend"}, LabelSourceK8s)
+	identityLabels, _ := FilterLabels(lbls)
+	c.Assert(identityLabels, HasLen, 1)
+
+	c.Assert(SetLabelPrefixes([]string{"k8s:app", "k8s:tier"}), IsNil)
+	c.Assert(GetLabelPrefixes(), DeepEquals, []string{"k8s:app", "k8s:tier"})
+	c.Assert(GetEffectiveLabelPrefixes(), HasLen, len(defaultLabelPrefixCfg().LabelPrefixes)+2)
+
+	identityLabels, _ = FilterLabels(lbls)
+	c.Assert(identityLabels, HasLen, 2)
+
+	// an invalid configuration must not replace the current one
+	c.Assert(SetLabelPrefixes([]string{"k8s:[invalid"}), Not(IsNil))
+	c.Assert(SetLabelPrefixes([]string{""}), Not(IsNil))
+	c.Assert(GetLabelPrefixes(), DeepEquals, []string{"k8s:app", "k8s:tier"})
+}
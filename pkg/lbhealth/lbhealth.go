@@ -0,0 +1,126 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lbhealth implements the health checks of load-balancer backends.
+package lbhealth
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/cilium/cilium/common/types"
+)
+
+// Probe runs the health check hc against the backend be once. Returns nil if
+// the backend passed the check.
+func Probe(hc types.LBHealthCheck, be types.LBBackEnd) error {
+	port := hc.Port
+	if port == 0 {
+		port = be.Port
+	}
+	if port == 0 {
+		return fmt.Errorf("no port to check")
+	}
+	addr := net.JoinHostPort(be.IP.String(), strconv.Itoa(int(port)))
+
+	switch hc.Type {
+	case types.LBHealthCheckTCP:
+		conn, err := net.DialTimeout("tcp", addr, hc.Timeout)
+		if err != nil {
+			return err
+		}
+		conn.Close()
+		return nil
+
+	case types.LBHealthCheckHTTP:
+		client := http.Client{
+			Timeout: hc.Timeout,
+			// Redirects count as healthy and are not followed
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+		resp, err := client.Get("http://" + addr + hc.Path)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+			return fmt.Errorf("unexpected status %s", resp.Status)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unknown health check type %q", hc.Type)
+}
+
+// Tracker derives the health of the backends of a service from the results
+// of consecutive health checks. Backends are identified by their address.
+// Tracker is not safe for concurrent use.
+type Tracker struct {
+	failures  map[string]int
+	successes map[string]int
+}
+
+// NewTracker returns a new Tracker.
+func NewTracker() *Tracker {
+	return &Tracker{
+		failures:  map[string]int{},
+		successes: map[string]int{},
+	}
+}
+
+// Update records the result err of a health check of the backend with the
+// given address and returns the new health of the backend. A backend turns
+// unhealthy after hc.UnhealthyThreshold consecutive failures and healthy
+// again after hc.HealthyThreshold consecutive successes. A backend with
+// unknown health turns healthy on the first success.
+func (t *Tracker) Update(hc types.LBHealthCheck, addr string, current types.LBBackEndHealth, err error) types.LBBackEndHealth {
+	if current == "" {
+		current = types.LBBackEndHealthUnknown
+	}
+
+	if err == nil {
+		t.failures[addr] = 0
+		t.successes[addr]++
+		if current == types.LBBackEndHealthUnknown || t.successes[addr] >= hc.HealthyThreshold {
+			return types.LBBackEndHealthHealthy
+		}
+		return current
+	}
+
+	t.successes[addr] = 0
+	t.failures[addr]++
+	if t.failures[addr] >= hc.UnhealthyThreshold {
+		return types.LBBackEndHealthUnhealthy
+	}
+	return current
+}
+
+// Prune forgets all backends whose address is not in addrs.
+func (t *Tracker) Prune(addrs map[string]struct{}) {
+	for addr := range t.failures {
+		if _, ok := addrs[addr]; !ok {
+			delete(t.failures, addr)
+		}
+	}
+	for addr := range t.successes {
+		if _, ok := addrs[addr]; !ok {
+			delete(t.successes, addr)
+		}
+	}
+}
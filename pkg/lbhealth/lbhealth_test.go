@@ -0,0 +1,116 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lbhealth
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cilium/cilium/common/types"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type LBHealthSuite struct{}
+
+var _ = Suite(&LBHealthSuite{})
+
+func backend(c *C, addr net.Addr) types.LBBackEnd {
+	tcpAddr := addr.(*net.TCPAddr)
+	be, err := types.NewLBBackEnd(types.TCP, tcpAddr.IP, uint16(tcpAddr.Port), 0)
+	c.Assert(err, IsNil)
+	return *be
+}
+
+func (s *LBHealthSuite) TestProbeTCP(c *C) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+
+	hc := types.LBHealthCheck{Type: types.LBHealthCheckTCP, Timeout: time.Second}
+	be := backend(c, l.Addr())
+	c.Assert(Probe(hc, be), IsNil)
+
+	l.Close()
+	c.Assert(Probe(hc, be), Not(IsNil))
+
+	be.Port = 0
+	c.Assert(Probe(hc, be), Not(IsNil))
+}
+
+func (s *LBHealthSuite) TestProbeHTTP(c *C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/healthz":
+			fmt.Fprintln(w, "ok")
+		case "/moved":
+			http.Redirect(w, r, "/elsewhere", http.StatusFound)
+		default:
+			http.Error(w, "failed", http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	be := backend(c, server.Listener.Addr())
+	hc := types.LBHealthCheck{Type: types.LBHealthCheckHTTP, Path: "/healthz", Timeout: time.Second}
+	c.Assert(Probe(hc, be), IsNil)
+
+	hc.Path = "/moved"
+	c.Assert(Probe(hc, be), IsNil)
+
+	hc.Path = "/"
+	c.Assert(Probe(hc, be), Not(IsNil))
+}
+
+func (s *LBHealthSuite) TestTracker(c *C) {
+	hc := types.LBHealthCheck{UnhealthyThreshold: 2, HealthyThreshold: 2}
+	failed := fmt.Errorf("failed")
+	t := NewTracker()
+
+	h := t.Update(hc, "a", "", nil)
+	c.Assert(h, Equals, types.LBBackEndHealthHealthy)
+
+	// A single failure does not change the health
+	h = t.Update(hc, "a", h, failed)
+	c.Assert(h, Equals, types.LBBackEndHealthHealthy)
+	h = t.Update(hc, "a", h, nil)
+	h = t.Update(hc, "a", h, failed)
+	c.Assert(h, Equals, types.LBBackEndHealthHealthy)
+	h = t.Update(hc, "a", h, failed)
+	c.Assert(h, Equals, types.LBBackEndHealthUnhealthy)
+
+	h = t.Update(hc, "a", h, nil)
+	c.Assert(h, Equals, types.LBBackEndHealthUnhealthy)
+	h = t.Update(hc, "a", h, nil)
+	c.Assert(h, Equals, types.LBBackEndHealthHealthy)
+
+	// Backends with unknown health remain unknown until the threshold
+	h = t.Update(hc, "b", types.LBBackEndHealthUnknown, failed)
+	c.Assert(h, Equals, types.LBBackEndHealthUnknown)
+	h = t.Update(hc, "b", h, failed)
+	c.Assert(h, Equals, types.LBBackEndHealthUnhealthy)
+
+	t.Prune(map[string]struct{}{"a": {}})
+	c.Assert(t.failures, HasLen, 1)
+	c.Assert(t.successes, HasLen, 1)
+}
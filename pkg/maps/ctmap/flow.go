@@ -0,0 +1,131 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ctmap
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/identity"
+	"github.com/cilium/cilium/pkg/u8proto"
+)
+
+// FlowEntry is the address family independent representation of a CT entry.
+// Addresses and ports are oriented in the direction of the packet which
+// created the entry, ports are in host byte order.
+type FlowEntry struct {
+	SrcIP   net.IP
+	DstIP   net.IP
+	SrcPort uint16
+	DstPort uint16
+	Proto   u8proto.U8proto
+
+	// Ingress is true if the entry tracks a connection towards a local
+	// endpoint
+	Ingress bool
+
+	// Related is true if the entry tracks a related flow, e.g. ICMP errors
+	Related bool
+
+	// RevNAT is the reverse NAT index of the service the connection has
+	// been load-balanced by or 0 if it has not been load-balanced
+	RevNAT uint16
+
+	RxPackets uint64
+	RxBytes   uint64
+	TxPackets uint64
+	TxBytes   uint64
+
+	// Lifetime is the time of the monotonic clock in seconds at which the
+	// entry expires
+	Lifetime uint32
+
+	// SrcSecID is the security identity of the source recorded by the
+	// datapath
+	SrcSecID identity.NumericIdentity
+}
+
+// String returns the flow tuple in human readable form
+func (f *FlowEntry) String() string {
+	dir := "OUT"
+	if f.Ingress {
+		dir = "IN"
+	}
+	return fmt.Sprintf("%s %s %s -> %s related=%t", f.Proto, dir,
+		net.JoinHostPort(f.SrcIP.String(), fmt.Sprintf("%d", f.SrcPort)),
+		net.JoinHostPort(f.DstIP.String(), fmt.Sprintf("%d", f.DstPort)),
+		f.Related)
+}
+
+// Expired returns true if the entry expired before the monotonic time now
+// given in seconds.
+func (f *FlowEntry) Expired(now uint32) bool {
+	return f.Lifetime < now
+}
+
+func newFlowEntry(src, dst net.IP, sport, dport uint16, proto u8proto.U8proto, flags uint8, e *CtEntry) FlowEntry {
+	return FlowEntry{
+		SrcIP:     src,
+		DstIP:     dst,
+		SrcPort:   byteorder.NetworkToHost(sport).(uint16),
+		DstPort:   byteorder.NetworkToHost(dport).(uint16),
+		Proto:     proto,
+		Ingress:   flags&TUPLE_F_IN != 0,
+		Related:   flags&TUPLE_F_RELATED != 0,
+		RevNAT:    byteorder.NetworkToHost(e.revnat).(uint16),
+		RxPackets: e.rx_packets,
+		RxBytes:   e.rx_bytes,
+		TxPackets: e.tx_packets,
+		TxBytes:   e.tx_bytes,
+		Lifetime:  e.lifetime,
+		SrcSecID:  identity.NumericIdentity(e.src_sec_id),
+	}
+}
+
+// DumpFlows iterates through the CT map m with name mapName and returns all
+// valid entries as FlowEntry.
+func DumpFlows(m *bpf.Map, mapName string) ([]FlowEntry, error) {
+	entries, err := dumpToSlice(m, mapName)
+	if err != nil {
+		return nil, err
+	}
+
+	flows := make([]FlowEntry, 0, len(entries))
+	for i := range entries {
+		value := &entries[i].Value
+
+		// In CT entries, the source address of the conntrack entry
+		// (`saddr`) is the destination of the packet received and the
+		// source port (`sport`) is its destination port.
+		switch k := entries[i].Key.(type) {
+		case *CtKey4Global:
+			if k.nexthdr == 0 {
+				continue
+			}
+			flows = append(flows, newFlowEntry(k.daddr.IP(), k.saddr.IP(),
+				k.dport, k.sport, k.nexthdr, k.flags, value))
+		case *CtKey6Global:
+			if k.nexthdr == 0 {
+				continue
+			}
+			flows = append(flows, newFlowEntry(k.daddr.IP(), k.saddr.IP(),
+				k.dport, k.sport, k.nexthdr, k.flags, value))
+		}
+	}
+
+	return flows, nil
+}
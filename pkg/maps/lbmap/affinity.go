@@ -0,0 +1,344 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lbmap
+
+import (
+	"fmt"
+	"net"
+	"time"
+	"unsafe"
+
+	"github.com/cilium/cilium/common/types"
+	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/byteorder"
+)
+
+const (
+	// maxAffinityEntries is the maximum number of clients with session
+	// affinity tracked across all services
+	maxAffinityEntries = 65536
+)
+
+var (
+	// AffinityMatchMap represents the BPF map holding the session affinity
+	// timeout of each service with session affinity
+	AffinityMatchMap = bpf.NewMap("cilium_lb_affinity_match",
+		bpf.MapTypeHash,
+		int(unsafe.Sizeof(AffinityMatchKey{})),
+		int(unsafe.Sizeof(AffinityMatchValue{})),
+		maxEntries,
+		0,
+		func(key []byte, value []byte) (bpf.MapKey, bpf.MapValue, error) {
+			k, v := AffinityMatchKey{}, AffinityMatchValue{}
+
+			if err := bpf.ConvertKeyValue(key, value, &k, &v); err != nil {
+				return nil, nil, err
+			}
+
+			return k.ToNetwork(), &v, nil
+		})
+	// Affinity4Map represents the BPF map for the backends selected for
+	// IPv4 clients of services with session affinity
+	Affinity4Map = bpf.NewMap("cilium_lb4_affinity",
+		bpf.MapTypeLRUHash,
+		int(unsafe.Sizeof(Affinity4Key{})),
+		int(unsafe.Sizeof(AffinityValue{})),
+		maxAffinityEntries,
+		0,
+		func(key []byte, value []byte) (bpf.MapKey, bpf.MapValue, error) {
+			k, v := Affinity4Key{}, AffinityValue{}
+
+			if err := bpf.ConvertKeyValue(key, value, &k, &v); err != nil {
+				return nil, nil, err
+			}
+
+			return k.ToNetwork(), &v, nil
+		})
+	// Affinity6Map represents the BPF map for the backends selected for
+	// IPv6 clients of services with session affinity
+	Affinity6Map = bpf.NewMap("cilium_lb6_affinity",
+		bpf.MapTypeLRUHash,
+		int(unsafe.Sizeof(Affinity6Key{})),
+		int(unsafe.Sizeof(AffinityValue{})),
+		maxAffinityEntries,
+		0,
+		func(key []byte, value []byte) (bpf.MapKey, bpf.MapValue, error) {
+			k, v := Affinity6Key{}, AffinityValue{}
+
+			if err := bpf.ConvertKeyValue(key, value, &k, &v); err != nil {
+				return nil, nil, err
+			}
+
+			return k.ToNetwork(), &v, nil
+		})
+)
+
+// AffinityMatchKey must match the key of 'cilium_lb_affinity_match' in
+// "bpf/lib/lb.h".
+type AffinityMatchKey struct {
+	RevNATID uint16
+}
+
+func (k AffinityMatchKey) NewValue() bpf.MapValue     { return &AffinityMatchValue{} }
+func (k *AffinityMatchKey) GetKeyPtr() unsafe.Pointer { return unsafe.Pointer(k) }
+func (k *AffinityMatchKey) String() string            { return fmt.Sprintf("%d", k.RevNATID) }
+
+// ToNetwork converts AffinityMatchKey to network byte order.
+func (k *AffinityMatchKey) ToNetwork() *AffinityMatchKey {
+	n := *k
+	n.RevNATID = byteorder.HostToNetwork(n.RevNATID).(uint16)
+	return &n
+}
+
+// AffinityMatchValue must match 'struct lb_affinity_match' in
+// "bpf/lib/common.h".
+type AffinityMatchValue struct {
+	// Timeout in seconds
+	Timeout uint32
+}
+
+func (v *AffinityMatchValue) GetValuePtr() unsafe.Pointer { return unsafe.Pointer(v) }
+func (v *AffinityMatchValue) String() string              { return fmt.Sprintf("timeout=%ds", v.Timeout) }
+
+// AffinityKey is the interface describing protocol independent key for the
+// session affinity maps.
+type AffinityKey interface {
+	bpf.MapKey
+
+	// Returns the client address
+	GetClientIP() net.IP
+
+	// Returns the reverse NAT ID of the service in host byte order
+	GetRevNATID() uint16
+}
+
+// Affinity4Key must match 'struct lb4_affinity_key' in "bpf/lib/common.h".
+type Affinity4Key struct {
+	ClientIP types.IPv4
+	RevNATID uint16
+	Pad      uint16
+}
+
+func (k Affinity4Key) NewValue() bpf.MapValue     { return &AffinityValue{} }
+func (k *Affinity4Key) GetKeyPtr() unsafe.Pointer { return unsafe.Pointer(k) }
+func (k *Affinity4Key) GetClientIP() net.IP       { return k.ClientIP.IP() }
+func (k *Affinity4Key) GetRevNATID() uint16       { return k.RevNATID }
+
+func (k *Affinity4Key) String() string {
+	return fmt.Sprintf("%s (%d)", k.ClientIP, k.RevNATID)
+}
+
+// ToNetwork converts Affinity4Key to network byte order.
+func (k *Affinity4Key) ToNetwork() *Affinity4Key {
+	n := *k
+	n.RevNATID = byteorder.HostToNetwork(n.RevNATID).(uint16)
+	return &n
+}
+
+// Affinity6Key must match 'struct lb6_affinity_key' in "bpf/lib/common.h".
+type Affinity6Key struct {
+	ClientIP types.IPv6
+	RevNATID uint16
+	Pad      uint16
+}
+
+func (k Affinity6Key) NewValue() bpf.MapValue     { return &AffinityValue{} }
+func (k *Affinity6Key) GetKeyPtr() unsafe.Pointer { return unsafe.Pointer(k) }
+func (k *Affinity6Key) GetClientIP() net.IP       { return k.ClientIP.IP() }
+func (k *Affinity6Key) GetRevNATID() uint16       { return k.RevNATID }
+
+func (k *Affinity6Key) String() string {
+	return fmt.Sprintf("%s (%d)", k.ClientIP, k.RevNATID)
+}
+
+// ToNetwork converts Affinity6Key to network byte order.
+func (k *Affinity6Key) ToNetwork() *Affinity6Key {
+	n := *k
+	n.RevNATID = byteorder.HostToNetwork(n.RevNATID).(uint16)
+	return &n
+}
+
+// AffinityValue must match 'struct lb_affinity_val' in "bpf/lib/common.h".
+type AffinityValue struct {
+	// LastUsed is the time in seconds of the monotonic clock at which
+	// the client has last been sent to the backend
+	LastUsed uint32
+	// Slave is the index of the backend (starting at 1)
+	Slave uint16
+	Pad   uint16
+}
+
+func (v *AffinityValue) GetValuePtr() unsafe.Pointer { return unsafe.Pointer(v) }
+
+func (v *AffinityValue) String() string {
+	return fmt.Sprintf("slave=%d last-used=%d", v.Slave, v.LastUsed)
+}
+
+// UpdateAffinityMatch enables session affinity with the given timeout for
+// the service with the given reverse NAT ID.
+func UpdateAffinityMatch(revNATID uint16, timeout time.Duration) error {
+	if _, err := AffinityMatchMap.OpenOrCreate(); err != nil {
+		return err
+	}
+
+	key := AffinityMatchKey{RevNATID: revNATID}
+	value := AffinityMatchValue{Timeout: uint32(timeout / time.Second)}
+
+	return AffinityMatchMap.Update(key.ToNetwork(), &value)
+}
+
+// LookupAffinityMatch returns the session affinity timeout of the service
+// with the given reverse NAT ID. ok is false if the service does not have
+// session affinity.
+func LookupAffinityMatch(revNATID uint16) (timeout time.Duration, ok bool) {
+	key := AffinityMatchKey{RevNATID: revNATID}
+	val, err := AffinityMatchMap.Lookup(key.ToNetwork())
+	if err != nil {
+		return 0, false
+	}
+
+	return time.Duration(val.(*AffinityMatchValue).Timeout) * time.Second, true
+}
+
+// DeleteAffinityMatch disables session affinity for the service with the
+// given reverse NAT ID. The clients tracked for the service are removed by
+// the next call to GCAffinity.
+func DeleteAffinityMatch(revNATID uint16) error {
+	if _, ok := LookupAffinityMatch(revNATID); !ok {
+		// Ignore if entry is not found.
+		return nil
+	}
+
+	key := AffinityMatchKey{RevNATID: revNATID}
+	return AffinityMatchMap.Delete(key.ToNetwork())
+}
+
+// affinityEntry is an entry of a session affinity map with the key in host
+// byte order
+type affinityEntry struct {
+	key   AffinityKey
+	value AffinityValue
+}
+
+// dumpAffinity returns all entries of the session affinity map m
+func dumpAffinity(m *bpf.Map) ([]affinityEntry, error) {
+	entries := []affinityEntry{}
+	err := m.DumpWithCallback(func(key bpf.MapKey, value bpf.MapValue) {
+		entries = append(entries, affinityEntry{
+			key:   key.(AffinityKey),
+			value: *value.(*AffinityValue),
+		})
+	})
+
+	return entries, err
+}
+
+// networkAffinityKey returns the key in network byte order
+func networkAffinityKey(key AffinityKey) bpf.MapKey {
+	switch k := key.(type) {
+	case *Affinity4Key:
+		return k.ToNetwork()
+	case *Affinity6Key:
+		return k.ToNetwork()
+	}
+	return key
+}
+
+// RemapAffinity updates the backends of the clients tracked for the service
+// with the given reverse NAT ID after the backends of the service have
+// changed. slaves maps the previous backend index of a client to its new
+// index. Clients of backends which are not part of slaves are removed so
+// that they are assigned a new backend. Returns the number of removed
+// clients.
+func RemapAffinity(revNATID uint16, ipv6 bool, slaves map[uint16]uint16) (int, error) {
+	m := Affinity4Map
+	if ipv6 {
+		m = Affinity6Map
+	}
+
+	entries, err := dumpAffinity(m)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, e := range entries {
+		if e.key.GetRevNATID() != revNATID {
+			continue
+		}
+
+		slave, ok := slaves[e.value.Slave]
+		switch {
+		case !ok:
+			if err := m.Delete(networkAffinityKey(e.key)); err == nil {
+				deleted++
+			}
+		case slave != e.value.Slave:
+			value := e.value
+			value.Slave = slave
+			if err := m.Update(networkAffinityKey(e.key), &value); err != nil {
+				return deleted, err
+			}
+		}
+	}
+
+	return deleted, nil
+}
+
+// GCAffinity removes the clients which have been idle for longer than the
+// session affinity timeout of their service as well as the clients of
+// services without session affinity. now is the current time of the
+// monotonic clock as returned by bpf.GetMtime(). Returns the number of
+// removed clients.
+func GCAffinity(now uint64) int {
+	tsec := uint32(now / uint64(time.Second))
+
+	// Without the timeouts, all clients would be considered to belong to
+	// services without session affinity
+	timeouts := map[uint16]uint32{}
+	err := AffinityMatchMap.DumpWithCallback(func(key bpf.MapKey, value bpf.MapValue) {
+		timeouts[key.(*AffinityMatchKey).RevNATID] = value.(*AffinityMatchValue).Timeout
+	})
+	if err != nil {
+		log.WithError(err).Warn("Unable to dump session affinity match map")
+		return 0
+	}
+
+	deleted := 0
+	for _, m := range []*bpf.Map{Affinity4Map, Affinity6Map} {
+		if err := m.Open(); err != nil {
+			continue
+		}
+
+		entries, err := dumpAffinity(m)
+		if err != nil {
+			log.WithError(err).Warn("Unable to dump session affinity map")
+			continue
+		}
+
+		for _, e := range entries {
+			timeout, ok := timeouts[e.key.GetRevNATID()]
+			if ok && e.value.LastUsed+timeout >= tsec {
+				continue
+			}
+
+			if err := m.Delete(networkAffinityKey(e.key)); err == nil {
+				deleted++
+			}
+		}
+	}
+
+	return deleted
+}
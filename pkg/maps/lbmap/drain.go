@@ -0,0 +1,111 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lbmap
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// DrainValue must match 'struct lb_drain' in "bpf/lib/common.h".
+type DrainValue struct {
+	// Slave is the index of the backend (starting at 1) receiving the
+	// new connections of the draining backend
+	Slave uint16
+	Pad   uint16
+}
+
+func (v *DrainValue) GetValuePtr() unsafe.Pointer { return unsafe.Pointer(v) }
+func (v *DrainValue) String() string              { return fmt.Sprintf("slave=%d", v.Slave) }
+
+// drainTargets returns the slaves receiving the new connections of the
+// draining backends. draining holds for each backend of the service, in the
+// order of the backends in the lb map, whether the backend is draining. The
+// new connections of the draining backends are spread across the backends
+// which are not draining. A draining backend is mapped to 0 if all backends
+// are draining.
+func drainTargets(draining []bool) map[uint16]uint16 {
+	active := []uint16{}
+	for i, d := range draining {
+		if !d {
+			active = append(active, uint16(i+1))
+		}
+	}
+
+	targets := map[uint16]uint16{}
+	n := 0
+	for i, d := range draining {
+		if !d {
+			continue
+		}
+		if len(active) == 0 {
+			targets[uint16(i+1)] = 0
+			continue
+		}
+		targets[uint16(i+1)] = active[n%len(active)]
+		n++
+	}
+
+	return targets
+}
+
+// UpdateDrain updates the draining backends of the service fe in the bpf
+// map. draining holds for each backend of the service, in the order of the
+// backends in the lb map, whether the backend is draining. New connections
+// of draining backends are sent to the other backends of the service unless
+// all backends are draining.
+func UpdateDrain(fe ServiceKey, draining []bool) error {
+	if _, err := fe.DrainMap().OpenOrCreate(); err != nil {
+		return err
+	}
+	defer fe.SetBackend(0)
+
+	targets := drainTargets(draining)
+	for i := range draining {
+		fe.SetBackend(i + 1)
+
+		slave, ok := targets[uint16(i+1)]
+		if !ok || slave == 0 {
+			if err := LookupAndDeleteServiceDrain(fe); err != nil {
+				return err
+			}
+			continue
+		}
+
+		value := DrainValue{Slave: slave}
+		if err := fe.DrainMap().Update(fe.ToNetwork(), &value); err != nil {
+			return fmt.Errorf("unable to update draining backend %d of %s: %s", i+1, fe.String(), err)
+		}
+	}
+
+	return nil
+}
+
+// LookupServiceDrain returns true if the backend of the service key is
+// draining.
+func LookupServiceDrain(key ServiceKey) bool {
+	_, err := key.DrainMap().Lookup(key.ToNetwork())
+	return err == nil
+}
+
+// LookupAndDeleteServiceDrain deletes entry from cilium_lb6_drain or cilium_lb4_drain
+func LookupAndDeleteServiceDrain(key ServiceKey) error {
+	if !LookupServiceDrain(key) {
+		// Ignore if entry is not found.
+		return nil
+	}
+
+	return key.DrainMap().Delete(key.ToNetwork())
+}
@@ -0,0 +1,33 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lbmap
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+func (s *LBMapSuite) TestDrainTargets(c *C) {
+	c.Assert(drainTargets(nil), HasLen, 0)
+	c.Assert(drainTargets([]bool{false, false}), HasLen, 0)
+
+	// New connections are spread across the backends not draining
+	c.Assert(drainTargets([]bool{true, false, true, false, true}), DeepEquals,
+		map[uint16]uint16{1: 2, 3: 4, 5: 2})
+
+	// Draining backends keep their new connections if there is no other
+	// backend, the agent removes them right away in that case
+	c.Assert(drainTargets([]bool{true, true}), DeepEquals,
+		map[uint16]uint16{1: 0, 2: 0})
+}
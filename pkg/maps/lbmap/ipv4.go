@@ -71,6 +71,36 @@ var (
 				return nil, nil, err
 			}
 
+			return svcKey.ToNetwork(), &svcVal, nil
+		})
+	Maglev4Map = bpf.NewMap("cilium_lb4_maglev",
+		bpf.MapTypeHash,
+		int(unsafe.Sizeof(Service4Key{})),
+		int(unsafe.Sizeof(MaglevValue{})),
+		maxFrontEnds,
+		0,
+		func(key []byte, value []byte) (bpf.MapKey, bpf.MapValue, error) {
+			svcKey, svcVal := Service4Key{}, MaglevValue{}
+
+			if err := bpf.ConvertKeyValue(key, value, &svcKey, &svcVal); err != nil {
+				return nil, nil, err
+			}
+
+			return svcKey.ToNetwork(), &svcVal, nil
+		})
+	Drain4Map = bpf.NewMap("cilium_lb4_drain",
+		bpf.MapTypeHash,
+		int(unsafe.Sizeof(Service4Key{})),
+		int(unsafe.Sizeof(DrainValue{})),
+		maxEntries,
+		0,
+		func(key []byte, value []byte) (bpf.MapKey, bpf.MapValue, error) {
+			svcKey, svcVal := Service4Key{}, DrainValue{}
+
+			if err := bpf.ConvertKeyValue(key, value, &svcKey, &svcVal); err != nil {
+				return nil, nil, err
+			}
+
 			return svcKey.ToNetwork(), &svcVal, nil
 		})
 )
@@ -85,6 +115,8 @@ type Service4Key struct {
 func (k Service4Key) IsIPv6() bool               { return false }
 func (k Service4Key) Map() *bpf.Map              { return Service4Map }
 func (k Service4Key) RRMap() *bpf.Map            { return RRSeq4Map }
+func (k Service4Key) MaglevMap() *bpf.Map        { return Maglev4Map }
+func (k Service4Key) DrainMap() *bpf.Map         { return Drain4Map }
 func (k Service4Key) NewValue() bpf.MapValue     { return &Service4Value{} }
 func (k *Service4Key) GetKeyPtr() unsafe.Pointer { return unsafe.Pointer(k) }
 func (k *Service4Key) GetPort() uint16           { return k.Port }
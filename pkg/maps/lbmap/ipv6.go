@@ -76,6 +76,38 @@ var (
 
 			return svcKey.ToNetwork(), svcVal, nil
 		})
+	// Maglev6Map represents the BPF map for Maglev lookup tables in IPv6 load
+	// balancer
+	Maglev6Map = bpf.NewMap("cilium_lb6_maglev",
+		bpf.MapTypeHash,
+		int(unsafe.Sizeof(Service6Key{})),
+		int(unsafe.Sizeof(MaglevValue{})),
+		maxFrontEnds,
+		0,
+		func(key []byte, value []byte) (bpf.MapKey, bpf.MapValue, error) {
+			svcKey, svcVal := Service6Key{}, MaglevValue{}
+
+			if err := bpf.ConvertKeyValue(key, value, &svcKey, &svcVal); err != nil {
+				return nil, nil, err
+			}
+
+			return svcKey.ToNetwork(), &svcVal, nil
+		})
+	Drain6Map = bpf.NewMap("cilium_lb6_drain",
+		bpf.MapTypeHash,
+		int(unsafe.Sizeof(Service6Key{})),
+		int(unsafe.Sizeof(DrainValue{})),
+		maxEntries,
+		0,
+		func(key []byte, value []byte) (bpf.MapKey, bpf.MapValue, error) {
+			svcKey, svcVal := Service6Key{}, DrainValue{}
+
+			if err := bpf.ConvertKeyValue(key, value, &svcKey, &svcVal); err != nil {
+				return nil, nil, err
+			}
+
+			return svcKey.ToNetwork(), &svcVal, nil
+		})
 )
 
 // Service6Key must match 'struct lb6_key' in "bpf/lib/common.h".
@@ -99,6 +131,8 @@ func NewService6Key(ip net.IP, port uint16, slave uint16) *Service6Key {
 func (k Service6Key) IsIPv6() bool               { return true }
 func (k Service6Key) Map() *bpf.Map              { return Service6Map }
 func (k Service6Key) RRMap() *bpf.Map            { return RRSeq6Map }
+func (k Service6Key) MaglevMap() *bpf.Map        { return Maglev6Map }
+func (k Service6Key) DrainMap() *bpf.Map         { return Drain6Map }
 func (k Service6Key) NewValue() bpf.MapValue     { return &Service6Value{} }
 func (k *Service6Key) GetKeyPtr() unsafe.Pointer { return unsafe.Pointer(k) }
 func (k *Service6Key) GetPort() uint16           { return k.Port }
@@ -50,6 +50,12 @@ type ServiceKey interface {
 	// Returns the BPF Weighted Round Robin map matching the key type
 	RRMap() *bpf.Map
 
+	// Returns the BPF Maglev lookup table map matching the key type
+	MaglevMap() *bpf.Map
+
+	// Returns the BPF map of the draining backends matching the key type
+	DrainMap() *bpf.Map
+
 	// Returns a RevNatValue matching a ServiceKey
 	RevNatValue() RevNatValue
 
@@ -139,6 +145,12 @@ func DeleteService(key ServiceKey) error {
 	if err != nil {
 		return err
 	}
+	if err := LookupAndDeleteServiceMaglev(key); err != nil {
+		return err
+	}
+	if err := LookupAndDeleteServiceDrain(key); err != nil {
+		return err
+	}
 	return LookupAndDeleteServiceWeights(key)
 }
 
@@ -316,8 +328,9 @@ func UpdateWrrSeq(fe ServiceKey, weights []uint16) error {
 	return UpdateServiceWeights(fe, svcRRSeq)
 }
 
-// AddSVC2BPFMap adds the given bpf service to the bpf maps.
-func AddSVC2BPFMap(fe ServiceKey, besValues []ServiceValue, addRevNAT bool, revNATID int) error {
+// AddSVC2BPFMap adds the given bpf service to the bpf maps. The backends of
+// new connections are selected with the given algorithm.
+func AddSVC2BPFMap(fe ServiceKey, besValues []ServiceValue, algorithm types.LBAlgorithm, addRevNAT bool, revNATID int) error {
 	var err error
 	var weights []uint16
 	// Put all the backend services first
@@ -356,12 +369,25 @@ func AddSVC2BPFMap(fe ServiceKey, besValues []ServiceValue, addRevNAT bool, revN
 	zeroValue := fe.NewValue().(ServiceValue)
 	zeroValue.SetCount(nSvcs - 1)
 	zeroValue.SetWeight(uint16(nNonZeroWeights))
+	// The reverse NAT ID of the master identifies the service in the
+	// session affinity maps
+	zeroValue.SetRevNat(revNATID)
 
 	err = UpdateService(fe, zeroValue)
 	if err != nil {
 		return fmt.Errorf("unable to update service %+v with the value %+v: %s", fe, zeroValue, err)
 	}
 
+	if algorithm == types.LBAlgorithmMaglev {
+		// The Maglev lookup table takes the weights into account
+		// and takes precedence over the wrr sequence
+		err = UpdateMaglev(fe, besValues)
+		if err != nil {
+			return fmt.Errorf("unable to update Maglev lookup table for %s: %s", fe.String(), err)
+		}
+		return nil
+	}
+
 	err = UpdateWrrSeq(fe, weights)
 	if err != nil {
 		return fmt.Errorf("unable to update service weights for %s with value %+v: %s", fe.String(), weights, err)
@@ -390,8 +416,9 @@ func LBSVC2ServiceKeynValue(svc types.LBSVC) (ServiceKey, []ServiceValue, error)
 
 	// Create a list of ServiceValues so we know everything is safe to put in the lb
 	// map
+	// Unhealthy backends are not added to the lb map
 	besValues := []ServiceValue{}
-	for _, be := range svc.BES {
+	for _, be := range svc.ActiveBackends() {
 		beValue := fe.NewValue().(ServiceValue)
 		if err := beValue.SetAddress(be.IP); err != nil {
 			return nil, nil, err
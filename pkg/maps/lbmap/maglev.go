@@ -0,0 +1,213 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lbmap
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net"
+	"sort"
+	"strconv"
+	"unsafe"
+)
+
+const (
+	// MaglevTableSize is the number of entries of the Maglev lookup table
+	// of a service. It must be a prime number and considerably larger
+	// than the number of backends of a service for the backends to be
+	// evenly balanced. Used by daemon for generating bpf define
+	// LB_MAGLEV_TABLE_SIZE.
+	MaglevTableSize = 1021
+)
+
+// MaglevValue must match 'struct lb_maglev' in "bpf/lib/common.h".
+type MaglevValue struct {
+	// Idx maps each entry of the lookup table to a backend index
+	// (starting at 0 for the first slave)
+	Idx [MaglevTableSize]uint16
+}
+
+func (m *MaglevValue) GetValuePtr() unsafe.Pointer { return unsafe.Pointer(m) }
+
+func (m *MaglevValue) String() string {
+	entries := map[uint16]int{}
+	max := uint16(0)
+	for _, idx := range m.Idx {
+		entries[idx]++
+		if idx > max {
+			max = idx
+		}
+	}
+
+	counts := make([]int, max+1)
+	for idx, n := range entries {
+		counts[idx] = n
+	}
+
+	return fmt.Sprintf("entries=%v", counts)
+}
+
+// maglevBackendName returns the name identifying a backend in the Maglev
+// lookup table. The name only depends on the backend address so that the
+// position of a backend in the table does not depend on the order of the
+// backends.
+func maglevBackendName(be ServiceValue) string {
+	switch v := be.(type) {
+	case *Service4Value:
+		return net.JoinHostPort(v.Address.String(), strconv.Itoa(int(v.Port)))
+	case *Service6Value:
+		return net.JoinHostPort(v.Address.String(), strconv.Itoa(int(v.Port)))
+	}
+	return be.String()
+}
+
+// maglevPermutation returns the offset and skip defining the preference list
+// of the backend with the given name.
+func maglevPermutation(name string) (offset, skip uint64) {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	offset = h.Sum64() % MaglevTableSize
+
+	// Derive the skip from a second, independent hash of the name
+	h.Write([]byte{0xff})
+	skip = h.Sum64()%(MaglevTableSize-1) + 1
+
+	return offset, skip
+}
+
+// generateMaglevTable generates the Maglev lookup table for the backends
+// with the given names and weights. Each backend is assigned a share of the
+// table entries proportional to its weight. If all weights are 0, all
+// backends are assigned an equal share. Backends with weight 0 are not
+// assigned any entries otherwise.
+func generateMaglevTable(names []string, weights []uint16) (*MaglevValue, error) {
+	n := len(names)
+	if n == 0 {
+		return nil, fmt.Errorf("needs at least 1 backend")
+	}
+	if n > MaglevTableSize {
+		return nil, fmt.Errorf("number of backends exceeds %d", MaglevTableSize)
+	}
+	if len(weights) != n {
+		return nil, fmt.Errorf("number of weights does not match number of backends")
+	}
+
+	g := uint16(0)
+	for _, w := range weights {
+		if w != 0 {
+			g = gcd(g, w)
+		}
+	}
+
+	// Number of entries each backend claims per round
+	turns := make([]uint64, n)
+	for i, w := range weights {
+		if g == 0 {
+			turns[i] = 1
+		} else {
+			turns[i] = uint64(w / g)
+		}
+	}
+
+	offsets := make([]uint64, n)
+	skips := make([]uint64, n)
+	for i, name := range names {
+		offsets[i], skips[i] = maglevPermutation(name)
+	}
+
+	// Backends take turns in the order of their names so that the table
+	// does not depend on the order of the backends
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool {
+		return names[order[a]] < names[order[b]]
+	})
+
+	taken := [MaglevTableSize]bool{}
+	next := make([]uint64, n)
+	value := MaglevValue{}
+
+	filled := 0
+	for filled < MaglevTableSize {
+		for _, i := range order {
+			for t := uint64(0); t < turns[i] && filled < MaglevTableSize; t++ {
+				// Claim the next free entry of the backend's
+				// preference list
+				c := (offsets[i] + next[i]*skips[i]) % MaglevTableSize
+				for taken[c] {
+					next[i]++
+					c = (offsets[i] + next[i]*skips[i]) % MaglevTableSize
+				}
+
+				taken[c] = true
+				value.Idx[c] = uint16(i)
+				next[i]++
+				filled++
+			}
+		}
+	}
+
+	return &value, nil
+}
+
+// UpdateServiceMaglev updates cilium_lb6_maglev or cilium_lb4_maglev bpf maps.
+func UpdateServiceMaglev(key ServiceKey, value *MaglevValue) error {
+	if _, err := key.MaglevMap().OpenOrCreate(); err != nil {
+		return err
+	}
+
+	return key.MaglevMap().Update(key.ToNetwork(), value)
+}
+
+// LookupServiceMaglev returns true if a Maglev lookup table exists for the
+// service key.
+func LookupServiceMaglev(key ServiceKey) bool {
+	_, err := key.MaglevMap().Lookup(key.ToNetwork())
+	return err == nil
+}
+
+// LookupAndDeleteServiceMaglev deletes entry from cilium_lb6_maglev or cilium_lb4_maglev
+func LookupAndDeleteServiceMaglev(key ServiceKey) error {
+	if !LookupServiceMaglev(key) {
+		// Ignore if entry is not found.
+		return nil
+	}
+
+	return key.MaglevMap().Delete(key.ToNetwork())
+}
+
+// UpdateMaglev updates bpf map with the Maglev lookup table generated for
+// the given backends.
+func UpdateMaglev(fe ServiceKey, besValues []ServiceValue) error {
+	if len(besValues) == 0 {
+		return LookupAndDeleteServiceMaglev(fe)
+	}
+
+	names := make([]string, 0, len(besValues))
+	weights := make([]uint16, 0, len(besValues))
+	for _, be := range besValues {
+		names = append(names, maglevBackendName(be))
+		weights = append(weights, be.GetWeight())
+	}
+
+	value, err := generateMaglevTable(names, weights)
+	if err != nil {
+		return fmt.Errorf("unable to generate Maglev lookup table for %s: %s", fe.String(), err)
+	}
+
+	return UpdateServiceMaglev(fe, value)
+}
@@ -0,0 +1,111 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lbmap
+
+import (
+	"fmt"
+	"testing"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type LBMapSuite struct{}
+
+var _ = Suite(&LBMapSuite{})
+
+func maglevNames(n int) []string {
+	names := make([]string, n)
+	for i := range names {
+		names[i] = fmt.Sprintf("10.0.0.%d:80", i+1)
+	}
+	return names
+}
+
+// maglevLookup returns the backend name of each entry of the lookup table
+func maglevLookup(c *C, names []string, weights []uint16) []string {
+	value, err := generateMaglevTable(names, weights)
+	c.Assert(err, IsNil)
+
+	lookup := make([]string, MaglevTableSize)
+	for i, idx := range value.Idx {
+		c.Assert(int(idx) < len(names), Equals, true)
+		lookup[i] = names[idx]
+	}
+	return lookup
+}
+
+func maglevShares(lookup []string) map[string]int {
+	shares := map[string]int{}
+	for _, name := range lookup {
+		shares[name]++
+	}
+	return shares
+}
+
+func (s *LBMapSuite) TestGenerateMaglevTable(c *C) {
+	_, err := generateMaglevTable(nil, nil)
+	c.Assert(err, Not(IsNil))
+	_, err = generateMaglevTable(maglevNames(2), []uint16{1})
+	c.Assert(err, Not(IsNil))
+
+	// Without weights all backends get an equal share
+	names := maglevNames(10)
+	shares := maglevShares(maglevLookup(c, names, make([]uint16, 10)))
+	c.Assert(shares, HasLen, 10)
+	for _, n := range shares {
+		c.Assert(n >= MaglevTableSize/10-1 && n <= MaglevTableSize/10+1, Equals, true)
+	}
+
+	// Backends with weight 0 are not selected if other backends have a
+	// weight, the share is proportional to the weight otherwise
+	shares = maglevShares(maglevLookup(c, names[:3], []uint16{30, 10, 0}))
+	c.Assert(shares, HasLen, 2)
+	c.Assert(shares[names[0]] >= 3*shares[names[1]]-3, Equals, true)
+	c.Assert(shares[names[0]] <= 3*shares[names[1]]+3, Equals, true)
+}
+
+func (s *LBMapSuite) TestMaglevTableConsistency(c *C) {
+	names := maglevNames(10)
+	lookup := maglevLookup(c, names, make([]uint16, 10))
+
+	// The table does not depend on the order of the backends
+	reversed := make([]string, len(names))
+	for i, name := range names {
+		reversed[len(names)-1-i] = name
+	}
+	c.Assert(maglevLookup(c, reversed, make([]uint16, 10)), DeepEquals, lookup)
+
+	// Removing a backend only remaps the entries of the removed backend
+	// and a small share of the other entries
+	removed := names[4]
+	updated := maglevLookup(c, append(append([]string{}, names[:4]...), names[5:]...), make([]uint16, 9))
+
+	changed := 0
+	for i := range lookup {
+		if lookup[i] == removed {
+			c.Assert(updated[i], Not(Equals), removed)
+			continue
+		}
+		if lookup[i] != updated[i] {
+			changed++
+		}
+	}
+	c.Assert(changed < MaglevTableSize/10, Equals, true)
+}
@@ -0,0 +1,256 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lbmap
+
+import (
+	"fmt"
+	"net"
+	"unsafe"
+
+	"github.com/cilium/cilium/common/types"
+	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/byteorder"
+)
+
+const (
+	// maxStatsEntries is the maximum number of backends with traffic
+	// statistics across all services
+	maxStatsEntries = 65536
+)
+
+var (
+	// Stats4Map represents the BPF map holding the traffic statistics of
+	// the IPv4 backends of each service
+	Stats4Map = bpf.NewMap("cilium_lb4_stats",
+		bpf.MapTypePerCPUHash,
+		int(unsafe.Sizeof(Stats4Key{})),
+		int(unsafe.Sizeof(StatsValue{})),
+		maxStatsEntries,
+		0,
+		func(key []byte, value []byte) (bpf.MapKey, bpf.MapValue, error) {
+			k, v := Stats4Key{}, newStatsValues()
+
+			if err := bpf.ConvertKeyValue(key, value, &k, v); err != nil {
+				return nil, nil, err
+			}
+
+			return k.ToNetwork(), v, nil
+		})
+	// Stats6Map represents the BPF map holding the traffic statistics of
+	// the IPv6 backends of each service
+	Stats6Map = bpf.NewMap("cilium_lb6_stats",
+		bpf.MapTypePerCPUHash,
+		int(unsafe.Sizeof(Stats6Key{})),
+		int(unsafe.Sizeof(StatsValue{})),
+		maxStatsEntries,
+		0,
+		func(key []byte, value []byte) (bpf.MapKey, bpf.MapValue, error) {
+			k, v := Stats6Key{}, newStatsValues()
+
+			if err := bpf.ConvertKeyValue(key, value, &k, v); err != nil {
+				return nil, nil, err
+			}
+
+			return k.ToNetwork(), v, nil
+		})
+)
+
+// StatsKey is the interface describing protocol independent key for the
+// traffic statistics maps.
+type StatsKey interface {
+	bpf.MapKey
+
+	// Returns the identifier of the backend in host byte order
+	GetStatsID() StatsID
+}
+
+// Stats4Key must match 'struct lb4_stats_key' in "bpf/lib/common.h".
+type Stats4Key struct {
+	Address  types.IPv4
+	Port     uint16
+	RevNATID uint16
+}
+
+func (k Stats4Key) NewValue() bpf.MapValue     { return newStatsValues() }
+func (k *Stats4Key) GetKeyPtr() unsafe.Pointer { return unsafe.Pointer(k) }
+
+func (k *Stats4Key) GetStatsID() StatsID {
+	return NewStatsID(k.RevNATID, k.Address.IP(), k.Port)
+}
+
+func (k *Stats4Key) String() string {
+	return fmt.Sprintf("%s:%d (%d)", k.Address, k.Port, k.RevNATID)
+}
+
+// ToNetwork converts Stats4Key to network byte order.
+func (k *Stats4Key) ToNetwork() *Stats4Key {
+	n := *k
+	n.Port = byteorder.HostToNetwork(n.Port).(uint16)
+	n.RevNATID = byteorder.HostToNetwork(n.RevNATID).(uint16)
+	return &n
+}
+
+// Stats6Key must match 'struct lb6_stats_key' in "bpf/lib/common.h".
+type Stats6Key struct {
+	Address  types.IPv6
+	Port     uint16
+	RevNATID uint16
+}
+
+func (k Stats6Key) NewValue() bpf.MapValue     { return newStatsValues() }
+func (k *Stats6Key) GetKeyPtr() unsafe.Pointer { return unsafe.Pointer(k) }
+
+func (k *Stats6Key) GetStatsID() StatsID {
+	return NewStatsID(k.RevNATID, k.Address.IP(), k.Port)
+}
+
+func (k *Stats6Key) String() string {
+	return fmt.Sprintf("[%s]:%d (%d)", k.Address, k.Port, k.RevNATID)
+}
+
+// ToNetwork converts Stats6Key to network byte order.
+func (k *Stats6Key) ToNetwork() *Stats6Key {
+	n := *k
+	n.Port = byteorder.HostToNetwork(n.Port).(uint16)
+	n.RevNATID = byteorder.HostToNetwork(n.RevNATID).(uint16)
+	return &n
+}
+
+// StatsValue must match 'struct lb_stats' in "bpf/lib/common.h".
+type StatsValue struct {
+	Packets uint64
+	Bytes   uint64
+	// Conns is the number of new TCP connections
+	Conns uint64
+}
+
+// Add adds the statistics of other to s
+func (s *StatsValue) Add(other StatsValue) {
+	s.Packets += other.Packets
+	s.Bytes += other.Bytes
+	s.Conns += other.Conns
+}
+
+func (s StatsValue) String() string {
+	return fmt.Sprintf("packets=%d bytes=%d conns=%d", s.Packets, s.Bytes, s.Conns)
+}
+
+// StatsValues holds the StatsValue of each possible CPU as stored in the
+// per-CPU traffic statistics maps
+type StatsValues []StatsValue
+
+func newStatsValues() *StatsValues {
+	v := make(StatsValues, bpf.GetNumPossibleCPUs())
+	return &v
+}
+
+func (s *StatsValues) GetValuePtr() unsafe.Pointer { return unsafe.Pointer(&(*s)[0]) }
+func (s *StatsValues) String() string              { return s.Sum().String() }
+
+// Sum returns the statistics summed up across all CPUs
+func (s *StatsValues) Sum() StatsValue {
+	sum := StatsValue{}
+	for _, v := range *s {
+		sum.Add(v)
+	}
+	return sum
+}
+
+// StatsID identifies the traffic statistics of a backend of a service
+type StatsID struct {
+	// RevNATID is the reverse NAT ID, i.e. the ID, of the service
+	RevNATID uint16
+	// Backend is the address and port of the backend
+	Backend string
+}
+
+// NewStatsID returns the identifier of the traffic statistics of the backend
+// with the given address and port of the service with the given reverse NAT
+// ID.
+func NewStatsID(revNATID uint16, ip net.IP, port uint16) StatsID {
+	return StatsID{
+		RevNATID: revNATID,
+		Backend:  net.JoinHostPort(ip.String(), fmt.Sprintf("%d", port)),
+	}
+}
+
+// networkStatsKey returns the key in network byte order
+func networkStatsKey(key StatsKey) bpf.MapKey {
+	switch k := key.(type) {
+	case *Stats4Key:
+		return k.ToNetwork()
+	case *Stats6Key:
+		return k.ToNetwork()
+	}
+	return key
+}
+
+// addStats adds the statistics of the map entry to stats
+func addStats(stats map[StatsID]StatsValue, key StatsKey, values *StatsValues) {
+	id := key.GetStatsID()
+	sum := stats[id]
+	sum.Add(values.Sum())
+	stats[id] = sum
+}
+
+// DumpStats returns the traffic statistics of all backends summed up across
+// all CPUs. Maps which do not exist are skipped.
+func DumpStats() (map[StatsID]StatsValue, error) {
+	stats := map[StatsID]StatsValue{}
+	for _, m := range []*bpf.Map{Stats4Map, Stats6Map} {
+		if err := m.Open(); err != nil {
+			continue
+		}
+
+		err := m.DumpWithCallback(func(key bpf.MapKey, value bpf.MapValue) {
+			addStats(stats, key.(StatsKey), value.(*StatsValues))
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to dump service traffic statistics: %s", err)
+		}
+	}
+
+	return stats, nil
+}
+
+// GCStats removes the traffic statistics of all backends which are not part
+// of alive. Returns the number of removed backends.
+func GCStats(alive map[StatsID]struct{}) int {
+	deleted := 0
+	for _, m := range []*bpf.Map{Stats4Map, Stats6Map} {
+		if err := m.Open(); err != nil {
+			continue
+		}
+
+		keys := []StatsKey{}
+		err := m.DumpWithCallback(func(key bpf.MapKey, value bpf.MapValue) {
+			if _, ok := alive[key.(StatsKey).GetStatsID()]; !ok {
+				keys = append(keys, key.(StatsKey))
+			}
+		})
+		if err != nil {
+			log.WithError(err).Warn("Unable to dump service traffic statistics map")
+			continue
+		}
+
+		for _, key := range keys {
+			if err := m.Delete(networkStatsKey(key)); err == nil {
+				deleted++
+			}
+		}
+	}
+
+	return deleted
+}
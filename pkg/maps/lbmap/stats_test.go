@@ -0,0 +1,64 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lbmap
+
+import (
+	"net"
+
+	"github.com/cilium/cilium/pkg/bpf"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *LBMapSuite) TestAddStats(c *C) {
+	key4 := &Stats4Key{Port: 80, RevNATID: 1}
+	copy(key4.Address[:], net.ParseIP("10.0.0.1").To4())
+	key6 := &Stats6Key{Port: 80, RevNATID: 2}
+	copy(key6.Address[:], net.ParseIP("f00d::1"))
+
+	c.Assert(key4.GetStatsID(), Equals, NewStatsID(1, net.ParseIP("10.0.0.1"), 80))
+	c.Assert(key6.GetStatsID(), Equals, StatsID{RevNATID: 2, Backend: "[f00d::1]:80"})
+
+	// The statistics of all CPUs are summed up
+	values := StatsValues{
+		{Packets: 1, Bytes: 100, Conns: 1},
+		{Packets: 2, Bytes: 200},
+		{Packets: 3, Bytes: 300, Conns: 2},
+	}
+	stats := map[StatsID]StatsValue{}
+	addStats(stats, key4, &values)
+	addStats(stats, key6, &StatsValues{{Packets: 1, Bytes: 60}})
+	c.Assert(stats, DeepEquals, map[StatsID]StatsValue{
+		{RevNATID: 1, Backend: "10.0.0.1:80"}: {Packets: 6, Bytes: 600, Conns: 3},
+		{RevNATID: 2, Backend: "[f00d::1]:80"}: {Packets: 1, Bytes: 60},
+	})
+}
+
+func (s *LBMapSuite) TestStatsValuesConvert(c *C) {
+	n := bpf.GetNumPossibleCPUs()
+	c.Assert(n > 0, Equals, true)
+
+	// The value of each CPU follows the previous one without padding
+	raw := make([]byte, n*24)
+	raw[0] = 1
+	raw[(n-1)*24+8] = 2
+	key := make([]byte, 8)
+	key[5] = 80
+
+	k, v := Stats4Key{}, newStatsValues()
+	c.Assert(bpf.ConvertKeyValue(key, raw, &k, v), IsNil)
+	c.Assert(k.ToNetwork().Port, Equals, uint16(80))
+	c.Assert(v.Sum(), Equals, StatsValue{Packets: 1, Bytes: 2})
+}
@@ -38,8 +38,44 @@ var (
 	// names and separated with a '_'
 	Namespace = "cilium"
 
+	// Subsystems
+
+	// SubsystemKVStore is the subsystem to scope metrics related to the
+	// kvstore
+	SubsystemKVStore = "kvstore"
+
+	// SubsystemIPAM is the subsystem to scope metrics related to IP
+	// address management
+	SubsystemIPAM = "ipam"
+
+	// SubsystemServices is the subsystem to scope metrics related to
+	// load-balancing services
+	SubsystemServices = "services"
+
 	// Labels
 
+	// LabelOperation is the label for the name of an operation
+	LabelOperation = "operation"
+
+	// LabelScope is the label for the scope of an operation, e.g. the
+	// class of kvstore keys
+	LabelScope = "scope"
+
+	// LabelPool is the label for the name of an IPAM pool
+	LabelPool = "pool"
+
+	// LabelFamily is the label for an address family
+	LabelFamily = "family"
+
+	// LabelService is the label for the frontend address of a service
+	LabelService = "service"
+
+	// LabelHealth is the label for the health of a service backend
+	LabelHealth = "health"
+
+	// LabelBackend is the label for the address of a service backend
+	LabelBackend = "backend"
+
 	// LabelValueOutcomeSuccess is used as a successful outcome of an operation
 	LabelValueOutcomeSuccess = "success"
 
@@ -129,6 +165,174 @@ var (
 		Help:        "Last timestamp when we received an event",
 		ConstLabels: prometheus.Labels{"source": LabelEventSourceAPI},
 	})
+
+	// Node monitor
+
+	// MonitorListenerQueueDepth is the number of notifications queued for
+	// each node monitor listener
+	MonitorListenerQueueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "monitor_listener_queue_depth",
+		Help:      "Number of notifications queued for a node monitor listener",
+	},
+		[]string{"listener", "pid"})
+
+	// MonitorListenerDropped is the number of notifications dropped for
+	// each node monitor listener
+	MonitorListenerDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "monitor_listener_dropped_notifications_total",
+		Help:      "Number of notifications dropped for a node monitor listener",
+	},
+		[]string{"listener", "pid"})
+
+	// MonitorListenerAge is the time in seconds since each node monitor
+	// listener connected
+	MonitorListenerAge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "monitor_listener_age_seconds",
+		Help:      "Number of seconds since a node monitor listener connected",
+	},
+		[]string{"listener", "pid"})
+
+	// MonitorListenersDisconnected is the number of node monitor listeners
+	// disconnected for being too slow
+	MonitorListenersDisconnected = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "monitor_listeners_disconnected_total",
+		Help:      "Number of node monitor listeners disconnected for being too slow",
+	})
+
+	// KVstore
+
+	// KVStoreOperationsDuration is the duration of kvstore operations in
+	// seconds, labelled by operation, key scope and outcome
+	KVStoreOperationsDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Subsystem: SubsystemKVStore,
+		Name:      "operations_duration_seconds",
+		Help:      "Duration in seconds of kvstore operations",
+	},
+		[]string{LabelOperation, LabelScope, "outcome"})
+
+	// KVStoreEventsQueued is the number of watch events received from the
+	// kvstore and queued for the watcher, labelled by key scope and event
+	// type
+	KVStoreEventsQueued = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: SubsystemKVStore,
+		Name:      "events_queued_total",
+		Help:      "Number of watch events received from the kvstore and queued for a watcher",
+	},
+		[]string{LabelScope, "action"})
+
+	// KVStoreWatchLag is the time in seconds the most recent watch event
+	// had to wait until the watcher was ready to accept it
+	KVStoreWatchLag = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Subsystem: SubsystemKVStore,
+		Name:      "watch_lag_seconds",
+		Help:      "Seconds the most recent watch event waited until it was accepted by the watcher",
+	},
+		[]string{LabelScope})
+
+	// IPAM
+
+	// IPAMCapacity is the number of addresses which can be allocated,
+	// labelled by pool and address family
+	IPAMCapacity = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Subsystem: SubsystemIPAM,
+		Name:      "capacity",
+		Help:      "Number of addresses which can be allocated",
+	},
+		[]string{LabelPool, LabelFamily})
+
+	// IPAMUsed is the number of allocated addresses, labelled by pool and
+	// address family
+	IPAMUsed = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Subsystem: SubsystemIPAM,
+		Name:      "used",
+		Help:      "Number of allocated addresses",
+	},
+		[]string{LabelPool, LabelFamily})
+
+	// IPAMFree is the number of addresses which can still be allocated,
+	// labelled by pool and address family
+	IPAMFree = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Subsystem: SubsystemIPAM,
+		Name:      "free",
+		Help:      "Number of addresses which can still be allocated",
+	},
+		[]string{LabelPool, LabelFamily})
+
+	// IPAMFragmentation is the fraction of the free addresses which are
+	// not part of the largest range of consecutive free addresses,
+	// labelled by pool and address family
+	IPAMFragmentation = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Subsystem: SubsystemIPAM,
+		Name:      "fragmentation_ratio",
+		Help:      "Fraction of free addresses not part of the largest range of consecutive free addresses",
+	},
+		[]string{LabelPool, LabelFamily})
+
+	// IPAMOrphans is the number of allocated addresses not used by any
+	// endpoint
+	IPAMOrphans = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Subsystem: SubsystemIPAM,
+		Name:      "orphans",
+		Help:      "Number of allocated addresses not used by any endpoint",
+	})
+
+	// Services
+
+	// ServiceBackends is the number of backends of services with a health
+	// check, labelled by service and health
+	ServiceBackends = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Subsystem: SubsystemServices,
+		Name:      "backends",
+		Help:      "Number of backends of health checked services by health",
+	},
+		[]string{LabelService, LabelHealth})
+
+	// ServiceHealthChecks is the number of backend health checks run,
+	// labelled by outcome
+	ServiceHealthChecks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: SubsystemServices,
+		Name:      "health_checks_total",
+		Help:      "Number of backend health checks",
+	},
+		[]string{"outcome"})
+
+	// ServiceBackendPackets describes the number of packets sent to each
+	// backend of each service. It is collected from the datapath by the
+	// daemon.
+	ServiceBackendPackets = prometheus.NewDesc(
+		prometheus.BuildFQName(Namespace, SubsystemServices, "backend_packets_total"),
+		"Number of packets sent to service backends",
+		[]string{LabelService, LabelBackend}, nil)
+
+	// ServiceBackendBytes describes the number of bytes sent to each
+	// backend of each service. It is collected from the datapath by the
+	// daemon.
+	ServiceBackendBytes = prometheus.NewDesc(
+		prometheus.BuildFQName(Namespace, SubsystemServices, "backend_bytes_total"),
+		"Number of bytes sent to service backends",
+		[]string{LabelService, LabelBackend}, nil)
+
+	// ServiceBackendConnections describes the number of new TCP
+	// connections sent to each backend of each service. It is collected
+	// from the datapath by the daemon.
+	ServiceBackendConnections = prometheus.NewDesc(
+		prometheus.BuildFQName(Namespace, SubsystemServices, "backend_connections_total"),
+		"Number of new TCP connections sent to service backends",
+		[]string{LabelService, LabelBackend}, nil)
 )
 
 func init() {
@@ -146,6 +350,24 @@ func init() {
 	MustRegister(EventTSK8s)
 	MustRegister(EventTSContainerd)
 	MustRegister(EventTSAPI)
+
+	MustRegister(MonitorListenerQueueDepth)
+	MustRegister(MonitorListenerDropped)
+	MustRegister(MonitorListenerAge)
+	MustRegister(MonitorListenersDisconnected)
+
+	MustRegister(KVStoreOperationsDuration)
+	MustRegister(KVStoreEventsQueued)
+	MustRegister(KVStoreWatchLag)
+
+	MustRegister(IPAMCapacity)
+	MustRegister(IPAMUsed)
+	MustRegister(IPAMFree)
+	MustRegister(IPAMFragmentation)
+	MustRegister(IPAMOrphans)
+
+	MustRegister(ServiceBackends)
+	MustRegister(ServiceHealthChecks)
 }
 
 // MustRegister adds the collector to the registry, exposing this metric to
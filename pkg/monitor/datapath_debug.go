@@ -93,6 +93,7 @@ const (
 	DbgIPIDMapFailed6
 	DbgIPIDMapSucceed4
 	DbgIPIDMapSucceed6
+	DbgMaglevSlaveSel
 )
 
 // must be in sync with <bpf/lib/conntrack.h>
@@ -276,6 +277,8 @@ func (n *DebugMsg) Dump(data []byte, prefix string) {
 		fmt.Printf("Packet hash=%d (%#x), selected_service=%d\n", n.Arg1, n.Arg1, n.Arg2)
 	case DbgRRSlaveSel:
 		fmt.Printf("RR slave selection hash=%d (%#x), selected_service=%d\n", n.Arg1, n.Arg1, n.Arg2)
+	case DbgMaglevSlaveSel:
+		fmt.Printf("Maglev slave selection hash=%d (%#x), selected_service=%d\n", n.Arg1, n.Arg1, n.Arg2)
 	case DbgLb6LookupMaster:
 		fmt.Printf("Master service lookup, addr.p4=%x key.dport=%d\n", n.Arg1, byteorder.NetworkToHost(uint16(n.Arg2)))
 	case DbgLb6LookupMasterFail:
@@ -82,6 +82,11 @@ func dropReason(reason uint8) string {
 	return fmt.Sprintf("%d", reason)
 }
 
+// DropReason returns the human readable reason of the drop
+func (n *DropNotify) DropReason() string {
+	return dropReason(n.SubType)
+}
+
 // DumpInfo prints a summary of the drop messages.
 func (n *DropNotify) DumpInfo(data []byte) {
 	fmt.Printf("xx drop (%s) flow %#x to endpoint %d, identity %d->%d: %s\n",
@@ -96,6 +96,18 @@ func connState(reason uint8) string {
 	return fmt.Sprintf("%d", reason)
 }
 
+// ObservationPointName returns the name of the observation point at which
+// the packet was traced, e.g. to-endpoint
+func (n *TraceNotify) ObservationPointName() string {
+	return obsPoint(n.ObsPoint)
+}
+
+// ConnState returns the connection tracking state of the traced packet, e.g.
+// established
+func (n *TraceNotify) ConnState() string {
+	return connState(n.Reason)
+}
+
 func (n *TraceNotify) traceSummary() string {
 	switch n.ObsPoint {
 	case TraceToLxc:
@@ -20,6 +20,7 @@ import (
 	"net"
 
 	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/u8proto"
 
 	"github.com/google/gopacket"
 	"github.com/google/gopacket/layers"
@@ -131,6 +132,59 @@ func GetConnectionSummary(data []byte) string {
 	return "[unknown]"
 }
 
+// ConnectionInfo is the network and transport layer information of a packet
+type ConnectionInfo struct {
+	SrcIP   net.IP
+	DstIP   net.IP
+	SrcPort uint16
+	DstPort uint16
+	Proto   u8proto.U8proto
+}
+
+// GetConnectionInfo decodes the data into layers and returns the addresses,
+// ports and protocol of the packet. Returns nil if the data does not contain
+// an IP packet.
+func GetConnectionInfo(data []byte) *ConnectionInfo {
+	dissectLock.Lock()
+	defer dissectLock.Unlock()
+
+	parser.DecodeLayers(data, &decoded)
+
+	var (
+		info  ConnectionInfo
+		hasIP bool
+	)
+
+	for _, typ := range decoded {
+		switch typ {
+		case layers.LayerTypeIPv4:
+			hasIP = true
+			info.SrcIP = append(net.IP(nil), ip4.SrcIP...)
+			info.DstIP = append(net.IP(nil), ip4.DstIP...)
+		case layers.LayerTypeIPv6:
+			hasIP = true
+			info.SrcIP = append(net.IP(nil), ip6.SrcIP...)
+			info.DstIP = append(net.IP(nil), ip6.DstIP...)
+		case layers.LayerTypeTCP:
+			info.Proto = u8proto.TCP
+			info.SrcPort, info.DstPort = uint16(tcp.SrcPort), uint16(tcp.DstPort)
+		case layers.LayerTypeUDP:
+			info.Proto = u8proto.UDP
+			info.SrcPort, info.DstPort = uint16(udp.SrcPort), uint16(udp.DstPort)
+		case layers.LayerTypeICMPv4:
+			info.Proto = u8proto.ICMP
+		case layers.LayerTypeICMPv6:
+			info.Proto = u8proto.ICMPv6
+		}
+	}
+
+	if !hasIP {
+		return nil
+	}
+
+	return &info
+}
+
 // Dissect parses and prints the provided data if dissect is set to true,
 // otherwise the data is printed as HEX output
 func Dissect(dissect bool, data []byte) {
@@ -73,6 +73,26 @@ func IsRunning(ep *endpoint.Endpoint) bool {
 	return !runtimeRunning
 }
 
+// IsContainerRunning returns false if the container with the given ID is
+// known to not be running. The runtime must be reachable to make this
+// decision, true is returned otherwise.
+func IsContainerRunning(containerID string) bool {
+	if dockerClient == nil {
+		return true
+	}
+
+	cont, err := dockerClient.ContainerInspect(ctx.Background(), containerID)
+	if client.IsErrContainerNotFound(err) {
+		return false
+	}
+
+	if err != nil {
+		return true
+	}
+
+	return cont.State.Running
+}
+
 // Status returns the status of the workload runtime
 func Status() *models.Status {
 	if dockerClient == nil {
@@ -369,7 +369,7 @@ func IgnoreRunningContainers() {
 		if cIP == nil {
 			continue
 		}
-		if err := ipam.AllocateIP(cIP.IP()); err != nil {
+		if err := ipam.AllocateIP(cIP.IP(), ipam.ContainerOwner(cont.ID)); err != nil {
 			continue
 		}
 		// TODO Release this address when the ignored container leaves
@@ -29,6 +29,7 @@ import (
 	"github.com/cilium/cilium/common/plugins"
 	"github.com/cilium/cilium/pkg/client"
 	"github.com/cilium/cilium/pkg/endpoint"
+	"github.com/cilium/cilium/pkg/ipam"
 	"github.com/cilium/cilium/pkg/labels"
 	"github.com/cilium/cilium/pkg/logging"
 	"github.com/cilium/cilium/pkg/logging/logfields"
@@ -89,6 +90,29 @@ type NetworkInfo struct {
 	} `json:"labels,omitempty"`
 }
 
+// K8sArgs are the arguments passed by the Kubernetes runtime via CNI_ARGS
+type K8sArgs struct {
+	cniTypes.CommonArgs
+	K8S_POD_NAMESPACE          cniTypes.UnmarshallableString
+	K8S_POD_NAME               cniTypes.UnmarshallableString
+	K8S_POD_INFRA_CONTAINER_ID cniTypes.UnmarshallableString
+}
+
+// getK8sPod returns the pod in the form namespace/name if the plugin has
+// been invoked by Kubernetes or an empty string otherwise
+func getK8sPod(args string) (string, error) {
+	k8sArgs := K8sArgs{}
+	if err := cniTypes.LoadArgs(args, &k8sArgs); err != nil {
+		return "", fmt.Errorf("unable to parse CNI args %q: %s", args, err)
+	}
+
+	if k8sArgs.K8S_POD_NAMESPACE == "" || k8sArgs.K8S_POD_NAME == "" {
+		return "", nil
+	}
+
+	return string(k8sArgs.K8S_POD_NAMESPACE) + "/" + string(k8sArgs.K8S_POD_NAME), nil
+}
+
 func main() {
 	skel.PluginMain(cmdAdd, cmdDel, version.All)
 }
@@ -356,7 +380,13 @@ func cmdAdd(args *skel.CmdArgs) error {
 		return nil
 	})
 
-	ipam, err := client.IPAMAllocate("")
+	pod, err := getK8sPod(args.Args)
+	if err != nil {
+		return err
+	}
+
+	owner := ipam.ContainerOwner(args.ContainerID)
+	ipam, err := client.IPAMAllocate("", "", pod, owner)
 	if err != nil {
 		return err
 	}
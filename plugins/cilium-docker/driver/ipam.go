@@ -18,7 +18,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
+	"github.com/cilium/cilium/pkg/annotation"
 	"github.com/cilium/cilium/pkg/client"
 	"github.com/cilium/cilium/pkg/logging/logfields"
 
@@ -28,8 +30,40 @@ import (
 const (
 	PoolIPv4 = "CiliumPoolv4"
 	PoolIPv6 = "CiliumPoolv6"
+
+	// poolNameSeparator separates the Cilium IPAM pool name from the
+	// address family in pool IDs
+	poolNameSeparator = ":"
 )
 
+// poolID returns the pool ID for the given address family pool and Cilium
+// IPAM pool. The IPAM pool is selected with the ipam-opt
+// io.cilium.network.ipam-pool when creating the network.
+func poolID(familyPool, ipamPool string) string {
+	if ipamPool == "" {
+		return familyPool
+	}
+	return familyPool + poolNameSeparator + ipamPool
+}
+
+// parsePoolID returns the address family and the Cilium IPAM pool encoded in
+// a pool ID
+func parsePoolID(id string) (family, ipamPool string) {
+	if i := strings.Index(id, poolNameSeparator); i >= 0 {
+		id, ipamPool = id[:i], id[i+1:]
+	}
+
+	family = client.AddressFamilyIPv6 // Default
+	switch id {
+	case PoolIPv4:
+		family = client.AddressFamilyIPv4
+	case PoolIPv6:
+		family = client.AddressFamilyIPv6
+	}
+
+	return family, ipamPool
+}
+
 func (driver *driver) ipamCapabilities(w http.ResponseWriter, r *http.Request) {
 	err := json.NewEncoder(w).Encode(&api.GetCapabilityResponse{})
 	if err != nil {
@@ -52,25 +86,55 @@ func (driver *driver) getDefaultAddressSpaces(w http.ResponseWriter, r *http.Req
 	objectResponse(w, resp)
 }
 
-func (driver *driver) getPoolResponse(req *api.RequestPoolRequest) *api.RequestPoolResponse {
+// ipv6PoolCIDR returns the IPv6 range of the Cilium IPAM pool with the given
+// name
+func (driver *driver) ipv6PoolCIDR(ipamPool string) (string, error) {
+	pools, err := driver.client.IPAMGetPools()
+	if err != nil {
+		return "", err
+	}
+
+	for _, pool := range pools {
+		if pool.Name != ipamPool {
+			continue
+		}
+		if pool.IPV6 == nil || pool.IPV6.Cidr == "" {
+			return "", fmt.Errorf("pool %s has no IPv6 range", ipamPool)
+		}
+		return pool.IPV6.Cidr, nil
+	}
+
+	return "", fmt.Errorf("unknown pool %s", ipamPool)
+}
+
+func (driver *driver) getPoolResponse(req *api.RequestPoolRequest) (*api.RequestPoolResponse, error) {
 	addr := driver.conf.Addressing
+	ipamPool := req.Options[annotation.IPAMPool]
 	if req.V6 == false {
 		return &api.RequestPoolResponse{
-			PoolID: PoolIPv4,
+			PoolID: poolID(PoolIPv4, ipamPool),
 			Pool:   "0.0.0.0/0",
 			Data: map[string]string{
 				"com.docker.network.gateway": addr.IPV4.IP + "/32",
 			},
+		}, nil
+	}
+
+	cidr := addr.IPV6.AllocRange
+	if ipamPool != "" {
+		var err error
+		if cidr, err = driver.ipv6PoolCIDR(ipamPool); err != nil {
+			return nil, err
 		}
 	}
 
 	return &api.RequestPoolResponse{
-		PoolID: PoolIPv6,
-		Pool:   addr.IPV6.AllocRange,
+		PoolID: poolID(PoolIPv6, ipamPool),
+		Pool:   cidr,
 		Data: map[string]string{
 			"com.docker.network.gateway": addr.IPV6.IP + "/128",
 		},
-	}
+	}, nil
 }
 
 func (driver *driver) requestPool(w http.ResponseWriter, r *http.Request) {
@@ -82,7 +146,11 @@ func (driver *driver) requestPool(w http.ResponseWriter, r *http.Request) {
 	}
 
 	log.WithField(logfields.Request, logfields.Repr(&req)).Debug("Request Pool request")
-	resp := driver.getPoolResponse(&req)
+	resp, err := driver.getPoolResponse(&req)
+	if err != nil {
+		sendError(w, fmt.Sprintf("Could not request pool: %s", err), http.StatusBadRequest)
+		return
+	}
 	log.WithField(logfields.Response, logfields.Repr(resp)).Debug("Request Pool response")
 	objectResponse(w, resp)
 }
@@ -108,15 +176,11 @@ func (driver *driver) requestAddress(w http.ResponseWriter, r *http.Request) {
 
 	log.WithField(logfields.Request, logfields.Repr(&request)).Debug("Request Address request")
 
-	family := client.AddressFamilyIPv6 // Default
-	switch request.PoolID {
-	case PoolIPv4:
-		family = client.AddressFamilyIPv4
-	case PoolIPv6:
-		family = client.AddressFamilyIPv6
-	}
+	family, ipamPool := parsePoolID(request.PoolID)
 
-	ipam, err := driver.client.IPAMAllocate(family)
+	// The container is not known when an address is requested, the
+	// endpoint created for the container becomes the owner of the address
+	ipam, err := driver.client.IPAMAllocate(family, ipamPool, "", "")
 	if err != nil {
 		sendError(w, fmt.Sprintf("Could not allocate IP address: %s", err), http.StatusBadRequest)
 		return